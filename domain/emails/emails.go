@@ -0,0 +1,79 @@
+package emails
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Every email has a plain-text template, which also defines the "subject" template, and an HTML template
+//
+//go:embed templates
+var files embed.FS
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(files, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(files, "templates/*.html"))
+)
+
+// Template names, matching the notification types
+const (
+	TemplateAssigned    = "assigned"
+	TemplateMentioned   = "mentioned"
+	TemplateDueSoon     = "due_soon"
+	TemplateDailyDigest = "daily_digest"
+)
+
+// TaskData is used by the emails about a single task
+type TaskData struct {
+	Recipient      string
+	Actor          string
+	BoardTitle     string
+	TaskName       string
+	DueDate        string
+	Excerpt        string
+	UnsubscribeURL string
+}
+
+// DigestData is used by the daily digest
+type DigestData struct {
+	Recipient      string
+	Boards         []DigestBoard
+	UnsubscribeURL string
+}
+
+type DigestBoard struct {
+	Title      string
+	Activities []DigestEntry
+}
+
+type DigestEntry struct {
+	Time    string
+	Summary string
+}
+
+// Render executes the subject, plain-text and HTML templates with the given name
+func Render(name string, data any) (string, string, string, error) {
+	var subject, text, html bytes.Buffer
+
+	err := textTemplates.ExecuteTemplate(&subject, name+".subject", data)
+	if err != nil {
+		return "", "", "", errors.Join(fmt.Errorf("failed to render %s subject", name), err)
+	}
+
+	err = textTemplates.ExecuteTemplate(&text, name+".txt", data)
+	if err != nil {
+		return "", "", "", errors.Join(fmt.Errorf("failed to render %s text", name), err)
+	}
+
+	err = htmlTemplates.ExecuteTemplate(&html, name+".html", data)
+	if err != nil {
+		return "", "", "", errors.Join(fmt.Errorf("failed to render %s html", name), err)
+	}
+
+	return strings.TrimSpace(subject.String()), text.String(), html.String(), nil
+}
@@ -0,0 +1,95 @@
+package entities
+
+import "time"
+
+type ActivityEntityType string
+
+const (
+	ActivityEntityBoard         ActivityEntityType = "board"
+	ActivityEntityMember        ActivityEntityType = "member"
+	ActivityEntityTaskList      ActivityEntityType = "task_list"
+	ActivityEntityTask          ActivityEntityType = "task"
+	ActivityEntityAttachment    ActivityEntityType = "attachment"
+	ActivityEntityComment       ActivityEntityType = "comment"
+	ActivityEntityAssignee      ActivityEntityType = "assignee"
+	ActivityEntityChecklist     ActivityEntityType = "checklist"
+	ActivityEntityChecklistItem ActivityEntityType = "checklist_item"
+	ActivityEntityDependency    ActivityEntityType = "dependency"
+	ActivityEntityCustomField   ActivityEntityType = "custom_field"
+	ActivityEntityFieldValue    ActivityEntityType = "field_value"
+	ActivityEntityLabel         ActivityEntityType = "label"
+	ActivityEntitySprint        ActivityEntityType = "sprint"
+	ActivityEntityWebhook       ActivityEntityType = "webhook"
+	ActivityEntityAutomation    ActivityEntityType = "automation_rule"
+	ActivityEntityTimeEntry     ActivityEntityType = "time_entry"
+)
+
+type ActivityAction string
+
+const (
+	ActivityCreated    ActivityAction = "created"
+	ActivityUpdated    ActivityAction = "updated"
+	ActivityMoved      ActivityAction = "moved"
+	ActivityDeleted    ActivityAction = "deleted"
+	ActivityAdded      ActivityAction = "added"
+	ActivityRemoved    ActivityAction = "removed"
+	ActivityArchived   ActivityAction = "archived"
+	ActivityUnarchived ActivityAction = "unarchived"
+	ActivityRestored   ActivityAction = "restored"
+	ActivityOverdue    ActivityAction = "overdue"
+	ActivityStarted    ActivityAction = "started"
+	ActivityClosed     ActivityAction = "closed"
+	ActivityStopped    ActivityAction = "stopped"
+)
+
+// Activity is an append-only record of a mutation made on a board
+type Activity struct {
+	ID         int                       `json:"id"`
+	IDBoard    int                       `json:"id_board"`
+	IDTask     int                       `json:"id_task,omitempty"`
+	Actor      User                      `json:"actor"`
+	EntityType ActivityEntityType        `json:"entity_type"`
+	EntityID   int                       `json:"entity_id"`
+	Action     ActivityAction            `json:"action"`
+	Changes    map[string]ActivityChange `json:"changes,omitempty"`
+	CreatedAt  time.Time                 `json:"created_at"`
+}
+
+// Event returns the event name of the activity, such as "task.moved"
+func (a Activity) Event() string {
+	return string(a.EntityType) + "." + string(a.Action)
+}
+
+// ActivityChange holds the value of a field before and after a mutation. Before is nil on creations and After is nil
+// on deletions.
+type ActivityChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type ActivityFilter struct {
+	// IDBoard filters the activities of a board. Ignored if zero.
+	IDBoard int
+
+	// IDTask filters the activities of a task. Ignored if zero.
+	IDTask int
+
+	// IDActor filters the activities made by a user. Ignored if zero.
+	IDActor int
+
+	// Action filters the activities by action. Ignored if empty.
+	Action ActivityAction
+
+	// BeforeID returns only activities older than the one with this ID, used as the pagination cursor. Ignored if
+	// zero.
+	BeforeID int
+
+	// AfterID returns only activities newer than the one with this ID, oldest first. Ignored if zero.
+	AfterID int
+
+	// Since returns only activities recorded at or after this time. Ignored if zero.
+	Since time.Time
+
+	// Limit is the maximum number of activities returned
+	Limit int
+}
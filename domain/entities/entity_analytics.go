@@ -0,0 +1,72 @@
+package entities
+
+import "time"
+
+// AnalyticsRange is the range of days a board report covers, both included. Days are in UTC.
+type AnalyticsRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// CumulativeFlow holds how many tasks each task list of the board held at the end of every day of the range
+type CumulativeFlow struct {
+	Days      []string             `json:"days"`
+	TaskLists []CumulativeFlowList `json:"task_lists"`
+}
+
+type CumulativeFlowList struct {
+	IDTaskList int    `json:"id_task_list"`
+	Name       string `json:"name"`
+
+	// Counts holds the number of tasks of the list at the end of each day of the range
+	Counts []int `json:"counts"`
+}
+
+// CumulativeFlowPoint is the number of tasks a task list held at the end of a day
+type CumulativeFlowPoint struct {
+	Day        time.Time
+	IDTaskList int
+	Tasks      int
+}
+
+// TaskFlowTimes are the times a finished task was created, started and completed
+type TaskFlowTimes struct {
+	IDTask    int
+	CreatedAt time.Time
+
+	// StartedAt is nil when the task was completed without being started
+	StartedAt   *time.Time
+	CompletedAt time.Time
+}
+
+// FlowTimes sums up the lead times, from creation to completion, and the cycle times, from start to completion, of
+// the tasks completed over the range
+type FlowTimes struct {
+	From      string        `json:"from"`
+	To        string        `json:"to"`
+	LeadTime  DurationStats `json:"lead_time"`
+	CycleTime DurationStats `json:"cycle_time"`
+}
+
+// DurationStats sums up durations, in hours
+type DurationStats struct {
+	Count   int     `json:"count"`
+	Average float64 `json:"average_hours"`
+	P50     float64 `json:"p50_hours"`
+	P75     float64 `json:"p75_hours"`
+	P85     float64 `json:"p85_hours"`
+	P95     float64 `json:"p95_hours"`
+}
+
+// WeeklyThroughput is how many tasks were completed over a week, starting on Monday
+type WeeklyThroughput struct {
+	Week      string `json:"week"`
+	Completed int    `json:"completed"`
+}
+
+// OverdueCount is how many unfinished tasks assigned to a user are overdue
+type OverdueCount struct {
+	// Assignee is nil for the overdue tasks assigned to nobody
+	Assignee *User `json:"assignee"`
+	Tasks    int   `json:"tasks"`
+}
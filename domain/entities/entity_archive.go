@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// Archive format. An archive is a zip file holding the Archive as JSON in ArchiveManifest, at its root, along with
+// the files of the attachments in ArchiveAttachmentsFolder, each named after the UUID of its attachment.
+const (
+	ArchiveFormat            = "taskflow-archive"
+	ArchiveVersion           = 1
+	ArchiveManifest          = "archive.json"
+	ArchiveAttachmentsFolder = "attachments"
+)
+
+// Archive holds boards along with their members, labels, custom fields, task lists and tasks, and the assignees,
+// labels, custom field values, dependencies, checklists, comments and attachments of the tasks. IDs only relate the
+// entities within the archive, users being matched by email when it is imported.
+type Archive struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+
+	// Environment is the environment of the instance the archive was exported from
+	Environment string    `json:"environment"`
+	ExportedAt  time.Time `json:"exported_at"`
+	ExportedBy  string    `json:"exported_by"`
+	Boards      []Board   `json:"boards"`
+}
@@ -0,0 +1,73 @@
+package entities
+
+import "time"
+
+type ThumbnailSize string
+
+const (
+	ThumbnailSmall  ThumbnailSize = "small"
+	ThumbnailMedium ThumbnailSize = "medium"
+	ThumbnailLarge  ThumbnailSize = "large"
+)
+
+// ThumbnailSizes lists every thumbnail size generated for image attachments
+var ThumbnailSizes = []ThumbnailSize{ThumbnailSmall, ThumbnailMedium, ThumbnailLarge}
+
+// Dimension returns the bounding box (in pixels) a thumbnail of this size must fit in, or 0 if the size is unknown
+func (s ThumbnailSize) Dimension() int {
+	switch s {
+	case ThumbnailSmall:
+		return 150
+	case ThumbnailMedium:
+		return 300
+	case ThumbnailLarge:
+		return 600
+	default:
+		return 0
+	}
+}
+
+type Attachment struct {
+	ID          int         `json:"id"`
+	UUID        string      `json:"uuid"`
+	IDTask      int         `json:"id_task"`
+	FileName    string      `json:"file_name"`
+	ContentType string      `json:"content_type"`
+	Size        int64       `json:"size"`
+	Path        string      `json:"-"`
+	URL         string      `json:"url"`
+	CreatedBy   User        `json:"created_by"`
+	Thumbnails  []Thumbnail `json:"thumbnails"`
+	StatusCode  int         `json:"status_code"`
+	CreatedAt   time.Time   `json:"created_at"`
+	ModifiedAt  time.Time   `json:"modified_at"`
+
+	// ImportedAuthor names the uploader of the imported attachment as the import tells, the importing user being
+	// credited
+	ImportedAuthor string `json:"imported_author,omitempty"`
+}
+
+// IsImage tells whether thumbnails can be generated for the attachment
+func (a Attachment) IsImage() bool {
+	switch a.ContentType {
+	case "image/png", "image/jpeg", "image/gif":
+		return true
+	default:
+		return false
+	}
+}
+
+type Thumbnail struct {
+	Size        ThumbnailSize `json:"size"`
+	Width       int           `json:"width"`
+	Height      int           `json:"height"`
+	ContentType string        `json:"content_type"`
+	Path        string        `json:"-"`
+	URL         string        `json:"url"`
+}
+
+// SignedURL grants access to a single attachment, without a session, until it expires
+type SignedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,129 @@
+package entities
+
+import "time"
+
+type AutomationTriggerType string
+
+const (
+	AutomationTaskMoved          AutomationTriggerType = "task_moved"
+	AutomationTaskCreated        AutomationTriggerType = "task_created"
+	AutomationDueDatePassed      AutomationTriggerType = "due_date_passed"
+	AutomationLabelAdded         AutomationTriggerType = "label_added"
+	AutomationChecklistCompleted AutomationTriggerType = "checklist_completed"
+)
+
+type AutomationActionType string
+
+const (
+	AutomationSetAssignee  AutomationActionType = "set_assignee"
+	AutomationAddLabel     AutomationActionType = "add_label"
+	AutomationMarkFinished AutomationActionType = "mark_finished"
+	AutomationMoveToList   AutomationActionType = "move_to_list"
+	AutomationPostComment  AutomationActionType = "post_comment"
+	AutomationCallWebhook  AutomationActionType = "call_webhook"
+)
+
+// AutomationRule runs its actions, in order, on the task of every activity of its board matching its trigger. The
+// actions are made on behalf of the author of the rule.
+type AutomationRule struct {
+	ID      int                `json:"id"`
+	UUID    string             `json:"uuid"`
+	IDBoard int                `json:"id_board"`
+	Name    string             `json:"name"`
+	Trigger AutomationTrigger  `json:"trigger"`
+	Actions []AutomationAction `json:"actions"`
+	Enabled bool               `json:"enabled"`
+
+	// Secret signs the calls of the webhook actions. It is only returned when the rule is created.
+	Secret string `json:"secret,omitempty"`
+
+	CreatedBy  User      `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+type AutomationTrigger struct {
+	Type AutomationTriggerType `json:"type"`
+
+	// IDTaskList narrows the task_moved and task_created triggers to the tasks moved to or created in the list. Any
+	// list matches when zero.
+	IDTaskList int `json:"id_task_list,omitempty"`
+
+	// IDLabel narrows the label_added trigger to the label. Any label matches when zero.
+	IDLabel int `json:"id_label,omitempty"`
+}
+
+// Matches tells whether the trigger fires on the given event, the event having its task list and label set
+func (t AutomationTrigger) Matches(event AutomationTrigger) bool {
+	if t.Type != event.Type {
+		return false
+	}
+
+	return (t.IDTaskList == 0 || t.IDTaskList == event.IDTaskList) && (t.IDLabel == 0 || t.IDLabel == event.IDLabel)
+}
+
+// AutomationAction is an action of a rule, its fields depending on its type
+type AutomationAction struct {
+	Type AutomationActionType `json:"type"`
+
+	// IDUser is the member assigned by set_assignee
+	IDUser int `json:"id_user,omitempty"`
+
+	// IDLabel is the label added by add_label
+	IDLabel int `json:"id_label,omitempty"`
+
+	// IDTaskList is the list move_to_list moves the task to, at its top
+	IDTaskList int `json:"id_task_list,omitempty"`
+
+	// Body is the comment posted by post_comment
+	Body string `json:"body,omitempty"`
+
+	// URL is the address call_webhook posts the activity to, which must resolve to a public IP address like the
+	// webhook URLs
+	URL string `json:"url,omitempty"`
+}
+
+type AutomationExecutionStatus string
+
+const (
+	AutomationExecutionSucceeded AutomationExecutionStatus = "succeeded"
+	AutomationExecutionFailed    AutomationExecutionStatus = "failed"
+
+	// AutomationExecutionSkipped is the status of the rules not run to protect from loops
+	AutomationExecutionSkipped AutomationExecutionStatus = "skipped"
+)
+
+// AutomationExecution is the log entry of a rule run on an activity
+type AutomationExecution struct {
+	ID         int                       `json:"id"`
+	IDRule     int                       `json:"id_rule"`
+	IDActivity int                       `json:"id_activity"`
+	IDTask     int                       `json:"id_task"`
+	Status     AutomationExecutionStatus `json:"status"`
+
+	// Depth is the number of rules whose actions led to the activity, 0 for the activities made by users
+	Depth int `json:"depth"`
+
+	// Results holds the outcome of each action run, the actions after a failed one being left out
+	Results []AutomationActionResult `json:"results"`
+
+	// Error tells why the rule was skipped
+	Error string `json:"error,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AutomationActionResult struct {
+	Type  AutomationActionType `json:"type"`
+	Error string               `json:"error,omitempty"`
+}
+
+// AutomationPayload is the JSON body posted by the webhook actions, along with the task the rule ran on
+type AutomationPayload struct {
+	ID        string    `json:"id"`
+	IDRule    int       `json:"id_rule"`
+	Event     string    `json:"event"`
+	Activity  Activity  `json:"activity"`
+	Task      Task      `json:"task"`
+	CreatedAt time.Time `json:"created_at"`
+}
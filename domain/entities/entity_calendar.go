@@ -0,0 +1,45 @@
+package entities
+
+import "time"
+
+// CalendarComponent is the iCalendar component the tasks of a feed are listed as
+type CalendarComponent string
+
+const (
+	// CalendarEvent lists the tasks as events at their due date, which every calendar application shows
+	CalendarEvent CalendarComponent = "VEVENT"
+
+	// CalendarTodo lists the tasks as to-dos, which only some calendar applications show
+	CalendarTodo CalendarComponent = "VTODO"
+)
+
+// CalendarFeed is the secret iCalendar URL of a user, listing the tasks with a due date assigned to the user or on the
+// chosen boards
+type CalendarFeed struct {
+	IDUser int `json:"id_user"`
+
+	// Token and URL are only returned when the token is generated, as only the hash of the token is stored
+	Token     string `json:"token,omitempty"`
+	URL       string `json:"url,omitempty"`
+	TokenHash string `json:"-"`
+
+	// Assigned lists the tasks assigned to the user, whatever their board
+	Assigned bool `json:"assigned"`
+
+	// IDBoards lists every task of these boards
+	IDBoards []int `json:"id_boards"`
+
+	// Finished lists the finished tasks too
+	Finished bool `json:"finished"`
+
+	Component  CalendarComponent `json:"component"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ModifiedAt time.Time         `json:"modified_at"`
+}
+
+// CalendarTask is a task listed in a calendar feed, along with the names of its board and task list
+type CalendarTask struct {
+	Task         Task
+	BoardTitle   string
+	TaskListName string
+}
@@ -0,0 +1,55 @@
+package entities
+
+import "time"
+
+type Checklist struct {
+	ID         int             `json:"id"`
+	UUID       string          `json:"uuid"`
+	IDTask     int             `json:"id_task"`
+	Name       string          `json:"name"`
+	Position   int             `json:"position"`
+	Items      []ChecklistItem `json:"items"`
+	CreatedBy  User            `json:"created_by"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ModifiedAt time.Time       `json:"modified_at"`
+}
+
+type ChecklistItem struct {
+	ID          int        `json:"id"`
+	UUID        string     `json:"uuid"`
+	IDChecklist int        `json:"id_checklist"`
+	Text        string     `json:"text"`
+	Position    int        `json:"position"`
+	Checked     bool       `json:"checked"`
+	CheckedAt   *time.Time `json:"checked_at"`
+	Assignee    *User      `json:"assignee"`
+	DueDate     *time.Time `json:"due_date"`
+
+	// IDSubtask is the task the item was promoted into. The item is checked when the subtask is finished.
+	IDSubtask int `json:"id_subtask,omitempty"`
+
+	CreatedBy  User      `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// Progress summarizes the checklist items of a task, such as 3 done out of 7
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// ChecklistProgress counts the checked items of the checklists
+func ChecklistProgress(checklists []Checklist) Progress {
+	var progress Progress
+	for _, checklist := range checklists {
+		for _, item := range checklist.Items {
+			progress.Total++
+			if item.Checked {
+				progress.Done++
+			}
+		}
+	}
+
+	return progress
+}
@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+type Comment struct {
+	ID         int       `json:"id"`
+	UUID       string    `json:"uuid"`
+	IDTask     int       `json:"id_task"`
+	Body       string    `json:"body"`
+	CreatedBy  User      `json:"created_by"`
+	StatusCode int       `json:"status_code"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
+
+	// ImportedAuthor names the author of the imported comment as the import tells, the importing user being credited
+	ImportedAuthor string `json:"imported_author,omitempty"`
+}
@@ -6,6 +6,10 @@ type Server struct {
 
 	// Host is the server host address
 	Host string `toml:"host"`
+
+	// PublicURL is the address the server is reached at by the users, used to build the links sent by email and the
+	// calendar feed URLs
+	PublicURL string `toml:"public_url"`
 }
 
 type Database struct {
@@ -38,6 +42,17 @@ type FileStorage struct {
 	StorageFolder string `toml:"storage_folder"`
 }
 
+type Trash struct {
+	// RetentionDays is how many days the deleted boards, task lists and tasks can be restored before being purged
+	RetentionDays int `toml:"retention_days"`
+}
+
+type Import struct {
+	// AttachmentsFolder is the only folder attachments are copied from when an import refers to local files. Local
+	// attachments are skipped when it is empty.
+	AttachmentsFolder string `toml:"attachments_folder"`
+}
+
 type SMTPConfig struct {
 	Host     string `toml:"host"`
 	Port     string `toml:"port"`
@@ -73,6 +88,12 @@ type Config struct {
 
 	SMTPConfig SMTPConfig
 
+	// Trash contains data related to the deleted items
+	Trash Trash
+
+	// Import contains data related to the imports of boards from other tools
+	Import Import
+
 	IntegrationToken string `toml:"integrationToken"`
 }
 
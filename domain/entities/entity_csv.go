@@ -0,0 +1,41 @@
+package entities
+
+// CSV columns of the tasks, which are also the targets the imported columns are mapped to. Custom fields are targeted
+// by their ID, such as "field.3".
+const (
+	CSVColumnList        = "list"
+	CSVColumnName        = "name"
+	CSVColumnDescription = "description"
+	CSVColumnAssignees   = "assignees"
+	CSVColumnLabels      = "labels"
+	CSVColumnDueDate     = "due_date"
+	CSVColumnStatus      = "status"
+	CSVColumnPriority    = "priority"
+	CSVColumnFieldPrefix = "field."
+)
+
+type CSVImportOptions struct {
+	// Mapping maps the headers of the CSV columns to their target, an empty target ignoring the column. Columns out of
+	// the mapping are matched by header against the exported headers and the names of the custom fields.
+	Mapping map[string]string `json:"mapping"`
+
+	// AllOrNothing creates no task at all when any row is invalid, instead of creating the tasks of the valid rows
+	AllOrNothing bool `json:"all_or_nothing"`
+}
+
+// CSVImportReport tells how many tasks an import created, along with the errors of the invalid rows
+type CSVImportReport struct {
+	Rows    int           `json:"rows"`
+	Created int           `json:"created"`
+	Errors  []CSVRowError `json:"errors"`
+
+	// WIPViolations are the task lists taken over their WIP limit by the import, when the board only warns about it
+	WIPViolations []WIPViolation `json:"wip_violations,omitempty"`
+}
+
+type CSVRowError struct {
+	// Row is the line of the row in the spreadsheet, the header being the first one
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
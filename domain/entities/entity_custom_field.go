@@ -0,0 +1,52 @@
+package entities
+
+import "time"
+
+type CustomFieldType string
+
+const (
+	CustomFieldText         CustomFieldType = "text"
+	CustomFieldNumber       CustomFieldType = "number"
+	CustomFieldDate         CustomFieldType = "date"
+	CustomFieldSingleSelect CustomFieldType = "single_select"
+	CustomFieldMultiSelect  CustomFieldType = "multi_select"
+	CustomFieldCheckbox     CustomFieldType = "checkbox"
+	CustomFieldUser         CustomFieldType = "user"
+)
+
+// IsSelect tells whether the values of the field are picked among its options
+func (t CustomFieldType) IsSelect() bool {
+	return t == CustomFieldSingleSelect || t == CustomFieldMultiSelect
+}
+
+// CustomField is a typed field defined on a board, which every task of the board can hold a value of
+type CustomField struct {
+	ID         int                 `json:"id"`
+	UUID       string              `json:"uuid"`
+	IDBoard    int                 `json:"id_board"`
+	Name       string              `json:"name"`
+	Type       CustomFieldType     `json:"type"`
+	Options    []CustomFieldOption `json:"options,omitempty"`
+	Position   int                 `json:"position"`
+	CreatedBy  User                `json:"created_by"`
+	CreatedAt  time.Time           `json:"created_at"`
+	ModifiedAt time.Time           `json:"modified_at"`
+}
+
+// CustomFieldOption is a choice of a select field. Values refer to its ID, so renaming it keeps the values.
+type CustomFieldOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// CustomFieldValue is the value of a custom field on a task. Only the member matching the field type is set: Options
+// holds the option IDs of select fields.
+type CustomFieldValue struct {
+	IDField int        `json:"id_field"`
+	Text    *string    `json:"text,omitempty"`
+	Number  *float64   `json:"number,omitempty"`
+	Date    *time.Time `json:"date,omitempty"`
+	Options []string   `json:"options,omitempty"`
+	Checked *bool      `json:"checked,omitempty"`
+	IDUser  int        `json:"id_user,omitempty"`
+}
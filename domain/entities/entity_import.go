@@ -0,0 +1,108 @@
+package entities
+
+import "time"
+
+// TrelloBoard is the part of a Trello board JSON export that is imported
+type TrelloBoard struct {
+	Name        string            `json:"name"`
+	Description string            `json:"desc"`
+	Lists       []TrelloList      `json:"lists"`
+	Cards       []TrelloCard      `json:"cards"`
+	Labels      []TrelloLabel     `json:"labels"`
+	Checklists  []TrelloChecklist `json:"checklists"`
+	Members     []TrelloMember    `json:"members"`
+	Actions     []TrelloAction    `json:"actions"`
+}
+
+type TrelloList struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Closed   bool    `json:"closed"`
+	Position float64 `json:"pos"`
+}
+
+type TrelloCard struct {
+	ID          string             `json:"id"`
+	IDList      string             `json:"idList"`
+	Name        string             `json:"name"`
+	Description string             `json:"desc"`
+	Closed      bool               `json:"closed"`
+	Position    float64            `json:"pos"`
+	Due         *time.Time         `json:"due"`
+	DueComplete bool               `json:"dueComplete"`
+	IDLabels    []string           `json:"idLabels"`
+	IDMembers   []string           `json:"idMembers"`
+	Attachments []TrelloAttachment `json:"attachments"`
+}
+
+type TrelloLabel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type TrelloChecklist struct {
+	ID         string            `json:"id"`
+	IDCard     string            `json:"idCard"`
+	Name       string            `json:"name"`
+	Position   float64           `json:"pos"`
+	CheckItems []TrelloCheckItem `json:"checkItems"`
+}
+
+type TrelloCheckItem struct {
+	Name     string     `json:"name"`
+	State    string     `json:"state"`
+	Position float64    `json:"pos"`
+	Due      *time.Time `json:"due"`
+	IDMember string     `json:"idMember"`
+}
+
+// TrelloMember is a member of the board. Exports only hold the email of the members when the exporting user is
+// allowed to see it.
+type TrelloMember struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	FullName string `json:"fullName"`
+	Email    string `json:"email"`
+}
+
+type TrelloAttachment struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	MimeType string `json:"mimeType"`
+}
+
+// TrelloAction is an entry of the board history. Only the comments are imported.
+type TrelloAction struct {
+	Type     string           `json:"type"`
+	Date     time.Time        `json:"date"`
+	IDMember string           `json:"idMemberCreator"`
+	Data     TrelloActionData `json:"data"`
+}
+
+type TrelloActionData struct {
+	Text string `json:"text"`
+	Card struct {
+		ID string `json:"id"`
+	} `json:"card"`
+}
+
+// ImportReport tells what an import created, or would create on a dry run
+type ImportReport struct {
+	DryRun bool `json:"dry_run"`
+
+	// Board is the created board, or the board that would be created on a dry run
+	Board       *Board `json:"board"`
+	TaskLists   int    `json:"task_lists"`
+	Tasks       int    `json:"tasks"`
+	Labels      int    `json:"labels"`
+	Checklists  int    `json:"checklists"`
+	Comments    int    `json:"comments"`
+	Attachments int    `json:"attachments"`
+
+	// Members holds the users matched by email, who become members of the board
+	Members []User `json:"members"`
+
+	// Warnings tells what could not be imported as is, such as members without an account or skipped attachments
+	Warnings []string `json:"warnings"`
+}
@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+// Job is a background job run by the scheduler
+type Job struct {
+	Name      string    `json:"name"`
+	Schedule  string    `json:"schedule"`
+	NextRunAt time.Time `json:"next_run_at"`
+}
+
+type JobRunStatus string
+
+const (
+	JobRunRunning   JobRunStatus = "running"
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+// JobRun is a run of a background job
+//
+// A job runs once per scheduled time, on the first instance to claim it
+type JobRun struct {
+	ID          int          `json:"id"`
+	Job         string       `json:"job"`
+	Instance    string       `json:"instance"`
+	Status      JobRunStatus `json:"status"`
+	Error       string       `json:"error,omitempty"`
+	ScheduledAt time.Time    `json:"scheduled_at"`
+	StartedAt   time.Time    `json:"started_at"`
+	FinishedAt  *time.Time   `json:"finished_at"`
+}
+
+type JobRunFilter struct {
+	// Job keeps the runs of the given job only, when set
+	Job string
+
+	// BeforeID keeps the runs older than the given one, for pagination
+	BeforeID int
+
+	Limit int
+}
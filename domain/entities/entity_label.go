@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+// Label is a colored tag defined on a board, which tasks of the board can be tagged with
+type Label struct {
+	ID         int       `json:"id"`
+	UUID       string    `json:"uuid"`
+	IDBoard    int       `json:"id_board"`
+	Name       string    `json:"name"`
+	Color      string    `json:"color"`
+	CreatedBy  User      `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
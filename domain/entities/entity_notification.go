@@ -0,0 +1,136 @@
+package entities
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotificationAssigned    NotificationType = "assigned"
+	NotificationMentioned   NotificationType = "mentioned"
+	NotificationDueSoon     NotificationType = "due_soon"
+	NotificationDailyDigest NotificationType = "daily_digest"
+
+	// NotificationCommented and NotificationTaskChanged only reach the inbox of the users watching the task or board
+	NotificationCommented   NotificationType = "commented"
+	NotificationTaskChanged NotificationType = "task_changed"
+
+	// NotificationAll is only used to unsubscribe from every email at once
+	NotificationAll NotificationType = "all"
+)
+
+// Emailed tells whether notifications of this type are also sent by email
+func (t NotificationType) Emailed() bool {
+	switch t {
+	case NotificationAssigned, NotificationMentioned, NotificationDueSoon:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotificationPreferences tells which emails the user wants to receive
+type NotificationPreferences struct {
+	IDUser       int        `json:"id_user"`
+	Assigned     bool       `json:"assigned"`
+	Mentioned    bool       `json:"mentioned"`
+	DueSoon      bool       `json:"due_soon"`
+	DailyDigest  bool       `json:"daily_digest"`
+	DigestSentAt *time.Time `json:"-"`
+}
+
+// DefaultNotificationPreferences are used for users who never changed their preferences. The digest is opt-in.
+func DefaultNotificationPreferences(userID int) NotificationPreferences {
+	return NotificationPreferences{
+		IDUser:    userID,
+		Assigned:  true,
+		Mentioned: true,
+		DueSoon:   true,
+	}
+}
+
+// Wants tells whether the user wants to be notified of the given type
+func (n NotificationPreferences) Wants(notificationType NotificationType) bool {
+	switch notificationType {
+	case NotificationAssigned:
+		return n.Assigned
+	case NotificationMentioned:
+		return n.Mentioned
+	case NotificationDueSoon:
+		return n.DueSoon
+	case NotificationDailyDigest:
+		return n.DailyDigest
+	default:
+		return false
+	}
+}
+
+// Unsubscribe turns off the given type, or every type for NotificationAll
+func (n *NotificationPreferences) Unsubscribe(notificationType NotificationType) {
+	switch notificationType {
+	case NotificationAssigned:
+		n.Assigned = false
+	case NotificationMentioned:
+		n.Mentioned = false
+	case NotificationDueSoon:
+		n.DueSoon = false
+	case NotificationDailyDigest:
+		n.DailyDigest = false
+	case NotificationAll:
+		n.Assigned = false
+		n.Mentioned = false
+		n.DueSoon = false
+		n.DailyDigest = false
+	}
+}
+
+// Notification is an entry of the user's in-app inbox
+type Notification struct {
+	ID         int              `json:"id"`
+	IDUser     int              `json:"id_user"`
+	Type       NotificationType `json:"type"`
+	IDBoard    int              `json:"id_board"`
+	IDTask     int              `json:"id_task,omitempty"`
+	IDActivity int              `json:"id_activity,omitempty"`
+	Actor      *User            `json:"actor,omitempty"`
+	Message    string           `json:"message"`
+	ReadAt     *time.Time       `json:"read_at"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+type NotificationFilter struct {
+	IDUser int
+
+	// Unread returns only the notifications not read yet
+	Unread bool
+
+	// BeforeID returns only notifications older than the one with this ID, used as the pagination cursor. Ignored if
+	// zero.
+	BeforeID int
+
+	// Limit is the maximum number of notifications returned
+	Limit int
+}
+
+type EmailStatus string
+
+const (
+	EmailPending EmailStatus = "pending"
+	EmailSent    EmailStatus = "sent"
+	EmailFailed  EmailStatus = "failed"
+)
+
+// Email is a message waiting in the email queue, or already sent
+type Email struct {
+	ID             int
+	To             string
+	Subject        string
+	Text           string
+	HTML           string
+	UnsubscribeURL string
+	Status         EmailStatus
+	Attempts       int
+	Error          string
+	NextAttemptAt  time.Time
+	SentAt         *time.Time
+	CreatedAt      time.Time
+}
@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+type SearchResultType string
+
+const (
+	SearchResultBoard   SearchResultType = "board"
+	SearchResultTask    SearchResultType = "task"
+	SearchResultComment SearchResultType = "comment"
+)
+
+// SearchResult is a board, task or comment matching a search. Comments are titled after their task.
+type SearchResult struct {
+	Type    SearchResultType `json:"type"`
+	ID      int              `json:"id"`
+	IDBoard int              `json:"id_board"`
+	IDTask  int              `json:"id_task,omitempty"`
+	Title   string           `json:"title"`
+
+	// Snippet is an excerpt of the description or comment body around the matches, which are wrapped in <mark> tags.
+	// The title and snippet are HTML escaped.
+	Snippet    string    `json:"snippet"`
+	Score      float64   `json:"score"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+type SearchFilter struct {
+	// IDUser restricts the search to the boards the user owns or is a member of
+	IDUser int
+
+	// Terms are the words to search, every one of them having to match as a word prefix
+	Terms []string
+
+	// Types filters the results by type. Ignored if empty.
+	Types []SearchResultType
+
+	// IDBoard restricts the search to a board. Ignored if zero.
+	IDBoard int
+
+	// Limit is the maximum number of results returned
+	Limit int
+}
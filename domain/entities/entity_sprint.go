@@ -0,0 +1,85 @@
+package entities
+
+import "time"
+
+type SprintStatus string
+
+const (
+	SprintPlanned SprintStatus = "planned"
+	SprintActive  SprintStatus = "active"
+	SprintClosed  SprintStatus = "closed"
+)
+
+// Sprint is an iteration of a board, the tasks committed to it being expected to be finished by its end date. A
+// board has at most one active sprint.
+type Sprint struct {
+	ID      int    `json:"id"`
+	UUID    string `json:"uuid"`
+	IDBoard int    `json:"id_board"`
+	Name    string `json:"name"`
+	Goal    string `json:"goal"`
+
+	// StartDate and EndDate are the first and last days of the sprint, in UTC
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+
+	Status    SprintStatus `json:"status"`
+	StartedAt *time.Time   `json:"started_at"`
+	ClosedAt  *time.Time   `json:"closed_at"`
+	TaskCount int          `json:"task_count"`
+
+	// Tasks is only filled when a single sprint is returned
+	Tasks []SprintTask `json:"tasks,omitempty"`
+
+	CreatedBy  User      `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// SprintTask is a task committed to a sprint
+type SprintTask struct {
+	Task    Task      `json:"task"`
+	AddedAt time.Time `json:"added_at"`
+
+	// CarriedOver tells whether the task was unfinished when the sprint closed
+	CarriedOver bool `json:"carried_over"`
+
+	// CompletedAt is the last time the task was finished, nil while it is unfinished
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// SprintBurndown holds the work remaining at the end of every day of a sprint, estimates being in minutes. The
+// remaining series stop at the current day while the sprint runs.
+type SprintBurndown struct {
+	Days              []string `json:"days"`
+	RemainingTasks    []int    `json:"remaining_tasks"`
+	RemainingEstimate []int    `json:"remaining_estimate"`
+
+	// IdealEstimate burns the estimate committed when the sprint started evenly down to zero on its last day
+	IdealEstimate []float64 `json:"ideal_estimate"`
+}
+
+// SprintReport sums up the tasks of a sprint, along with their estimates
+type SprintReport struct {
+	Sprint Sprint `json:"sprint"`
+
+	// Committed holds the tasks committed when the sprint started, Added the ones committed after
+	Committed SprintScope `json:"committed"`
+	Added     SprintScope `json:"added"`
+
+	Completed SprintScope `json:"completed"`
+
+	// Unfinished holds the tasks left unfinished, carried over once the sprint is closed
+	Unfinished SprintScope `json:"unfinished"`
+}
+
+// SprintScope is a number of tasks along with the sum of their estimates, in minutes
+type SprintScope struct {
+	Tasks    int `json:"tasks"`
+	Estimate int `json:"estimate"`
+}
+
+// Add returns the sum of both scopes
+func (s SprintScope) Add(other SprintScope) SprintScope {
+	return SprintScope{Tasks: s.Tasks + other.Tasks, Estimate: s.Estimate + other.Estimate}
+}
@@ -9,38 +9,249 @@ const (
 	TaskFinished    = 1
 )
 
+// Status codes of the boards, task lists and tasks. Archived items are left out of the default listings, while
+// deleted items stay in the trash until they are restored or purged.
+const (
+	StatusActive   = 0
+	StatusArchived = 1
+	StatusDeleted  = 2
+)
+
+type TaskPriority int
+
+const (
+	TaskPriorityNone   TaskPriority = 0
+	TaskPriorityLow    TaskPriority = 1
+	TaskPriorityMedium TaskPriority = 2
+	TaskPriorityHigh   TaskPriority = 3
+	TaskPriorityUrgent TaskPriority = 4
+)
+
 type Board struct {
-	ID          int        `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	CreatedBy   User       `json:"created_by"`
-	Users       []User     `json:"users"`
-	TaskLists   []TaskList `json:"task_lists"`
-	StatusCode  int        `json:"status_code"`
-	CreatedAt   time.Time  `json:"created_at"`
-	ModifiedAt  time.Time  `json:"modified_at"`
+	ID           int           `json:"id"`
+	UUID         string        `json:"uuid"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	CreatedBy    User          `json:"created_by"`
+	Users        []User        `json:"users"`
+	Labels       []Label       `json:"labels"`
+	CustomFields []CustomField `json:"custom_fields"`
+	TaskLists    []TaskList    `json:"task_lists"`
+
+	// WIPWarnOnly lets tasks be created in or moved into task lists having reached their WIP limit, the responses
+	// flagging the violation instead
+	WIPWarnOnly bool `json:"wip_warn_only"`
+
+	StatusCode int       `json:"status_code"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
 }
 
 type TaskList struct {
-	ID          int       `json:"id"`
-	IDBoard     int       `json:"id_board"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedBy   User      `json:"created_by"`
-	Tasks       []Task    `json:"tasks"`
-	StatusCode  int       `json:"status_code"`
-	CreatedAt   time.Time `json:"created_at"`
-	ModifiedAt  time.Time `json:"modified_at"`
+	ID          int    `json:"id"`
+	UUID        string `json:"uuid"`
+	IDBoard     int    `json:"id_board"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Position    int    `json:"position"`
+	CreatedBy   User   `json:"created_by"`
+	Tasks       []Task `json:"tasks"`
+
+	// WIPLimit is the maximum number of active tasks the list can hold, 0 meaning no limit
+	WIPLimit int `json:"wip_limit"`
+
+	// TaskCount is the number of active tasks the list holds, whatever the tasks listed
+	TaskCount int `json:"task_count"`
+
+	StatusCode int       `json:"status_code"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
 }
 
 type Task struct {
 	ID          int                  `json:"id"`
+	UUID        string               `json:"uuid"`
 	IDTaskList  int                  `json:"id_task_list"`
+	IDBoard     int                  `json:"id_board"`
 	Name        string               `json:"name"`
 	Description string               `json:"description"`
+	Position    int                  `json:"position"`
 	CreatedBy   User                 `json:"created_by"`
 	Status      TaskCompletionStatus `json:"status"`
-	StatusCode  int                  `json:"status_code"`
-	CreatedAt   time.Time            `json:"created_at"`
-	ModifiedAt  time.Time            `json:"modified_at"`
+	Priority    TaskPriority         `json:"priority"`
+	DueDate     *time.Time           `json:"due_date"`
+	OverdueAt   *time.Time           `json:"overdue_at"`
+	Recurrence  *Recurrence          `json:"recurrence"`
+	IDParent    int                  `json:"id_parent,omitempty"`
+	Progress    Progress             `json:"progress"`
+	Checklists  []Checklist          `json:"checklists,omitempty"`
+	BlockedBy   []int                `json:"blocked_by,omitempty"`
+	Fields      []CustomFieldValue   `json:"fields"`
+	Blocks      []int                `json:"blocks,omitempty"`
+	Assignees   []User               `json:"assignees"`
+	Labels      []Label              `json:"labels"`
+	Attachments []Attachment         `json:"attachments"`
+
+	// OriginalEstimate is the time the task was expected to take, in minutes, 0 when not estimated
+	OriginalEstimate int `json:"original_estimate"`
+
+	// Comments is only filled when a board is added along with its content, or exported
+	Comments []Comment `json:"comments,omitempty"`
+
+	// ImportedAuthor names the author of the imported task as the import tells, the importing user being credited
+	ImportedAuthor string `json:"imported_author,omitempty"`
+
+	// WIPViolation is only set when the task was created in a task list over its WIP limit
+	WIPViolation *WIPViolation `json:"wip_violation,omitempty"`
+
+	StatusCode int       `json:"status_code"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// Recurrence makes a task repeat: once it is completed or its due date passes, its next occurrence is created and
+// takes over the recurrence
+type Recurrence struct {
+	// Rule is an RRULE subset, such as "FREQ=WEEKLY;BYDAY=MO" or "FREQ=MONTHLY;BYMONTHDAY=1"
+	Rule string `json:"rule"`
+
+	// IDTaskList is the list the next occurrence is created in, the list of the task when not set
+	IDTaskList int `json:"id_task_list,omitempty"`
+}
+
+// WIPViolation tells that a task was created in or moved into a task list having reached its WIP limit, which the
+// board only warns about
+type WIPViolation struct {
+	IDTaskList int `json:"id_task_list"`
+	Limit      int `json:"limit"`
+
+	// Tasks is the number of active tasks the list holds along with the task
+	Tasks int `json:"tasks"`
+}
+
+// TaskDependency tells that the blocker task must be finished before the task can be
+type TaskDependency struct {
+	IDTask    int       `json:"id_task"`
+	IDBlocker int       `json:"id_blocker"`
+	CreatedBy User      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DependencyGraph holds the tasks of a board having dependencies, along with the dependencies between them
+type DependencyGraph struct {
+	Tasks        []DependencyNode `json:"tasks"`
+	Dependencies []TaskDependency `json:"dependencies"`
+}
+
+type DependencyNode struct {
+	ID         int                  `json:"id"`
+	IDTaskList int                  `json:"id_task_list"`
+	Name       string               `json:"name"`
+	Status     TaskCompletionStatus `json:"status"`
+
+	// Blocked tells whether any blocker of the task is unfinished
+	Blocked bool `json:"blocked"`
+}
+
+type BoardSort string
+
+const (
+	BoardSortCreated  BoardSort = "created_at"
+	BoardSortModified BoardSort = "modified_at"
+	BoardSortTitle    BoardSort = "title"
+)
+
+type BoardFilter struct {
+	// IDUser filters the boards the user owns or is a member of
+	IDUser int
+
+	// Text filters the boards whose title contains it. Ignored if empty.
+	Text string
+
+	// Archived returns the archived boards instead of the active ones
+	Archived bool
+
+	// Sort is the order of the boards, by creation time when empty
+	Sort BoardSort
+
+	// Descending reverses the order of the boards
+	Descending bool
+
+	// AfterID returns only boards sorted after the one with this ID, used as the pagination cursor. Ignored if zero.
+	AfterID int
+
+	// Limit is the maximum number of boards returned
+	Limit int
+}
+
+type TaskSort string
+
+const (
+	TaskSortPosition TaskSort = "position"
+	TaskSortDueDate  TaskSort = "due_date"
+	TaskSortPriority TaskSort = "priority"
+	TaskSortModified TaskSort = "modified_at"
+
+	// TaskSortField sorts by the value of a custom field, requested as "field:<id>"
+	TaskSortField TaskSort = "field"
+)
+
+// TaskSortFieldPrefix prefixes the ID of the custom field in the requested sort
+const TaskSortFieldPrefix = "field:"
+
+type TaskFilter struct {
+	// IDBoard filters the tasks of a board
+	IDBoard int
+
+	// IDAssignee filters the tasks assigned to a user. Ignored if zero.
+	IDAssignee int
+
+	// IDLabel filters the tasks tagged with a label. Ignored if zero.
+	IDLabel int
+
+	// DueAfter returns only tasks due at or after this time. Ignored if nil.
+	DueAfter *time.Time
+
+	// DueBefore returns only tasks due before this time. Ignored if nil.
+	DueBefore *time.Time
+
+	// Status filters the tasks by completion status. Ignored if nil.
+	Status *TaskCompletionStatus
+
+	// Text filters the tasks whose name or description contains it. Ignored if empty.
+	Text string
+
+	// Fields filters the tasks by custom field values, all of them having to match
+	Fields []TaskFieldFilter
+
+	// Archived returns the archived tasks, along with the tasks of the archived lists, instead of the active ones
+	Archived bool
+
+	// Sort is the order of the tasks, by list and position when empty. Tasks without due date always come last when
+	// sorting by due date, as do tasks without value when sorting by a custom field.
+	Sort TaskSort
+
+	// SortField is the custom field sorted by when Sort is TaskSortField
+	SortField *CustomField
+
+	// Descending reverses the order of the tasks
+	Descending bool
+
+	// AfterID returns only tasks sorted after the one with this ID, used as the pagination cursor. Ignored if zero.
+	AfterID int
+
+	// Limit is the maximum number of tasks returned
+	Limit int
+}
+
+// TaskFieldFilter matches the tasks whose value of a custom field equals the given one. Select values match when they
+// contain the option, and dates match on the whole day.
+type TaskFieldFilter struct {
+	IDField int
+
+	// Raw is the value as given in the request, parsed into Value according to Type once the field is known
+	Raw string
+
+	Type  CustomFieldType
+	Value CustomFieldValue
 }
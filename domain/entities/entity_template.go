@@ -0,0 +1,65 @@
+package entities
+
+import "time"
+
+// BoardTemplate holds the structure of a board saved by a user, to create new boards from
+type BoardTemplate struct {
+	ID          int             `json:"id"`
+	UUID        string          `json:"uuid"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Content     TemplateContent `json:"content"`
+	CreatedBy   User            `json:"created_by"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ModifiedAt  time.Time       `json:"modified_at"`
+}
+
+// TemplateContent is what a board created from a template starts with. Labels and custom fields are referred to by
+// name, as their names are unique per board.
+type TemplateContent struct {
+	Labels    []TemplateLabel    `json:"labels"`
+	Fields    []TemplateField    `json:"custom_fields"`
+	TaskLists []TemplateTaskList `json:"task_lists"`
+}
+
+type TemplateLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type TemplateField struct {
+	Name    string              `json:"name"`
+	Type    CustomFieldType     `json:"type"`
+	Options []CustomFieldOption `json:"options,omitempty"`
+}
+
+type TemplateTaskList struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Tasks       []TemplateTask `json:"tasks,omitempty"`
+}
+
+type TemplateTask struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Priority    TaskPriority `json:"priority"`
+
+	// OriginalEstimate is the estimate of the task in minutes, 0 when not estimated
+	OriginalEstimate int `json:"original_estimate,omitempty"`
+
+	// DueInDays is the number of days between the creation of the board and the due date of the task, which has no
+	// due date when not set
+	DueInDays *int `json:"due_in_days,omitempty"`
+
+	// Labels holds the names of the labels of the task
+	Labels []string `json:"labels,omitempty"`
+}
+
+// DuplicateOptions tells what is copied along with the task lists, labels and custom fields of a board
+type DuplicateOptions struct {
+	Tasks   bool `json:"include_tasks"`
+	Members bool `json:"include_members"`
+
+	// Attachments copies the attachments of the tasks, along with their files. It requires the tasks to be copied.
+	Attachments bool `json:"include_attachments"`
+}
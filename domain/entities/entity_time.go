@@ -0,0 +1,71 @@
+package entities
+
+import "time"
+
+// TimeEntry is time a user spent on a task, tracked with a timer or entered manually
+type TimeEntry struct {
+	ID        int       `json:"id"`
+	UUID      string    `json:"uuid"`
+	IDTask    int       `json:"id_task"`
+	IDBoard   int       `json:"id_board"`
+	User      User      `json:"user"`
+	StartedAt time.Time `json:"started_at"`
+
+	// EndedAt is nil while the timer of the entry runs
+	EndedAt *time.Time `json:"ended_at"`
+
+	// Minutes is the time spent, set once the timer stops, partial minutes being rounded up
+	Minutes int    `json:"minutes"`
+	Note    string `json:"note"`
+
+	// Manual tells whether the entry was entered rather than tracked with a timer
+	Manual bool `json:"manual"`
+
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// TimeReportFilter selects the time entries of a report, which started over the range of days. Days are in UTC.
+type TimeReportFilter struct {
+	// IDBoard reports the time spent on the tasks of a board. Ignored if zero.
+	IDBoard int
+
+	// IDUser reports the time spent by a user. Ignored if zero.
+	IDUser int
+
+	From time.Time
+	To   time.Time
+}
+
+// TimeReport sums up the time entries matching a filter, the running timers being left out
+type TimeReport struct {
+	From    string            `json:"from"`
+	To      string            `json:"to"`
+	Minutes int               `json:"minutes"`
+	Users   []TimeReportUser  `json:"users"`
+	Tasks   []TimeReportTask  `json:"tasks"`
+	Entries []TimeReportEntry `json:"entries"`
+}
+
+// TimeReportUser is the time a user spent over the range
+type TimeReportUser struct {
+	User    User `json:"user"`
+	Minutes int  `json:"minutes"`
+}
+
+// TimeReportTask is the time spent on a task over the range, along with its original estimate
+type TimeReportTask struct {
+	IDTask           int    `json:"id_task"`
+	IDBoard          int    `json:"id_board"`
+	Name             string `json:"name"`
+	OriginalEstimate int    `json:"original_estimate"`
+	Minutes          int    `json:"minutes"`
+}
+
+// TimeReportEntry is a time entry of a report, along with the names of its task and board
+type TimeReportEntry struct {
+	Entry        TimeEntry `json:"entry"`
+	BoardTitle   string    `json:"board_title"`
+	TaskName     string    `json:"task_name"`
+	TaskEstimate int       `json:"task_estimate"`
+}
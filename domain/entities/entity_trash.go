@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+type TrashItemType string
+
+const (
+	TrashBoard    TrashItemType = "board"
+	TrashTaskList TrashItemType = "task_list"
+	TrashTask     TrashItemType = "task"
+)
+
+// TrashItem is a deleted board, task list or task, which can be restored until it is purged
+type TrashItem struct {
+	Type    TrashItemType `json:"type"`
+	ID      int           `json:"id"`
+	IDBoard int           `json:"id_board"`
+
+	// IDTaskList is the task list of a deleted task
+	IDTaskList int `json:"id_task_list,omitempty"`
+
+	// Title is the title of the board, or the name of the task list or task
+	Title     string    `json:"title"`
+	CreatedBy User      `json:"created_by"`
+	DeletedAt time.Time `json:"deleted_at"`
+
+	// PurgeAt is when the item is deleted for good, along with its attachments
+	PurgeAt time.Time `json:"purge_at"`
+}
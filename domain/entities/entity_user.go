@@ -16,3 +16,10 @@ type UserCredentials struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+// AuthToken is the content of a session token
+type AuthToken struct {
+	ID        string
+	IDUser    int
+	ExpiresAt time.Time
+}
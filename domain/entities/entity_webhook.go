@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+type Webhook struct {
+	ID                  int       `json:"id"`
+	UUID                string    `json:"uuid"`
+	IDBoard             int       `json:"id_board"`
+	URL                 string    `json:"url"`
+	Secret              string    `json:"secret,omitempty"`
+	Events              []string  `json:"events"`
+	Enabled             bool      `json:"enabled"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	CreatedBy           User      `json:"created_by"`
+	CreatedAt           time.Time `json:"created_at"`
+	ModifiedAt          time.Time `json:"modified_at"`
+}
+
+// Accepts tells whether the webhook is subscribed to the event, named "<entity type>.<action>"
+//
+// An empty event list accepts every event. Filters can use "*" as the entity type or the action, such as "task.*".
+func (w Webhook) Accepts(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+
+	entityType, action, _ := strings.Cut(event, ".")
+	for _, filter := range w.Events {
+		filterType, filterAction, found := strings.Cut(filter, ".")
+		if !found {
+			filterAction = "*"
+		}
+
+		if (filterType == "*" || filterType == entityType) && (filterAction == "*" || filterAction == action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+type WebhookDelivery struct {
+	ID            int                   `json:"id"`
+	UUID          string                `json:"uuid"`
+	IDWebhook     int                   `json:"id_webhook"`
+	IDActivity    int                   `json:"id_activity"`
+	Event         string                `json:"event"`
+	Payload       json.RawMessage       `json:"payload"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int                   `json:"attempts"`
+	ResponseCode  int                   `json:"response_code"`
+	Error         string                `json:"error"`
+	NextAttemptAt time.Time             `json:"next_attempt_at"`
+	DeliveredAt   *time.Time            `json:"delivered_at"`
+	CreatedAt     time.Time             `json:"created_at"`
+}
+
+// WebhookPayload is the JSON body sent to webhooks
+type WebhookPayload struct {
+	// ID is the delivery UUID, which is kept on redeliveries so receivers can deduplicate them
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	Activity  Activity  `json:"activity"`
+	CreatedAt time.Time `json:"created_at"`
+}
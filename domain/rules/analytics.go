@@ -0,0 +1,10 @@
+package rules
+
+// Analytics rules
+const (
+	// AnalyticsDefaultDays is the number of days, up to today, reports cover when no range is given
+	AnalyticsDefaultDays = 90
+
+	// AnalyticsMaxDays bounds the range of the reports, which are computed on request
+	AnalyticsMaxDays = 366
+)
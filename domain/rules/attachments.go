@@ -0,0 +1,27 @@
+package rules
+
+import (
+	"strings"
+	"time"
+)
+
+// Attachment rules
+const (
+	AttachmentMaxSize         = 25 << 20
+	AttachmentMaxFileNameSize = 255
+)
+
+// Signed URL rules
+const (
+	SignedURLDefaultTTL = time.Hour
+	SignedURLMaxTTL     = 7 * 24 * time.Hour
+)
+
+// ValidateFileName checks if the file name can be stored and later sent back in a Content-Disposition header
+func ValidateFileName(name string) bool {
+	if name == "" || name == "." || name == ".." || len(name) > AttachmentMaxFileNameSize {
+		return false
+	}
+
+	return !strings.ContainsAny(name, "\"\r\n/\\")
+}
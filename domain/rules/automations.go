@@ -0,0 +1,69 @@
+package rules
+
+import (
+	"taskflow/domain/entities"
+	"time"
+)
+
+// Automation rules
+const (
+	AutomationMaxRulesPerBoard = 50
+	AutomationMaxActions       = 10
+
+	// AutomationMaxDepth is the number of rules that can chain, each one running on an activity of the previous one.
+	// A rule never runs twice in a chain either.
+	AutomationMaxDepth = 5
+
+	// AutomationExecutionRetention is how long the execution log of the rules is kept
+	AutomationExecutionRetention = 30 * 24 * time.Hour
+)
+
+// ValidateAutomationTrigger checks the type of the trigger, and that it is only narrowed the way its type allows
+func ValidateAutomationTrigger(trigger entities.AutomationTrigger) bool {
+	if trigger.IDTaskList < 0 || trigger.IDLabel < 0 {
+		return false
+	}
+
+	switch trigger.Type {
+	case entities.AutomationTaskMoved, entities.AutomationTaskCreated:
+		return trigger.IDLabel == 0
+	case entities.AutomationLabelAdded:
+		return trigger.IDTaskList == 0
+	case entities.AutomationDueDatePassed, entities.AutomationChecklistCompleted:
+		return trigger.IDTaskList == 0 && trigger.IDLabel == 0
+	default:
+		return false
+	}
+}
+
+// ValidateAutomationActions checks that there is at least one action, and that each action has the fields its type
+// requires
+func ValidateAutomationActions(actions []entities.AutomationAction) bool {
+	if len(actions) == 0 || len(actions) > AutomationMaxActions {
+		return false
+	}
+
+	for _, action := range actions {
+		valid := false
+		switch action.Type {
+		case entities.AutomationSetAssignee:
+			valid = action.IDUser > 0
+		case entities.AutomationAddLabel:
+			valid = action.IDLabel > 0
+		case entities.AutomationMarkFinished:
+			valid = true
+		case entities.AutomationMoveToList:
+			valid = action.IDTaskList > 0
+		case entities.AutomationPostComment:
+			valid = ValidateComment(action.Body)
+		case entities.AutomationCallWebhook:
+			valid = ValidateWebhookURL(action.URL)
+		}
+
+		if !valid {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,40 @@
+package rules
+
+import (
+	"taskflow/domain/entities"
+	"unicode/utf8"
+)
+
+// Board rules
+const (
+	BoardTitleMaxLetters    = 255
+	TaskNameMaxLetters      = 255
+	CommentMaxLetters       = 10_000
+	ChecklistItemMaxLetters = 1024
+	WIPLimitMax             = 1000
+)
+
+// ValidateTitle checks the title of a board, or the name of a task list or task
+func ValidateTitle(title string) bool {
+	letters := utf8.RuneCountInString(title)
+	return letters > 0 && letters <= BoardTitleMaxLetters
+}
+
+func ValidateComment(body string) bool {
+	letters := utf8.RuneCountInString(body)
+	return letters > 0 && letters <= CommentMaxLetters
+}
+
+func ValidateChecklistItem(text string) bool {
+	letters := utf8.RuneCountInString(text)
+	return letters > 0 && letters <= ChecklistItemMaxLetters
+}
+
+func ValidatePriority(priority entities.TaskPriority) bool {
+	return priority >= entities.TaskPriorityNone && priority <= entities.TaskPriorityUrgent
+}
+
+// ValidateWIPLimit checks the WIP limit of a task list, 0 meaning no limit
+func ValidateWIPLimit(limit int) bool {
+	return limit >= 0 && limit <= WIPLimitMax
+}
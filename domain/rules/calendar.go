@@ -0,0 +1,24 @@
+package rules
+
+import (
+	"taskflow/domain/entities"
+	"time"
+)
+
+// Calendar rules
+const (
+	CalendarFeedMaxBoards = 50
+
+	// CalendarFeedMaxTasks limits the tasks listed in a feed, the soonest due being kept
+	CalendarFeedMaxTasks = 2000
+
+	// CalendarFeedPastWindow is how long after their due date tasks are still listed in a feed
+	CalendarFeedPastWindow = 90 * 24 * time.Hour
+
+	// CalendarEventDuration is the duration of the events listing the tasks, which start at the due date
+	CalendarEventDuration = 30 * time.Minute
+)
+
+func ValidateCalendarComponent(component entities.CalendarComponent) bool {
+	return component == entities.CalendarEvent || component == entities.CalendarTodo
+}
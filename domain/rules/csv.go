@@ -0,0 +1,10 @@
+package rules
+
+// CSV rules
+const (
+	CSVImportMaxSize = 10 << 20
+	CSVImportMaxRows = 5_000
+
+	// CSVListSeparator separates the assignees, labels and select options within a cell
+	CSVListSeparator = ";"
+)
@@ -0,0 +1,109 @@
+package rules
+
+import (
+	"math"
+	"slices"
+	"taskflow/domain/entities"
+	"unicode/utf8"
+)
+
+// Custom field rules
+const (
+	CustomFieldMaxPerBoard    = 50
+	CustomFieldMaxOptions     = 100
+	CustomFieldTextMaxLetters = 1024
+)
+
+func ValidateCustomFieldType(fieldType entities.CustomFieldType) bool {
+	switch fieldType {
+	case entities.CustomFieldText,
+		entities.CustomFieldNumber,
+		entities.CustomFieldDate,
+		entities.CustomFieldSingleSelect,
+		entities.CustomFieldMultiSelect,
+		entities.CustomFieldCheckbox,
+		entities.CustomFieldUser:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidateCustomFieldOptions checks that select fields have between 1 and CustomFieldMaxOptions options with distinct
+// names, and that other fields have none
+func ValidateCustomFieldOptions(fieldType entities.CustomFieldType, options []entities.CustomFieldOption) bool {
+	if !fieldType.IsSelect() {
+		return len(options) == 0
+	}
+
+	if len(options) == 0 || len(options) > CustomFieldMaxOptions {
+		return false
+	}
+
+	names := make(map[string]bool, len(options))
+	for _, option := range options {
+		if !ValidateTitle(option.Name) || names[option.Name] {
+			return false
+		}
+		names[option.Name] = true
+	}
+
+	return true
+}
+
+// ValidateCustomFieldValue checks that the value only sets the member matching the type of the field, and that select
+// values are distinct options of the field
+func ValidateCustomFieldValue(field entities.CustomField, value entities.CustomFieldValue) bool {
+	set := 0
+	for _, isSet := range []bool{
+		value.Text != nil,
+		value.Number != nil,
+		value.Date != nil,
+		value.Options != nil,
+		value.Checked != nil,
+		value.IDUser != 0,
+	} {
+		if isSet {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return false
+	}
+
+	switch field.Type {
+	case entities.CustomFieldText:
+		if value.Text == nil {
+			return false
+		}
+
+		letters := utf8.RuneCountInString(*value.Text)
+		return letters > 0 && letters <= CustomFieldTextMaxLetters
+	case entities.CustomFieldNumber:
+		return value.Number != nil && !math.IsNaN(*value.Number) && !math.IsInf(*value.Number, 0)
+	case entities.CustomFieldDate:
+		return value.Date != nil
+	case entities.CustomFieldSingleSelect, entities.CustomFieldMultiSelect:
+		if len(value.Options) == 0 || (field.Type == entities.CustomFieldSingleSelect && len(value.Options) > 1) {
+			return false
+		}
+
+		for i, optionID := range value.Options {
+			known := slices.ContainsFunc(field.Options, func(option entities.CustomFieldOption) bool {
+				return option.ID == optionID
+			})
+			if !known || slices.Contains(value.Options[:i], optionID) {
+				return false
+			}
+		}
+
+		return true
+	case entities.CustomFieldCheckbox:
+		return value.Checked != nil
+	case entities.CustomFieldUser:
+		return value.IDUser > 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,17 @@
+package rules
+
+// Import rules
+const (
+	ImportMaxSize = 50 << 20
+
+	// ArchiveMaxSize is the size of the largest archive imported, attachments included. Its manifest can't be larger
+	// than ArchiveManifestMaxSize.
+	ArchiveMaxSize         = 2 << 30
+	ArchiveManifestMaxSize = 100 << 20
+
+	// ImportDefaultLabelColor is the color of the imported labels without a known color
+	ImportDefaultLabelColor = "#b3bac5"
+
+	// ImportedAuthorMaxLetters limits the name of the author kept on the imported items credited to the importing user
+	ImportedAuthorMaxLetters = 255
+)
@@ -0,0 +1,34 @@
+package rules
+
+import "time"
+
+// Background job schedules, as cron expressions in the server's time zone
+const (
+	DueSoonRemindersSchedule = "*/15 * * * *"
+	OverdueTasksSchedule     = "*/5 * * * *"
+	RecurringTasksSchedule   = "*/5 * * * *"
+
+	// DailyDigestsSchedule runs hourly, as each user gets their digest a day after the previous one
+	DailyDigestsSchedule = "@hourly"
+
+	ExpiredTokensSchedule = "30 3 * * *"
+	JobRunsSchedule       = "45 3 * * *"
+	TrashPurgeSchedule    = "15 4 * * *"
+
+	AutomationExecutionsSchedule = "0 4 * * *"
+)
+
+// JobRunRetention is how long the run history of the background jobs is kept
+const JobRunRetention = 30 * 24 * time.Hour
+
+// TrashDefaultRetention is how long the deleted items are kept in the trash when the retention is not configured
+const TrashDefaultRetention = 30 * 24 * time.Hour
+
+// TrashRetention returns how long the deleted items are kept in the trash, given the configured number of days
+func TrashRetention(days int) time.Duration {
+	if days <= 0 {
+		return TrashDefaultRetention
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -0,0 +1,24 @@
+package rules
+
+import (
+	"regexp"
+	"unicode/utf8"
+)
+
+// Label rules
+const (
+	LabelMaxPerBoard    = 100
+	LabelNameMaxLetters = 50
+)
+
+var labelColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+func ValidateLabelName(name string) bool {
+	letters := utf8.RuneCountInString(name)
+	return letters > 0 && letters <= LabelNameMaxLetters
+}
+
+// ValidateLabelColor checks that the color is an hexadecimal RGB color, such as "#61bd4f"
+func ValidateLabelColor(color string) bool {
+	return labelColorRegex.MatchString(color)
+}
@@ -0,0 +1,65 @@
+package rules
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Notification rules
+const (
+	// EmailMaxAttempts is the number of times an email is attempted before being marked as failed
+	EmailMaxAttempts = 10
+
+	EmailBaseBackoff = time.Minute
+	EmailMaxBackoff  = 6 * time.Hour
+
+	// DueSoonWindow is how long before its due date the assignees of a task are reminded
+	DueSoonWindow = 24 * time.Hour
+
+	// DigestInterval is the time between two digests sent to the same user
+	DigestInterval = 24 * time.Hour
+
+	// DigestMaxActivities is the maximum number of activities listed per board in a digest
+	DigestMaxActivities = 50
+
+	// NotificationMaxLetters is the maximum size of an inbox notification message
+	NotificationMaxLetters = 1024
+)
+
+// mentionPattern matches "@" followed by an email or an email local part, at the start of the text or after a space
+var mentionPattern = regexp.MustCompile(`(?:^|[\s(])@([\p{L}\p{N}._%+\-]+(?:@[\p{L}\p{N}.\-]+)?)`)
+
+// EmailBackoff returns how long to wait before the next attempt, doubling the wait after each failed attempt
+func EmailBackoff(attempts int) time.Duration {
+	return exponentialBackoff(attempts, EmailBaseBackoff, EmailMaxBackoff)
+}
+
+// ParseMentions returns the lowercase handles mentioned in the text, such as "ana" for "@ana" and
+// "ana@example.com" for "@ana@example.com", without duplicates
+func ParseMentions(text string) []string {
+	mentions := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		mention := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if mention == "" || seen[mention] {
+			continue
+		}
+
+		seen[mention] = true
+		mentions = append(mentions, mention)
+	}
+
+	return mentions
+}
+
+// MentionMatches tells whether the mention refers to the user with the given email
+func MentionMatches(mention string, email string) bool {
+	email = strings.ToLower(email)
+	if mention == email {
+		return true
+	}
+
+	localPart, _, _ := strings.Cut(email, "@")
+	return mention == localPart
+}
@@ -0,0 +1,16 @@
+package rules
+
+// Pagination rules
+const (
+	PageDefaultLimit = 50
+	PageMaxLimit     = 200
+)
+
+// PageLimit returns the limit clamped to the pagination rules, using the default limit when not provided
+func PageLimit(limit int) int {
+	if limit <= 0 {
+		return PageDefaultLimit
+	}
+
+	return min(limit, PageMaxLimit)
+}
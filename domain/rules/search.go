@@ -0,0 +1,9 @@
+package rules
+
+// Search rules
+const (
+	// SearchMinTermLetters is the shortest word indexed by MySQL full-text indexes, shorter words are ignored
+	SearchMinTermLetters = 3
+	SearchMaxTerms       = 10
+	SearchSnippetLetters = 160
+)
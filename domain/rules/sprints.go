@@ -0,0 +1,28 @@
+package rules
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// Sprint rules
+const (
+	SprintGoalMaxLetters = 2048
+
+	// SprintMaxDays bounds the length of the sprints, both days included
+	SprintMaxDays = 90
+)
+
+// ValidateSprintGoal checks the goal of a sprint, which is optional
+func ValidateSprintGoal(goal string) bool {
+	return utf8.RuneCountInString(goal) <= SprintGoalMaxLetters
+}
+
+// ValidateSprintDates checks that the sprint ends on or after its first day, and lasts at most SprintMaxDays days
+func ValidateSprintDates(start time.Time, end time.Time) bool {
+	if start.IsZero() || end.Before(start) {
+		return false
+	}
+
+	return end.Sub(start) < SprintMaxDays*24*time.Hour
+}
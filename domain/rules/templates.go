@@ -0,0 +1,14 @@
+package rules
+
+import "unicode/utf8"
+
+// Template rules
+const (
+	TemplateMaxPerUser     = 100
+	TemplateNameMaxLetters = 255
+)
+
+func ValidateTemplateName(name string) bool {
+	letters := utf8.RuneCountInString(name)
+	return letters > 0 && letters <= TemplateNameMaxLetters
+}
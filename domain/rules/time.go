@@ -0,0 +1,35 @@
+package rules
+
+import "unicode/utf8"
+
+// Time tracking rules
+const (
+	// EstimateMaxMinutes bounds the original estimate of the tasks
+	EstimateMaxMinutes = 10_000 * 60
+
+	// TimeEntryMaxMinutes bounds the time entered manually at once
+	TimeEntryMaxMinutes = 24 * 60
+
+	TimeNoteMaxLetters = 1024
+
+	// TimeReportDefaultDays is the number of days, up to today, time reports cover when no range is given
+	TimeReportDefaultDays = 31
+
+	// TimeReportMaxDays bounds the range of the time reports
+	TimeReportMaxDays = 366
+)
+
+// ValidateEstimate checks the original estimate of a task in minutes, 0 meaning not estimated
+func ValidateEstimate(minutes int) bool {
+	return minutes >= 0 && minutes <= EstimateMaxMinutes
+}
+
+// ValidateTimeEntry checks the minutes and note of a time entry made manually
+func ValidateTimeEntry(minutes int, note string) bool {
+	return minutes > 0 && minutes <= TimeEntryMaxMinutes && ValidateTimeNote(note)
+}
+
+// ValidateTimeNote checks the note of a time entry, which is optional
+func ValidateTimeNote(note string) bool {
+	return utf8.RuneCountInString(note) <= TimeNoteMaxLetters
+}
@@ -0,0 +1,94 @@
+package rules
+
+import (
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Webhook rules
+const (
+	WebhookURLMaxLetters = 2048
+	WebhookMaxEvents     = 50
+	WebhookMaxPerBoard   = 20
+
+	// WebhookMaxAttempts is the number of times a delivery is attempted before being marked as failed
+	WebhookMaxAttempts = 8
+
+	// WebhookMaxConsecutiveFailures is the number of failed attempts in a row that disables a webhook
+	WebhookMaxConsecutiveFailures = 15
+
+	WebhookTimeout     = 10 * time.Second
+	WebhookBaseBackoff = 30 * time.Second
+	WebhookMaxBackoff  = 6 * time.Hour
+)
+
+// ValidateWebhookURL checks that the URL is an absolute HTTP(S) URL. Hosts given as IP addresses must be public, the
+// addresses of the other hosts being checked when connecting to them.
+func ValidateWebhookURL(rawURL string) bool {
+	if rawURL == "" || len(rawURL) > WebhookURLMaxLetters {
+		return false
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return false
+	}
+
+	if strings.EqualFold(parsed.Hostname(), "localhost") {
+		return false
+	}
+
+	addr, err := netip.ParseAddr(parsed.Hostname())
+	return err != nil || IsPublicAddress(addr)
+}
+
+// IsPublicAddress tells whether webhooks can be sent to the IP address, which must not be a loopback, private,
+// link-local, multicast or unspecified address. Cloud metadata endpoints are link-local.
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// ValidateWebhookEvents checks the event filters, such as "task.created", "task.*" or "*"
+func ValidateWebhookEvents(events []string) bool {
+	if len(events) > WebhookMaxEvents {
+		return false
+	}
+
+	for _, event := range events {
+		if event == "" || strings.Count(event, ".") > 1 || strings.ContainsAny(event, " \t\r\n") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WebhookBackoff returns how long to wait before the next attempt, doubling the wait after each failed attempt
+func WebhookBackoff(attempts int) time.Duration {
+	return exponentialBackoff(attempts, WebhookBaseBackoff, WebhookMaxBackoff)
+}
+
+// exponentialBackoff doubles the base wait after each failed attempt, up to the maximum
+func exponentialBackoff(attempts int, base time.Duration, maximum time.Duration) time.Duration {
+	backoff := base
+	for i := 1; i < attempts && backoff < maximum; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maximum)
+}
@@ -0,0 +1,39 @@
+package status_codes
+
+type AttachmentStatusCode int
+
+func (a AttachmentStatusCode) String() string {
+	return AttachmentStatusCodeToString(a)
+}
+
+func (a AttachmentStatusCode) Int() int {
+	return int(a)
+}
+
+const (
+	AttachmentSuccess AttachmentStatusCode = iota
+	AttachmentFailure
+	AttachmentTaskNotFound
+	AttachmentEmptyFile
+	AttachmentFileTooLarge
+	AttachmentInvalidFileName
+)
+
+func AttachmentStatusCodeToString(code AttachmentStatusCode) string {
+	switch code {
+	case AttachmentSuccess:
+		return "SUCCESS"
+	case AttachmentFailure:
+		return "FAILURE"
+	case AttachmentTaskNotFound:
+		return "TASK_NOT_FOUND"
+	case AttachmentEmptyFile:
+		return "EMPTY_FILE"
+	case AttachmentFileTooLarge:
+		return "FILE_TOO_LARGE"
+	case AttachmentInvalidFileName:
+		return "INVALID_FILE_NAME"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,54 @@
+package status_codes
+
+type AutomationStatusCode int
+
+func (a AutomationStatusCode) String() string {
+	return AutomationStatusCodeToString(a)
+}
+
+func (a AutomationStatusCode) Int() int {
+	return int(a)
+}
+
+const (
+	AutomationSuccess AutomationStatusCode = iota
+	AutomationFailure
+	AutomationBoardNotFound
+	AutomationRuleNotFound
+	AutomationInvalidName
+	AutomationInvalidTrigger
+	AutomationInvalidActions
+	AutomationTaskListNotFound
+	AutomationLabelNotFound
+	AutomationUserNotFound
+	AutomationTooManyRules
+)
+
+func AutomationStatusCodeToString(code AutomationStatusCode) string {
+	switch code {
+	case AutomationSuccess:
+		return "SUCCESS"
+	case AutomationFailure:
+		return "FAILURE"
+	case AutomationBoardNotFound:
+		return "BOARD_NOT_FOUND"
+	case AutomationRuleNotFound:
+		return "RULE_NOT_FOUND"
+	case AutomationInvalidName:
+		return "INVALID_NAME"
+	case AutomationInvalidTrigger:
+		return "INVALID_TRIGGER"
+	case AutomationInvalidActions:
+		return "INVALID_ACTIONS"
+	case AutomationTaskListNotFound:
+		return "TASK_LIST_NOT_FOUND"
+	case AutomationLabelNotFound:
+		return "LABEL_NOT_FOUND"
+	case AutomationUserNotFound:
+		return "USER_NOT_FOUND"
+	case AutomationTooManyRules:
+		return "TOO_MANY_RULES"
+	default:
+		return "UNKNOWN"
+	}
+}
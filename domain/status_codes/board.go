@@ -0,0 +1,102 @@
+package status_codes
+
+type BoardStatusCode int
+
+func (b BoardStatusCode) String() string {
+	return BoardStatusCodeToString(b)
+}
+
+func (b BoardStatusCode) Int() int {
+	return int(b)
+}
+
+const (
+	BoardSuccess BoardStatusCode = iota
+	BoardFailure
+	BoardNotFound
+	BoardInvalidTitle
+	BoardInvalidName
+	BoardTaskListNotFound
+	BoardTaskNotFound
+	BoardUserNotFound
+	BoardMemberAlreadyExist
+	BoardMemberNotFound
+	BoardCannotRemoveOwner
+	BoardAssigneeAlreadyExist
+	BoardAssigneeNotFound
+	BoardInvalidRecurrence
+	BoardTaskBlocked
+	BoardBlockerNotFound
+	BoardDependencyAlreadyExist
+	BoardDependencyNotFound
+	BoardDependencyCycle
+	BoardInvalidPriority
+	BoardInvalidFilter
+	BoardInvalidOptions
+	BoardTrashItemNotFound
+	BoardParentInTrash
+	BoardInvalidWIPLimit
+	BoardWIPLimitReached
+	BoardInvalidEstimate
+)
+
+func BoardStatusCodeToString(code BoardStatusCode) string {
+	switch code {
+	case BoardSuccess:
+		return "SUCCESS"
+	case BoardFailure:
+		return "FAILURE"
+	case BoardNotFound:
+		return "BOARD_NOT_FOUND"
+	case BoardInvalidTitle:
+		return "INVALID_TITLE"
+	case BoardInvalidName:
+		return "INVALID_NAME"
+	case BoardTaskListNotFound:
+		return "TASK_LIST_NOT_FOUND"
+	case BoardTaskNotFound:
+		return "TASK_NOT_FOUND"
+	case BoardUserNotFound:
+		return "USER_NOT_FOUND"
+	case BoardMemberAlreadyExist:
+		return "MEMBER_ALREADY_EXIST"
+	case BoardMemberNotFound:
+		return "MEMBER_NOT_FOUND"
+	case BoardCannotRemoveOwner:
+		return "CANNOT_REMOVE_OWNER"
+	case BoardAssigneeAlreadyExist:
+		return "ASSIGNEE_ALREADY_EXIST"
+	case BoardAssigneeNotFound:
+		return "ASSIGNEE_NOT_FOUND"
+	case BoardInvalidRecurrence:
+		return "INVALID_RECURRENCE"
+	case BoardTaskBlocked:
+		return "TASK_BLOCKED"
+	case BoardBlockerNotFound:
+		return "BLOCKER_NOT_FOUND"
+	case BoardDependencyAlreadyExist:
+		return "DEPENDENCY_ALREADY_EXIST"
+	case BoardDependencyNotFound:
+		return "DEPENDENCY_NOT_FOUND"
+	case BoardDependencyCycle:
+		return "DEPENDENCY_CYCLE"
+	case BoardInvalidPriority:
+		return "INVALID_PRIORITY"
+	case BoardInvalidFilter:
+		return "INVALID_FILTER"
+	case BoardInvalidOptions:
+		return "INVALID_OPTIONS"
+	case BoardTrashItemNotFound:
+		return "TRASH_ITEM_NOT_FOUND"
+	case BoardParentInTrash:
+		return "PARENT_IN_TRASH"
+	case BoardInvalidWIPLimit:
+		return "INVALID_WIP_LIMIT"
+	case BoardWIPLimitReached:
+		return "WIP_LIMIT_REACHED"
+	case BoardInvalidEstimate:
+		return "INVALID_ESTIMATE"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,42 @@
+package status_codes
+
+type CalendarStatusCode int
+
+func (c CalendarStatusCode) String() string {
+	return CalendarStatusCodeToString(c)
+}
+
+func (c CalendarStatusCode) Int() int {
+	return int(c)
+}
+
+const (
+	CalendarSuccess CalendarStatusCode = iota
+	CalendarFailure
+	CalendarFeedNotFound
+	CalendarBoardNotFound
+	CalendarTooManyBoards
+	CalendarInvalidComponent
+	CalendarEmptyFeed
+)
+
+func CalendarStatusCodeToString(code CalendarStatusCode) string {
+	switch code {
+	case CalendarSuccess:
+		return "SUCCESS"
+	case CalendarFailure:
+		return "FAILURE"
+	case CalendarFeedNotFound:
+		return "FEED_NOT_FOUND"
+	case CalendarBoardNotFound:
+		return "BOARD_NOT_FOUND"
+	case CalendarTooManyBoards:
+		return "TOO_MANY_BOARDS"
+	case CalendarInvalidComponent:
+		return "INVALID_COMPONENT"
+	case CalendarEmptyFeed:
+		return "EMPTY_FEED"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,54 @@
+package status_codes
+
+type ChecklistStatusCode int
+
+func (c ChecklistStatusCode) String() string {
+	return ChecklistStatusCodeToString(c)
+}
+
+func (c ChecklistStatusCode) Int() int {
+	return int(c)
+}
+
+const (
+	ChecklistSuccess ChecklistStatusCode = iota
+	ChecklistFailure
+	ChecklistTaskNotFound
+	ChecklistNotFound
+	ChecklistItemNotFound
+	ChecklistInvalidName
+	ChecklistInvalidText
+	ChecklistAssigneeNotMember
+	ChecklistItemAlreadyPromoted
+	ChecklistTaskListNotFound
+	ChecklistWIPLimitReached
+)
+
+func ChecklistStatusCodeToString(code ChecklistStatusCode) string {
+	switch code {
+	case ChecklistSuccess:
+		return "SUCCESS"
+	case ChecklistFailure:
+		return "FAILURE"
+	case ChecklistTaskNotFound:
+		return "TASK_NOT_FOUND"
+	case ChecklistNotFound:
+		return "CHECKLIST_NOT_FOUND"
+	case ChecklistItemNotFound:
+		return "ITEM_NOT_FOUND"
+	case ChecklistInvalidName:
+		return "INVALID_NAME"
+	case ChecklistInvalidText:
+		return "INVALID_TEXT"
+	case ChecklistAssigneeNotMember:
+		return "ASSIGNEE_NOT_MEMBER"
+	case ChecklistItemAlreadyPromoted:
+		return "ITEM_ALREADY_PROMOTED"
+	case ChecklistTaskListNotFound:
+		return "TASK_LIST_NOT_FOUND"
+	case ChecklistWIPLimitReached:
+		return "WIP_LIMIT_REACHED"
+	default:
+		return "UNKNOWN"
+	}
+}
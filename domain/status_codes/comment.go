@@ -0,0 +1,36 @@
+package status_codes
+
+type CommentStatusCode int
+
+func (c CommentStatusCode) String() string {
+	return CommentStatusCodeToString(c)
+}
+
+func (c CommentStatusCode) Int() int {
+	return int(c)
+}
+
+const (
+	CommentSuccess CommentStatusCode = iota
+	CommentFailure
+	CommentTaskNotFound
+	CommentNotFound
+	CommentInvalidBody
+)
+
+func CommentStatusCodeToString(code CommentStatusCode) string {
+	switch code {
+	case CommentSuccess:
+		return "SUCCESS"
+	case CommentFailure:
+		return "FAILURE"
+	case CommentTaskNotFound:
+		return "TASK_NOT_FOUND"
+	case CommentNotFound:
+		return "COMMENT_NOT_FOUND"
+	case CommentInvalidBody:
+		return "INVALID_BODY"
+	default:
+		return "UNKNOWN"
+	}
+}
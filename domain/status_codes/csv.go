@@ -0,0 +1,42 @@
+package status_codes
+
+type CSVStatusCode int
+
+func (c CSVStatusCode) String() string {
+	return CSVStatusCodeToString(c)
+}
+
+func (c CSVStatusCode) Int() int {
+	return int(c)
+}
+
+const (
+	CSVSuccess CSVStatusCode = iota
+	CSVFailure
+	CSVInvalidFile
+	CSVFileTooLarge
+	CSVTooManyRows
+	CSVInvalidMapping
+	CSVInvalidRows
+)
+
+func CSVStatusCodeToString(code CSVStatusCode) string {
+	switch code {
+	case CSVSuccess:
+		return "SUCCESS"
+	case CSVFailure:
+		return "FAILURE"
+	case CSVInvalidFile:
+		return "INVALID_FILE"
+	case CSVFileTooLarge:
+		return "FILE_TOO_LARGE"
+	case CSVTooManyRows:
+		return "TOO_MANY_ROWS"
+	case CSVInvalidMapping:
+		return "INVALID_MAPPING"
+	case CSVInvalidRows:
+		return "INVALID_ROWS"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,57 @@
+package status_codes
+
+type CustomFieldStatusCode int
+
+func (c CustomFieldStatusCode) String() string {
+	return CustomFieldStatusCodeToString(c)
+}
+
+func (c CustomFieldStatusCode) Int() int {
+	return int(c)
+}
+
+const (
+	CustomFieldSuccess CustomFieldStatusCode = iota
+	CustomFieldFailure
+	CustomFieldBoardNotFound
+	CustomFieldNotFound
+	CustomFieldTaskNotFound
+	CustomFieldInvalidName
+	CustomFieldInvalidType
+	CustomFieldInvalidOptions
+	CustomFieldInvalidValue
+	CustomFieldNameAlreadyExist
+	CustomFieldLimitReached
+	CustomFieldUserNotMember
+)
+
+func CustomFieldStatusCodeToString(code CustomFieldStatusCode) string {
+	switch code {
+	case CustomFieldSuccess:
+		return "SUCCESS"
+	case CustomFieldFailure:
+		return "FAILURE"
+	case CustomFieldBoardNotFound:
+		return "BOARD_NOT_FOUND"
+	case CustomFieldNotFound:
+		return "FIELD_NOT_FOUND"
+	case CustomFieldTaskNotFound:
+		return "TASK_NOT_FOUND"
+	case CustomFieldInvalidName:
+		return "INVALID_NAME"
+	case CustomFieldInvalidType:
+		return "INVALID_TYPE"
+	case CustomFieldInvalidOptions:
+		return "INVALID_OPTIONS"
+	case CustomFieldInvalidValue:
+		return "INVALID_VALUE"
+	case CustomFieldNameAlreadyExist:
+		return "NAME_ALREADY_EXIST"
+	case CustomFieldLimitReached:
+		return "FIELD_LIMIT_REACHED"
+	case CustomFieldUserNotMember:
+		return "USER_NOT_MEMBER"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,36 @@
+package status_codes
+
+type ImportStatusCode int
+
+func (c ImportStatusCode) String() string {
+	return ImportStatusCodeToString(c)
+}
+
+func (c ImportStatusCode) Int() int {
+	return int(c)
+}
+
+const (
+	ImportSuccess ImportStatusCode = iota
+	ImportFailure
+	ImportInvalidFile
+	ImportFileTooLarge
+	ImportUnsupportedVersion
+)
+
+func ImportStatusCodeToString(code ImportStatusCode) string {
+	switch code {
+	case ImportSuccess:
+		return "SUCCESS"
+	case ImportFailure:
+		return "FAILURE"
+	case ImportInvalidFile:
+		return "INVALID_FILE"
+	case ImportFileTooLarge:
+		return "FILE_TOO_LARGE"
+	case ImportUnsupportedVersion:
+		return "UNSUPPORTED_VERSION"
+	default:
+		return "UNKNOWN"
+	}
+}
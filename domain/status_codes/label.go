@@ -0,0 +1,54 @@
+package status_codes
+
+type LabelStatusCode int
+
+func (c LabelStatusCode) String() string {
+	return LabelStatusCodeToString(c)
+}
+
+func (c LabelStatusCode) Int() int {
+	return int(c)
+}
+
+const (
+	LabelSuccess LabelStatusCode = iota
+	LabelFailure
+	LabelBoardNotFound
+	LabelNotFound
+	LabelTaskNotFound
+	LabelInvalidName
+	LabelInvalidColor
+	LabelNameAlreadyExist
+	LabelLimitReached
+	LabelAlreadyAdded
+	LabelNotAdded
+)
+
+func LabelStatusCodeToString(code LabelStatusCode) string {
+	switch code {
+	case LabelSuccess:
+		return "SUCCESS"
+	case LabelFailure:
+		return "FAILURE"
+	case LabelBoardNotFound:
+		return "BOARD_NOT_FOUND"
+	case LabelNotFound:
+		return "LABEL_NOT_FOUND"
+	case LabelTaskNotFound:
+		return "TASK_NOT_FOUND"
+	case LabelInvalidName:
+		return "INVALID_NAME"
+	case LabelInvalidColor:
+		return "INVALID_COLOR"
+	case LabelNameAlreadyExist:
+		return "NAME_ALREADY_EXIST"
+	case LabelLimitReached:
+		return "LABEL_LIMIT_REACHED"
+	case LabelAlreadyAdded:
+		return "LABEL_ALREADY_ADDED"
+	case LabelNotAdded:
+		return "LABEL_NOT_ADDED"
+	default:
+		return "UNKNOWN"
+	}
+}
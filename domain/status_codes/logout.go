@@ -0,0 +1,30 @@
+package status_codes
+
+type LogoutStatusCode int
+
+func (l LogoutStatusCode) String() string {
+	return LogoutStatusCodeToString(l)
+}
+
+func (l LogoutStatusCode) Int() int {
+	return int(l)
+}
+
+const (
+	LogoutSuccess LogoutStatusCode = iota
+	LogoutFailure
+	LogoutInvalidToken
+)
+
+func LogoutStatusCodeToString(code LogoutStatusCode) string {
+	switch code {
+	case LogoutSuccess:
+		return "SUCCESS"
+	case LogoutFailure:
+		return "FAILURE"
+	case LogoutInvalidToken:
+		return "INVALID_TOKEN"
+	default:
+		return "UNKNOWN_ERROR"
+	}
+}
@@ -0,0 +1,42 @@
+package status_codes
+
+type NotificationStatusCode int
+
+func (n NotificationStatusCode) String() string {
+	return NotificationStatusCodeToString(n)
+}
+
+func (n NotificationStatusCode) Int() int {
+	return int(n)
+}
+
+const (
+	NotificationSuccess NotificationStatusCode = iota
+	NotificationFailure
+	NotificationInvalidType
+	NotificationInvalidSignature
+	NotificationNotFound
+	NotificationBoardNotFound
+	NotificationTaskNotFound
+)
+
+func NotificationStatusCodeToString(code NotificationStatusCode) string {
+	switch code {
+	case NotificationSuccess:
+		return "SUCCESS"
+	case NotificationFailure:
+		return "FAILURE"
+	case NotificationInvalidType:
+		return "INVALID_TYPE"
+	case NotificationInvalidSignature:
+		return "INVALID_SIGNATURE"
+	case NotificationNotFound:
+		return "NOTIFICATION_NOT_FOUND"
+	case NotificationBoardNotFound:
+		return "BOARD_NOT_FOUND"
+	case NotificationTaskNotFound:
+		return "TASK_NOT_FOUND"
+	default:
+		return "UNKNOWN"
+	}
+}
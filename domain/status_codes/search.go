@@ -0,0 +1,33 @@
+package status_codes
+
+type SearchStatusCode int
+
+func (c SearchStatusCode) String() string {
+	return SearchStatusCodeToString(c)
+}
+
+func (c SearchStatusCode) Int() int {
+	return int(c)
+}
+
+const (
+	SearchSuccess SearchStatusCode = iota
+	SearchFailure
+	SearchInvalidQuery
+	SearchInvalidType
+)
+
+func SearchStatusCodeToString(code SearchStatusCode) string {
+	switch code {
+	case SearchSuccess:
+		return "SUCCESS"
+	case SearchFailure:
+		return "FAILURE"
+	case SearchInvalidQuery:
+		return "INVALID_QUERY"
+	case SearchInvalidType:
+		return "INVALID_TYPE"
+	default:
+		return "UNKNOWN"
+	}
+}
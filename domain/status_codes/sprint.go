@@ -0,0 +1,69 @@
+package status_codes
+
+type SprintStatusCode int
+
+func (s SprintStatusCode) String() string {
+	return SprintStatusCodeToString(s)
+}
+
+func (s SprintStatusCode) Int() int {
+	return int(s)
+}
+
+const (
+	SprintSuccess SprintStatusCode = iota
+	SprintFailure
+	SprintBoardNotFound
+	SprintNotFound
+	SprintInvalidName
+	SprintInvalidGoal
+	SprintInvalidDates
+	SprintNotPlanned
+	SprintNotActive
+	SprintAlreadyClosed
+	SprintOtherActive
+	SprintInvalidTarget
+	SprintTaskNotFound
+	SprintTaskAlreadyAdded
+	SprintTaskInOtherSprint
+	SprintTaskNotInSprint
+)
+
+func SprintStatusCodeToString(code SprintStatusCode) string {
+	switch code {
+	case SprintSuccess:
+		return "SUCCESS"
+	case SprintFailure:
+		return "FAILURE"
+	case SprintBoardNotFound:
+		return "BOARD_NOT_FOUND"
+	case SprintNotFound:
+		return "SPRINT_NOT_FOUND"
+	case SprintInvalidName:
+		return "INVALID_NAME"
+	case SprintInvalidGoal:
+		return "INVALID_GOAL"
+	case SprintInvalidDates:
+		return "INVALID_DATES"
+	case SprintNotPlanned:
+		return "SPRINT_NOT_PLANNED"
+	case SprintNotActive:
+		return "SPRINT_NOT_ACTIVE"
+	case SprintAlreadyClosed:
+		return "SPRINT_ALREADY_CLOSED"
+	case SprintOtherActive:
+		return "OTHER_SPRINT_ACTIVE"
+	case SprintInvalidTarget:
+		return "INVALID_CARRY_OVER_TARGET"
+	case SprintTaskNotFound:
+		return "TASK_NOT_FOUND"
+	case SprintTaskAlreadyAdded:
+		return "TASK_ALREADY_ADDED"
+	case SprintTaskInOtherSprint:
+		return "TASK_IN_OTHER_SPRINT"
+	case SprintTaskNotInSprint:
+		return "TASK_NOT_IN_SPRINT"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,42 @@
+package status_codes
+
+type TemplateStatusCode int
+
+func (c TemplateStatusCode) String() string {
+	return TemplateStatusCodeToString(c)
+}
+
+func (c TemplateStatusCode) Int() int {
+	return int(c)
+}
+
+const (
+	TemplateSuccess TemplateStatusCode = iota
+	TemplateFailure
+	TemplateBoardNotFound
+	TemplateNotFound
+	TemplateInvalidName
+	TemplateInvalidTitle
+	TemplateLimitReached
+)
+
+func TemplateStatusCodeToString(code TemplateStatusCode) string {
+	switch code {
+	case TemplateSuccess:
+		return "SUCCESS"
+	case TemplateFailure:
+		return "FAILURE"
+	case TemplateBoardNotFound:
+		return "BOARD_NOT_FOUND"
+	case TemplateNotFound:
+		return "TEMPLATE_NOT_FOUND"
+	case TemplateInvalidName:
+		return "INVALID_NAME"
+	case TemplateInvalidTitle:
+		return "INVALID_TITLE"
+	case TemplateLimitReached:
+		return "TEMPLATE_LIMIT_REACHED"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,45 @@
+package status_codes
+
+type TimeStatusCode int
+
+func (t TimeStatusCode) String() string {
+	return TimeStatusCodeToString(t)
+}
+
+func (t TimeStatusCode) Int() int {
+	return int(t)
+}
+
+const (
+	TimeSuccess TimeStatusCode = iota
+	TimeFailure
+	TimeTaskNotFound
+	TimeEntryNotFound
+	TimeTimerRunning
+	TimeNoTimerRunning
+	TimeEntryRunning
+	TimeInvalidEntry
+)
+
+func TimeStatusCodeToString(code TimeStatusCode) string {
+	switch code {
+	case TimeSuccess:
+		return "SUCCESS"
+	case TimeFailure:
+		return "FAILURE"
+	case TimeTaskNotFound:
+		return "TASK_NOT_FOUND"
+	case TimeEntryNotFound:
+		return "ENTRY_NOT_FOUND"
+	case TimeTimerRunning:
+		return "TIMER_ALREADY_RUNNING"
+	case TimeNoTimerRunning:
+		return "NO_TIMER_RUNNING"
+	case TimeEntryRunning:
+		return "ENTRY_RUNNING"
+	case TimeInvalidEntry:
+		return "INVALID_ENTRY"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,45 @@
+package status_codes
+
+type WebhookStatusCode int
+
+func (w WebhookStatusCode) String() string {
+	return WebhookStatusCodeToString(w)
+}
+
+func (w WebhookStatusCode) Int() int {
+	return int(w)
+}
+
+const (
+	WebhookSuccess WebhookStatusCode = iota
+	WebhookFailure
+	WebhookBoardNotFound
+	WebhookNotFound
+	WebhookDeliveryNotFound
+	WebhookInvalidURL
+	WebhookInvalidEvents
+	WebhookLimitReached
+)
+
+func WebhookStatusCodeToString(code WebhookStatusCode) string {
+	switch code {
+	case WebhookSuccess:
+		return "SUCCESS"
+	case WebhookFailure:
+		return "FAILURE"
+	case WebhookBoardNotFound:
+		return "BOARD_NOT_FOUND"
+	case WebhookNotFound:
+		return "WEBHOOK_NOT_FOUND"
+	case WebhookDeliveryNotFound:
+		return "DELIVERY_NOT_FOUND"
+	case WebhookInvalidURL:
+		return "INVALID_URL"
+	case WebhookInvalidEvents:
+		return "INVALID_EVENTS"
+	case WebhookLimitReached:
+		return "WEBHOOK_LIMIT_REACHED"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,278 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/infrastructure/datastore"
+	"taskflow/infrastructure/pubsub"
+	"time"
+)
+
+// ActivityListener reacts to the activities recorded on any board, e.g. to deliver them to external systems
+//
+// Listeners are called synchronously by Record, so they should hand long-running work to their own workers
+type ActivityListener interface {
+	OnActivity(ctx context.Context, activity entities.Activity)
+}
+
+type activityListeners struct {
+	mutex     sync.RWMutex
+	listeners []ActivityListener
+}
+
+type ActivityUseCases struct {
+	repository      datastore.ActivityRepository
+	boardRepository datastore.BoardRepository
+	broker          pubsub.Broker
+	listeners       *activityListeners
+}
+
+func NewActivityUseCases(
+	repository datastore.ActivityRepository,
+	boardRepository datastore.BoardRepository,
+	broker pubsub.Broker,
+) ActivityUseCases {
+	return ActivityUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		broker:          broker,
+		listeners:       &activityListeners{},
+	}
+}
+
+// AddListener registers a listener that is notified of every activity recorded from now on
+func (a ActivityUseCases) AddListener(listener ActivityListener) {
+	a.listeners.mutex.Lock()
+	defer a.listeners.mutex.Unlock()
+
+	a.listeners.listeners = append(a.listeners.listeners, listener)
+}
+
+// Record appends the activity to the board's log, notifies the listeners and publishes it to the board's subscribers
+//
+// Failures are only logged, since the mutation described by the activity already happened
+func (a ActivityUseCases) Record(ctx context.Context, activity entities.Activity) {
+	activity.CreatedAt = time.Now()
+
+	err := a.repository.AddActivity(ctx, &activity)
+	if err != nil {
+		slog.ErrorContext(
+			ctx,
+			"failed to record activity",
+			"board", activity.IDBoard,
+			"entity_type", activity.EntityType,
+			"entity_id", activity.EntityID,
+			"action", activity.Action,
+			"cause", err,
+		)
+		return
+	}
+
+	a.notifyListeners(ctx, activity)
+
+	payload, err := json.Marshal(activity)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to marshal activity", "activity", activity.ID, "cause", err)
+		return
+	}
+
+	err = a.broker.Publish(ctx, boardTopic(activity.IDBoard), payload)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to publish activity", "activity", activity.ID, "cause", err)
+	}
+}
+
+func (a ActivityUseCases) notifyListeners(ctx context.Context, activity entities.Activity) {
+	a.listeners.mutex.RLock()
+	listeners := a.listeners.listeners
+	a.listeners.mutex.RUnlock()
+
+	// The listeners outlive the request that caused the activity
+	ctx = context.WithoutCancel(ctx)
+	for _, listener := range listeners {
+		listener.OnActivity(ctx, activity)
+	}
+}
+
+// Subscribe streams the activities of the board to the user, as they are recorded
+//
+// If lastID is provided, the activities recorded after it are replayed first, so clients can reconnect without
+// missing events. The channel is closed when the context is done, when the subscriber falls behind, or when the user
+// loses access to the board.
+func (a ActivityUseCases) Subscribe(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+	lastID int,
+) (<-chan entities.Activity, error) {
+	err := checkBoardMember(ctx, a.boardRepository, boardID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subscribe before replaying, so nothing recorded in between is lost
+	subscription, err := a.broker.Subscribe(ctx, boardTopic(boardID))
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to subscribe to board"), err)
+	}
+
+	activities := make(chan entities.Activity)
+	go func() {
+		defer close(activities)
+		defer subscription.Close()
+
+		send := func(activity entities.Activity) bool {
+			select {
+			case activities <- activity:
+				return !revokesAccess(activity, user.ID)
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Activities recorded while replaying are received twice
+		replayed := make(map[int]struct{})
+
+		for lastID > 0 {
+			missed, err := a.repository.GetActivities(ctx, entities.ActivityFilter{
+				IDBoard: boardID,
+				AfterID: lastID,
+				Limit:   rules.PageMaxLimit,
+			})
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to replay board activities", "board", boardID, "cause", err)
+				return
+			}
+
+			for _, activity := range missed {
+				if !send(activity) {
+					return
+				}
+
+				replayed[activity.ID] = struct{}{}
+				lastID = activity.ID
+			}
+
+			if len(missed) < rules.PageMaxLimit {
+				break
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case payload, ok := <-subscription.Messages():
+				if !ok {
+					return
+				}
+
+				var activity entities.Activity
+				err := json.Unmarshal(payload, &activity)
+				if err != nil {
+					slog.ErrorContext(ctx, "failed to unmarshal activity", "cause", err)
+					continue
+				}
+
+				if _, ok := replayed[activity.ID]; ok {
+					continue
+				}
+
+				if !send(activity) {
+					return
+				}
+			}
+		}
+	}()
+
+	return activities, nil
+}
+
+// GetBoardActivities returns a page of the board's activity feed, newest first
+func (a ActivityUseCases) GetBoardActivities(
+	ctx context.Context,
+	user *entities.User,
+	filter entities.ActivityFilter,
+) ([]entities.Activity, error) {
+	err := checkBoardMember(ctx, a.boardRepository, filter.IDBoard, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.IDTask = 0
+	filter.Limit = rules.PageLimit(filter.Limit)
+	return a.repository.GetActivities(ctx, filter)
+}
+
+// GetTaskActivities returns a page of the task's activity feed, newest first
+func (a ActivityUseCases) GetTaskActivities(
+	ctx context.Context,
+	user *entities.User,
+	filter entities.ActivityFilter,
+) ([]entities.Activity, error) {
+	task, err := a.boardRepository.GetTaskByID(ctx, filter.IDTask)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, a.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.IDBoard = task.IDBoard
+	filter.Limit = rules.PageLimit(filter.Limit)
+	return a.repository.GetActivities(ctx, filter)
+}
+
+func boardTopic(boardID int) string {
+	return fmt.Sprintf("boards/%d", boardID)
+}
+
+// revokesAccess tells whether the activity removes the user's access to the board
+func revokesAccess(activity entities.Activity, userID int) bool {
+	switch activity.EntityType {
+	case entities.ActivityEntityBoard:
+		return activity.Action == entities.ActivityDeleted
+	case entities.ActivityEntityMember:
+		return activity.Action == entities.ActivityRemoved && activity.EntityID == userID
+	default:
+		return false
+	}
+}
+
+// activityChanges collects the fields changed by a mutation
+type activityChanges map[string]entities.ActivityChange
+
+// set adds the field if its value changed
+func (c activityChanges) set(field string, before any, after any) activityChanges {
+	if before != after {
+		c[field] = entities.ActivityChange{Before: before, After: after}
+	}
+
+	return c
+}
+
+// dueDateChange returns the due date as recorded in activity changes, which compares values instead of pointers
+func dueDateChange(dueDate *time.Time) any {
+	if dueDate == nil {
+		return nil
+	}
+
+	return dueDate.UTC().Format(time.RFC3339)
+}
+
+// recurrenceChange returns the recurrence rule as recorded in activity changes
+func recurrenceChange(recurrence *entities.Recurrence) any {
+	if recurrence == nil {
+		return nil
+	}
+
+	return recurrence.Rule
+}
@@ -0,0 +1,267 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+type AnalyticsUseCases struct {
+	repository      datastore.AnalyticsRepository
+	boardRepository datastore.BoardRepository
+}
+
+func NewAnalyticsUseCases(
+	repository datastore.AnalyticsRepository,
+	boardRepository datastore.BoardRepository,
+) AnalyticsUseCases {
+	return AnalyticsUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+	}
+}
+
+// GetCumulativeFlow returns how many tasks each active task list of the board held at the end of every day of the
+// range, the task lists being in board order. Only the members of the board can see it.
+func (a AnalyticsUseCases) GetCumulativeFlow(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+	dateRange entities.AnalyticsRange,
+) (*entities.CumulativeFlow, error) {
+	dateRange, err := a.checkReport(ctx, user, boardID, dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	taskLists, err := a.boardRepository.GetTaskLists(ctx, boardID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get board task lists"), err)
+	}
+
+	points, err := a.repository.GetCumulativeFlow(ctx, boardID, dateRange.From, dateRange.To)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get cumulative flow"), err)
+	}
+
+	flow := &entities.CumulativeFlow{
+		Days:      make([]string, 0),
+		TaskLists: make([]entities.CumulativeFlowList, 0),
+	}
+
+	dayIndexes := make(map[string]int)
+	for day := dateRange.From; !day.After(dateRange.To); day = day.AddDate(0, 0, 1) {
+		dayIndexes[day.Format(time.DateOnly)] = len(flow.Days)
+		flow.Days = append(flow.Days, day.Format(time.DateOnly))
+	}
+
+	listIndexes := make(map[int]int)
+	for _, taskList := range taskLists {
+		if taskList.StatusCode != entities.StatusActive {
+			continue
+		}
+
+		listIndexes[taskList.ID] = len(flow.TaskLists)
+		flow.TaskLists = append(flow.TaskLists, entities.CumulativeFlowList{
+			IDTaskList: taskList.ID,
+			Name:       taskList.Name,
+			Counts:     make([]int, len(flow.Days)),
+		})
+	}
+
+	// Task lists archived or deleted since are left out
+	for _, point := range points {
+		listIndex, found := listIndexes[point.IDTaskList]
+		if !found {
+			continue
+		}
+
+		dayIndex, found := dayIndexes[point.Day.Format(time.DateOnly)]
+		if !found {
+			continue
+		}
+
+		flow.TaskLists[listIndex].Counts[dayIndex] = point.Tasks
+	}
+
+	return flow, nil
+}
+
+// GetFlowTimes returns the lead and cycle time statistics of the tasks of the board completed over the range. Tasks
+// start when first moved into the given task list, or out of the task list they were created in when zero, the tasks
+// completed without being started being left out of the cycle times. Only the members of the board can see it.
+func (a AnalyticsUseCases) GetFlowTimes(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+	dateRange entities.AnalyticsRange,
+	startListID int,
+) (*entities.FlowTimes, error) {
+	dateRange, err := a.checkReport(ctx, user, boardID, dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	if startListID != 0 {
+		taskList, err := a.boardRepository.GetTaskListByID(ctx, startListID)
+		if err != nil {
+			return nil, err
+		}
+
+		if taskList.IDBoard != boardID {
+			return nil, entities.ErrNotFound
+		}
+	}
+
+	until := dateRange.To.AddDate(0, 0, 1)
+	times, err := a.repository.GetFlowTimes(ctx, boardID, startListID, dateRange.From, until)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get flow times"), err)
+	}
+
+	leadTimes := make([]float64, 0, len(times))
+	cycleTimes := make([]float64, 0, len(times))
+	for _, taskTimes := range times {
+		leadTimes = append(leadTimes, taskTimes.CompletedAt.Sub(taskTimes.CreatedAt).Hours())
+		if taskTimes.StartedAt != nil {
+			cycleTimes = append(cycleTimes, taskTimes.CompletedAt.Sub(*taskTimes.StartedAt).Hours())
+		}
+	}
+
+	return &entities.FlowTimes{
+		From:      dateRange.From.Format(time.DateOnly),
+		To:        dateRange.To.Format(time.DateOnly),
+		LeadTime:  durationStats(leadTimes),
+		CycleTime: durationStats(cycleTimes),
+	}, nil
+}
+
+// GetThroughput returns how many tasks of the board were completed each week of the range, weeks starting on Monday.
+// Only the members of the board can see it.
+func (a AnalyticsUseCases) GetThroughput(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+	dateRange entities.AnalyticsRange,
+) ([]entities.WeeklyThroughput, error) {
+	dateRange, err := a.checkReport(ctx, user, boardID, dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	until := dateRange.To.AddDate(0, 0, 1)
+	completed, err := a.repository.GetWeeklyThroughput(ctx, boardID, dateRange.From, until)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get weekly throughput"), err)
+	}
+
+	byWeek := make(map[string]int)
+	for _, week := range completed {
+		byWeek[week.Week] = week.Completed
+	}
+
+	// Weeks without completions are listed too
+	weekday := (int(dateRange.From.Weekday()) + 6) % 7
+	weeks := make([]entities.WeeklyThroughput, 0)
+	for week := dateRange.From.AddDate(0, 0, -weekday); week.Before(until); week = week.AddDate(0, 0, 7) {
+		weeks = append(weeks, entities.WeeklyThroughput{
+			Week:      week.Format(time.DateOnly),
+			Completed: byWeek[week.Format(time.DateOnly)],
+		})
+	}
+
+	return weeks, nil
+}
+
+// GetOverdue returns how many unfinished tasks of the board are overdue, by assignee, the most overdue first. Only
+// the members of the board can see it.
+func (a AnalyticsUseCases) GetOverdue(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+) ([]entities.OverdueCount, error) {
+	_, err := a.checkReport(ctx, user, boardID, entities.AnalyticsRange{})
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := a.repository.GetOverdueByAssignee(ctx, boardID, time.Now())
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get overdue tasks"), err)
+	}
+
+	return counts, nil
+}
+
+// checkReport checks that the user is a member of the board, and returns the range of the report. The range defaults
+// to the last AnalyticsDefaultDays days, its days being truncated to UTC midnights.
+func (a AnalyticsUseCases) checkReport(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+	dateRange entities.AnalyticsRange,
+) (entities.AnalyticsRange, error) {
+	board, err := a.boardRepository.GetBoardByID(ctx, boardID)
+	if err != nil {
+		return dateRange, err
+	}
+
+	err = checkBoardMember(ctx, a.boardRepository, board.ID, user.ID)
+	if err != nil {
+		return dateRange, err
+	}
+
+	if dateRange.To.IsZero() {
+		dateRange.To = time.Now()
+	}
+
+	dateRange.To = dateRange.To.UTC().Truncate(24 * time.Hour)
+	if dateRange.From.IsZero() {
+		dateRange.From = dateRange.To.AddDate(0, 0, 1-rules.AnalyticsDefaultDays)
+	}
+
+	dateRange.From = dateRange.From.UTC().Truncate(24 * time.Hour)
+	if dateRange.From.After(dateRange.To) || dateRange.To.Sub(dateRange.From) >= rules.AnalyticsMaxDays*24*time.Hour {
+		return dateRange, entities.ErrBadRequest
+	}
+
+	return dateRange, nil
+}
+
+// durationStats returns the count, average and percentiles of the durations in hours, percentiles being interpolated
+// between the closest ranks
+func durationStats(hours []float64) entities.DurationStats {
+	stats := entities.DurationStats{Count: len(hours)}
+	if len(hours) == 0 {
+		return stats
+	}
+
+	slices.Sort(hours)
+
+	var sum float64
+	for _, value := range hours {
+		sum += value
+	}
+
+	percentile := func(p float64) float64 {
+		rank := p * float64(len(hours)-1)
+		lower := int(math.Floor(rank))
+		upper := min(lower+1, len(hours)-1)
+		value := hours[lower] + (hours[upper]-hours[lower])*(rank-float64(lower))
+
+		return math.Round(value*100) / 100
+	}
+
+	stats.Average = math.Round(sum/float64(len(hours))*100) / 100
+	stats.P50 = percentile(0.50)
+	stats.P75 = percentile(0.75)
+	stats.P85 = percentile(0.85)
+	stats.P95 = percentile(0.95)
+
+	return stats
+}
@@ -0,0 +1,535 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/util"
+	"taskflow/infrastructure/datastore"
+	"taskflow/infrastructure/filestore"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// thumbnailWorkers is the number of goroutines generating thumbnails in background
+	thumbnailWorkers = 2
+
+	// thumbnailQueueSize is the number of image attachments waiting for their thumbnails
+	thumbnailQueueSize = 256
+)
+
+type AttachmentUseCases struct {
+	repository      datastore.AttachmentRepository
+	boardRepository datastore.BoardRepository
+	fileStorage     filestore.FileStorage
+	thumbnailQueue  chan entities.Attachment
+	securityKey     string
+	activity        ActivityUseCases
+}
+
+func NewAttachmentUseCases(
+	repository datastore.AttachmentRepository,
+	boardRepository datastore.BoardRepository,
+	fileStorage filestore.FileStorage,
+	securityKey string,
+	activity ActivityUseCases,
+) AttachmentUseCases {
+	a := AttachmentUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		fileStorage:     fileStorage,
+		thumbnailQueue:  make(chan entities.Attachment, thumbnailQueueSize),
+		securityKey:     securityKey,
+		activity:        activity,
+	}
+
+	for range thumbnailWorkers {
+		go a.thumbnailWorker()
+	}
+
+	return a
+}
+
+func (a AttachmentUseCases) UploadAttachment(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	fileName string,
+	data []byte,
+) (*entities.Attachment, status_codes.AttachmentStatusCode, error) {
+	task, err := a.boardRepository.GetTaskByID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.AttachmentTaskNotFound, nil
+		}
+
+		return nil, status_codes.AttachmentFailure, errors.Join(errors.New("failed to get task"), err)
+	}
+
+	err = checkBoardMember(ctx, a.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, status_codes.AttachmentFailure, err
+	}
+
+	if len(data) == 0 {
+		return nil, status_codes.AttachmentEmptyFile, nil
+	}
+
+	if len(data) > rules.AttachmentMaxSize {
+		return nil, status_codes.AttachmentFileTooLarge, nil
+	}
+
+	if !rules.ValidateFileName(fileName) {
+		return nil, status_codes.AttachmentInvalidFileName, nil
+	}
+
+	attachmentUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.AttachmentFailure, errors.Join(errors.New("failed to generate attachment UUID"), err)
+	}
+
+	folder := attachmentFolder(attachmentUUID.String())
+	err = a.fileStorage.CreateAll(folder)
+	if err != nil {
+		return nil, status_codes.AttachmentFailure, errors.Join(errors.New("failed to create attachment folder"), err)
+	}
+
+	attachment := &entities.Attachment{
+		UUID:        attachmentUUID.String(),
+		IDTask:      task.ID,
+		FileName:    fileName,
+		ContentType: http.DetectContentType(data),
+		Size:        int64(len(data)),
+		Path:        folder + "/original",
+		CreatedBy:   *user,
+	}
+
+	err = a.fileStorage.UploadFile(attachment.Path, data)
+	if err != nil {
+		return nil, status_codes.AttachmentFailure, errors.Join(errors.New("failed to store attachment file"), err)
+	}
+
+	err = a.repository.AddAttachment(ctx, attachment)
+	if err != nil {
+		_ = a.fileStorage.DeleteFile(attachment.Path)
+		return nil, status_codes.AttachmentFailure, errors.Join(errors.New("failed to save attachment"), err)
+	}
+
+	if attachment.IsImage() {
+		a.queueThumbnails(ctx, *attachment)
+	}
+
+	a.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityAttachment,
+		EntityID:   attachment.ID,
+		Action:     entities.ActivityCreated,
+		Changes:    activityChanges{}.set("file_name", nil, attachment.FileName),
+	})
+
+	withAttachmentURLs(attachment)
+	return attachment, status_codes.AttachmentSuccess, nil
+}
+
+// GenerateThumbnails generates in background the thumbnails of the image attachments added without being uploaded,
+// such as imported attachments
+func (a AttachmentUseCases) GenerateThumbnails(ctx context.Context, attachments []entities.Attachment) {
+	for _, attachment := range attachments {
+		if attachment.IsImage() {
+			a.queueThumbnails(ctx, attachment)
+		}
+	}
+}
+
+// GetAttachmentFile returns the attachment and its opened file. The caller must close the file.
+func (a AttachmentUseCases) GetAttachmentFile(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Attachment, *os.File, error) {
+	attachment, err := a.getAttachment(ctx, user, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.openAttachmentFile(attachment)
+}
+
+// GetThumbnailFile returns the thumbnail of the given size and its opened file. The caller must close the file.
+//
+// Returns entities.ErrNotFound if the attachment is not an image or the thumbnail was not generated yet.
+func (a AttachmentUseCases) GetThumbnailFile(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	size entities.ThumbnailSize,
+) (*entities.Thumbnail, *os.File, error) {
+	if size.Dimension() == 0 {
+		return nil, nil, entities.ErrBadRequest
+	}
+
+	_, err := a.getAttachment(ctx, user, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.openThumbnailFile(ctx, id, size)
+}
+
+// GetSignedURL returns a URL granting access to the attachment, without a session, for the given duration
+//
+// The duration defaults to rules.SignedURLDefaultTTL and is capped by rules.SignedURLMaxTTL
+func (a AttachmentUseCases) GetSignedURL(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	ttl time.Duration,
+) (*entities.SignedURL, error) {
+	_, err := a.getAttachment(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl <= 0 {
+		ttl = rules.SignedURLDefaultTTL
+	}
+	ttl = min(ttl, rules.SignedURLMaxTTL)
+
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second).UTC()
+	signature := util.SignFileURL(id, expiresAt, a.securityKey)
+
+	signedURL := &entities.SignedURL{
+		URL:       fmt.Sprintf("/api/files/%d?expires=%d&signature=%s", id, expiresAt.Unix(), signature),
+		ExpiresAt: expiresAt,
+	}
+
+	return signedURL, nil
+}
+
+// GetSignedAttachmentFile returns the attachment and its opened file if the signature was issued by GetSignedURL for
+// this attachment and has not expired yet. The caller must close the file.
+func (a AttachmentUseCases) GetSignedAttachmentFile(
+	ctx context.Context,
+	id int,
+	expiresAt time.Time,
+	signature string,
+) (*entities.Attachment, *os.File, error) {
+	err := a.checkSignature(id, expiresAt, signature)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := a.repository.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.openAttachmentFile(attachment)
+}
+
+// GetSignedThumbnailFile is the same as GetSignedAttachmentFile, but for the thumbnail of the given size
+func (a AttachmentUseCases) GetSignedThumbnailFile(
+	ctx context.Context,
+	id int,
+	expiresAt time.Time,
+	signature string,
+	size entities.ThumbnailSize,
+) (*entities.Thumbnail, *os.File, error) {
+	if size.Dimension() == 0 {
+		return nil, nil, entities.ErrBadRequest
+	}
+
+	err := a.checkSignature(id, expiresAt, signature)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.openThumbnailFile(ctx, id, size)
+}
+
+func (a AttachmentUseCases) DeleteAttachment(ctx context.Context, user *entities.User, id int) error {
+	attachment, task, err := a.getAttachmentTask(ctx, user, id)
+	if err != nil {
+		return err
+	}
+
+	err = a.repository.DeleteAttachment(ctx, id)
+	if err != nil {
+		return errors.Join(errors.New("failed to delete attachment"), err)
+	}
+
+	deleteAttachmentFiles(ctx, a.fileStorage, attachment)
+
+	a.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityAttachment,
+		EntityID:   attachment.ID,
+		Action:     entities.ActivityDeleted,
+		Changes:    activityChanges{}.set("file_name", attachment.FileName, nil),
+	})
+
+	return nil
+}
+
+// getAttachment returns the attachment if the user is a member of its board
+func (a AttachmentUseCases) getAttachment(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Attachment, error) {
+	attachment, _, err := a.getAttachmentTask(ctx, user, id)
+	return attachment, err
+}
+
+// getAttachmentTask returns the attachment and its task if the user is a member of the task's board
+func (a AttachmentUseCases) getAttachmentTask(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Attachment, *entities.Task, error) {
+	attachment, err := a.repository.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	task, err := a.boardRepository.GetTaskByID(ctx, attachment.IDTask)
+	if err != nil {
+		return nil, nil, errors.Join(errors.New("failed to get attachment task"), err)
+	}
+
+	err = checkBoardMember(ctx, a.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	withAttachmentURLs(attachment)
+	return attachment, task, nil
+}
+
+// checkSignature returns entities.ErrForbidden if the signature is invalid or expired
+func (a AttachmentUseCases) checkSignature(id int, expiresAt time.Time, signature string) error {
+	if time.Now().After(expiresAt) {
+		return entities.ErrForbidden
+	}
+
+	if !util.CheckFileURLSignature(id, expiresAt, signature, a.securityKey) {
+		return entities.ErrForbidden
+	}
+
+	return nil
+}
+
+func (a AttachmentUseCases) openAttachmentFile(attachment *entities.Attachment) (*entities.Attachment, *os.File, error) {
+	file, err := a.fileStorage.ServeFile(attachment.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, entities.ErrNotFound
+		}
+
+		return nil, nil, errors.Join(errors.New("failed to open attachment file"), err)
+	}
+
+	return attachment, file, nil
+}
+
+func (a AttachmentUseCases) openThumbnailFile(
+	ctx context.Context,
+	id int,
+	size entities.ThumbnailSize,
+) (*entities.Thumbnail, *os.File, error) {
+	thumbnail, err := a.repository.GetThumbnail(ctx, id, size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := a.fileStorage.ServeFile(thumbnail.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, entities.ErrNotFound
+		}
+
+		return nil, nil, errors.Join(errors.New("failed to open thumbnail file"), err)
+	}
+
+	return thumbnail, file, nil
+}
+
+// queueThumbnails queues the image attachment for the thumbnail workers. The attachment is dropped, and left without
+// thumbnails, when the queue is full, so uploads never wait for the workers.
+func (a AttachmentUseCases) queueThumbnails(ctx context.Context, attachment entities.Attachment) {
+	select {
+	case a.thumbnailQueue <- attachment:
+	default:
+		slog.WarnContext(ctx, "thumbnail queue full, attachment dropped", "attachment", attachment.ID)
+	}
+}
+
+func (a AttachmentUseCases) thumbnailWorker() {
+	for attachment := range a.thumbnailQueue {
+		err := a.generateThumbnails(context.Background(), attachment)
+		if err != nil {
+			slog.Error(
+				"failed to generate thumbnails",
+				"attachment", attachment.ID,
+				"cause", err,
+			)
+		}
+	}
+}
+
+// generateThumbnails creates every thumbnail size for the attachment and stores them next to the original file
+func (a AttachmentUseCases) generateThumbnails(ctx context.Context, attachment entities.Attachment) error {
+	file, err := a.fileStorage.ServeFile(attachment.Path)
+	if err != nil {
+		return errors.Join(errors.New("failed to open attachment file"), err)
+	}
+
+	data, err := io.ReadAll(file)
+	_ = file.Close()
+	if err != nil {
+		return errors.Join(errors.New("failed to read attachment file"), err)
+	}
+
+	folder := attachmentFolder(attachment.UUID)
+	for _, size := range entities.ThumbnailSizes {
+		encoded, contentType, width, height, err := util.MakeThumbnail(data, size.Dimension())
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to make %s thumbnail", size), err)
+		}
+
+		extension := "png"
+		if contentType == "image/jpeg" {
+			extension = "jpg"
+		}
+
+		thumbnail := entities.Thumbnail{
+			Size:        size,
+			Width:       width,
+			Height:      height,
+			ContentType: contentType,
+			Path:        fmt.Sprintf("%s/thumbnail_%s.%s", folder, size, extension),
+		}
+
+		err = a.fileStorage.UploadFile(thumbnail.Path, encoded)
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to store %s thumbnail", size), err)
+		}
+
+		err = a.repository.AddThumbnail(ctx, attachment.ID, thumbnail)
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to save %s thumbnail", size), err)
+		}
+	}
+
+	return nil
+}
+
+func attachmentFolder(attachmentUUID string) string {
+	return "attachments/" + attachmentUUID
+}
+
+// deleteAttachmentFiles removes the original file and the thumbnails of an attachment from the file storage
+func deleteAttachmentFiles(ctx context.Context, fileStorage filestore.FileStorage, attachment *entities.Attachment) {
+	paths := []string{attachment.Path}
+	for _, thumbnail := range attachment.Thumbnails {
+		paths = append(paths, thumbnail.Path)
+	}
+
+	for _, path := range paths {
+		err := fileStorage.DeleteFile(path)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to delete attachment file", "path", path, "cause", err)
+		}
+	}
+}
+
+// copyAttachmentFiles copies the original file and the thumbnails of an attachment into the folder of a new UUID,
+// returning the copy of the attachment with the new UUID and paths. Nothing is left behind when the copy fails.
+func copyAttachmentFiles(
+	ctx context.Context,
+	fileStorage filestore.FileStorage,
+	attachment entities.Attachment,
+) (*entities.Attachment, error) {
+	copyUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to generate attachment UUID"), err)
+	}
+
+	folder := attachmentFolder(copyUUID.String())
+	err = fileStorage.CreateAll(folder)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to create attachment folder"), err)
+	}
+
+	attachmentCopy := attachment
+	attachmentCopy.UUID = copyUUID.String()
+	attachmentCopy.Path = folder + "/original"
+	attachmentCopy.Thumbnails = make([]entities.Thumbnail, 0, len(attachment.Thumbnails))
+
+	err = copyFile(fileStorage, attachment.Path, attachmentCopy.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, thumbnail := range attachment.Thumbnails {
+		thumbnailCopy := thumbnail
+		thumbnailCopy.Path = folder + "/" + path.Base(thumbnail.Path)
+
+		err = copyFile(fileStorage, thumbnail.Path, thumbnailCopy.Path)
+		if err != nil {
+			deleteAttachmentFiles(ctx, fileStorage, &attachmentCopy)
+			return nil, err
+		}
+
+		attachmentCopy.Thumbnails = append(attachmentCopy.Thumbnails, thumbnailCopy)
+	}
+
+	return &attachmentCopy, nil
+}
+
+// copyFile copies the file at the source path of the file storage to the target path
+func copyFile(fileStorage filestore.FileStorage, source string, target string) error {
+	file, err := fileStorage.ServeFile(source)
+	if err != nil {
+		return errors.Join(errors.New("failed to open file"), err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return errors.Join(errors.New("failed to read file"), err)
+	}
+
+	err = fileStorage.UploadFile(target, data)
+	if err != nil {
+		return errors.Join(errors.New("failed to write file"), err)
+	}
+
+	return nil
+}
+
+// withAttachmentURLs fills the API URLs of the attachment and its thumbnails
+func withAttachmentURLs(attachment *entities.Attachment) {
+	attachment.URL = fmt.Sprintf("/api/attachments/%d", attachment.ID)
+	for i := range attachment.Thumbnails {
+		attachment.Thumbnails[i].URL = fmt.Sprintf(
+			"/api/attachments/%d/thumbnail?size=%s",
+			attachment.ID,
+			attachment.Thumbnails[i].Size,
+		)
+	}
+}
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/domain/util"
+	"testing"
+	"time"
+)
+
+func TestCheckSignature(t *testing.T) {
+	a := AttachmentUseCases{securityKey: "key"}
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	signature := util.SignFileURL(1, expiresAt, "key")
+	tampered := []byte(signature)
+	tampered[0] ^= 1
+
+	tests := []struct {
+		name      string
+		id        int
+		expiresAt time.Time
+		signature string
+		wantErr   error
+	}{
+		{name: "valid", id: 1, expiresAt: expiresAt, signature: signature},
+		{name: "other attachment", id: 2, expiresAt: expiresAt, signature: signature, wantErr: entities.ErrForbidden},
+		{
+			name:      "extended expiration",
+			id:        1,
+			expiresAt: expiresAt.Add(time.Hour),
+			signature: signature,
+			wantErr:   entities.ErrForbidden,
+		},
+		{
+			name:      "tampered signature",
+			id:        1,
+			expiresAt: expiresAt,
+			signature: string(tampered),
+			wantErr:   entities.ErrForbidden,
+		},
+		{
+			name:      "other key",
+			id:        1,
+			expiresAt: expiresAt,
+			signature: util.SignFileURL(1, expiresAt, "other"),
+			wantErr:   entities.ErrForbidden,
+		},
+		{name: "empty signature", id: 1, expiresAt: expiresAt, wantErr: entities.ErrForbidden},
+		{
+			name:      "expired",
+			id:        1,
+			expiresAt: expiresAt.Add(-2 * time.Hour),
+			signature: util.SignFileURL(1, expiresAt.Add(-2*time.Hour), "key"),
+			wantErr:   entities.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		err := a.checkSignature(tt.id, tt.expiresAt, tt.signature)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: checkSignature() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -3,12 +3,14 @@ package usecases
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"strings"
 	"taskflow/domain/entities"
 	"taskflow/domain/rules"
 	"taskflow/domain/status_codes"
 	"taskflow/domain/util"
 	"taskflow/infrastructure/datastore"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -108,16 +110,56 @@ func (a AuthUseCases) GetUserByEmail(ctx context.Context, email string) (*entiti
 }
 
 func (a AuthUseCases) GetUserByToken(ctx context.Context, token string) (*entities.User, error) {
-	id, expired, err := util.GetUserIDFromToken(token, a.pasetoSecurityKey)
+	authToken, err := util.ParseAuthToken(token, a.pasetoSecurityKey)
 	if err != nil {
 		return nil, errors.Join(errors.New("failed to get user ID from token"), err)
 	}
 
-	if expired {
+	if time.Now().After(authToken.ExpiresAt) {
 		return nil, errors.Join(errors.New("token expired"))
 	}
 
-	return a.repository.GetUserByID(ctx, int(id))
+	revoked, err := a.repository.IsTokenRevoked(ctx, authToken.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to check token revocation"), err)
+	}
+
+	if revoked {
+		return nil, errors.New("token revoked")
+	}
+
+	return a.repository.GetUserByID(ctx, authToken.IDUser)
+}
+
+// Logout revokes the session token, which is rejected from then on
+func (a AuthUseCases) Logout(ctx context.Context, token string) (status_codes.LogoutStatusCode, error) {
+	authToken, err := util.ParseAuthToken(token, a.pasetoSecurityKey)
+	if err != nil {
+		return status_codes.LogoutInvalidToken, nil
+	}
+
+	// Expired tokens are already rejected
+	if time.Now().After(authToken.ExpiresAt) {
+		return status_codes.LogoutSuccess, nil
+	}
+
+	err = a.repository.RevokeToken(ctx, *authToken)
+	if err != nil {
+		return status_codes.LogoutFailure, errors.Join(errors.New("failed to revoke token"), err)
+	}
+
+	return status_codes.LogoutSuccess, nil
+}
+
+// DeleteExpiredTokens forgets the revoked tokens that expired, as they are rejected anyway
+func (a AuthUseCases) DeleteExpiredTokens(ctx context.Context) error {
+	deleted, err := a.repository.DeleteExpiredTokens(ctx, time.Now())
+	if err != nil {
+		return errors.Join(errors.New("failed to delete expired tokens"), err)
+	}
+
+	slog.InfoContext(ctx, "deleted expired tokens", "count", deleted)
+	return nil
 }
 
 func (a AuthUseCases) CheckCredentials(
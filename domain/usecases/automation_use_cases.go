@@ -0,0 +1,682 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/util"
+	"taskflow/infrastructure/datastore"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// automationQueueSize is the number of activities waiting for the rules to run on them
+	automationQueueSize = 256
+
+	// automationSecretSize is the number of random bytes of the rule secrets
+	automationSecretSize = 32
+
+	// automationMaxErrorLetters limits the error stored with a failed action
+	automationMaxErrorLetters = 1024
+)
+
+// automationChainKey is the context key holding the IDs of the rules whose actions led to the activities recorded
+// with the context
+type automationChainKey struct{}
+
+// automationEvent is an activity the rules of its board run on
+type automationEvent struct {
+	activity entities.Activity
+	trigger  entities.AutomationTrigger
+	chain    []int
+}
+
+type AutomationUseCases struct {
+	repository          datastore.AutomationRepository
+	boardRepository     datastore.BoardRepository
+	labelRepository     datastore.LabelRepository
+	checklistRepository datastore.ChecklistRepository
+	boardUseCases       BoardUseCases
+	labelUseCases       LabelUseCases
+	commentUseCases     CommentUseCases
+	activity            ActivityUseCases
+	client              *http.Client
+	events              chan automationEvent
+}
+
+func NewAutomationUseCases(
+	repository datastore.AutomationRepository,
+	boardRepository datastore.BoardRepository,
+	labelRepository datastore.LabelRepository,
+	checklistRepository datastore.ChecklistRepository,
+	boardUseCases BoardUseCases,
+	labelUseCases LabelUseCases,
+	commentUseCases CommentUseCases,
+	activity ActivityUseCases,
+) AutomationUseCases {
+	a := AutomationUseCases{
+		repository:          repository,
+		boardRepository:     boardRepository,
+		labelRepository:     labelRepository,
+		checklistRepository: checklistRepository,
+		boardUseCases:       boardUseCases,
+		labelUseCases:       labelUseCases,
+		commentUseCases:     commentUseCases,
+		activity:            activity,
+		client:              newWebhookClient(),
+		events:              make(chan automationEvent, automationQueueSize),
+	}
+
+	go a.eventWorker()
+
+	return a
+}
+
+// OnActivity queues the activity for the rules of its board when it is a task event a rule can be triggered by. The
+// rules run in background, one activity at a time, the actions of a rule being able to trigger other rules.
+func (a AutomationUseCases) OnActivity(ctx context.Context, activity entities.Activity) {
+	trigger, ok := automationTriggerOf(activity)
+	if !ok {
+		return
+	}
+
+	chain, _ := ctx.Value(automationChainKey{}).([]int)
+	select {
+	case a.events <- automationEvent{activity: activity, trigger: trigger, chain: chain}:
+	default:
+		slog.WarnContext(ctx, "automation queue full, activity dropped", "activity", activity.ID)
+	}
+}
+
+// GetRules returns the automation rules of the board, without their secrets. Only the board owner can see them.
+func (a AutomationUseCases) GetRules(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+) ([]entities.AutomationRule, error) {
+	err := a.checkBoardOwner(ctx, user, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	automationRules, err := a.repository.GetRulesByBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range automationRules {
+		automationRules[i].Secret = ""
+	}
+
+	return automationRules, nil
+}
+
+// CreateRule adds an automation rule to the board. Only the board owner can create it, the actions of the rule being
+// made on their behalf.
+//
+// The generated secret is only returned here, so the receivers of the webhook actions can verify the signatures.
+func (a AutomationUseCases) CreateRule(
+	ctx context.Context,
+	user *entities.User,
+	rule entities.AutomationRule,
+) (*entities.AutomationRule, status_codes.AutomationStatusCode, error) {
+	statusCode, err := a.getOwnedBoard(ctx, user, rule.IDBoard)
+	if statusCode != status_codes.AutomationSuccess || err != nil {
+		return nil, statusCode, err
+	}
+
+	statusCode, err = a.validateRule(ctx, rule)
+	if statusCode != status_codes.AutomationSuccess || err != nil {
+		return nil, statusCode, err
+	}
+
+	automationRules, err := a.repository.GetRulesByBoard(ctx, rule.IDBoard)
+	if err != nil {
+		return nil, status_codes.AutomationFailure, errors.Join(errors.New("failed to get board rules"), err)
+	}
+
+	if len(automationRules) >= rules.AutomationMaxRulesPerBoard {
+		return nil, status_codes.AutomationTooManyRules, nil
+	}
+
+	ruleUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.AutomationFailure, errors.Join(errors.New("failed to generate rule UUID"), err)
+	}
+
+	secret, err := util.GenerateSecret(automationSecretSize)
+	if err != nil {
+		return nil, status_codes.AutomationFailure, errors.Join(errors.New("failed to generate rule secret"), err)
+	}
+
+	rule.UUID = ruleUUID.String()
+	rule.Secret = secret
+	rule.Enabled = true
+	rule.CreatedBy = *user
+
+	err = a.repository.AddRule(ctx, &rule)
+	if err != nil {
+		return nil, status_codes.AutomationFailure, errors.Join(errors.New("failed to save rule"), err)
+	}
+
+	a.activity.Record(ctx, entities.Activity{
+		IDBoard:    rule.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityAutomation,
+		EntityID:   rule.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("name", nil, rule.Name).
+			set("trigger", nil, string(rule.Trigger.Type)).
+			set("actions", nil, automationActionsChange(rule.Actions)).
+			set("enabled", nil, rule.Enabled),
+	})
+
+	return &rule, status_codes.AutomationSuccess, nil
+}
+
+// UpdateRule updates the name, trigger, actions and enabled flag of an automation rule
+func (a AutomationUseCases) UpdateRule(
+	ctx context.Context,
+	user *entities.User,
+	rule entities.AutomationRule,
+) (status_codes.AutomationStatusCode, error) {
+	current, statusCode, err := a.getOwnedRule(ctx, user, rule.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	rule.IDBoard = current.IDBoard
+	statusCode, err = a.validateRule(ctx, rule)
+	if statusCode != status_codes.AutomationSuccess || err != nil {
+		return statusCode, err
+	}
+
+	err = a.repository.UpdateRule(ctx, &rule)
+	if err != nil {
+		return status_codes.AutomationFailure, errors.Join(errors.New("failed to update rule"), err)
+	}
+
+	a.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityAutomation,
+		EntityID:   current.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set("name", current.Name, rule.Name).
+			set("trigger", string(current.Trigger.Type), string(rule.Trigger.Type)).
+			set("actions", automationActionsChange(current.Actions), automationActionsChange(rule.Actions)).
+			set("enabled", current.Enabled, rule.Enabled),
+	})
+
+	return status_codes.AutomationSuccess, nil
+}
+
+// DeleteRule deletes an automation rule along with its executions
+func (a AutomationUseCases) DeleteRule(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.AutomationStatusCode, error) {
+	current, statusCode, err := a.getOwnedRule(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	err = a.repository.DeleteRule(ctx, id)
+	if err != nil {
+		return status_codes.AutomationFailure, errors.Join(errors.New("failed to delete rule"), err)
+	}
+
+	a.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityAutomation,
+		EntityID:   current.ID,
+		Action:     entities.ActivityDeleted,
+		Changes:    activityChanges{}.set("name", current.Name, nil),
+	})
+
+	return status_codes.AutomationSuccess, nil
+}
+
+// GetExecutions returns the execution log of an automation rule, newest first
+func (a AutomationUseCases) GetExecutions(
+	ctx context.Context,
+	user *entities.User,
+	ruleID int,
+	beforeID int,
+	limit int,
+) ([]entities.AutomationExecution, error) {
+	rule, err := a.repository.GetRuleByID(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.checkBoardOwner(ctx, user, rule.IDBoard)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.repository.GetExecutions(ctx, ruleID, beforeID, rules.PageLimit(limit))
+}
+
+// DeleteOldExecutions deletes the executions logged more than AutomationExecutionRetention ago
+func (a AutomationUseCases) DeleteOldExecutions(ctx context.Context) error {
+	deleted, err := a.repository.DeleteExecutions(ctx, time.Now().Add(-rules.AutomationExecutionRetention))
+	if err != nil {
+		return errors.Join(errors.New("failed to delete automation executions"), err)
+	}
+
+	slog.InfoContext(ctx, "deleted old automation executions", "count", deleted)
+	return nil
+}
+
+func (a AutomationUseCases) eventWorker() {
+	for event := range a.events {
+		a.runRules(context.Background(), event)
+	}
+}
+
+// runRules runs the enabled rules of the board matching the event, and logs their executions. A rule already in the
+// chain of rules that led to the event, or beyond AutomationMaxDepth, is skipped.
+func (a AutomationUseCases) runRules(ctx context.Context, event automationEvent) {
+	automationRules, err := a.repository.GetRulesByBoard(ctx, event.activity.IDBoard)
+	if err != nil {
+		slog.Error("failed to get board rules", "board", event.activity.IDBoard, "cause", err)
+		return
+	}
+
+	matching := make([]entities.AutomationRule, 0)
+	for _, rule := range automationRules {
+		if rule.Enabled && rule.Trigger.Matches(event.trigger) {
+			matching = append(matching, rule)
+		}
+	}
+
+	if len(matching) == 0 {
+		return
+	}
+
+	if event.trigger.Type == entities.AutomationChecklistCompleted {
+		completed, err := a.isChecklistCompleted(ctx, event.activity)
+		if err != nil {
+			slog.Error("failed to get checklist", "activity", event.activity.ID, "cause", err)
+			return
+		}
+
+		if !completed {
+			return
+		}
+	}
+
+	for _, rule := range matching {
+		execution := entities.AutomationExecution{
+			IDRule:     rule.ID,
+			IDActivity: event.activity.ID,
+			IDTask:     event.activity.IDTask,
+			Depth:      len(event.chain),
+		}
+
+		switch {
+		case slices.Contains(event.chain, rule.ID):
+			execution.Status = entities.AutomationExecutionSkipped
+			execution.Error = "the rule already ran in this chain"
+		case len(event.chain) >= rules.AutomationMaxDepth:
+			execution.Status = entities.AutomationExecutionSkipped
+			execution.Error = "too many chained rules"
+		default:
+			chain := append(slices.Clone(event.chain), rule.ID)
+			ruleCtx := context.WithValue(ctx, automationChainKey{}, chain)
+			execution.Results, execution.Status = a.runActions(ruleCtx, rule, event.activity)
+		}
+
+		if execution.Results == nil {
+			execution.Results = make([]entities.AutomationActionResult, 0)
+		}
+
+		err = a.repository.AddExecution(ctx, &execution)
+		if err != nil {
+			slog.Error("failed to save automation execution", "rule", rule.ID, "cause", err)
+		}
+	}
+}
+
+// runActions runs the actions of the rule in order, stopping at the first one failing
+func (a AutomationUseCases) runActions(
+	ctx context.Context,
+	rule entities.AutomationRule,
+	activity entities.Activity,
+) ([]entities.AutomationActionResult, entities.AutomationExecutionStatus) {
+	results := make([]entities.AutomationActionResult, 0, len(rule.Actions))
+	for _, action := range rule.Actions {
+		result := entities.AutomationActionResult{Type: action.Type}
+
+		err := a.runAction(ctx, rule, action, activity)
+		if err != nil {
+			result.Error = truncate(err.Error(), automationMaxErrorLetters)
+			return append(results, result), entities.AutomationExecutionFailed
+		}
+
+		results = append(results, result)
+	}
+
+	return results, entities.AutomationExecutionSucceeded
+}
+
+// runAction runs an action on the task of the activity, on behalf of the author of the rule. Actions leaving the task
+// as it was, such as adding a label it already has, succeed.
+func (a AutomationUseCases) runAction(
+	ctx context.Context,
+	rule entities.AutomationRule,
+	action entities.AutomationAction,
+	activity entities.Activity,
+) error {
+	actor := &rule.CreatedBy
+	switch action.Type {
+	case entities.AutomationSetAssignee:
+		statusCode, err := a.boardUseCases.AssignTask(ctx, actor, activity.IDTask, action.IDUser)
+		return actionError(statusCode, err, status_codes.BoardSuccess, status_codes.BoardAssigneeAlreadyExist)
+	case entities.AutomationAddLabel:
+		statusCode, err := a.labelUseCases.AddTaskLabel(ctx, actor, activity.IDTask, action.IDLabel)
+		return actionError(statusCode, err, status_codes.LabelSuccess, status_codes.LabelAlreadyAdded)
+	case entities.AutomationMarkFinished:
+		task, err := a.boardRepository.GetTaskByID(ctx, activity.IDTask)
+		if err != nil {
+			return err
+		}
+
+		if task.Status == entities.TaskFinished {
+			return nil
+		}
+
+		task.Status = entities.TaskFinished
+		statusCode, err := a.boardUseCases.UpdateTask(ctx, actor, *task)
+		return actionError(statusCode, err, status_codes.BoardSuccess)
+	case entities.AutomationMoveToList:
+		task, err := a.boardRepository.GetTaskByID(ctx, activity.IDTask)
+		if err != nil {
+			return err
+		}
+
+		if task.IDTaskList == action.IDTaskList {
+			return nil
+		}
+
+		_, statusCode, err := a.boardUseCases.MoveTask(ctx, actor, activity.IDTask, action.IDTaskList, 0)
+		return actionError(statusCode, err, status_codes.BoardSuccess)
+	case entities.AutomationPostComment:
+		comment := entities.Comment{IDTask: activity.IDTask, Body: action.Body}
+		_, statusCode, err := a.commentUseCases.CreateComment(ctx, actor, comment)
+		return actionError(statusCode, err, status_codes.CommentSuccess)
+	case entities.AutomationCallWebhook:
+		return a.callWebhook(ctx, rule, action.URL, activity)
+	default:
+		return errors.New("unknown action " + string(action.Type))
+	}
+}
+
+// callWebhook posts the activity along with its task to the URL, signed with the rule secret. The client is the
+// webhook one, refusing to connect to internal addresses.
+func (a AutomationUseCases) callWebhook(
+	ctx context.Context,
+	rule entities.AutomationRule,
+	url string,
+	activity entities.Activity,
+) error {
+	task, err := a.boardRepository.GetTaskByID(ctx, activity.IDTask)
+	if err != nil {
+		return err
+	}
+
+	callUUID, err := uuid.NewRandom()
+	if err != nil {
+		return errors.Join(errors.New("failed to generate call UUID"), err)
+	}
+
+	payload, err := json.Marshal(entities.AutomationPayload{
+		ID:        callUUID.String(),
+		IDRule:    rule.ID,
+		Event:     activity.Event(),
+		Activity:  activity,
+		Task:      *task,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return errors.Join(errors.New("failed to marshal automation payload"), err)
+	}
+
+	_, err = postSigned(ctx, a.client, url, rule.Secret, activity.Event(), callUUID.String(), payload)
+	return err
+}
+
+// isChecklistCompleted tells whether every item of the checklist holding the item checked by the activity is checked
+func (a AutomationUseCases) isChecklistCompleted(ctx context.Context, activity entities.Activity) (bool, error) {
+	item, err := a.checklistRepository.GetItemByID(ctx, activity.EntityID)
+	if err != nil {
+		return false, err
+	}
+
+	checklists, err := a.checklistRepository.GetChecklistsByTask(ctx, activity.IDTask)
+	if err != nil {
+		return false, err
+	}
+
+	for _, checklist := range checklists {
+		if checklist.ID != item.IDChecklist {
+			continue
+		}
+
+		for _, checklistItem := range checklist.Items {
+			if !checklistItem.Checked {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// validateRule checks the rule, and that the task lists, labels and users it refers to belong to its board
+func (a AutomationUseCases) validateRule(
+	ctx context.Context,
+	rule entities.AutomationRule,
+) (status_codes.AutomationStatusCode, error) {
+	if !rules.ValidateTitle(rule.Name) {
+		return status_codes.AutomationInvalidName, nil
+	}
+
+	if !rules.ValidateAutomationTrigger(rule.Trigger) {
+		return status_codes.AutomationInvalidTrigger, nil
+	}
+
+	if !rules.ValidateAutomationActions(rule.Actions) {
+		return status_codes.AutomationInvalidActions, nil
+	}
+
+	taskListIDs := make([]int, 0)
+	labelIDs := make([]int, 0)
+	userIDs := make([]int, 0)
+	if rule.Trigger.IDTaskList != 0 {
+		taskListIDs = append(taskListIDs, rule.Trigger.IDTaskList)
+	}
+
+	if rule.Trigger.IDLabel != 0 {
+		labelIDs = append(labelIDs, rule.Trigger.IDLabel)
+	}
+
+	for _, action := range rule.Actions {
+		switch action.Type {
+		case entities.AutomationSetAssignee:
+			userIDs = append(userIDs, action.IDUser)
+		case entities.AutomationAddLabel:
+			labelIDs = append(labelIDs, action.IDLabel)
+		case entities.AutomationMoveToList:
+			taskListIDs = append(taskListIDs, action.IDTaskList)
+		}
+	}
+
+	for _, id := range taskListIDs {
+		taskList, err := a.boardRepository.GetTaskListByID(ctx, id)
+		if errors.Is(err, entities.ErrNotFound) || err == nil && taskList.IDBoard != rule.IDBoard {
+			return status_codes.AutomationTaskListNotFound, nil
+		}
+
+		if err != nil {
+			return status_codes.AutomationFailure, errors.Join(errors.New("failed to get task list"), err)
+		}
+	}
+
+	for _, id := range labelIDs {
+		label, err := a.labelRepository.GetLabelByID(ctx, id)
+		if errors.Is(err, entities.ErrNotFound) || err == nil && label.IDBoard != rule.IDBoard {
+			return status_codes.AutomationLabelNotFound, nil
+		}
+
+		if err != nil {
+			return status_codes.AutomationFailure, errors.Join(errors.New("failed to get label"), err)
+		}
+	}
+
+	for _, id := range userIDs {
+		member, err := a.boardRepository.IsBoardMember(ctx, rule.IDBoard, id)
+		if err != nil {
+			return status_codes.AutomationFailure, errors.Join(errors.New("failed to get board member"), err)
+		}
+
+		if !member {
+			return status_codes.AutomationUserNotFound, nil
+		}
+	}
+
+	return status_codes.AutomationSuccess, nil
+}
+
+// getOwnedBoard checks that the board exists and the user owns it
+func (a AutomationUseCases) getOwnedBoard(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+) (status_codes.AutomationStatusCode, error) {
+	err := a.checkBoardOwner(ctx, user, boardID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return status_codes.AutomationBoardNotFound, nil
+		}
+
+		return status_codes.AutomationFailure, err
+	}
+
+	return status_codes.AutomationSuccess, nil
+}
+
+// getOwnedRule returns the rule if the user owns its board. A nil rule is returned along with the status code or
+// error to send back otherwise.
+func (a AutomationUseCases) getOwnedRule(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.AutomationRule, status_codes.AutomationStatusCode, error) {
+	rule, err := a.repository.GetRuleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.AutomationRuleNotFound, nil
+		}
+
+		return nil, status_codes.AutomationFailure, errors.Join(errors.New("failed to get rule"), err)
+	}
+
+	err = a.checkBoardOwner(ctx, user, rule.IDBoard)
+	if err != nil {
+		return nil, status_codes.AutomationFailure, err
+	}
+
+	return rule, status_codes.AutomationSuccess, nil
+}
+
+// checkBoardOwner returns entities.ErrForbidden if the user is not the owner of the board
+func (a AutomationUseCases) checkBoardOwner(ctx context.Context, user *entities.User, boardID int) error {
+	board, err := a.boardRepository.GetBoardByID(ctx, boardID)
+	if err != nil {
+		return err
+	}
+
+	if board.CreatedBy.ID != user.ID {
+		return entities.ErrForbidden
+	}
+
+	return nil
+}
+
+// automationActionsChange returns the actions of a rule as recorded in activity changes, by type only: the URLs of the
+// webhook actions are only shown to the board owner
+func automationActionsChange(actions []entities.AutomationAction) string {
+	types := make([]string, 0, len(actions))
+	for _, action := range actions {
+		types = append(types, string(action.Type))
+	}
+
+	return strings.Join(types, ",")
+}
+
+// automationTriggerOf returns the trigger fired by the activity, with the task list or label it happened on. Only task
+// activities fire triggers.
+func automationTriggerOf(activity entities.Activity) (entities.AutomationTrigger, bool) {
+	if activity.IDTask == 0 {
+		return entities.AutomationTrigger{}, false
+	}
+
+	switch activity.Event() {
+	case "task.created":
+		taskListID, _ := activity.Changes["id_task_list"].After.(int)
+		return entities.AutomationTrigger{Type: entities.AutomationTaskCreated, IDTaskList: taskListID}, true
+	case "task.moved":
+		// Moves within a task list do not change it
+		change, found := activity.Changes["id_task_list"]
+		if !found {
+			return entities.AutomationTrigger{}, false
+		}
+
+		taskListID, _ := change.After.(int)
+		return entities.AutomationTrigger{Type: entities.AutomationTaskMoved, IDTaskList: taskListID}, true
+	case "task.overdue":
+		return entities.AutomationTrigger{Type: entities.AutomationDueDatePassed}, true
+	case "label.added":
+		return entities.AutomationTrigger{Type: entities.AutomationLabelAdded, IDLabel: activity.EntityID}, true
+	case "checklist_item.updated":
+		checked, _ := activity.Changes["checked"].After.(bool)
+		if !checked {
+			return entities.AutomationTrigger{}, false
+		}
+
+		return entities.AutomationTrigger{Type: entities.AutomationChecklistCompleted}, true
+	default:
+		return entities.AutomationTrigger{}, false
+	}
+}
+
+// actionError returns the error of an action made through another use case, any status code but the succeeded ones
+// being an error
+func actionError(statusCode status_codes.StatusCode, err error, succeeded ...status_codes.StatusCode) error {
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(succeeded, statusCode) {
+		return errors.New(statusCode.String())
+	}
+
+	return nil
+}
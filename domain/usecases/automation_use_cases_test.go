@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/datastore"
+	"testing"
+)
+
+func TestAutomationWebhookRefusesInternalAddresses(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	a := NewAutomationUseCases(
+		nil,
+		nil,
+		nil,
+		nil,
+		BoardUseCases{},
+		LabelUseCases{},
+		CommentUseCases{},
+		ActivityUseCases{},
+	)
+	_, err := postSigned(context.Background(), a.client, receiver.URL, "secret", "task.created", "id", nil)
+	if !errors.Is(err, errWebhookAddress) {
+		t.Errorf("postSigned() to %s error = %v, want %v", receiver.URL, err, errWebhookAddress)
+	}
+}
+
+func TestValidateAutomationWebhookActions(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{url: "https://example.com/automations", valid: true},
+		{url: "http://localhost:8067/api/jobs", valid: false},
+		{url: "http://127.0.0.1/hooks", valid: false},
+		{url: "http://169.254.169.254/latest/meta-data", valid: false},
+		{url: "http://192.168.0.10/hooks", valid: false},
+	}
+
+	for _, tt := range tests {
+		actions := []entities.AutomationAction{{Type: entities.AutomationCallWebhook, URL: tt.url}}
+		if got := rules.ValidateAutomationActions(actions); got != tt.valid {
+			t.Errorf("ValidateAutomationActions(call_webhook %q) = %v, want %v", tt.url, got, tt.valid)
+		}
+	}
+}
+
+// fakeAutomationRepository keeps the rules of a single board and logs their executions in memory
+type fakeAutomationRepository struct {
+	datastore.AutomationRepository
+
+	rules      []entities.AutomationRule
+	executions []entities.AutomationExecution
+}
+
+func (f *fakeAutomationRepository) GetRulesByBoard(_ context.Context, _ int) ([]entities.AutomationRule, error) {
+	return f.rules, nil
+}
+
+func (f *fakeAutomationRepository) AddExecution(_ context.Context, execution *entities.AutomationExecution) error {
+	f.executions = append(f.executions, *execution)
+	return nil
+}
+
+func TestAutomationChainStopsLoops(t *testing.T) {
+	boardRepository, boards, activity := newWIPTestBoard(false)
+
+	// Each rule moves back the tasks the other one moves
+	moveTo := func(id int, from int, to int) entities.AutomationRule {
+		return entities.AutomationRule{
+			ID:      id,
+			IDBoard: 1,
+			Trigger: entities.AutomationTrigger{Type: entities.AutomationTaskMoved, IDTaskList: from},
+			Actions: []entities.AutomationAction{{Type: entities.AutomationMoveToList, IDTaskList: to}},
+			Enabled: true,
+		}
+	}
+
+	repository := &fakeAutomationRepository{rules: []entities.AutomationRule{moveTo(1, 2, 1), moveTo(2, 1, 2)}}
+	a := AutomationUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		boardUseCases:   boards,
+		events:          make(chan automationEvent, automationQueueSize),
+	}
+	activity.AddListener(a)
+
+	_, statusCode, err := boards.MoveTask(context.Background(), &entities.User{ID: 1}, 1, 2, 0)
+	if err != nil || statusCode != status_codes.BoardSuccess {
+		t.Fatalf("MoveTask() status = %v, error = %v", statusCode, err)
+	}
+
+	// The events are run one at a time, as the worker does
+	for len(a.events) > 0 {
+		a.runRules(context.Background(), <-a.events)
+	}
+
+	want := []struct {
+		rule   int
+		status entities.AutomationExecutionStatus
+		depth  int
+	}{
+		{rule: 1, status: entities.AutomationExecutionSucceeded, depth: 0},
+		{rule: 2, status: entities.AutomationExecutionSucceeded, depth: 1},
+		{rule: 1, status: entities.AutomationExecutionSkipped, depth: 2},
+	}
+
+	if len(repository.executions) != len(want) {
+		t.Fatalf("%d executions, want %d: %+v", len(repository.executions), len(want), repository.executions)
+	}
+
+	for i, execution := range repository.executions {
+		if execution.IDRule != want[i].rule || execution.Status != want[i].status || execution.Depth != want[i].depth {
+			t.Errorf("execution %d = rule %d %s at depth %d, want rule %d %s at depth %d", i, execution.IDRule,
+				execution.Status, execution.Depth, want[i].rule, want[i].status, want[i].depth)
+		}
+	}
+
+	task, _ := boardRepository.GetTaskByID(context.Background(), 1)
+	if task.IDTaskList != 2 {
+		t.Errorf("task in task list %d, want 2", task.IDTaskList)
+	}
+}
+
+func TestAutomationMaxDepth(t *testing.T) {
+	rule := entities.AutomationRule{
+		ID:      1,
+		IDBoard: 1,
+		Trigger: entities.AutomationTrigger{Type: entities.AutomationTaskMoved},
+		Enabled: true,
+	}
+	repository := &fakeAutomationRepository{rules: []entities.AutomationRule{rule}}
+	a := AutomationUseCases{repository: repository, events: make(chan automationEvent, 1)}
+
+	chain := make([]int, 0, rules.AutomationMaxDepth)
+	for i := range rules.AutomationMaxDepth {
+		chain = append(chain, i+2)
+	}
+
+	// The chain of the context the activity was recorded with is queued along with it
+	ctx := context.WithValue(context.Background(), automationChainKey{}, chain)
+	a.OnActivity(ctx, entities.Activity{
+		IDBoard:    1,
+		IDTask:     1,
+		EntityType: entities.ActivityEntityTask,
+		Action:     entities.ActivityMoved,
+		Changes:    map[string]entities.ActivityChange{"id_task_list": {Before: 1, After: 2}},
+	})
+
+	event := <-a.events
+	if len(event.chain) != rules.AutomationMaxDepth {
+		t.Fatalf("event chain = %v, want %v", event.chain, chain)
+	}
+
+	a.runRules(context.Background(), event)
+	if len(repository.executions) != 1 || repository.executions[0].Status != entities.AutomationExecutionSkipped {
+		t.Fatalf("executions = %+v, want the rule skipped", repository.executions)
+	}
+
+	if repository.executions[0].Error != "too many chained rules" {
+		t.Errorf("error = %q, want too many chained rules", repository.executions[0].Error)
+	}
+}
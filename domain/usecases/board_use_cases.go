@@ -2,20 +2,1965 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
 	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/util"
 	"taskflow/infrastructure/datastore"
+	"taskflow/infrastructure/filestore"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type BoardUseCases struct {
-	repository datastore.BoardRepository
+	repository           datastore.BoardRepository
+	attachmentRepository datastore.AttachmentRepository
+	authRepository       datastore.AuthRepository
+	checklistRepository  datastore.ChecklistRepository
+	fieldRepository      datastore.CustomFieldRepository
+	labelRepository      datastore.LabelRepository
+	fileStorage          filestore.FileStorage
+	activity             ActivityUseCases
+
+	// trashRetention is how long the deleted items can be restored before being purged
+	trashRetention time.Duration
 }
 
-func NewBoardUseCases(repository datastore.BoardRepository) BoardUseCases {
+func NewBoardUseCases(
+	repository datastore.BoardRepository,
+	attachmentRepository datastore.AttachmentRepository,
+	authRepository datastore.AuthRepository,
+	checklistRepository datastore.ChecklistRepository,
+	fieldRepository datastore.CustomFieldRepository,
+	labelRepository datastore.LabelRepository,
+	fileStorage filestore.FileStorage,
+	activity ActivityUseCases,
+	trashRetention time.Duration,
+) BoardUseCases {
 	return BoardUseCases{
-		repository: repository,
+		repository:           repository,
+		attachmentRepository: attachmentRepository,
+		authRepository:       authRepository,
+		checklistRepository:  checklistRepository,
+		fieldRepository:      fieldRepository,
+		labelRepository:      labelRepository,
+		fileStorage:          fileStorage,
+		activity:             activity,
+		trashRetention:       trashRetention,
+	}
+}
+
+// GetBoards returns a page of the boards the user owns or is a member of, matching the filter
+func (b BoardUseCases) GetBoards(
+	ctx context.Context,
+	user *entities.User,
+	filter entities.BoardFilter,
+) ([]entities.Board, status_codes.BoardStatusCode, error) {
+	switch filter.Sort {
+	case "":
+		filter.Sort = entities.BoardSortCreated
+	case entities.BoardSortCreated, entities.BoardSortModified, entities.BoardSortTitle:
+	default:
+		return nil, status_codes.BoardInvalidFilter, nil
+	}
+
+	filter.IDUser = user.ID
+	filter.Text = strings.TrimSpace(filter.Text)
+	filter.Limit = rules.PageLimit(filter.Limit)
+
+	boards, err := b.repository.GetBoards(ctx, filter)
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to get boards"), err)
+	}
+
+	return boards, status_codes.BoardSuccess, nil
+}
+
+// GetBoard returns the board with its members, labels, custom fields, task lists and tasks, if the user is a member
+// of the board. Only the active task lists and tasks are returned, unless archived is set: the archived task lists
+// are then returned with all their tasks, along with the other task lists holding archived tasks.
+func (b BoardUseCases) GetBoard(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	archived bool,
+) (*entities.Board, error) {
+	board, err := b.repository.GetBoardByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, b.repository, board.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	board.Users, err = b.repository.GetBoardMembers(ctx, board.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get board members"), err)
+	}
+
+	board.Labels, err = b.labelRepository.GetLabelsByBoard(ctx, board.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get board labels"), err)
+	}
+
+	board.CustomFields, err = b.fieldRepository.GetFieldsByBoard(ctx, board.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get board custom fields"), err)
+	}
+
+	taskLists, err := b.repository.GetTaskLists(ctx, board.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get board task lists"), err)
+	}
+
+	tasks, err := b.repository.GetTasksByBoard(ctx, board.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get board tasks"), err)
+	}
+
+	archivedLists := make(map[int]bool)
+	for _, taskList := range taskLists {
+		archivedLists[taskList.ID] = taskList.StatusCode == entities.StatusArchived
+	}
+
+	tasks = slices.DeleteFunc(tasks, func(task entities.Task) bool {
+		return archived != (archivedLists[task.IDTaskList] || task.StatusCode == entities.StatusArchived)
+	})
+
+	err = b.withTaskDetails(ctx, board.ID, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	tasksByList := make(map[int][]entities.Task)
+	for _, task := range tasks {
+		tasksByList[task.IDTaskList] = append(tasksByList[task.IDTaskList], task)
+	}
+
+	board.TaskLists = make([]entities.TaskList, 0, len(taskLists))
+	for _, taskList := range taskLists {
+		if archived != archivedLists[taskList.ID] && (!archived || tasksByList[taskList.ID] == nil) {
+			continue
+		}
+
+		taskList.Tasks = tasksByList[taskList.ID]
+		if taskList.Tasks == nil {
+			taskList.Tasks = make([]entities.Task, 0)
+		}
+
+		board.TaskLists = append(board.TaskLists, taskList)
+	}
+
+	return board, nil
+}
+
+// SearchTasks returns a page of the tasks of the board matching the filter, with the same details as on the board
+func (b BoardUseCases) SearchTasks(
+	ctx context.Context,
+	user *entities.User,
+	filter entities.TaskFilter,
+) ([]entities.Task, status_codes.BoardStatusCode, error) {
+	board, statusCode, err := b.getBoard(ctx, user, filter.IDBoard)
+	if board == nil {
+		return nil, statusCode, err
+	}
+
+	sortFieldID := 0
+	switch filter.Sort {
+	case "":
+		filter.Sort = entities.TaskSortPosition
+	case entities.TaskSortPosition, entities.TaskSortDueDate, entities.TaskSortPriority, entities.TaskSortModified:
+	default:
+		value, found := strings.CutPrefix(string(filter.Sort), entities.TaskSortFieldPrefix)
+		if !found {
+			return nil, status_codes.BoardInvalidFilter, nil
+		}
+
+		sortFieldID, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, status_codes.BoardInvalidFilter, nil
+		}
+
+		filter.Sort = entities.TaskSortField
+	}
+
+	if len(filter.Fields) > 0 || filter.Sort == entities.TaskSortField {
+		fields, err := b.fieldRepository.GetFieldsByBoard(ctx, board.ID)
+		if err != nil {
+			return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to get board custom fields"), err)
+		}
+
+		if filter.Sort == entities.TaskSortField {
+			index := slices.IndexFunc(fields, func(field entities.CustomField) bool {
+				return field.ID == sortFieldID
+			})
+			if index < 0 {
+				return nil, status_codes.BoardInvalidFilter, nil
+			}
+
+			filter.SortField = &fields[index]
+		}
+
+		for i := range filter.Fields {
+			index := slices.IndexFunc(fields, func(field entities.CustomField) bool {
+				return field.ID == filter.Fields[i].IDField
+			})
+			if index < 0 {
+				return nil, status_codes.BoardInvalidFilter, nil
+			}
+
+			value, ok := parseFieldFilter(fields[index], filter.Fields[i].Raw)
+			if !ok {
+				return nil, status_codes.BoardInvalidFilter, nil
+			}
+
+			filter.Fields[i].Type = fields[index].Type
+			filter.Fields[i].Value = value
+		}
+	}
+
+	filter.Text = strings.TrimSpace(filter.Text)
+	filter.Limit = rules.PageLimit(filter.Limit)
+
+	tasks, err := b.repository.GetTasks(ctx, filter)
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to search tasks"), err)
+	}
+
+	err = b.withTaskDetails(ctx, board.ID, tasks)
+	if err != nil {
+		return nil, status_codes.BoardFailure, err
+	}
+
+	return tasks, status_codes.BoardSuccess, nil
+}
+
+func (b BoardUseCases) CreateBoard(
+	ctx context.Context,
+	user *entities.User,
+	board entities.Board,
+) (*entities.Board, status_codes.BoardStatusCode, error) {
+	board.Title = strings.TrimSpace(board.Title)
+	board.Description = strings.TrimSpace(board.Description)
+
+	if !rules.ValidateTitle(board.Title) {
+		return nil, status_codes.BoardInvalidTitle, nil
+	}
+
+	boardUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to generate board UUID"), err)
+	}
+
+	board.UUID = boardUUID.String()
+	board.CreatedBy = *user
+
+	err = b.repository.AddBoard(ctx, &board)
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to save board"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    board.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityBoard,
+		EntityID:   board.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("title", nil, board.Title).
+			set("description", nil, board.Description),
+	})
+
+	return &board, status_codes.BoardSuccess, nil
+}
+
+// DuplicateBoard copies the task lists, labels and custom fields of the board into a new board owned by the user,
+// along with the tasks, members and attachments when the options include them. The copy keeps the title of the
+// board when no title is given.
+func (b BoardUseCases) DuplicateBoard(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	title string,
+	options entities.DuplicateOptions,
+) (*entities.Board, status_codes.BoardStatusCode, error) {
+	source, statusCode, err := b.getBoard(ctx, user, id)
+	if source == nil {
+		return nil, statusCode, err
+	}
+
+	if options.Attachments && !options.Tasks {
+		return nil, status_codes.BoardInvalidOptions, nil
+	}
+
+	board := entities.Board{
+		Title:       strings.TrimSpace(title),
+		Description: source.Description,
+		WIPWarnOnly: source.WIPWarnOnly,
+		CreatedBy:   *user,
+	}
+
+	if board.Title == "" {
+		board.Title = source.Title
+	}
+
+	if !rules.ValidateTitle(board.Title) {
+		return nil, status_codes.BoardInvalidTitle, nil
+	}
+
+	boardUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to generate board UUID"), err)
+	}
+
+	board.UUID = boardUUID.String()
+
+	// The files are copied beforehand, as they can't be part of the database transaction
+	attachments := make(map[int]entities.Attachment)
+	if options.Attachments {
+		attachments, err = b.copyBoardAttachments(ctx, source.ID)
+		if err != nil {
+			return nil, status_codes.BoardFailure, err
+		}
+	}
+
+	err = b.repository.DuplicateBoard(ctx, source.ID, &board, options, attachments)
+	if err != nil {
+		for _, attachment := range attachments {
+			deleteAttachmentFiles(ctx, b.fileStorage, &attachment)
+		}
+
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to duplicate board"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    board.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityBoard,
+		EntityID:   board.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("title", nil, board.Title).
+			set("description", nil, board.Description).
+			set("duplicated_from", nil, source.ID),
+	})
+
+	return &board, status_codes.BoardSuccess, nil
+}
+
+// UpdateBoard updates the title and description of the board, and whether it only warns about the WIP limits of its
+// task lists
+func (b BoardUseCases) UpdateBoard(
+	ctx context.Context,
+	user *entities.User,
+	board entities.Board,
+) (status_codes.BoardStatusCode, error) {
+	current, statusCode, err := b.getBoard(ctx, user, board.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	board.Title = strings.TrimSpace(board.Title)
+	board.Description = strings.TrimSpace(board.Description)
+
+	if !rules.ValidateTitle(board.Title) {
+		return status_codes.BoardInvalidTitle, nil
+	}
+
+	err = b.repository.UpdateBoard(ctx, &board)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to update board"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    board.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityBoard,
+		EntityID:   board.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set("title", current.Title, board.Title).
+			set("description", current.Description, board.Description).
+			set("wip_warn_only", current.WIPWarnOnly, board.WIPWarnOnly),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// DeleteBoard moves the board with all its task lists and tasks to the trash. Only the board owner can delete it.
+func (b BoardUseCases) DeleteBoard(ctx context.Context, user *entities.User, id int) (status_codes.BoardStatusCode, error) {
+	board, statusCode, err := b.getBoard(ctx, user, id)
+	if board == nil {
+		return statusCode, err
+	}
+
+	if board.CreatedBy.ID != user.ID {
+		return status_codes.BoardFailure, entities.ErrForbidden
+	}
+
+	err = b.repository.DeleteBoard(ctx, id)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to delete board"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    board.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityBoard,
+		EntityID:   board.ID,
+		Action:     entities.ActivityDeleted,
+		Changes: activityChanges{}.
+			set("title", board.Title, nil).
+			set("description", board.Description, nil),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// SetBoardArchived archives or unarchives the board. Only the board owner can archive it.
+func (b BoardUseCases) SetBoardArchived(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	archived bool,
+) (status_codes.BoardStatusCode, error) {
+	board, statusCode, err := b.getBoard(ctx, user, id)
+	if board == nil {
+		return statusCode, err
+	}
+
+	if board.CreatedBy.ID != user.ID {
+		return status_codes.BoardFailure, entities.ErrForbidden
+	}
+
+	err = b.repository.SetBoardStatusCode(ctx, board.ID, archivedStatusCode(archived))
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to archive board"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    board.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityBoard,
+		EntityID:   board.ID,
+		Action:     archivedAction(archived),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// AddBoardMember gives the user with the given email access to the board. Only the board owner can add members.
+func (b BoardUseCases) AddBoardMember(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+	email string,
+) (status_codes.BoardStatusCode, error) {
+	board, statusCode, err := b.getBoard(ctx, user, boardID)
+	if board == nil {
+		return statusCode, err
+	}
+
+	if board.CreatedBy.ID != user.ID {
+		return status_codes.BoardFailure, entities.ErrForbidden
+	}
+
+	member, err := b.authRepository.GetUserByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return status_codes.BoardUserNotFound, nil
+		}
+
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to get user by email"), err)
+	}
+
+	isMember, err := b.repository.IsBoardMember(ctx, boardID, member.ID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to check board membership"), err)
+	}
+
+	if isMember {
+		return status_codes.BoardMemberAlreadyExist, nil
 	}
+
+	err = b.repository.AddBoardMember(ctx, boardID, member.ID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to add board member"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    boardID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityMember,
+		EntityID:   member.ID,
+		Action:     entities.ActivityAdded,
+		Changes:    activityChanges{}.set("email", nil, member.Email),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// RemoveBoardMember removes the member from the board. The board owner can remove anyone and members can only leave.
+func (b BoardUseCases) RemoveBoardMember(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+	memberID int,
+) (status_codes.BoardStatusCode, error) {
+	board, statusCode, err := b.getBoard(ctx, user, boardID)
+	if board == nil {
+		return statusCode, err
+	}
+
+	if board.CreatedBy.ID != user.ID && memberID != user.ID {
+		return status_codes.BoardFailure, entities.ErrForbidden
+	}
+
+	if board.CreatedBy.ID == memberID {
+		return status_codes.BoardCannotRemoveOwner, nil
+	}
+
+	member, err := b.authRepository.GetUserByID(ctx, memberID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return status_codes.BoardMemberNotFound, nil
+		}
+
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to get member"), err)
+	}
+
+	isMember, err := b.repository.IsBoardMember(ctx, boardID, memberID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to check board membership"), err)
+	}
+
+	if !isMember {
+		return status_codes.BoardMemberNotFound, nil
+	}
+
+	err = b.repository.RemoveBoardMember(ctx, boardID, memberID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to remove board member"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    boardID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityMember,
+		EntityID:   memberID,
+		Action:     entities.ActivityRemoved,
+		Changes:    activityChanges{}.set("email", member.Email, nil),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// CreateTaskList adds the task list at the end of its board
+func (b BoardUseCases) CreateTaskList(
+	ctx context.Context,
+	user *entities.User,
+	taskList entities.TaskList,
+) (*entities.TaskList, status_codes.BoardStatusCode, error) {
+	board, statusCode, err := b.getBoard(ctx, user, taskList.IDBoard)
+	if board == nil {
+		return nil, statusCode, err
+	}
+
+	taskList.Name = strings.TrimSpace(taskList.Name)
+	taskList.Description = strings.TrimSpace(taskList.Description)
+
+	if !rules.ValidateTitle(taskList.Name) {
+		return nil, status_codes.BoardInvalidName, nil
+	}
+
+	if !rules.ValidateWIPLimit(taskList.WIPLimit) {
+		return nil, status_codes.BoardInvalidWIPLimit, nil
+	}
+
+	taskListUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to generate task list UUID"), err)
+	}
+
+	taskList.UUID = taskListUUID.String()
+	taskList.CreatedBy = *user
+	taskList.Tasks = make([]entities.Task, 0)
+
+	err = b.repository.AddTaskList(ctx, &taskList)
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to save task list"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    taskList.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTaskList,
+		EntityID:   taskList.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("name", nil, taskList.Name).
+			set("description", nil, taskList.Description).
+			set("wip_limit", nil, taskList.WIPLimit),
+	})
+
+	return &taskList, status_codes.BoardSuccess, nil
+}
+
+// UpdateTaskList updates the name, description and WIP limit of the task list
+func (b BoardUseCases) UpdateTaskList(
+	ctx context.Context,
+	user *entities.User,
+	taskList entities.TaskList,
+) (status_codes.BoardStatusCode, error) {
+	current, statusCode, err := b.getTaskList(ctx, user, taskList.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	taskList.Name = strings.TrimSpace(taskList.Name)
+	taskList.Description = strings.TrimSpace(taskList.Description)
+
+	if !rules.ValidateTitle(taskList.Name) {
+		return status_codes.BoardInvalidName, nil
+	}
+
+	if !rules.ValidateWIPLimit(taskList.WIPLimit) {
+		return status_codes.BoardInvalidWIPLimit, nil
+	}
+
+	err = b.repository.UpdateTaskList(ctx, &taskList)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to update task list"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTaskList,
+		EntityID:   current.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set("name", current.Name, taskList.Name).
+			set("description", current.Description, taskList.Description).
+			set("wip_limit", current.WIPLimit, taskList.WIPLimit),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// MoveTaskList places the task list at the given position of its board
+func (b BoardUseCases) MoveTaskList(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	position int,
+) (status_codes.BoardStatusCode, error) {
+	current, statusCode, err := b.getTaskList(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	position = max(position, 0)
+
+	err = b.repository.MoveTaskList(ctx, id, position)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to move task list"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTaskList,
+		EntityID:   current.ID,
+		Action:     entities.ActivityMoved,
+		Changes:    activityChanges{}.set("position", current.Position, position),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// DeleteTaskList moves the task list with all its tasks to the trash
+func (b BoardUseCases) DeleteTaskList(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.BoardStatusCode, error) {
+	current, statusCode, err := b.getTaskList(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	err = b.repository.DeleteTaskList(ctx, id)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to delete task list"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTaskList,
+		EntityID:   current.ID,
+		Action:     entities.ActivityDeleted,
+		Changes: activityChanges{}.
+			set("name", current.Name, nil).
+			set("description", current.Description, nil),
+	})
+
+	return status_codes.BoardSuccess, nil
 }
 
-func (b BoardUseCases) GetBoards(ctx context.Context) ([]entities.Board, error) {
-	return b.GetBoards(ctx)
+// SetTaskListArchived archives or unarchives the task list, its tasks being archived along with it
+func (b BoardUseCases) SetTaskListArchived(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	archived bool,
+) (status_codes.BoardStatusCode, error) {
+	taskList, statusCode, err := b.getTaskList(ctx, user, id)
+	if taskList == nil {
+		return statusCode, err
+	}
+
+	err = b.repository.SetTaskListStatusCode(ctx, taskList.ID, archivedStatusCode(archived))
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to archive task list"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    taskList.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTaskList,
+		EntityID:   taskList.ID,
+		Action:     archivedAction(archived),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// GetTask returns the task with its assignees, labels, attachments, checklists, custom field values and dependencies,
+// if the user is a member of the task's board
+func (b BoardUseCases) GetTask(ctx context.Context, user *entities.User, id int) (*entities.Task, error) {
+	task, err := b.repository.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, b.repository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Assignees, err = b.repository.GetTaskAssignees(ctx, task.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get task assignees"), err)
+	}
+
+	task.Labels, err = b.labelRepository.GetLabelsByTask(ctx, task.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get task labels"), err)
+	}
+
+	task.Attachments, err = b.attachmentRepository.GetAttachmentsByTask(ctx, task.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get task attachments"), err)
+	}
+
+	for i := range task.Attachments {
+		withAttachmentURLs(&task.Attachments[i])
+	}
+
+	task.Checklists, err = b.checklistRepository.GetChecklistsByTask(ctx, task.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get task checklists"), err)
+	}
+
+	task.Progress = entities.ChecklistProgress(task.Checklists)
+
+	task.Fields, err = b.fieldRepository.GetValuesByTask(ctx, task.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get task custom field values"), err)
+	}
+
+	dependencies, err := b.repository.GetTaskDependencies(ctx, task.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get task dependencies"), err)
+	}
+
+	for _, dependency := range dependencies {
+		if dependency.IDTask == task.ID {
+			task.BlockedBy = append(task.BlockedBy, dependency.IDBlocker)
+		} else {
+			task.Blocks = append(task.Blocks, dependency.IDTask)
+		}
+	}
+
+	return task, nil
+}
+
+// CreateTask adds the task at the end of its task list. A task list having reached its WIP limit rejects the task,
+// unless the board only warns about it, the violation being set on the created task.
+func (b BoardUseCases) CreateTask(
+	ctx context.Context,
+	user *entities.User,
+	task entities.Task,
+) (*entities.Task, status_codes.BoardStatusCode, error) {
+	taskList, statusCode, err := b.getTaskList(ctx, user, task.IDTaskList)
+	if taskList == nil {
+		return nil, statusCode, err
+	}
+
+	task.Name = strings.TrimSpace(task.Name)
+	task.Description = strings.TrimSpace(task.Description)
+
+	if !rules.ValidateTitle(task.Name) {
+		return nil, status_codes.BoardInvalidName, nil
+	}
+
+	if !rules.ValidatePriority(task.Priority) {
+		return nil, status_codes.BoardInvalidPriority, nil
+	}
+
+	if !rules.ValidateEstimate(task.OriginalEstimate) {
+		return nil, status_codes.BoardInvalidEstimate, nil
+	}
+
+	violation, statusCode, err := b.checkWIPLimit(ctx, taskList)
+	if statusCode != status_codes.BoardSuccess {
+		return nil, statusCode, err
+	}
+
+	taskUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to generate task UUID"), err)
+	}
+
+	task.UUID = taskUUID.String()
+	task.IDBoard = taskList.IDBoard
+	task.CreatedBy = *user
+	task.Assignees = make([]entities.User, 0)
+	task.Labels = make([]entities.Label, 0)
+	task.Attachments = make([]entities.Attachment, 0)
+	task.Fields = make([]entities.CustomFieldValue, 0)
+	task.WIPViolation = violation
+
+	err = b.repository.AddTask(ctx, &task)
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to save task"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTask,
+		EntityID:   task.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("name", nil, task.Name).
+			set("description", nil, task.Description).
+			set("id_task_list", nil, task.IDTaskList).
+			set("priority", nil, task.Priority).
+			set("due_date", nil, dueDateChange(task.DueDate)).
+			set("original_estimate", nil, task.OriginalEstimate),
+	})
+
+	return &task, status_codes.BoardSuccess, nil
+}
+
+// UpdateTask updates the name, description, completion status, priority and due date of the task
+func (b BoardUseCases) UpdateTask(
+	ctx context.Context,
+	user *entities.User,
+	task entities.Task,
+) (status_codes.BoardStatusCode, error) {
+	current, statusCode, err := b.getTask(ctx, user, task.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	task.Name = strings.TrimSpace(task.Name)
+	task.Description = strings.TrimSpace(task.Description)
+
+	if !rules.ValidateTitle(task.Name) {
+		return status_codes.BoardInvalidName, nil
+	}
+
+	if !rules.ValidatePriority(task.Priority) {
+		return status_codes.BoardInvalidPriority, nil
+	}
+
+	if !rules.ValidateEstimate(task.OriginalEstimate) {
+		return status_codes.BoardInvalidEstimate, nil
+	}
+
+	if task.Status != entities.TaskFinished {
+		task.Status = entities.TaskNotFinished
+	}
+
+	// A task can't be finished before its blockers
+	if current.Status != entities.TaskFinished && task.Status == entities.TaskFinished {
+		blockers, err := b.repository.CountUnfinishedBlockers(ctx, current.ID)
+		if err != nil {
+			return status_codes.BoardFailure, errors.Join(errors.New("failed to count task blockers"), err)
+		}
+
+		if blockers > 0 {
+			return status_codes.BoardTaskBlocked, nil
+		}
+	}
+
+	err = b.repository.UpdateTask(ctx, &task)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to update task"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		IDTask:     current.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTask,
+		EntityID:   current.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set("name", current.Name, task.Name).
+			set("description", current.Description, task.Description).
+			set("status", current.Status, task.Status).
+			set("priority", current.Priority, task.Priority).
+			set("due_date", dueDateChange(current.DueDate), dueDateChange(task.DueDate)).
+			set("original_estimate", current.OriginalEstimate, task.OriginalEstimate),
+	})
+
+	// The checklist item a subtask was promoted from follows its completion
+	if current.IDParent != 0 && current.Status != task.Status {
+		var checkedAt *time.Time
+		if task.Status == entities.TaskFinished {
+			now := time.Now()
+			checkedAt = &now
+		}
+
+		err = b.checklistRepository.SetSubtaskItemsChecked(ctx, current.ID, checkedAt)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to check subtask items", "task", current.ID, "cause", err)
+		}
+	}
+
+	// Completing a recurring task creates its next occurrence
+	if current.Recurrence != nil && current.Status != entities.TaskFinished && task.Status == entities.TaskFinished {
+		current.DueDate = task.DueDate
+
+		err = b.createNextOccurrence(ctx, *user, *current)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to create next occurrence", "task", current.ID, "cause", err)
+		}
+	}
+
+	return status_codes.BoardSuccess, nil
+}
+
+// MoveTask places the task at the given position of a task list, which must be on the same board. A task list having
+// reached its WIP limit rejects the tasks moved from other lists, unless the board only warns about it, the violation
+// being returned then.
+func (b BoardUseCases) MoveTask(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	taskListID int,
+	position int,
+) (*entities.WIPViolation, status_codes.BoardStatusCode, error) {
+	current, statusCode, err := b.getTask(ctx, user, id)
+	if current == nil {
+		return nil, statusCode, err
+	}
+
+	taskList, err := b.repository.GetTaskListByID(ctx, taskListID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.BoardTaskListNotFound, nil
+		}
+
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to get task list"), err)
+	}
+
+	if taskList.IDBoard != current.IDBoard {
+		return nil, status_codes.BoardTaskListNotFound, nil
+	}
+
+	var violation *entities.WIPViolation
+	if taskList.ID != current.IDTaskList {
+		violation, statusCode, err = b.checkWIPLimit(ctx, taskList)
+		if statusCode != status_codes.BoardSuccess {
+			return nil, statusCode, err
+		}
+	}
+
+	position = max(position, 0)
+
+	err = b.repository.MoveTask(ctx, id, taskListID, position)
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to move task"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		IDTask:     current.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTask,
+		EntityID:   current.ID,
+		Action:     entities.ActivityMoved,
+		Changes: activityChanges{}.
+			set("id_task_list", current.IDTaskList, taskListID).
+			set("position", current.Position, position),
+	})
+
+	return violation, status_codes.BoardSuccess, nil
+}
+
+// DeleteTask moves the task to the trash
+func (b BoardUseCases) DeleteTask(ctx context.Context, user *entities.User, id int) (status_codes.BoardStatusCode, error) {
+	current, statusCode, err := b.getTask(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	err = b.repository.DeleteTask(ctx, id)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to delete task"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		IDTask:     current.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTask,
+		EntityID:   current.ID,
+		Action:     entities.ActivityDeleted,
+		Changes: activityChanges{}.
+			set("name", current.Name, nil).
+			set("description", current.Description, nil).
+			set("id_task_list", current.IDTaskList, nil),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// SetTaskArchived archives or unarchives the task. A task list having reached its WIP limit rejects the unarchived
+// task, unless the board only warns about it, the violation being returned then.
+func (b BoardUseCases) SetTaskArchived(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	archived bool,
+) (*entities.WIPViolation, status_codes.BoardStatusCode, error) {
+	task, statusCode, err := b.getTask(ctx, user, id)
+	if task == nil {
+		return nil, statusCode, err
+	}
+
+	var violation *entities.WIPViolation
+	if !archived && task.StatusCode == entities.StatusArchived {
+		taskList, err := b.repository.GetTaskListByID(ctx, task.IDTaskList)
+		if err != nil {
+			return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to get task list"), err)
+		}
+
+		violation, statusCode, err = b.checkWIPLimit(ctx, taskList)
+		if statusCode != status_codes.BoardSuccess {
+			return nil, statusCode, err
+		}
+	}
+
+	err = b.repository.SetTaskStatusCode(ctx, task.ID, archivedStatusCode(archived))
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to archive task"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTask,
+		EntityID:   task.ID,
+		Action:     archivedAction(archived),
+	})
+
+	return violation, status_codes.BoardSuccess, nil
+}
+
+// GetTrash returns the deleted boards the user owns, along with the deleted task lists and tasks of the boards the
+// user is a member of, until their retention expires
+func (b BoardUseCases) GetTrash(ctx context.Context, user *entities.User) ([]entities.TrashItem, error) {
+	items, err := b.repository.GetTrash(ctx, user.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get trash"), err)
+	}
+
+	now := time.Now()
+	items = slices.DeleteFunc(items, func(item entities.TrashItem) bool {
+		return !b.purgeTime(item).After(now)
+	})
+
+	for i := range items {
+		items[i].PurgeAt = b.purgeTime(items[i])
+	}
+
+	return items, nil
+}
+
+// RestoreTrashItem restores the deleted board, task list or task, as long as its retention did not expire. Only the
+// board owner can restore a board, while task lists and tasks can only be restored once what holds them is out of
+// the trash. A task list having reached its WIP limit rejects the restored task, unless the board only warns about it,
+// the violation being returned then.
+func (b BoardUseCases) RestoreTrashItem(
+	ctx context.Context,
+	user *entities.User,
+	itemType entities.TrashItemType,
+	id int,
+) (*entities.WIPViolation, status_codes.BoardStatusCode, error) {
+	item, err := b.repository.GetTrashItem(ctx, itemType, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.BoardTrashItemNotFound, nil
+		}
+
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to get trash item"), err)
+	}
+
+	if !b.purgeTime(*item).After(time.Now()) {
+		return nil, status_codes.BoardTrashItemNotFound, nil
+	}
+
+	var violation *entities.WIPViolation
+	activity := entities.Activity{
+		IDBoard:  item.IDBoard,
+		Actor:    *user,
+		EntityID: item.ID,
+		Action:   entities.ActivityRestored,
+	}
+
+	switch item.Type {
+	case entities.TrashBoard:
+		if item.CreatedBy.ID != user.ID {
+			return nil, status_codes.BoardFailure, entities.ErrForbidden
+		}
+
+		activity.EntityType = entities.ActivityEntityBoard
+		err = b.repository.SetBoardStatusCode(ctx, item.ID, entities.StatusActive)
+	case entities.TrashTaskList:
+		board, statusCode, boardErr := b.getBoard(ctx, user, item.IDBoard)
+		if board == nil {
+			if statusCode == status_codes.BoardNotFound {
+				return nil, status_codes.BoardParentInTrash, nil
+			}
+
+			return nil, statusCode, boardErr
+		}
+
+		activity.EntityType = entities.ActivityEntityTaskList
+		err = b.repository.SetTaskListStatusCode(ctx, item.ID, entities.StatusActive)
+	case entities.TrashTask:
+		taskList, statusCode, taskListErr := b.getTaskList(ctx, user, item.IDTaskList)
+		if taskList == nil {
+			if statusCode == status_codes.BoardTaskListNotFound {
+				return nil, status_codes.BoardParentInTrash, nil
+			}
+
+			return nil, statusCode, taskListErr
+		}
+
+		violation, statusCode, err = b.checkWIPLimit(ctx, taskList)
+		if statusCode != status_codes.BoardSuccess {
+			return nil, statusCode, err
+		}
+
+		activity.IDTask = item.ID
+		activity.EntityType = entities.ActivityEntityTask
+		err = b.repository.SetTaskStatusCode(ctx, item.ID, entities.StatusActive)
+	}
+	if err != nil {
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to restore trash item"), err)
+	}
+
+	b.activity.Record(ctx, activity)
+	return violation, status_codes.BoardSuccess, nil
+}
+
+// PurgeTrash deletes for good the items whose retention expired, along with the files of their attachments
+func (b BoardUseCases) PurgeTrash(ctx context.Context) error {
+	paths, purged, err := b.repository.PurgeTrash(ctx, time.Now().Add(-b.trashRetention))
+	if err != nil {
+		return errors.Join(errors.New("failed to purge trash"), err)
+	}
+
+	for _, filePath := range paths {
+		err = b.fileStorage.DeleteFile(filePath)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to delete purged file", "path", filePath, "cause", err)
+		}
+	}
+
+	slog.InfoContext(ctx, "purged trash", "count", purged, "files", len(paths))
+	return nil
+}
+
+// AssignTask assigns a member of the task's board to the task
+func (b BoardUseCases) AssignTask(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	assigneeID int,
+) (status_codes.BoardStatusCode, error) {
+	task, statusCode, err := b.getTask(ctx, user, taskID)
+	if task == nil {
+		return statusCode, err
+	}
+
+	assignee, err := b.authRepository.GetUserByID(ctx, assigneeID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return status_codes.BoardUserNotFound, nil
+		}
+
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to get assignee"), err)
+	}
+
+	isMember, err := b.repository.IsBoardMember(ctx, task.IDBoard, assignee.ID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to check board membership"), err)
+	}
+
+	if !isMember {
+		return status_codes.BoardMemberNotFound, nil
+	}
+
+	isAssignee, err := b.repository.IsTaskAssignee(ctx, task.ID, assignee.ID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to check task assignee"), err)
+	}
+
+	if isAssignee {
+		return status_codes.BoardAssigneeAlreadyExist, nil
+	}
+
+	err = b.repository.AddTaskAssignee(ctx, task.ID, assignee.ID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to add task assignee"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityAssignee,
+		EntityID:   assignee.ID,
+		Action:     entities.ActivityAdded,
+		Changes:    activityChanges{}.set("email", nil, assignee.Email),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// UnassignTask removes the user from the assignees of the task
+func (b BoardUseCases) UnassignTask(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	assigneeID int,
+) (status_codes.BoardStatusCode, error) {
+	task, statusCode, err := b.getTask(ctx, user, taskID)
+	if task == nil {
+		return statusCode, err
+	}
+
+	isAssignee, err := b.repository.IsTaskAssignee(ctx, task.ID, assigneeID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to check task assignee"), err)
+	}
+
+	if !isAssignee {
+		return status_codes.BoardAssigneeNotFound, nil
+	}
+
+	assignee, err := b.authRepository.GetUserByID(ctx, assigneeID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to get assignee"), err)
+	}
+
+	err = b.repository.RemoveTaskAssignee(ctx, task.ID, assigneeID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to remove task assignee"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityAssignee,
+		EntityID:   assigneeID,
+		Action:     entities.ActivityRemoved,
+		Changes:    activityChanges{}.set("email", assignee.Email, nil),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// AddTaskDependency makes the blocker, a task of the same board, block the task. Dependencies closing a cycle are
+// rejected.
+func (b BoardUseCases) AddTaskDependency(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	blockerID int,
+) (status_codes.BoardStatusCode, error) {
+	task, statusCode, err := b.getTask(ctx, user, taskID)
+	if task == nil {
+		return statusCode, err
+	}
+
+	if blockerID == task.ID {
+		return status_codes.BoardDependencyCycle, nil
+	}
+
+	blocker, err := b.repository.GetTaskByID(ctx, blockerID)
+	if err != nil && !errors.Is(err, entities.ErrNotFound) {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to get blocker task"), err)
+	}
+
+	if blocker == nil || blocker.IDBoard != task.IDBoard {
+		return status_codes.BoardBlockerNotFound, nil
+	}
+
+	dependencies, err := b.repository.GetTaskDependencies(ctx, task.ID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to get task dependencies"), err)
+	}
+
+	for _, dependency := range dependencies {
+		if dependency.IDTask == task.ID && dependency.IDBlocker == blocker.ID {
+			return status_codes.BoardDependencyAlreadyExist, nil
+		}
+	}
+
+	added, err := b.repository.AddTaskDependency(ctx, task.IDBoard, entities.TaskDependency{
+		IDTask:    task.ID,
+		IDBlocker: blocker.ID,
+		CreatedBy: *user,
+	})
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to add task dependency"), err)
+	}
+
+	if !added {
+		return status_codes.BoardDependencyCycle, nil
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityDependency,
+		EntityID:   blocker.ID,
+		Action:     entities.ActivityAdded,
+		Changes:    activityChanges{}.set("blocker", nil, blocker.Name),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// RemoveTaskDependency stops the blocker from blocking the task
+func (b BoardUseCases) RemoveTaskDependency(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	blockerID int,
+) (status_codes.BoardStatusCode, error) {
+	task, statusCode, err := b.getTask(ctx, user, taskID)
+	if task == nil {
+		return statusCode, err
+	}
+
+	dependencies, err := b.repository.GetTaskDependencies(ctx, task.ID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to get task dependencies"), err)
+	}
+
+	found := slices.ContainsFunc(dependencies, func(dependency entities.TaskDependency) bool {
+		return dependency.IDTask == task.ID && dependency.IDBlocker == blockerID
+	})
+	if !found {
+		return status_codes.BoardDependencyNotFound, nil
+	}
+
+	blocker, err := b.repository.GetTaskByID(ctx, blockerID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to get blocker task"), err)
+	}
+
+	err = b.repository.RemoveTaskDependency(ctx, task.ID, blockerID)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to remove task dependency"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityDependency,
+		EntityID:   blockerID,
+		Action:     entities.ActivityRemoved,
+		Changes:    activityChanges{}.set("blocker", blocker.Name, nil),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// GetDependencyGraph returns the tasks of the board having dependencies, along with the dependencies between them
+func (b BoardUseCases) GetDependencyGraph(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+) (*entities.DependencyGraph, error) {
+	board, err := b.repository.GetBoardByID(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, b.repository, board.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies, err := b.repository.GetDependenciesByBoard(ctx, board.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get board dependencies"), err)
+	}
+
+	tasks, err := b.repository.GetTasksByBoard(ctx, board.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get board tasks"), err)
+	}
+
+	statusByTask := make(map[int]entities.TaskCompletionStatus)
+	for _, task := range tasks {
+		statusByTask[task.ID] = task.Status
+	}
+
+	linked := make(map[int]bool)
+	blocked := make(map[int]bool)
+	for _, dependency := range dependencies {
+		linked[dependency.IDTask] = true
+		linked[dependency.IDBlocker] = true
+		if statusByTask[dependency.IDBlocker] != entities.TaskFinished {
+			blocked[dependency.IDTask] = true
+		}
+	}
+
+	graph := entities.DependencyGraph{
+		Tasks:        make([]entities.DependencyNode, 0, len(linked)),
+		Dependencies: dependencies,
+	}
+
+	for _, task := range tasks {
+		if !linked[task.ID] {
+			continue
+		}
+
+		graph.Tasks = append(graph.Tasks, entities.DependencyNode{
+			ID:         task.ID,
+			IDTaskList: task.IDTaskList,
+			Name:       task.Name,
+			Status:     task.Status,
+			Blocked:    blocked[task.ID],
+		})
+	}
+
+	return &graph, nil
+}
+
+// withTaskDetails fills the attachments, assignees, labels, checklist progress and custom field values of the tasks of
+// the board
+func (b BoardUseCases) withTaskDetails(ctx context.Context, boardID int, tasks []entities.Task) error {
+	attachments, err := b.attachmentRepository.GetAttachmentsByBoard(ctx, boardID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board attachments"), err)
+	}
+
+	assigneesByTask, err := b.repository.GetAssigneesByBoard(ctx, boardID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board assignees"), err)
+	}
+
+	progressByTask, err := b.checklistRepository.GetProgressByBoard(ctx, boardID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board checklist progress"), err)
+	}
+
+	valuesByTask, err := b.fieldRepository.GetValuesByBoard(ctx, boardID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board custom field values"), err)
+	}
+
+	labelsByTask, err := b.labelRepository.GetTaskLabelsByBoard(ctx, boardID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board task labels"), err)
+	}
+
+	attachmentsByTask := make(map[int][]entities.Attachment)
+	for _, attachment := range attachments {
+		withAttachmentURLs(&attachment)
+		attachmentsByTask[attachment.IDTask] = append(attachmentsByTask[attachment.IDTask], attachment)
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		task.Attachments = attachmentsByTask[task.ID]
+		if task.Attachments == nil {
+			task.Attachments = make([]entities.Attachment, 0)
+		}
+		task.Assignees = assigneesByTask[task.ID]
+		if task.Assignees == nil {
+			task.Assignees = make([]entities.User, 0)
+		}
+		task.Labels = labelsByTask[task.ID]
+		if task.Labels == nil {
+			task.Labels = make([]entities.Label, 0)
+		}
+		task.Progress = progressByTask[task.ID]
+		task.Fields = valuesByTask[task.ID]
+		if task.Fields == nil {
+			task.Fields = make([]entities.CustomFieldValue, 0)
+		}
+	}
+
+	return nil
+}
+
+// copyBoardAttachments copies the files of the attachments of the board, returning the copies by source attachment ID.
+// The copies are removed again when any file fails to be copied.
+func (b BoardUseCases) copyBoardAttachments(ctx context.Context, boardID int) (map[int]entities.Attachment, error) {
+	attachments, err := b.attachmentRepository.GetAttachmentsByBoard(ctx, boardID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get board attachments"), err)
+	}
+
+	copies := make(map[int]entities.Attachment, len(attachments))
+	for _, attachment := range attachments {
+		attachmentCopy, err := copyAttachmentFiles(ctx, b.fileStorage, attachment)
+		if err != nil {
+			for _, copied := range copies {
+				deleteAttachmentFiles(ctx, b.fileStorage, &copied)
+			}
+
+			return nil, errors.Join(errors.New("failed to copy attachment files"), err)
+		}
+
+		copies[attachment.ID] = *attachmentCopy
+	}
+
+	return copies, nil
+}
+
+// getBoard returns the board if the user is a member of it. A nil board is returned along with the status code or
+// error to send back otherwise.
+func (b BoardUseCases) getBoard(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Board, status_codes.BoardStatusCode, error) {
+	board, err := b.repository.GetBoardByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.BoardNotFound, nil
+		}
+
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to get board"), err)
+	}
+
+	err = checkBoardMember(ctx, b.repository, board.ID, user.ID)
+	if err != nil {
+		return nil, status_codes.BoardFailure, err
+	}
+
+	return board, status_codes.BoardSuccess, nil
+}
+
+// getTaskList is the same as getBoard, for the board of the task list
+func (b BoardUseCases) getTaskList(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.TaskList, status_codes.BoardStatusCode, error) {
+	taskList, err := b.repository.GetTaskListByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.BoardTaskListNotFound, nil
+		}
+
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to get task list"), err)
+	}
+
+	err = checkBoardMember(ctx, b.repository, taskList.IDBoard, user.ID)
+	if err != nil {
+		return nil, status_codes.BoardFailure, err
+	}
+
+	return taskList, status_codes.BoardSuccess, nil
+}
+
+// checkWIPLimit checks that the task list can take one more task. The violation of its WIP limit is returned when the
+// board only warns about it.
+func (b BoardUseCases) checkWIPLimit(
+	ctx context.Context,
+	taskList *entities.TaskList,
+) (*entities.WIPViolation, status_codes.BoardStatusCode, error) {
+	violation, rejected, err := checkTaskListWIPLimit(ctx, b.repository, taskList, 1)
+	if err != nil {
+		return nil, status_codes.BoardFailure, err
+	}
+
+	if rejected {
+		return nil, status_codes.BoardWIPLimitReached, nil
+	}
+
+	return violation, status_codes.BoardSuccess, nil
+}
+
+// checkTaskListWIPLimit returns the violation of the WIP limit of the task list once the added tasks are in it, along
+// with whether its board rejects them rather than only warning about it
+func checkTaskListWIPLimit(
+	ctx context.Context,
+	repository datastore.BoardRepository,
+	taskList *entities.TaskList,
+	added int,
+) (*entities.WIPViolation, bool, error) {
+	violation := wipViolation(*taskList, added)
+	if violation == nil {
+		return nil, false, nil
+	}
+
+	board, err := repository.GetBoardByID(ctx, taskList.IDBoard)
+	if err != nil {
+		return nil, false, errors.Join(errors.New("failed to get board"), err)
+	}
+
+	if !board.WIPWarnOnly {
+		return nil, true, nil
+	}
+
+	return violation, false, nil
+}
+
+// wipViolation returns the violation of the WIP limit of the task list once the added tasks are in it, nil when the
+// list can take them
+func wipViolation(taskList entities.TaskList, added int) *entities.WIPViolation {
+	if taskList.WIPLimit == 0 || taskList.TaskCount+added <= taskList.WIPLimit {
+		return nil
+	}
+
+	return &entities.WIPViolation{
+		IDTaskList: taskList.ID,
+		Limit:      taskList.WIPLimit,
+		Tasks:      taskList.TaskCount + added,
+	}
+}
+
+// createNextOccurrence creates the next occurrence of the recurring task, due on the first date of its rule that
+// follows the due date of the task and is still to come. A task list having reached its WIP limit skips it, unless the
+// board only warns about it: the recurring tasks job then creates it once the list has room and the due date passed.
+func (b BoardUseCases) createNextOccurrence(ctx context.Context, actor entities.User, task entities.Task) error {
+	rule, err := util.ParseRecurrenceRule(task.Recurrence.Rule)
+	if err != nil {
+		return errors.Join(errors.New("invalid recurrence rule"), err)
+	}
+
+	now := time.Now()
+	dueDate := now
+	if task.DueDate != nil {
+		dueDate = *task.DueDate
+	}
+
+	dueDate = rule.Next(dueDate)
+	for !dueDate.IsZero() && !dueDate.After(now) {
+		dueDate = rule.Next(dueDate)
+	}
+
+	if dueDate.IsZero() {
+		return errors.New("recurrence rule has no next occurrence")
+	}
+
+	taskUUID, err := uuid.NewRandom()
+	if err != nil {
+		return errors.Join(errors.New("failed to generate task UUID"), err)
+	}
+
+	occurrence := entities.Task{
+		UUID:             taskUUID.String(),
+		IDTaskList:       task.IDTaskList,
+		IDBoard:          task.IDBoard,
+		Name:             task.Name,
+		Description:      task.Description,
+		CreatedBy:        task.CreatedBy,
+		Status:           entities.TaskNotFinished,
+		Priority:         task.Priority,
+		DueDate:          &dueDate,
+		Recurrence:       task.Recurrence,
+		OriginalEstimate: task.OriginalEstimate,
+	}
+
+	if task.Recurrence.IDTaskList != 0 {
+		occurrence.IDTaskList = task.Recurrence.IDTaskList
+	}
+
+	taskList, err := b.repository.GetTaskListByID(ctx, occurrence.IDTaskList)
+	if err != nil {
+		return errors.Join(errors.New("failed to get task list"), err)
+	}
+
+	violation, rejected, err := checkTaskListWIPLimit(ctx, b.repository, taskList, 1)
+	if err != nil {
+		return err
+	}
+
+	if rejected {
+		slog.WarnContext(ctx, "next occurrence skipped, task list at its WIP limit", "task", task.ID,
+			"task_list", taskList.ID)
+		return nil
+	}
+
+	added, err := b.repository.AddTaskOccurrence(ctx, task.ID, &occurrence)
+	if err != nil {
+		return errors.Join(errors.New("failed to save next occurrence"), err)
+	}
+
+	// Another request or instance created it first
+	if !added {
+		return nil
+	}
+
+	if violation != nil {
+		slog.WarnContext(ctx, "next occurrence takes the task list over its WIP limit", "task", occurrence.ID,
+			"task_list", taskList.ID, "tasks", violation.Tasks, "limit", violation.Limit)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    occurrence.IDBoard,
+		IDTask:     occurrence.ID,
+		Actor:      actor,
+		EntityType: entities.ActivityEntityTask,
+		EntityID:   occurrence.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("name", nil, occurrence.Name).
+			set("description", nil, occurrence.Description).
+			set("id_task_list", nil, occurrence.IDTaskList).
+			set("due_date", nil, dueDateChange(occurrence.DueDate)).
+			set("recurrence", nil, recurrenceChange(occurrence.Recurrence)),
+	})
+
+	return nil
+}
+
+// getTask is the same as getBoard, for the board of the task
+func (b BoardUseCases) getTask(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Task, status_codes.BoardStatusCode, error) {
+	task, err := b.repository.GetTaskByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.BoardTaskNotFound, nil
+		}
+
+		return nil, status_codes.BoardFailure, errors.Join(errors.New("failed to get task"), err)
+	}
+
+	err = checkBoardMember(ctx, b.repository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, status_codes.BoardFailure, err
+	}
+
+	return task, status_codes.BoardSuccess, nil
+}
+
+// SetTaskRecurrence makes the task repeat, or stops it from repeating when the recurrence is nil
+func (b BoardUseCases) SetTaskRecurrence(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	recurrence *entities.Recurrence,
+) (status_codes.BoardStatusCode, error) {
+	task, statusCode, err := b.getTask(ctx, user, taskID)
+	if task == nil {
+		return statusCode, err
+	}
+
+	if recurrence != nil {
+		rule, err := util.ParseRecurrenceRule(recurrence.Rule)
+		if err != nil {
+			return status_codes.BoardInvalidRecurrence, nil
+		}
+
+		recurrence.Rule = rule.String()
+
+		// Next occurrences can't be created on another board
+		if recurrence.IDTaskList != 0 {
+			taskList, err := b.repository.GetTaskListByID(ctx, recurrence.IDTaskList)
+			if err != nil && !errors.Is(err, entities.ErrNotFound) {
+				return status_codes.BoardFailure, errors.Join(errors.New("failed to get task list"), err)
+			}
+
+			if taskList == nil || taskList.IDBoard != task.IDBoard {
+				return status_codes.BoardTaskListNotFound, nil
+			}
+		}
+	}
+
+	err = b.repository.SetTaskRecurrence(ctx, task.ID, recurrence)
+	if err != nil {
+		return status_codes.BoardFailure, errors.Join(errors.New("failed to set task recurrence"), err)
+	}
+
+	b.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTask,
+		EntityID:   task.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set("recurrence", recurrenceChange(task.Recurrence), recurrenceChange(recurrence)),
+	})
+
+	return status_codes.BoardSuccess, nil
+}
+
+// CreateRecurringTasks creates the next occurrence of the recurring tasks whose due date passed, so a series goes on
+// even when an occurrence is never completed
+func (b BoardUseCases) CreateRecurringTasks(ctx context.Context) error {
+	tasks, err := b.repository.GetRecurringTasksDue(ctx, time.Now())
+	if err != nil {
+		return errors.Join(errors.New("failed to get recurring tasks"), err)
+	}
+
+	for _, task := range tasks {
+		err = b.createNextOccurrence(ctx, task.CreatedBy, task)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to create next occurrence", "task", task.ID, "cause", err)
+		}
+	}
+
+	return nil
+}
+
+// FlagOverdueTasks flags the unfinished tasks whose due date passed. The activity recorded for each of them is made
+// on behalf of the task author.
+func (b BoardUseCases) FlagOverdueTasks(ctx context.Context) error {
+	flagged, err := b.repository.FlagOverdueTasks(ctx, time.Now())
+	if err != nil {
+		return errors.Join(errors.New("failed to flag overdue tasks"), err)
+	}
+
+	for _, task := range flagged {
+		b.activity.Record(ctx, entities.Activity{
+			IDBoard:    task.IDBoard,
+			IDTask:     task.ID,
+			Actor:      task.CreatedBy,
+			EntityType: entities.ActivityEntityTask,
+			EntityID:   task.ID,
+			Action:     entities.ActivityOverdue,
+			Changes:    activityChanges{}.set("due_date", nil, dueDateChange(task.DueDate)),
+		})
+	}
+
+	slog.InfoContext(ctx, "flagged overdue tasks", "count", len(flagged))
+	return nil
+}
+
+// purgeTime returns when the deleted item is purged
+func (b BoardUseCases) purgeTime(item entities.TrashItem) time.Time {
+	return item.DeletedAt.Add(b.trashRetention)
+}
+
+// archivedStatusCode returns the status code of an archived or unarchived item
+func archivedStatusCode(archived bool) int {
+	if archived {
+		return entities.StatusArchived
+	}
+
+	return entities.StatusActive
+}
+
+// archivedAction returns the activity action of archiving or unarchiving an item
+func archivedAction(archived bool) entities.ActivityAction {
+	if archived {
+		return entities.ActivityArchived
+	}
+
+	return entities.ActivityUnarchived
+}
+
+// parseFieldFilter parses the raw value of a custom field filter: dates are days such as "2024-05-31", select values
+// are option IDs, checkboxes are "true" or "false" and users are IDs
+func parseFieldFilter(field entities.CustomField, raw string) (entities.CustomFieldValue, bool) {
+	value := entities.CustomFieldValue{IDField: field.ID}
+
+	switch field.Type {
+	case entities.CustomFieldText:
+		value.Text = &raw
+	case entities.CustomFieldNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return value, false
+		}
+		value.Number = &number
+	case entities.CustomFieldDate:
+		date, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return value, false
+		}
+		value.Date = &date
+	case entities.CustomFieldSingleSelect, entities.CustomFieldMultiSelect:
+		if !slices.ContainsFunc(field.Options, sameOption(raw)) {
+			return value, false
+		}
+		value.Options = []string{raw}
+	case entities.CustomFieldCheckbox:
+		checked, err := strconv.ParseBool(raw)
+		if err != nil {
+			return value, false
+		}
+		value.Checked = &checked
+	case entities.CustomFieldUser:
+		userID, err := strconv.Atoi(raw)
+		if err != nil || userID <= 0 {
+			return value, false
+		}
+		value.IDUser = userID
+	default:
+		return value, false
+	}
+
+	return value, true
+}
+
+// checkBoardMember returns entities.ErrForbidden if the user is neither the owner nor a member of the board
+func checkBoardMember(ctx context.Context, repository datastore.BoardRepository, boardID int, userID int) error {
+	member, err := repository.IsBoardMember(ctx, boardID, userID)
+	if err != nil {
+		return errors.Join(errors.New("failed to check board membership"), err)
+	}
+
+	if !member {
+		return entities.ErrForbidden
+	}
+
+	return nil
 }
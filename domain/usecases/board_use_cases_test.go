@@ -0,0 +1,402 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"taskflow/domain/entities"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/datastore"
+	"taskflow/infrastructure/pubsub/membroker"
+	"testing"
+	"time"
+)
+
+// fakeBoardRepository keeps a single board in memory, counting the active tasks of its task lists like the MySQL
+// repository does
+type fakeBoardRepository struct {
+	datastore.BoardRepository
+
+	board     entities.Board
+	taskLists []entities.TaskList
+	tasks     []entities.Task
+	trash     []entities.TrashItem
+	recurring []entities.Task
+}
+
+func (f *fakeBoardRepository) GetBoardByID(_ context.Context, _ int) (*entities.Board, error) {
+	board := f.board
+	return &board, nil
+}
+
+func (f *fakeBoardRepository) IsBoardMember(_ context.Context, _ int, _ int) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeBoardRepository) GetBoardMembers(_ context.Context, _ int) ([]entities.User, error) {
+	return make([]entities.User, 0), nil
+}
+
+func (f *fakeBoardRepository) GetTaskLists(_ context.Context, _ int) ([]entities.TaskList, error) {
+	taskLists := make([]entities.TaskList, 0, len(f.taskLists))
+	for _, taskList := range f.taskLists {
+		taskLists = append(taskLists, f.counted(taskList))
+	}
+
+	return taskLists, nil
+}
+
+func (f *fakeBoardRepository) GetTaskListByID(_ context.Context, id int) (*entities.TaskList, error) {
+	i := slices.IndexFunc(f.taskLists, func(taskList entities.TaskList) bool { return taskList.ID == id })
+	if i < 0 {
+		return nil, entities.ErrNotFound
+	}
+
+	taskList := f.counted(f.taskLists[i])
+	return &taskList, nil
+}
+
+func (f *fakeBoardRepository) GetTasksByBoard(_ context.Context, _ int) ([]entities.Task, error) {
+	return slices.Clone(f.tasks), nil
+}
+
+func (f *fakeBoardRepository) GetTaskByID(_ context.Context, id int) (*entities.Task, error) {
+	i := slices.IndexFunc(f.tasks, func(task entities.Task) bool { return task.ID == id })
+	if i < 0 {
+		return nil, entities.ErrNotFound
+	}
+
+	task := f.tasks[i]
+	return &task, nil
+}
+
+func (f *fakeBoardRepository) GetAssigneesByBoard(_ context.Context, _ int) (map[int][]entities.User, error) {
+	return nil, nil
+}
+
+func (f *fakeBoardRepository) SetTaskStatusCode(_ context.Context, id int, statusCode int) error {
+	for i := range f.tasks {
+		if f.tasks[i].ID == id {
+			f.tasks[i].StatusCode = statusCode
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeBoardRepository) MoveTask(_ context.Context, id int, taskListID int, _ int) error {
+	for i := range f.tasks {
+		if f.tasks[i].ID == id {
+			f.tasks[i].IDTaskList = taskListID
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeBoardRepository) GetTrashItem(
+	_ context.Context,
+	itemType entities.TrashItemType,
+	id int,
+) (*entities.TrashItem, error) {
+	i := slices.IndexFunc(f.trash, func(item entities.TrashItem) bool {
+		return item.Type == itemType && item.ID == id
+	})
+	if i < 0 {
+		return nil, entities.ErrNotFound
+	}
+
+	item := f.trash[i]
+	return &item, nil
+}
+
+func (f *fakeBoardRepository) GetRecurringTasksDue(_ context.Context, _ time.Time) ([]entities.Task, error) {
+	return slices.Clone(f.recurring), nil
+}
+
+func (f *fakeBoardRepository) AddTaskOccurrence(_ context.Context, _ int, task *entities.Task) (bool, error) {
+	f.add(task)
+	return true, nil
+}
+
+func (f *fakeBoardRepository) AddTasks(_ context.Context, tasks []entities.Task) error {
+	for i := range tasks {
+		f.add(&tasks[i])
+	}
+
+	return nil
+}
+
+func (f *fakeBoardRepository) add(task *entities.Task) {
+	task.ID = len(f.tasks) + 100
+	task.StatusCode = entities.StatusActive
+	f.tasks = append(f.tasks, *task)
+}
+
+// counted returns the task list with the number of its active tasks
+func (f *fakeBoardRepository) counted(taskList entities.TaskList) entities.TaskList {
+	taskList.TaskCount = 0
+	for _, task := range f.tasks {
+		if task.IDTaskList == taskList.ID && task.StatusCode == entities.StatusActive {
+			taskList.TaskCount++
+		}
+	}
+
+	return taskList
+}
+
+type fakeActivityRepository struct {
+	datastore.ActivityRepository
+}
+
+func (f fakeActivityRepository) AddActivity(_ context.Context, _ *entities.Activity) error {
+	return nil
+}
+
+type fakeAttachmentRepository struct {
+	datastore.AttachmentRepository
+}
+
+func (f fakeAttachmentRepository) GetAttachmentsByBoard(_ context.Context, _ int) ([]entities.Attachment, error) {
+	return nil, nil
+}
+
+type fakeCustomFieldRepository struct {
+	datastore.CustomFieldRepository
+}
+
+func (f fakeCustomFieldRepository) GetFieldsByBoard(_ context.Context, _ int) ([]entities.CustomField, error) {
+	return make([]entities.CustomField, 0), nil
+}
+
+func (f fakeCustomFieldRepository) GetValuesByBoard(
+	_ context.Context,
+	_ int,
+) (map[int][]entities.CustomFieldValue, error) {
+	return nil, nil
+}
+
+type fakeLabelRepository struct {
+	datastore.LabelRepository
+}
+
+func (f fakeLabelRepository) GetLabelsByBoard(_ context.Context, _ int) ([]entities.Label, error) {
+	return make([]entities.Label, 0), nil
+}
+
+func (f fakeLabelRepository) GetTaskLabelsByBoard(_ context.Context, _ int) (map[int][]entities.Label, error) {
+	return nil, nil
+}
+
+// fakeChecklistRepository holds a single checklist with a single item, of the task 1
+type fakeChecklistRepository struct {
+	datastore.ChecklistRepository
+
+	promoted bool
+}
+
+func (f *fakeChecklistRepository) GetChecklistByID(_ context.Context, id int) (*entities.Checklist, error) {
+	return &entities.Checklist{ID: id, IDTask: 1}, nil
+}
+
+func (f *fakeChecklistRepository) GetItemByID(_ context.Context, id int) (*entities.ChecklistItem, error) {
+	return &entities.ChecklistItem{ID: id, IDChecklist: 1, Text: "item"}, nil
+}
+
+func (f *fakeChecklistRepository) PromoteItem(_ context.Context, _ int, subtask *entities.Task) (bool, error) {
+	f.promoted = true
+	subtask.ID = 100
+	return true, nil
+}
+
+func (f *fakeChecklistRepository) GetProgressByBoard(_ context.Context, _ int) (map[int]entities.Progress, error) {
+	return nil, nil
+}
+
+// newWIPTestBoard returns a board whose first task list is at its WIP limit of 2 tasks, with an archived task and a
+// deleted task in it. The recurring task 5 of the second task list repeats in the first one.
+func newWIPTestBoard(warnOnly bool) (*fakeBoardRepository, BoardUseCases, ActivityUseCases) {
+	repository := &fakeBoardRepository{
+		board: entities.Board{ID: 1, Title: "Board", WIPWarnOnly: warnOnly},
+		taskLists: []entities.TaskList{
+			{ID: 1, IDBoard: 1, Name: "Doing", WIPLimit: 2},
+			{ID: 2, IDBoard: 1, Name: "Backlog"},
+		},
+		tasks: []entities.Task{
+			{ID: 1, IDBoard: 1, IDTaskList: 1, Name: "first", StatusCode: entities.StatusActive},
+			{ID: 2, IDBoard: 1, IDTaskList: 1, Name: "second", StatusCode: entities.StatusActive},
+			{ID: 3, IDBoard: 1, IDTaskList: 1, Name: "archived", StatusCode: entities.StatusArchived},
+			{ID: 4, IDBoard: 1, IDTaskList: 1, Name: "deleted", StatusCode: entities.StatusDeleted},
+		},
+		trash: []entities.TrashItem{
+			{Type: entities.TrashTask, ID: 4, IDBoard: 1, IDTaskList: 1, DeletedAt: time.Now()},
+		},
+	}
+
+	dueDate := time.Now().Add(-time.Hour)
+	repository.recurring = []entities.Task{{
+		ID:         5,
+		IDBoard:    1,
+		IDTaskList: 2,
+		Name:       "recurring",
+		DueDate:    &dueDate,
+		Recurrence: &entities.Recurrence{Rule: "FREQ=DAILY", IDTaskList: 1},
+	}}
+
+	activity := NewActivityUseCases(fakeActivityRepository{}, repository, membroker.NewMemoryBroker())
+	boards := NewBoardUseCases(
+		repository,
+		fakeAttachmentRepository{},
+		nil,
+		&fakeChecklistRepository{},
+		fakeCustomFieldRepository{},
+		fakeLabelRepository{},
+		nil,
+		activity,
+		time.Hour,
+	)
+
+	return repository, boards, activity
+}
+
+func TestWIPLimitUnarchiveAndRestore(t *testing.T) {
+	user := &entities.User{ID: 1}
+	for _, warnOnly := range []bool{false, true} {
+		repository, boards, _ := newWIPTestBoard(warnOnly)
+		ctx := context.Background()
+
+		violation, statusCode, err := boards.SetTaskArchived(ctx, user, 3, false)
+		checkWIPResult(t, "SetTaskArchived()", warnOnly, violation, statusCode, err, 3)
+
+		archived, _ := repository.GetTaskByID(ctx, 3)
+		if (archived.StatusCode == entities.StatusActive) != warnOnly {
+			t.Errorf("warn only = %v: unarchived task status code = %d", warnOnly, archived.StatusCode)
+		}
+
+		violation, statusCode, err = boards.RestoreTrashItem(ctx, user, entities.TrashTask, 4)
+		want := 3
+		if warnOnly {
+			want = 4
+		}
+		checkWIPResult(t, "RestoreTrashItem()", warnOnly, violation, statusCode, err, want)
+
+		deleted, _ := repository.GetTaskByID(ctx, 4)
+		if (deleted.StatusCode == entities.StatusActive) != warnOnly {
+			t.Errorf("warn only = %v: restored task status code = %d", warnOnly, deleted.StatusCode)
+		}
+	}
+}
+
+func TestWIPLimitRecurrence(t *testing.T) {
+	for _, warnOnly := range []bool{false, true} {
+		repository, boards, _ := newWIPTestBoard(warnOnly)
+
+		err := boards.CreateRecurringTasks(context.Background())
+		if err != nil {
+			t.Fatalf("CreateRecurringTasks() error = %v", err)
+		}
+
+		taskList, _ := repository.GetTaskListByID(context.Background(), 1)
+		want := 2
+		if warnOnly {
+			want = 3
+		}
+
+		if taskList.TaskCount != want {
+			t.Errorf("warn only = %v: %d tasks in the task list, want %d", warnOnly, taskList.TaskCount, want)
+		}
+	}
+}
+
+func TestWIPLimitPromote(t *testing.T) {
+	user := &entities.User{ID: 1}
+	for _, warnOnly := range []bool{false, true} {
+		repository, _, activity := newWIPTestBoard(warnOnly)
+		checklists := &fakeChecklistRepository{}
+		c := NewChecklistUseCases(checklists, repository, activity)
+
+		subtask, statusCode, err := c.PromoteItem(context.Background(), user, 1, 1)
+		if err != nil {
+			t.Fatalf("warn only = %v: PromoteItem() error = %v", warnOnly, err)
+		}
+
+		if !warnOnly {
+			if statusCode != status_codes.ChecklistWIPLimitReached || checklists.promoted {
+				t.Errorf("PromoteItem() status = %v, promoted = %v, want rejected", statusCode, checklists.promoted)
+			}
+			continue
+		}
+
+		if statusCode != status_codes.ChecklistSuccess || subtask == nil || subtask.WIPViolation == nil {
+			t.Fatalf("warn only: PromoteItem() status = %v, subtask = %+v, want a violation", statusCode, subtask)
+		}
+
+		if subtask.WIPViolation.Tasks != 3 || subtask.WIPViolation.Limit != 2 {
+			t.Errorf("warn only: violation = %+v, want 3 tasks for a limit of 2", subtask.WIPViolation)
+		}
+	}
+}
+
+func TestWIPLimitCSVImport(t *testing.T) {
+	user := &entities.User{ID: 1}
+	data := []byte("List,Name\nDoing,third\nBacklog,fourth\nDoing,fifth\n")
+
+	for _, warnOnly := range []bool{false, true} {
+		repository, boards, activity := newWIPTestBoard(warnOnly)
+		c := NewCSVUseCases(boards, repository, activity)
+
+		report, statusCode, err := c.ImportTasks(context.Background(), user, 1, data, entities.CSVImportOptions{})
+		if err != nil || statusCode != status_codes.CSVSuccess {
+			t.Fatalf("warn only = %v: ImportTasks() status = %v, error = %v", warnOnly, statusCode, err)
+		}
+
+		if warnOnly {
+			if report.Created != 3 || len(report.Errors) != 0 || len(report.WIPViolations) != 1 {
+				t.Errorf("warn only: report = %+v, want 3 tasks and a violation", report)
+			} else if report.WIPViolations[0].Tasks != 4 {
+				t.Errorf("warn only: violation = %+v, want 4 tasks", report.WIPViolations[0])
+			}
+			continue
+		}
+
+		if report.Created != 1 || len(report.Errors) != 2 || len(report.WIPViolations) != 0 {
+			t.Errorf("report = %+v, want 1 task and 2 row errors", report)
+		}
+
+		if len(report.Errors) == 2 && (report.Errors[0].Row != 2 || report.Errors[1].Row != 4) {
+			t.Errorf("row errors = %+v, want rows 2 and 4", report.Errors)
+		}
+	}
+}
+
+// checkWIPResult checks that the task list at its limit rejected the task, or took it with the given number of tasks
+// when the board only warns
+func checkWIPResult(
+	t *testing.T,
+	name string,
+	warnOnly bool,
+	violation *entities.WIPViolation,
+	statusCode status_codes.BoardStatusCode,
+	err error,
+	tasks int,
+) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("warn only = %v: %s error = %v", warnOnly, name, err)
+	}
+
+	if !warnOnly {
+		if statusCode != status_codes.BoardWIPLimitReached || violation != nil {
+			t.Errorf("%s status = %v, violation = %+v, want rejected", name, statusCode, violation)
+		}
+		return
+	}
+
+	if statusCode != status_codes.BoardSuccess || violation == nil {
+		t.Fatalf("warn only: %s status = %v, violation = %+v, want a violation", name, statusCode, violation)
+	}
+
+	if violation.IDTaskList != 1 || violation.Limit != 2 || violation.Tasks != tasks {
+		t.Errorf("warn only: %s violation = %+v, want %d tasks for a limit of 2", name, violation, tasks)
+	}
+}
@@ -0,0 +1,261 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/util"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+const (
+	// calendarTokenSize is the number of random bytes of the feed tokens
+	calendarTokenSize = 32
+
+	// calendarProductID identifies the application generating the feeds
+	calendarProductID = "-//Taskflow//Calendar feed//EN"
+)
+
+type CalendarUseCases struct {
+	repository      datastore.CalendarRepository
+	boardRepository datastore.BoardRepository
+	publicURL       string
+}
+
+func NewCalendarUseCases(
+	repository datastore.CalendarRepository,
+	boardRepository datastore.BoardRepository,
+	publicURL string,
+) CalendarUseCases {
+	return CalendarUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		publicURL:       strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// GetFeed returns the calendar feed of the user, without its token
+func (c CalendarUseCases) GetFeed(ctx context.Context, user *entities.User) (*entities.CalendarFeed, error) {
+	return c.repository.GetFeed(ctx, user.ID)
+}
+
+// SaveFeed creates or updates the calendar feed of the user. The token is only generated, and returned along with the
+// URL of the feed, when the feed is created.
+func (c CalendarUseCases) SaveFeed(
+	ctx context.Context,
+	user *entities.User,
+	feed entities.CalendarFeed,
+) (*entities.CalendarFeed, status_codes.CalendarStatusCode, error) {
+	if feed.Component == "" {
+		feed.Component = entities.CalendarEvent
+	}
+
+	if !rules.ValidateCalendarComponent(feed.Component) {
+		return nil, status_codes.CalendarInvalidComponent, nil
+	}
+
+	feed.IDBoards = slices.Compact(slices.Sorted(slices.Values(feed.IDBoards)))
+	if len(feed.IDBoards) > rules.CalendarFeedMaxBoards {
+		return nil, status_codes.CalendarTooManyBoards, nil
+	}
+
+	if !feed.Assigned && len(feed.IDBoards) == 0 {
+		return nil, status_codes.CalendarEmptyFeed, nil
+	}
+
+	for _, boardID := range feed.IDBoards {
+		member, err := c.boardRepository.IsBoardMember(ctx, boardID, user.ID)
+		if err != nil {
+			return nil, status_codes.CalendarFailure, errors.Join(errors.New("failed to check board member"), err)
+		}
+
+		if !member {
+			return nil, status_codes.CalendarBoardNotFound, nil
+		}
+	}
+
+	_, err := c.repository.GetFeed(ctx, user.ID)
+	created := errors.Is(err, entities.ErrNotFound)
+	if err != nil && !created {
+		return nil, status_codes.CalendarFailure, errors.Join(errors.New("failed to get calendar feed"), err)
+	}
+
+	feed.IDUser = user.ID
+	feed.Token = ""
+	feed.URL = ""
+	feed.TokenHash = ""
+	if created {
+		err = c.withNewToken(&feed)
+		if err != nil {
+			return nil, status_codes.CalendarFailure, err
+		}
+	}
+
+	err = c.repository.SaveFeed(ctx, &feed)
+	if err != nil {
+		return nil, status_codes.CalendarFailure, errors.Join(errors.New("failed to save calendar feed"), err)
+	}
+
+	return &feed, status_codes.CalendarSuccess, nil
+}
+
+// RegenerateToken replaces the token of the calendar feed of the user, the previous URL no longer working. The new
+// token is returned along with the new URL.
+func (c CalendarUseCases) RegenerateToken(
+	ctx context.Context,
+	user *entities.User,
+) (*entities.CalendarFeed, status_codes.CalendarStatusCode, error) {
+	feed, err := c.repository.GetFeed(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.CalendarFeedNotFound, nil
+		}
+
+		return nil, status_codes.CalendarFailure, errors.Join(errors.New("failed to get calendar feed"), err)
+	}
+
+	err = c.withNewToken(feed)
+	if err != nil {
+		return nil, status_codes.CalendarFailure, err
+	}
+
+	found, err := c.repository.SetFeedTokenHash(ctx, user.ID, feed.TokenHash)
+	if err != nil {
+		return nil, status_codes.CalendarFailure, errors.Join(errors.New("failed to set calendar feed token"), err)
+	}
+
+	if !found {
+		return nil, status_codes.CalendarFeedNotFound, nil
+	}
+
+	return feed, status_codes.CalendarSuccess, nil
+}
+
+// DeleteFeed deletes the calendar feed of the user, revoking its token
+func (c CalendarUseCases) DeleteFeed(
+	ctx context.Context,
+	user *entities.User,
+) (status_codes.CalendarStatusCode, error) {
+	found, err := c.repository.DeleteFeed(ctx, user.ID)
+	if err != nil {
+		return status_codes.CalendarFailure, errors.Join(errors.New("failed to delete calendar feed"), err)
+	}
+
+	if !found {
+		return status_codes.CalendarFeedNotFound, nil
+	}
+
+	return status_codes.CalendarSuccess, nil
+}
+
+// GetCalendar returns the iCalendar document of the feed with the given token, along with its entity tag, which only
+// changes when the document does
+//
+// Tasks stay listed until CalendarFeedPastWindow after their due date, and only on the boards the user is still a
+// member of
+func (c CalendarUseCases) GetCalendar(ctx context.Context, token string) ([]byte, string, error) {
+	feed, err := c.repository.GetFeedByTokenHash(ctx, util.HashToken(token))
+	if err != nil {
+		return nil, "", err
+	}
+
+	dueAfter := time.Now().Add(-rules.CalendarFeedPastWindow)
+	tasks, err := c.repository.GetFeedTasks(ctx, feed, dueAfter, rules.CalendarFeedMaxTasks)
+	if err != nil {
+		return nil, "", errors.Join(errors.New("failed to get calendar feed tasks"), err)
+	}
+
+	calendar := util.NewICalendar(calendarProductID, "Taskflow")
+	for _, task := range tasks {
+		writeCalendarTask(calendar, feed.Component, task)
+	}
+
+	document := calendar.Bytes()
+	sum := sha256.Sum256(document)
+
+	return document, `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// withNewToken generates a new token for the feed, along with its hash and URL
+func (c CalendarUseCases) withNewToken(feed *entities.CalendarFeed) error {
+	token, err := util.GenerateSecret(calendarTokenSize)
+	if err != nil {
+		return errors.Join(errors.New("failed to generate calendar feed token"), err)
+	}
+
+	feed.Token = token
+	feed.TokenHash = util.HashToken(token)
+	feed.URL = c.publicURL + "/api/calendar/feeds/" + token + ".ics"
+
+	return nil
+}
+
+// writeCalendarTask writes the task as the given component. Times of the task are used for the timestamps of the
+// component, so that the document stays the same until the tasks change.
+func writeCalendarTask(
+	calendar *util.ICalendar,
+	component entities.CalendarComponent,
+	calendarTask entities.CalendarTask,
+) {
+	task := calendarTask.Task
+
+	calendar.Property("BEGIN", string(component))
+	calendar.Text("UID", task.UUID+"@taskflow")
+	calendar.Time("DTSTAMP", task.ModifiedAt)
+	calendar.Time("CREATED", task.CreatedAt)
+	calendar.Time("LAST-MODIFIED", task.ModifiedAt)
+	calendar.Text("SUMMARY", task.Name)
+
+	description := calendarTask.BoardTitle + " / " + calendarTask.TaskListName
+	if task.Description != "" {
+		description += "\n\n" + task.Description
+	}
+
+	calendar.Text("DESCRIPTION", description)
+	calendar.Text("CATEGORIES", calendarTask.BoardTitle)
+
+	switch component {
+	case entities.CalendarTodo:
+		calendar.Time("DUE", *task.DueDate)
+		if task.Status == entities.TaskFinished {
+			calendar.Property("STATUS", "COMPLETED")
+		} else {
+			calendar.Property("STATUS", "NEEDS-ACTION")
+		}
+
+		if priority := calendarPriority(task.Priority); priority > 0 {
+			calendar.Property("PRIORITY", strconv.Itoa(priority))
+		}
+	default:
+		calendar.Time("DTSTART", *task.DueDate)
+		calendar.Time("DTEND", task.DueDate.Add(rules.CalendarEventDuration))
+		calendar.Property("TRANSP", "TRANSPARENT")
+	}
+
+	calendar.Property("END", string(component))
+}
+
+// calendarPriority returns the iCalendar priority of the task priority, from 1 for the highest to 9 for the lowest, or
+// 0 when undefined
+func calendarPriority(priority entities.TaskPriority) int {
+	switch priority {
+	case entities.TaskPriorityUrgent:
+		return 1
+	case entities.TaskPriorityHigh:
+		return 3
+	case entities.TaskPriorityMedium:
+		return 5
+	case entities.TaskPriorityLow:
+		return 7
+	default:
+		return 0
+	}
+}
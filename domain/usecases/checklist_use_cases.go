@@ -0,0 +1,594 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/datastore"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChecklistUseCases struct {
+	repository      datastore.ChecklistRepository
+	boardRepository datastore.BoardRepository
+	activity        ActivityUseCases
+}
+
+func NewChecklistUseCases(
+	repository datastore.ChecklistRepository,
+	boardRepository datastore.BoardRepository,
+	activity ActivityUseCases,
+) ChecklistUseCases {
+	return ChecklistUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		activity:        activity,
+	}
+}
+
+// GetChecklists returns the checklists of the task along with their items
+func (c ChecklistUseCases) GetChecklists(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+) ([]entities.Checklist, error) {
+	task, err := c.boardRepository.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, c.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.repository.GetChecklistsByTask(ctx, taskID)
+}
+
+// CreateChecklist adds the checklist after the other checklists of its task
+func (c ChecklistUseCases) CreateChecklist(
+	ctx context.Context,
+	user *entities.User,
+	checklist entities.Checklist,
+) (*entities.Checklist, status_codes.ChecklistStatusCode, error) {
+	task, statusCode, err := c.getTask(ctx, user, checklist.IDTask)
+	if task == nil {
+		return nil, statusCode, err
+	}
+
+	checklist.Name = strings.TrimSpace(checklist.Name)
+	if !rules.ValidateTitle(checklist.Name) {
+		return nil, status_codes.ChecklistInvalidName, nil
+	}
+
+	checklistUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.ChecklistFailure, errors.Join(errors.New("failed to generate checklist UUID"), err)
+	}
+
+	checklist.UUID = checklistUUID.String()
+	checklist.CreatedBy = *user
+	checklist.Items = make([]entities.ChecklistItem, 0)
+
+	err = c.repository.AddChecklist(ctx, &checklist)
+	if err != nil {
+		return nil, status_codes.ChecklistFailure, errors.Join(errors.New("failed to save checklist"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityChecklist,
+		EntityID:   checklist.ID,
+		Action:     entities.ActivityCreated,
+		Changes:    activityChanges{}.set("name", nil, checklist.Name),
+	})
+
+	return &checklist, status_codes.ChecklistSuccess, nil
+}
+
+// UpdateChecklist renames the checklist
+func (c ChecklistUseCases) UpdateChecklist(
+	ctx context.Context,
+	user *entities.User,
+	checklist entities.Checklist,
+) (status_codes.ChecklistStatusCode, error) {
+	current, task, statusCode, err := c.getChecklist(ctx, user, checklist.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	checklist.Name = strings.TrimSpace(checklist.Name)
+	if !rules.ValidateTitle(checklist.Name) {
+		return status_codes.ChecklistInvalidName, nil
+	}
+
+	err = c.repository.UpdateChecklist(ctx, &checklist)
+	if err != nil {
+		return status_codes.ChecklistFailure, errors.Join(errors.New("failed to update checklist"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityChecklist,
+		EntityID:   current.ID,
+		Action:     entities.ActivityUpdated,
+		Changes:    activityChanges{}.set("name", current.Name, checklist.Name),
+	})
+
+	return status_codes.ChecklistSuccess, nil
+}
+
+// MoveChecklist places the checklist at the given position among the checklists of its task
+func (c ChecklistUseCases) MoveChecklist(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	position int,
+) (status_codes.ChecklistStatusCode, error) {
+	current, task, statusCode, err := c.getChecklist(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	position = max(position, 0)
+
+	err = c.repository.MoveChecklist(ctx, id, position)
+	if err != nil {
+		return status_codes.ChecklistFailure, errors.Join(errors.New("failed to move checklist"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityChecklist,
+		EntityID:   current.ID,
+		Action:     entities.ActivityMoved,
+		Changes:    activityChanges{}.set("position", current.Position, position),
+	})
+
+	return status_codes.ChecklistSuccess, nil
+}
+
+// DeleteChecklist deletes the checklist along with its items. Subtasks promoted from the items are kept.
+func (c ChecklistUseCases) DeleteChecklist(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.ChecklistStatusCode, error) {
+	current, task, statusCode, err := c.getChecklist(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	err = c.repository.DeleteChecklist(ctx, id)
+	if err != nil {
+		return status_codes.ChecklistFailure, errors.Join(errors.New("failed to delete checklist"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityChecklist,
+		EntityID:   current.ID,
+		Action:     entities.ActivityDeleted,
+		Changes:    activityChanges{}.set("name", current.Name, nil),
+	})
+
+	return status_codes.ChecklistSuccess, nil
+}
+
+// CreateItem adds the item at the end of its checklist. The assignee, if any, must be a member of the board.
+func (c ChecklistUseCases) CreateItem(
+	ctx context.Context,
+	user *entities.User,
+	item entities.ChecklistItem,
+) (*entities.ChecklistItem, status_codes.ChecklistStatusCode, error) {
+	checklist, task, statusCode, err := c.getChecklist(ctx, user, item.IDChecklist)
+	if checklist == nil {
+		return nil, statusCode, err
+	}
+
+	item.Text = strings.TrimSpace(item.Text)
+	if !rules.ValidateChecklistItem(item.Text) {
+		return nil, status_codes.ChecklistInvalidText, nil
+	}
+
+	statusCode, err = c.checkAssignee(ctx, task.IDBoard, item.Assignee)
+	if statusCode != status_codes.ChecklistSuccess {
+		return nil, statusCode, err
+	}
+
+	itemUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.ChecklistFailure, errors.Join(errors.New("failed to generate item UUID"), err)
+	}
+
+	item.UUID = itemUUID.String()
+	item.CreatedBy = *user
+
+	err = c.repository.AddItem(ctx, &item)
+	if err != nil {
+		return nil, status_codes.ChecklistFailure, errors.Join(errors.New("failed to save item"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityChecklistItem,
+		EntityID:   item.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("text", nil, item.Text).
+			set("id_checklist", nil, item.IDChecklist).
+			set("assignee", nil, itemAssigneeChange(item.Assignee)).
+			set("due_date", nil, dueDateChange(item.DueDate)),
+	})
+
+	// Returns the assignee details along with the saved item
+	created, err := c.repository.GetItemByID(ctx, item.ID)
+	if err != nil {
+		return nil, status_codes.ChecklistFailure, errors.Join(errors.New("failed to get item"), err)
+	}
+
+	return created, status_codes.ChecklistSuccess, nil
+}
+
+// UpdateItem updates the text, check, assignee and due date of the item. The check of a promoted item follows the
+// completion of its subtask, so it can't be changed here.
+func (c ChecklistUseCases) UpdateItem(
+	ctx context.Context,
+	user *entities.User,
+	item entities.ChecklistItem,
+) (status_codes.ChecklistStatusCode, error) {
+	current, task, statusCode, err := c.getItem(ctx, user, item.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	item.Text = strings.TrimSpace(item.Text)
+	if !rules.ValidateChecklistItem(item.Text) {
+		return status_codes.ChecklistInvalidText, nil
+	}
+
+	statusCode, err = c.checkAssignee(ctx, task.IDBoard, item.Assignee)
+	if statusCode != status_codes.ChecklistSuccess {
+		return statusCode, err
+	}
+
+	if current.IDSubtask != 0 {
+		item.Checked = current.Checked
+	}
+
+	// Keeps the original check time of an item that stays checked
+	item.CheckedAt = nil
+	if item.Checked {
+		now := time.Now()
+		item.CheckedAt = &now
+		if current.CheckedAt != nil {
+			item.CheckedAt = current.CheckedAt
+		}
+	}
+
+	err = c.repository.UpdateItem(ctx, &item)
+	if err != nil {
+		return status_codes.ChecklistFailure, errors.Join(errors.New("failed to update item"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityChecklistItem,
+		EntityID:   current.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set("text", current.Text, item.Text).
+			set("checked", current.Checked, item.Checked).
+			set("assignee", itemAssigneeChange(current.Assignee), itemAssigneeChange(item.Assignee)).
+			set("due_date", dueDateChange(current.DueDate), dueDateChange(item.DueDate)),
+	})
+
+	return status_codes.ChecklistSuccess, nil
+}
+
+// MoveItem places the item at the given position of a checklist, which must belong to the same task
+func (c ChecklistUseCases) MoveItem(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	checklistID int,
+	position int,
+) (status_codes.ChecklistStatusCode, error) {
+	current, task, statusCode, err := c.getItem(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	checklist, err := c.repository.GetChecklistByID(ctx, checklistID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return status_codes.ChecklistNotFound, nil
+		}
+
+		return status_codes.ChecklistFailure, errors.Join(errors.New("failed to get checklist"), err)
+	}
+
+	if checklist.IDTask != task.ID {
+		return status_codes.ChecklistNotFound, nil
+	}
+
+	position = max(position, 0)
+
+	err = c.repository.MoveItem(ctx, id, checklistID, position)
+	if err != nil {
+		return status_codes.ChecklistFailure, errors.Join(errors.New("failed to move item"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityChecklistItem,
+		EntityID:   current.ID,
+		Action:     entities.ActivityMoved,
+		Changes: activityChanges{}.
+			set("id_checklist", current.IDChecklist, checklistID).
+			set("position", current.Position, position),
+	})
+
+	return status_codes.ChecklistSuccess, nil
+}
+
+// DeleteItem deletes the item. A subtask promoted from it is kept.
+func (c ChecklistUseCases) DeleteItem(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.ChecklistStatusCode, error) {
+	current, task, statusCode, err := c.getItem(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	err = c.repository.DeleteItem(ctx, id)
+	if err != nil {
+		return status_codes.ChecklistFailure, errors.Join(errors.New("failed to delete item"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityChecklistItem,
+		EntityID:   current.ID,
+		Action:     entities.ActivityDeleted,
+		Changes:    activityChanges{}.set("text", current.Text, nil),
+	})
+
+	return status_codes.ChecklistSuccess, nil
+}
+
+// PromoteItem turns the item into a subtask of its task, created at the end of the given task list or of the list of
+// the parent task when none is given. The subtask takes the due date, assignee and check of the item, and the item
+// gets checked once the subtask is finished. A task list having reached its WIP limit rejects the subtask, unless the
+// board only warns about it, the violation being set on the subtask.
+func (c ChecklistUseCases) PromoteItem(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	taskListID int,
+) (*entities.Task, status_codes.ChecklistStatusCode, error) {
+	item, task, statusCode, err := c.getItem(ctx, user, id)
+	if item == nil {
+		return nil, statusCode, err
+	}
+
+	if item.IDSubtask != 0 {
+		return nil, status_codes.ChecklistItemAlreadyPromoted, nil
+	}
+
+	if taskListID == 0 {
+		taskListID = task.IDTaskList
+	}
+
+	taskList, err := c.boardRepository.GetTaskListByID(ctx, taskListID)
+	if err != nil && !errors.Is(err, entities.ErrNotFound) {
+		return nil, status_codes.ChecklistFailure, errors.Join(errors.New("failed to get task list"), err)
+	}
+
+	if taskList == nil || taskList.IDBoard != task.IDBoard {
+		return nil, status_codes.ChecklistTaskListNotFound, nil
+	}
+
+	violation, rejected, err := checkTaskListWIPLimit(ctx, c.boardRepository, taskList, 1)
+	if err != nil {
+		return nil, status_codes.ChecklistFailure, err
+	}
+
+	if rejected {
+		return nil, status_codes.ChecklistWIPLimitReached, nil
+	}
+
+	subtaskUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.ChecklistFailure, errors.Join(errors.New("failed to generate task UUID"), err)
+	}
+
+	// Long items keep their full text in the description of the subtask
+	name, description := item.Text, ""
+	if letters := []rune(name); len(letters) > rules.TaskNameMaxLetters {
+		name, description = strings.TrimSpace(string(letters[:rules.TaskNameMaxLetters])), item.Text
+	}
+
+	subtask := entities.Task{
+		UUID:        subtaskUUID.String(),
+		IDTaskList:  taskList.ID,
+		IDBoard:     task.IDBoard,
+		Name:        name,
+		Description: description,
+		CreatedBy:   *user,
+		Status:      entities.TaskNotFinished,
+		DueDate:     item.DueDate,
+		IDParent:    task.ID,
+		Assignees:   make([]entities.User, 0),
+		Attachments: make([]entities.Attachment, 0),
+	}
+
+	subtask.WIPViolation = violation
+	if item.Checked {
+		subtask.Status = entities.TaskFinished
+	}
+
+	if item.Assignee != nil {
+		subtask.Assignees = append(subtask.Assignees, *item.Assignee)
+	}
+
+	promoted, err := c.repository.PromoteItem(ctx, item.ID, &subtask)
+	if err != nil {
+		return nil, status_codes.ChecklistFailure, errors.Join(errors.New("failed to promote item"), err)
+	}
+
+	if !promoted {
+		return nil, status_codes.ChecklistItemAlreadyPromoted, nil
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    subtask.IDBoard,
+		IDTask:     subtask.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTask,
+		EntityID:   subtask.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("name", nil, subtask.Name).
+			set("description", nil, subtask.Description).
+			set("id_task_list", nil, subtask.IDTaskList).
+			set("id_parent", nil, subtask.IDParent).
+			set("due_date", nil, dueDateChange(subtask.DueDate)),
+	})
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityChecklistItem,
+		EntityID:   item.ID,
+		Action:     entities.ActivityUpdated,
+		Changes:    activityChanges{}.set("id_subtask", nil, subtask.ID),
+	})
+
+	return &subtask, status_codes.ChecklistSuccess, nil
+}
+
+// getTask returns the task if the user is a member of its board. A nil task is returned along with the status code or
+// error to send back otherwise.
+func (c ChecklistUseCases) getTask(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Task, status_codes.ChecklistStatusCode, error) {
+	task, err := c.boardRepository.GetTaskByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.ChecklistTaskNotFound, nil
+		}
+
+		return nil, status_codes.ChecklistFailure, errors.Join(errors.New("failed to get task"), err)
+	}
+
+	err = checkBoardMember(ctx, c.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, status_codes.ChecklistFailure, err
+	}
+
+	return task, status_codes.ChecklistSuccess, nil
+}
+
+// getChecklist is the same as getTask, for the checklist and its task
+func (c ChecklistUseCases) getChecklist(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Checklist, *entities.Task, status_codes.ChecklistStatusCode, error) {
+	checklist, err := c.repository.GetChecklistByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, nil, status_codes.ChecklistNotFound, nil
+		}
+
+		return nil, nil, status_codes.ChecklistFailure, errors.Join(errors.New("failed to get checklist"), err)
+	}
+
+	task, statusCode, err := c.getTask(ctx, user, checklist.IDTask)
+	if task == nil {
+		return nil, nil, statusCode, err
+	}
+
+	return checklist, task, status_codes.ChecklistSuccess, nil
+}
+
+// getItem is the same as getTask, for the checklist item and its task
+func (c ChecklistUseCases) getItem(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.ChecklistItem, *entities.Task, status_codes.ChecklistStatusCode, error) {
+	item, err := c.repository.GetItemByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, nil, status_codes.ChecklistItemNotFound, nil
+		}
+
+		return nil, nil, status_codes.ChecklistFailure, errors.Join(errors.New("failed to get item"), err)
+	}
+
+	checklist, task, statusCode, err := c.getChecklist(ctx, user, item.IDChecklist)
+	if checklist == nil {
+		return nil, nil, statusCode, err
+	}
+
+	return item, task, status_codes.ChecklistSuccess, nil
+}
+
+// checkAssignee checks that the item assignee, if any, is a member of the board
+func (c ChecklistUseCases) checkAssignee(
+	ctx context.Context,
+	boardID int,
+	assignee *entities.User,
+) (status_codes.ChecklistStatusCode, error) {
+	if assignee == nil {
+		return status_codes.ChecklistSuccess, nil
+	}
+
+	isMember, err := c.boardRepository.IsBoardMember(ctx, boardID, assignee.ID)
+	if err != nil {
+		return status_codes.ChecklistFailure, errors.Join(errors.New("failed to check board membership"), err)
+	}
+
+	if !isMember {
+		return status_codes.ChecklistAssigneeNotMember, nil
+	}
+
+	return status_codes.ChecklistSuccess, nil
+}
+
+// itemAssigneeChange returns the assignee of a checklist item as recorded in activity changes
+func itemAssigneeChange(assignee *entities.User) any {
+	if assignee == nil {
+		return nil
+	}
+
+	return assignee.ID
+}
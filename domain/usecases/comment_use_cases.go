@@ -0,0 +1,198 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/datastore"
+
+	"github.com/google/uuid"
+)
+
+type CommentUseCases struct {
+	repository      datastore.CommentRepository
+	boardRepository datastore.BoardRepository
+	activity        ActivityUseCases
+}
+
+func NewCommentUseCases(
+	repository datastore.CommentRepository,
+	boardRepository datastore.BoardRepository,
+	activity ActivityUseCases,
+) CommentUseCases {
+	return CommentUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		activity:        activity,
+	}
+}
+
+// GetComments returns a page of the comments of the task posted after the one with the given ID, oldest first
+func (c CommentUseCases) GetComments(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	afterID int,
+	limit int,
+) ([]entities.Comment, error) {
+	task, err := c.boardRepository.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, c.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.repository.GetCommentsByTask(ctx, taskID, afterID, rules.PageLimit(limit))
+}
+
+func (c CommentUseCases) CreateComment(
+	ctx context.Context,
+	user *entities.User,
+	comment entities.Comment,
+) (*entities.Comment, status_codes.CommentStatusCode, error) {
+	task, err := c.boardRepository.GetTaskByID(ctx, comment.IDTask)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.CommentTaskNotFound, nil
+		}
+
+		return nil, status_codes.CommentFailure, errors.Join(errors.New("failed to get task"), err)
+	}
+
+	err = checkBoardMember(ctx, c.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, status_codes.CommentFailure, err
+	}
+
+	comment.Body = strings.TrimSpace(comment.Body)
+	if !rules.ValidateComment(comment.Body) {
+		return nil, status_codes.CommentInvalidBody, nil
+	}
+
+	commentUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.CommentFailure, errors.Join(errors.New("failed to generate comment UUID"), err)
+	}
+
+	comment.UUID = commentUUID.String()
+	comment.CreatedBy = *user
+
+	err = c.repository.AddComment(ctx, &comment)
+	if err != nil {
+		return nil, status_codes.CommentFailure, errors.Join(errors.New("failed to save comment"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityComment,
+		EntityID:   comment.ID,
+		Action:     entities.ActivityCreated,
+		Changes:    activityChanges{}.set("body", nil, comment.Body),
+	})
+
+	return &comment, status_codes.CommentSuccess, nil
+}
+
+// UpdateComment updates the body of a comment. Only the comment author can update it.
+func (c CommentUseCases) UpdateComment(
+	ctx context.Context,
+	user *entities.User,
+	comment entities.Comment,
+) (status_codes.CommentStatusCode, error) {
+	current, task, statusCode, err := c.getAuthoredComment(ctx, user, comment.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	comment.Body = strings.TrimSpace(comment.Body)
+	if !rules.ValidateComment(comment.Body) {
+		return status_codes.CommentInvalidBody, nil
+	}
+
+	err = c.repository.UpdateComment(ctx, &comment)
+	if err != nil {
+		return status_codes.CommentFailure, errors.Join(errors.New("failed to update comment"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityComment,
+		EntityID:   current.ID,
+		Action:     entities.ActivityUpdated,
+		Changes:    activityChanges{}.set("body", current.Body, comment.Body),
+	})
+
+	return status_codes.CommentSuccess, nil
+}
+
+// DeleteComment deletes a comment. Only the comment author can delete it.
+func (c CommentUseCases) DeleteComment(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.CommentStatusCode, error) {
+	current, task, statusCode, err := c.getAuthoredComment(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	err = c.repository.DeleteComment(ctx, id)
+	if err != nil {
+		return status_codes.CommentFailure, errors.Join(errors.New("failed to delete comment"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityComment,
+		EntityID:   current.ID,
+		Action:     entities.ActivityDeleted,
+		Changes:    activityChanges{}.set("body", current.Body, nil),
+	})
+
+	return status_codes.CommentSuccess, nil
+}
+
+// getAuthoredComment returns the comment and its task if the user wrote it and is still a member of the board. A nil
+// comment is returned along with the status code or error to send back otherwise.
+func (c CommentUseCases) getAuthoredComment(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Comment, *entities.Task, status_codes.CommentStatusCode, error) {
+	comment, err := c.repository.GetCommentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, nil, status_codes.CommentNotFound, nil
+		}
+
+		return nil, nil, status_codes.CommentFailure, errors.Join(errors.New("failed to get comment"), err)
+	}
+
+	task, err := c.boardRepository.GetTaskByID(ctx, comment.IDTask)
+	if err != nil {
+		return nil, nil, status_codes.CommentFailure, errors.Join(errors.New("failed to get comment task"), err)
+	}
+
+	err = checkBoardMember(ctx, c.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, nil, status_codes.CommentFailure, err
+	}
+
+	if comment.CreatedBy.ID != user.ID {
+		return nil, nil, status_codes.CommentFailure, entities.ErrForbidden
+	}
+
+	return comment, task, status_codes.CommentSuccess, nil
+}
@@ -0,0 +1,678 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/datastore"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// utf8BOM starts the exported files, for spreadsheets to read them as UTF-8
+const utf8BOM = "\uFEFF"
+
+// csvFormulaPrefixes are the first characters spreadsheets read as a formula, which exported cells are escaped from
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvColumns are the task columns in export order, along with their headers. Custom fields follow under their name.
+var csvColumns = []struct {
+	target string
+	header string
+}{
+	{target: entities.CSVColumnList, header: "List"},
+	{target: entities.CSVColumnName, header: "Name"},
+	{target: entities.CSVColumnDescription, header: "Description"},
+	{target: entities.CSVColumnAssignees, header: "Assignees"},
+	{target: entities.CSVColumnLabels, header: "Labels"},
+	{target: entities.CSVColumnDueDate, header: "Due date"},
+	{target: entities.CSVColumnStatus, header: "Status"},
+	{target: entities.CSVColumnPriority, header: "Priority"},
+}
+
+// csvPriorities are the names of the task priorities, indexed by priority
+var csvPriorities = []string{"none", "low", "medium", "high", "urgent"}
+
+const (
+	csvFinished   = "finished"
+	csvUnfinished = "unfinished"
+)
+
+type CSVUseCases struct {
+	boards          BoardUseCases
+	boardRepository datastore.BoardRepository
+	activity        ActivityUseCases
+}
+
+func NewCSVUseCases(
+	boards BoardUseCases,
+	boardRepository datastore.BoardRepository,
+	activity ActivityUseCases,
+) CSVUseCases {
+	return CSVUseCases{
+		boards:          boards,
+		boardRepository: boardRepository,
+		activity:        activity,
+	}
+}
+
+// ExportTasks returns the tasks of the board as CSV, one row per task with its list, assignee emails, labels, due date,
+// status, priority and custom field values. Only the active tasks are exported, unless archived is set.
+func (c CSVUseCases) ExportTasks(ctx context.Context, user *entities.User, boardID int, archived bool) ([]byte, error) {
+	board, err := c.boards.GetBoard(ctx, user, boardID, archived)
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make(map[int]string, len(board.Users)+1)
+	emails[board.CreatedBy.ID] = board.CreatedBy.Email
+	for _, member := range board.Users {
+		emails[member.ID] = member.Email
+	}
+
+	header := make([]string, 0, len(csvColumns)+len(board.CustomFields))
+	for _, column := range csvColumns {
+		header = append(header, column.header)
+	}
+	for _, field := range board.CustomFields {
+		header = append(header, escapeCSVCell(field.Name))
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(utf8BOM)
+
+	writer := csv.NewWriter(&buffer)
+	writer.UseCRLF = true
+
+	err = writer.Write(header)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to write CSV header"), err)
+	}
+
+	for _, taskList := range board.TaskLists {
+		for _, task := range taskList.Tasks {
+			err = writer.Write(csvTaskRow(board, taskList, task, emails))
+			if err != nil {
+				return nil, errors.Join(errors.New("failed to write CSV row"), err)
+			}
+		}
+	}
+
+	writer.Flush()
+	err = writer.Error()
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to write CSV"), err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// ImportTasks creates a task in the active list named in each row of the CSV file, the columns being mapped as the
+// options tell. Rows are validated one by one, the report holding the errors of the invalid rows. The tasks of the
+// valid rows are all created in a single transaction, unless all or nothing is asked and any row is invalid. The rows
+// taking a task list over its WIP limit are invalid, unless the board only warns about it, the report telling the
+// lists over their limit then.
+func (c CSVUseCases) ImportTasks(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+	data []byte,
+	options entities.CSVImportOptions,
+) (*entities.CSVImportReport, status_codes.CSVStatusCode, error) {
+	board, err := c.boards.GetBoard(ctx, user, boardID, false)
+	if err != nil {
+		return nil, status_codes.CSVFailure, err
+	}
+
+	data = bytes.TrimPrefix(data, []byte(utf8BOM))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = csvDelimiter(data)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, status_codes.CSVInvalidFile, nil
+	}
+
+	if len(records)-1 > rules.CSVImportMaxRows {
+		return nil, status_codes.CSVTooManyRows, nil
+	}
+
+	columns, ok := csvImportColumns(records[0], options.Mapping, board.CustomFields)
+	if !ok {
+		return nil, status_codes.CSVInvalidMapping, nil
+	}
+
+	lookup := newCSVLookup(board)
+	report := entities.CSVImportReport{Errors: make([]entities.CSVRowError, 0)}
+	tasks := make([]entities.Task, 0, len(records)-1)
+	added := make(map[int]int)
+	violations := make(map[int]entities.WIPViolation)
+	for i, record := range records[1:] {
+		if !slices.ContainsFunc(record, func(cell string) bool { return strings.TrimSpace(cell) != "" }) {
+			continue
+		}
+
+		report.Rows++
+
+		task, rowErrors := lookup.task(record, columns)
+		for j := range rowErrors {
+			rowErrors[j].Row = i + 2
+		}
+
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, rowErrors...)
+			continue
+		}
+
+		index := slices.IndexFunc(board.TaskLists, func(taskList entities.TaskList) bool {
+			return taskList.ID == task.IDTaskList
+		})
+		if index >= 0 {
+			taskList := board.TaskLists[index]
+			violation := wipViolation(taskList, added[taskList.ID]+1)
+			if violation != nil && !board.WIPWarnOnly {
+				report.Errors = append(report.Errors, entities.CSVRowError{
+					Row:     i + 2,
+					Message: fmt.Sprintf("the task list %q has reached its WIP limit", taskList.Name),
+				})
+				continue
+			}
+
+			if violation != nil {
+				violations[taskList.ID] = *violation
+			}
+		}
+
+		added[task.IDTaskList]++
+
+		taskUUID, err := uuid.NewRandom()
+		if err != nil {
+			return nil, status_codes.CSVFailure, errors.Join(errors.New("failed to generate task UUID"), err)
+		}
+
+		task.UUID = taskUUID.String()
+		task.IDBoard = board.ID
+		task.CreatedBy = *user
+		tasks = append(tasks, task)
+	}
+
+	if len(report.Errors) > 0 && options.AllOrNothing {
+		return &report, status_codes.CSVInvalidRows, nil
+	}
+
+	if len(tasks) > 0 {
+		err = c.boardRepository.AddTasks(ctx, tasks)
+		if err != nil {
+			return nil, status_codes.CSVFailure, errors.Join(errors.New("failed to save tasks"), err)
+		}
+	}
+
+	report.Created = len(tasks)
+
+	for _, taskList := range board.TaskLists {
+		if violation, found := violations[taskList.ID]; found {
+			report.WIPViolations = append(report.WIPViolations, violation)
+		}
+	}
+
+	for _, task := range tasks {
+		c.activity.Record(ctx, entities.Activity{
+			IDBoard:    task.IDBoard,
+			IDTask:     task.ID,
+			Actor:      *user,
+			EntityType: entities.ActivityEntityTask,
+			EntityID:   task.ID,
+			Action:     entities.ActivityCreated,
+			Changes: activityChanges{}.
+				set("name", nil, task.Name).
+				set("description", nil, task.Description).
+				set("id_task_list", nil, task.IDTaskList).
+				set("priority", nil, task.Priority).
+				set("due_date", nil, dueDateChange(task.DueDate)),
+		})
+	}
+
+	return &report, status_codes.CSVSuccess, nil
+}
+
+// csvTaskRow returns the cells of the task, emails holding the emails of the board users by ID
+func csvTaskRow(board *entities.Board, taskList entities.TaskList, task entities.Task, emails map[int]string) []string {
+	assignees := make([]string, 0, len(task.Assignees))
+	for _, assignee := range task.Assignees {
+		assignees = append(assignees, assignee.Email)
+	}
+
+	labels := make([]string, 0, len(task.Labels))
+	for _, label := range task.Labels {
+		labels = append(labels, label.Name)
+	}
+
+	var dueDate string
+	if task.DueDate != nil {
+		dueDate = task.DueDate.UTC().Format(time.DateTime)
+	}
+
+	status := csvUnfinished
+	if task.Status == entities.TaskFinished {
+		status = csvFinished
+	}
+
+	var priority string
+	if int(task.Priority) < len(csvPriorities) {
+		priority = csvPriorities[task.Priority]
+	}
+
+	row := []string{
+		escapeCSVCell(taskList.Name),
+		escapeCSVCell(task.Name),
+		escapeCSVCell(task.Description),
+		escapeCSVCell(strings.Join(assignees, rules.CSVListSeparator)),
+		escapeCSVCell(strings.Join(labels, rules.CSVListSeparator)),
+		dueDate,
+		status,
+		priority,
+	}
+
+	for _, field := range board.CustomFields {
+		i := slices.IndexFunc(task.Fields, func(value entities.CustomFieldValue) bool {
+			return value.IDField == field.ID
+		})
+		if i < 0 {
+			row = append(row, "")
+			continue
+		}
+
+		row = append(row, csvFieldCell(field, task.Fields[i], emails))
+	}
+
+	return row
+}
+
+// csvFieldCell returns the value as exported: select values by option name and user values by email
+func csvFieldCell(field entities.CustomField, value entities.CustomFieldValue, emails map[int]string) string {
+	switch {
+	case value.Text != nil:
+		return escapeCSVCell(*value.Text)
+	case value.Number != nil:
+		return strconv.FormatFloat(*value.Number, 'f', -1, 64)
+	case value.Date != nil:
+		return value.Date.Format(time.DateOnly)
+	case value.Options != nil:
+		names := make([]string, 0, len(value.Options))
+		for _, optionID := range value.Options {
+			i := slices.IndexFunc(field.Options, sameOption(optionID))
+			if i >= 0 {
+				names = append(names, field.Options[i].Name)
+			}
+		}
+
+		return escapeCSVCell(strings.Join(names, rules.CSVListSeparator))
+	case value.Checked != nil:
+		return strconv.FormatBool(*value.Checked)
+	case value.IDUser != 0:
+		return escapeCSVCell(emails[value.IDUser])
+	default:
+		return ""
+	}
+}
+
+// escapeCSVCell prefixes the cells starting like a formula with a quote, for spreadsheets to show them as text
+func escapeCSVCell(cell string) string {
+	if cell != "" && strings.ContainsRune(csvFormulaPrefixes, rune(cell[0])) {
+		return "'" + cell
+	}
+
+	return cell
+}
+
+// unescapeCSVCell removes the quote escapeCSVCell prefixes cells with
+func unescapeCSVCell(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(cell[1])) {
+		return cell[1:]
+	}
+
+	return cell
+}
+
+// csvDelimiter returns the most frequent of the comma, semicolon and tab on the header line, spreadsheets using the
+// semicolon in locales where the comma is the decimal separator
+func csvDelimiter(data []byte) rune {
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+
+	delimiter := ','
+	for _, candidate := range []rune{';', '\t'} {
+		if bytes.Count(header, []byte(string(candidate))) > bytes.Count(header, []byte(string(delimiter))) {
+			delimiter = candidate
+		}
+	}
+
+	return delimiter
+}
+
+// csvImportColumn is a column of an imported file along with its target, an empty target ignoring the column
+type csvImportColumn struct {
+	header string
+	target string
+}
+
+// csvImportColumns returns the columns of the header, mapped as the mapping tells or matched by header otherwise. It
+// returns false if the mapping refers to missing columns or unknown targets, if two columns have the same target, or
+// if the list or name column is missing.
+func csvImportColumns(
+	header []string,
+	mapping map[string]string,
+	fields []entities.CustomField,
+) ([]csvImportColumn, bool) {
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	for mapped := range mapping {
+		if !slices.Contains(header, mapped) {
+			return nil, false
+		}
+	}
+
+	targets := make(map[string]bool)
+	for _, column := range csvColumns {
+		targets[column.target] = true
+	}
+	for _, field := range fields {
+		targets[entities.CSVColumnFieldPrefix+strconv.Itoa(field.ID)] = true
+	}
+
+	columns := make([]csvImportColumn, len(header))
+	used := make(map[string]bool)
+	for i, name := range header {
+		target, mapped := mapping[name]
+		if !mapped {
+			target = defaultCSVTarget(name, fields, used)
+		}
+
+		if target == "" {
+			continue
+		}
+
+		if !targets[target] || used[target] {
+			return nil, false
+		}
+
+		used[target] = true
+		columns[i] = csvImportColumn{header: name, target: target}
+	}
+
+	return columns, used[entities.CSVColumnList] && used[entities.CSVColumnName]
+}
+
+// defaultCSVTarget returns the target of the column matching the header, as exported, among the targets not used yet.
+// Task columns come before custom fields of the same name.
+func defaultCSVTarget(header string, fields []entities.CustomField, used map[string]bool) string {
+	normalized := strings.ReplaceAll(strings.ToLower(header), " ", "_")
+	for _, column := range csvColumns {
+		if column.target == normalized && !used[column.target] {
+			return column.target
+		}
+	}
+
+	for _, field := range fields {
+		target := entities.CSVColumnFieldPrefix + strconv.Itoa(field.ID)
+		if strings.EqualFold(field.Name, header) && !used[target] {
+			return target
+		}
+	}
+
+	return ""
+}
+
+// csvLookup finds the task lists, members, labels and custom fields of the board the imported cells refer to. Names
+// and emails are matched regardless of case.
+type csvLookup struct {
+	taskLists map[string]int
+	members   map[string]entities.User
+	labels    map[string]entities.Label
+	fields    map[string]entities.CustomField
+}
+
+func newCSVLookup(board *entities.Board) csvLookup {
+	lookup := csvLookup{
+		taskLists: make(map[string]int),
+		members:   make(map[string]entities.User),
+		labels:    make(map[string]entities.Label),
+		fields:    make(map[string]entities.CustomField),
+	}
+
+	for _, taskList := range board.TaskLists {
+		name := strings.ToLower(taskList.Name)
+		if _, found := lookup.taskLists[name]; !found {
+			lookup.taskLists[name] = taskList.ID
+		}
+	}
+
+	for _, member := range append([]entities.User{board.CreatedBy}, board.Users...) {
+		lookup.members[strings.ToLower(member.Email)] = member
+	}
+
+	for _, label := range board.Labels {
+		lookup.labels[strings.ToLower(label.Name)] = label
+	}
+
+	for _, field := range board.CustomFields {
+		lookup.fields[entities.CSVColumnFieldPrefix+strconv.Itoa(field.ID)] = field
+	}
+
+	return lookup
+}
+
+// task returns the task of the record, or the errors of its invalid cells
+func (l csvLookup) task(record []string, columns []csvImportColumn) (entities.Task, []entities.CSVRowError) {
+	task := entities.Task{
+		Assignees:   make([]entities.User, 0),
+		Labels:      make([]entities.Label, 0),
+		Attachments: make([]entities.Attachment, 0),
+		Fields:      make([]entities.CustomFieldValue, 0),
+	}
+
+	var rowErrors []entities.CSVRowError
+	fail := func(column csvImportColumn, format string, args ...any) {
+		rowErrors = append(rowErrors, entities.CSVRowError{
+			Column:  column.header,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for i, column := range columns {
+		if column.target == "" {
+			continue
+		}
+
+		var cell string
+		if i < len(record) {
+			cell = unescapeCSVCell(strings.TrimSpace(record[i]))
+		}
+
+		switch column.target {
+		case entities.CSVColumnList:
+			var found bool
+			task.IDTaskList, found = l.taskLists[strings.ToLower(cell)]
+			if !found {
+				fail(column, "unknown task list %q", cell)
+			}
+		case entities.CSVColumnName:
+			task.Name = cell
+			if !rules.ValidateTitle(task.Name) {
+				fail(column, "the name must have between 1 and %d letters", rules.TaskNameMaxLetters)
+			}
+		case entities.CSVColumnDescription:
+			task.Description = cell
+		case entities.CSVColumnAssignees:
+			for _, email := range splitCSVList(cell) {
+				member, found := l.members[strings.ToLower(email)]
+				if !found {
+					fail(column, "%q is not a member of the board", email)
+					continue
+				}
+
+				task.Assignees = append(task.Assignees, member)
+			}
+		case entities.CSVColumnLabels:
+			for _, name := range splitCSVList(cell) {
+				label, found := l.labels[strings.ToLower(name)]
+				if !found {
+					fail(column, "unknown label %q", name)
+					continue
+				}
+
+				task.Labels = append(task.Labels, label)
+			}
+		case entities.CSVColumnDueDate:
+			if cell == "" {
+				continue
+			}
+
+			dueDate, ok := parseCSVTime(cell)
+			if !ok {
+				fail(column, "invalid due date %q", cell)
+				continue
+			}
+
+			task.DueDate = &dueDate
+		case entities.CSVColumnStatus:
+			switch strings.ToLower(cell) {
+			case "", csvUnfinished:
+				task.Status = entities.TaskNotFinished
+			case csvFinished:
+				task.Status = entities.TaskFinished
+			default:
+				fail(column, "invalid status %q", cell)
+			}
+		case entities.CSVColumnPriority:
+			priority, ok := parseCSVPriority(cell)
+			if !ok {
+				fail(column, "invalid priority %q", cell)
+				continue
+			}
+
+			task.Priority = priority
+		default:
+			if cell == "" {
+				continue
+			}
+
+			field := l.fields[column.target]
+			value, ok := l.fieldValue(field, cell)
+			if !ok || !rules.ValidateCustomFieldValue(field, value) {
+				fail(column, "invalid %s value %q", field.Type, cell)
+				continue
+			}
+
+			task.Fields = append(task.Fields, value)
+		}
+	}
+
+	return task, rowErrors
+}
+
+// fieldValue parses the cell as exported, returning false if it cannot be parsed
+func (l csvLookup) fieldValue(field entities.CustomField, cell string) (entities.CustomFieldValue, bool) {
+	value := entities.CustomFieldValue{IDField: field.ID}
+
+	switch field.Type {
+	case entities.CustomFieldText:
+		value.Text = &cell
+	case entities.CustomFieldNumber:
+		number, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return value, false
+		}
+		value.Number = &number
+	case entities.CustomFieldDate:
+		date, ok := parseCSVTime(cell)
+		if !ok {
+			return value, false
+		}
+		date = date.Truncate(24 * time.Hour)
+		value.Date = &date
+	case entities.CustomFieldSingleSelect, entities.CustomFieldMultiSelect:
+		value.Options = make([]string, 0)
+		for _, name := range splitCSVList(cell) {
+			i := slices.IndexFunc(field.Options, func(option entities.CustomFieldOption) bool {
+				return strings.EqualFold(option.Name, name)
+			})
+			if i < 0 {
+				return value, false
+			}
+			value.Options = append(value.Options, field.Options[i].ID)
+		}
+	case entities.CustomFieldCheckbox:
+		checked, err := strconv.ParseBool(cell)
+		if err != nil {
+			return value, false
+		}
+		value.Checked = &checked
+	case entities.CustomFieldUser:
+		member, found := l.members[strings.ToLower(cell)]
+		if !found {
+			return value, false
+		}
+		value.IDUser = member.ID
+	default:
+		return value, false
+	}
+
+	return value, true
+}
+
+// splitCSVList returns the non-empty items of the cell, separated by rules.CSVListSeparator
+func splitCSVList(cell string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(cell, rules.CSVListSeparator) {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// parseCSVTime parses a date and time as exported, an RFC 3339 time or a date, in UTC unless the time tells otherwise
+func parseCSVTime(cell string) (time.Time, bool) {
+	for _, layout := range []string{time.DateTime, time.RFC3339, time.DateOnly} {
+		parsed, err := time.Parse(layout, cell)
+		if err == nil {
+			return parsed.UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// parseCSVPriority parses a priority by name or number, no priority being set when the cell is empty
+func parseCSVPriority(cell string) (entities.TaskPriority, bool) {
+	if cell == "" {
+		return entities.TaskPriorityNone, true
+	}
+
+	i := slices.Index(csvPriorities, strings.ToLower(cell))
+	if i < 0 {
+		number, err := strconv.Atoi(cell)
+		if err != nil {
+			return 0, false
+		}
+		i = number
+	}
+
+	priority := entities.TaskPriority(i)
+	return priority, rules.ValidatePriority(priority)
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"taskflow/domain/entities"
+	"testing"
+)
+
+func TestCSVTaskRowEscapesCells(t *testing.T) {
+	text := "+1"
+	board := &entities.Board{
+		CustomFields: []entities.CustomField{
+			{ID: 1, Name: "Text", Type: entities.CustomFieldText},
+			{ID: 2, Name: "Owner", Type: entities.CustomFieldUser},
+		},
+	}
+	taskList := entities.TaskList{Name: "@list"}
+	task := entities.Task{
+		Name:        "=HYPERLINK(\"https://example.com\")",
+		Description: "-2+3",
+		Assignees:   []entities.User{{ID: 7, Email: "=cmd@example.com"}},
+		Fields:      []entities.CustomFieldValue{{IDField: 1, Text: &text}, {IDField: 2, IDUser: 7}},
+	}
+
+	row := csvTaskRow(board, taskList, task, map[int]string{7: "=cmd@example.com"})
+	cells := map[int]string{
+		0: "'@list",
+		1: "'=HYPERLINK(\"https://example.com\")",
+		2: "'-2+3",
+		3: "'=cmd@example.com",
+		8: "'+1",
+		9: "'=cmd@example.com",
+	}
+
+	for i, want := range cells {
+		if row[i] != want {
+			t.Errorf("cell %d = %q, want %q", i, row[i], want)
+		}
+
+		if got := unescapeCSVCell(row[i]); got != want[1:] {
+			t.Errorf("unescapeCSVCell(%q) = %q, want %q", row[i], got, want[1:])
+		}
+	}
+}
@@ -0,0 +1,509 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/datastore"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CustomFieldUseCases struct {
+	repository      datastore.CustomFieldRepository
+	boardRepository datastore.BoardRepository
+	activity        ActivityUseCases
+}
+
+func NewCustomFieldUseCases(
+	repository datastore.CustomFieldRepository,
+	boardRepository datastore.BoardRepository,
+	activity ActivityUseCases,
+) CustomFieldUseCases {
+	return CustomFieldUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		activity:        activity,
+	}
+}
+
+// GetFields returns the custom fields of the board, in order
+func (c CustomFieldUseCases) GetFields(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+) ([]entities.CustomField, error) {
+	board, err := c.boardRepository.GetBoardByID(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, c.boardRepository, board.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.repository.GetFieldsByBoard(ctx, board.ID)
+}
+
+// CreateField adds the custom field after the other fields of the board. Only the board owner can define fields.
+func (c CustomFieldUseCases) CreateField(
+	ctx context.Context,
+	user *entities.User,
+	field entities.CustomField,
+) (*entities.CustomField, status_codes.CustomFieldStatusCode, error) {
+	board, statusCode, err := c.getOwnedBoard(ctx, user, field.IDBoard)
+	if board == nil {
+		return nil, statusCode, err
+	}
+
+	field.Name = strings.TrimSpace(field.Name)
+	if !rules.ValidateTitle(field.Name) {
+		return nil, status_codes.CustomFieldInvalidName, nil
+	}
+
+	if !rules.ValidateCustomFieldType(field.Type) {
+		return nil, status_codes.CustomFieldInvalidType, nil
+	}
+
+	for i := range field.Options {
+		field.Options[i].ID = ""
+	}
+
+	field.Options, statusCode, err = c.withOptionIDs(field, nil)
+	if statusCode != status_codes.CustomFieldSuccess {
+		return nil, statusCode, err
+	}
+
+	fields, err := c.repository.GetFieldsByBoard(ctx, board.ID)
+	if err != nil {
+		return nil, status_codes.CustomFieldFailure, errors.Join(errors.New("failed to get custom fields"), err)
+	}
+
+	if len(fields) >= rules.CustomFieldMaxPerBoard {
+		return nil, status_codes.CustomFieldLimitReached, nil
+	}
+
+	if hasFieldNamed(fields, field.Name, 0) {
+		return nil, status_codes.CustomFieldNameAlreadyExist, nil
+	}
+
+	fieldUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.CustomFieldFailure, errors.Join(errors.New("failed to generate field UUID"), err)
+	}
+
+	field.UUID = fieldUUID.String()
+	field.CreatedBy = *user
+
+	err = c.repository.AddField(ctx, &field)
+	if err != nil {
+		return nil, status_codes.CustomFieldFailure, errors.Join(errors.New("failed to save custom field"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    board.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityCustomField,
+		EntityID:   field.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("name", nil, field.Name).
+			set("type", nil, string(field.Type)),
+	})
+
+	return &field, status_codes.CustomFieldSuccess, nil
+}
+
+// UpdateField renames the field and updates its options. Options are kept by ID: options without an ID are added and
+// the values of removed options are removed from the tasks. The type of a field can't change.
+func (c CustomFieldUseCases) UpdateField(
+	ctx context.Context,
+	user *entities.User,
+	field entities.CustomField,
+) (status_codes.CustomFieldStatusCode, error) {
+	current, statusCode, err := c.getOwnedField(ctx, user, field.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	field.Name = strings.TrimSpace(field.Name)
+	if !rules.ValidateTitle(field.Name) {
+		return status_codes.CustomFieldInvalidName, nil
+	}
+
+	field.Type = current.Type
+	field.Options, statusCode, err = c.withOptionIDs(field, current.Options)
+	if statusCode != status_codes.CustomFieldSuccess {
+		return statusCode, err
+	}
+
+	fields, err := c.repository.GetFieldsByBoard(ctx, current.IDBoard)
+	if err != nil {
+		return status_codes.CustomFieldFailure, errors.Join(errors.New("failed to get custom fields"), err)
+	}
+
+	if hasFieldNamed(fields, field.Name, current.ID) {
+		return status_codes.CustomFieldNameAlreadyExist, nil
+	}
+
+	removedOptions := make([]string, 0)
+	for _, option := range current.Options {
+		if !slices.ContainsFunc(field.Options, sameOption(option.ID)) {
+			removedOptions = append(removedOptions, option.ID)
+		}
+	}
+
+	err = c.repository.UpdateField(ctx, &field, removedOptions)
+	if err != nil {
+		return status_codes.CustomFieldFailure, errors.Join(errors.New("failed to update custom field"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityCustomField,
+		EntityID:   current.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set("name", current.Name, field.Name).
+			set("options", optionNames(current.Options), optionNames(field.Options)),
+	})
+
+	return status_codes.CustomFieldSuccess, nil
+}
+
+// DeleteField deletes the field along with its values
+func (c CustomFieldUseCases) DeleteField(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.CustomFieldStatusCode, error) {
+	current, statusCode, err := c.getOwnedField(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	err = c.repository.DeleteField(ctx, id)
+	if err != nil {
+		return status_codes.CustomFieldFailure, errors.Join(errors.New("failed to delete custom field"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityCustomField,
+		EntityID:   current.ID,
+		Action:     entities.ActivityDeleted,
+		Changes: activityChanges{}.
+			set("name", current.Name, nil).
+			set("type", string(current.Type), nil),
+	})
+
+	return status_codes.CustomFieldSuccess, nil
+}
+
+// SetFieldValue sets the value of a field of the board on the task. User values must be members of the board.
+func (c CustomFieldUseCases) SetFieldValue(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	value entities.CustomFieldValue,
+) (status_codes.CustomFieldStatusCode, error) {
+	task, field, statusCode, err := c.getTaskField(ctx, user, taskID, value.IDField)
+	if task == nil {
+		return statusCode, err
+	}
+
+	if value.Text != nil {
+		text := strings.TrimSpace(*value.Text)
+		value.Text = &text
+	}
+
+	if value.Date != nil {
+		date := value.Date.UTC()
+		value.Date = &date
+	}
+
+	if !rules.ValidateCustomFieldValue(*field, value) {
+		return status_codes.CustomFieldInvalidValue, nil
+	}
+
+	if field.Type == entities.CustomFieldUser {
+		isMember, err := c.boardRepository.IsBoardMember(ctx, task.IDBoard, value.IDUser)
+		if err != nil {
+			return status_codes.CustomFieldFailure, errors.Join(errors.New("failed to check board membership"), err)
+		}
+
+		if !isMember {
+			return status_codes.CustomFieldUserNotMember, nil
+		}
+	}
+
+	current, err := c.getValue(ctx, task.ID, field.ID)
+	if err != nil {
+		return status_codes.CustomFieldFailure, err
+	}
+
+	err = c.repository.SetValue(ctx, task.ID, value)
+	if err != nil {
+		return status_codes.CustomFieldFailure, errors.Join(errors.New("failed to set custom field value"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityFieldValue,
+		EntityID:   field.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set(field.Name, fieldValueChange(*field, current), fieldValueChange(*field, &value)),
+	})
+
+	return status_codes.CustomFieldSuccess, nil
+}
+
+// RemoveFieldValue clears the value of the field on the task
+func (c CustomFieldUseCases) RemoveFieldValue(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	fieldID int,
+) (status_codes.CustomFieldStatusCode, error) {
+	task, field, statusCode, err := c.getTaskField(ctx, user, taskID, fieldID)
+	if task == nil {
+		return statusCode, err
+	}
+
+	current, err := c.getValue(ctx, task.ID, field.ID)
+	if err != nil {
+		return status_codes.CustomFieldFailure, err
+	}
+
+	if current == nil {
+		return status_codes.CustomFieldSuccess, nil
+	}
+
+	err = c.repository.DeleteValue(ctx, task.ID, field.ID)
+	if err != nil {
+		return status_codes.CustomFieldFailure, errors.Join(errors.New("failed to remove custom field value"), err)
+	}
+
+	c.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityFieldValue,
+		EntityID:   field.ID,
+		Action:     entities.ActivityUpdated,
+		Changes:    activityChanges{}.set(field.Name, fieldValueChange(*field, current), nil),
+	})
+
+	return status_codes.CustomFieldSuccess, nil
+}
+
+// getOwnedBoard returns the board if the user owns it. A nil board is returned along with the status code or error to
+// send back otherwise.
+func (c CustomFieldUseCases) getOwnedBoard(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Board, status_codes.CustomFieldStatusCode, error) {
+	board, err := c.boardRepository.GetBoardByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.CustomFieldBoardNotFound, nil
+		}
+
+		return nil, status_codes.CustomFieldFailure, errors.Join(errors.New("failed to get board"), err)
+	}
+
+	err = checkBoardMember(ctx, c.boardRepository, board.ID, user.ID)
+	if err != nil {
+		return nil, status_codes.CustomFieldFailure, err
+	}
+
+	if board.CreatedBy.ID != user.ID {
+		return nil, status_codes.CustomFieldFailure, entities.ErrForbidden
+	}
+
+	return board, status_codes.CustomFieldSuccess, nil
+}
+
+// getOwnedField is the same as getOwnedBoard, for the board of the field
+func (c CustomFieldUseCases) getOwnedField(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.CustomField, status_codes.CustomFieldStatusCode, error) {
+	field, err := c.repository.GetFieldByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.CustomFieldNotFound, nil
+		}
+
+		return nil, status_codes.CustomFieldFailure, errors.Join(errors.New("failed to get custom field"), err)
+	}
+
+	board, statusCode, err := c.getOwnedBoard(ctx, user, field.IDBoard)
+	if board == nil {
+		return nil, statusCode, err
+	}
+
+	return field, status_codes.CustomFieldSuccess, nil
+}
+
+// getTaskField returns the task and the field of its board if the user is a member of the board. A nil task is
+// returned along with the status code or error to send back otherwise.
+func (c CustomFieldUseCases) getTaskField(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	fieldID int,
+) (*entities.Task, *entities.CustomField, status_codes.CustomFieldStatusCode, error) {
+	task, err := c.boardRepository.GetTaskByID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, nil, status_codes.CustomFieldTaskNotFound, nil
+		}
+
+		return nil, nil, status_codes.CustomFieldFailure, errors.Join(errors.New("failed to get task"), err)
+	}
+
+	err = checkBoardMember(ctx, c.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, nil, status_codes.CustomFieldFailure, err
+	}
+
+	field, err := c.repository.GetFieldByID(ctx, fieldID)
+	if err != nil && !errors.Is(err, entities.ErrNotFound) {
+		return nil, nil, status_codes.CustomFieldFailure, errors.Join(errors.New("failed to get custom field"), err)
+	}
+
+	if field == nil || field.IDBoard != task.IDBoard {
+		return nil, nil, status_codes.CustomFieldNotFound, nil
+	}
+
+	return task, field, status_codes.CustomFieldSuccess, nil
+}
+
+// getValue returns the value of the field on the task, or nil if it has none
+func (c CustomFieldUseCases) getValue(
+	ctx context.Context,
+	taskID int,
+	fieldID int,
+) (*entities.CustomFieldValue, error) {
+	values, err := c.repository.GetValuesByTask(ctx, taskID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get custom field values"), err)
+	}
+
+	for _, value := range values {
+		if value.IDField == fieldID {
+			return &value, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// withOptionIDs validates the options of the field, keeping the IDs of the current options and generating the IDs of
+// the new ones
+func (c CustomFieldUseCases) withOptionIDs(
+	field entities.CustomField,
+	current []entities.CustomFieldOption,
+) ([]entities.CustomFieldOption, status_codes.CustomFieldStatusCode, error) {
+	options := make([]entities.CustomFieldOption, 0, len(field.Options))
+	for _, option := range field.Options {
+		option.Name = strings.TrimSpace(option.Name)
+
+		if option.ID == "" {
+			optionUUID, err := uuid.NewRandom()
+			if err != nil {
+				return nil, status_codes.CustomFieldFailure, errors.Join(
+					errors.New("failed to generate option UUID"),
+					err,
+				)
+			}
+
+			option.ID = optionUUID.String()
+		} else if !slices.ContainsFunc(current, sameOption(option.ID)) ||
+			slices.ContainsFunc(options, sameOption(option.ID)) {
+			return nil, status_codes.CustomFieldInvalidOptions, nil
+		}
+
+		options = append(options, option)
+	}
+
+	if !rules.ValidateCustomFieldOptions(field.Type, options) {
+		return nil, status_codes.CustomFieldInvalidOptions, nil
+	}
+
+	if len(options) == 0 {
+		return nil, status_codes.CustomFieldSuccess, nil
+	}
+
+	return options, status_codes.CustomFieldSuccess, nil
+}
+
+// hasFieldNamed tells whether a field other than the excluded one has the name
+func hasFieldNamed(fields []entities.CustomField, name string, excludedID int) bool {
+	return slices.ContainsFunc(fields, func(field entities.CustomField) bool {
+		return field.ID != excludedID && strings.EqualFold(field.Name, name)
+	})
+}
+
+func sameOption(id string) func(option entities.CustomFieldOption) bool {
+	return func(option entities.CustomFieldOption) bool {
+		return option.ID == id
+	}
+}
+
+// optionNames returns the option names as recorded in activity changes
+func optionNames(options []entities.CustomFieldOption) any {
+	if len(options) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(options))
+	for _, option := range options {
+		names = append(names, option.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// fieldValueChange returns the custom field value as recorded in activity changes, which compares values instead of
+// pointers and slices
+func fieldValueChange(field entities.CustomField, value *entities.CustomFieldValue) any {
+	switch {
+	case value == nil:
+		return nil
+	case value.Text != nil:
+		return *value.Text
+	case value.Number != nil:
+		return *value.Number
+	case value.Date != nil:
+		return value.Date.UTC().Format(time.RFC3339)
+	case value.Checked != nil:
+		return *value.Checked
+	case value.IDUser != 0:
+		return value.IDUser
+	}
+
+	selected := make([]entities.CustomFieldOption, 0, len(value.Options))
+	for _, optionID := range value.Options {
+		index := slices.IndexFunc(field.Options, sameOption(optionID))
+		if index >= 0 {
+			selected = append(selected, field.Options[index])
+		}
+	}
+
+	return optionNames(selected)
+}
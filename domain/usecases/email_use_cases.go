@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"taskflow/domain/emails"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/infrastructure/datastore"
+	"taskflow/infrastructure/mailer"
+	"time"
+)
+
+const (
+	// emailPollInterval is how often the worker looks for due emails when it is not woken up
+	emailPollInterval = 30 * time.Second
+
+	// emailBatchSize is the number of due emails the worker fetches at once
+	emailBatchSize = 20
+
+	// emailSendLease is how long a claimed email is hidden from other workers while being sent
+	emailSendLease = 2 * time.Minute
+
+	// emailMaxErrorLetters limits the error stored with a failed email
+	emailMaxErrorLetters = 1024
+)
+
+// EmailUseCases queues emails in the database and sends them in background, so an SMTP outage never fails the request
+// that caused the email. Failed emails are retried with exponential backoff.
+type EmailUseCases struct {
+	repository datastore.EmailRepository
+	mailer     mailer.Mailer
+	wake       chan struct{}
+}
+
+func NewEmailUseCases(repository datastore.EmailRepository, mailer mailer.Mailer) EmailUseCases {
+	e := EmailUseCases{
+		repository: repository,
+		mailer:     mailer,
+		wake:       make(chan struct{}, 1),
+	}
+
+	go e.emailWorker()
+
+	return e
+}
+
+// Queue renders the template and queues the email to the given address
+func (e EmailUseCases) Queue(
+	ctx context.Context,
+	to string,
+	template string,
+	data any,
+	unsubscribeURL string,
+) error {
+	subject, text, html, err := emails.Render(template, data)
+	if err != nil {
+		return err
+	}
+
+	email := entities.Email{
+		To:             to,
+		Subject:        subject,
+		Text:           text,
+		HTML:           html,
+		UnsubscribeURL: unsubscribeURL,
+		Status:         entities.EmailPending,
+		NextAttemptAt:  time.Now(),
+	}
+
+	err = e.repository.AddEmail(ctx, &email)
+	if err != nil {
+		return errors.Join(errors.New("failed to queue email"), err)
+	}
+
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (e EmailUseCases) emailWorker() {
+	ticker := time.NewTicker(emailPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.wake:
+		}
+
+		e.sendDueEmails(context.Background())
+	}
+}
+
+// sendDueEmails sends the emails whose next attempt is due, until none is left
+func (e EmailUseCases) sendDueEmails(ctx context.Context) {
+	for {
+		queued, err := e.repository.GetDueEmails(ctx, emailBatchSize)
+		if err != nil {
+			slog.Error("failed to get due emails", "cause", err)
+			return
+		}
+
+		sent := 0
+		for _, email := range queued {
+			claimed, err := e.repository.ClaimEmail(ctx, email.ID, time.Now().Add(emailSendLease))
+			if err != nil {
+				slog.Error("failed to claim email", "email", email.ID, "cause", err)
+				continue
+			}
+
+			if !claimed {
+				continue
+			}
+
+			err = e.sendEmail(ctx, email)
+			if err != nil {
+				slog.Error("failed to update email", "email", email.ID, "cause", err)
+			}
+			sent++
+		}
+
+		if len(queued) < emailBatchSize || sent == 0 {
+			return
+		}
+	}
+}
+
+// sendEmail sends the email and saves the outcome of the attempt
+func (e EmailUseCases) sendEmail(ctx context.Context, email entities.Email) error {
+	email.Attempts++
+
+	err := e.mailer.Send(ctx, email)
+	if err == nil {
+		now := time.Now()
+		email.Status = entities.EmailSent
+		email.Error = ""
+		email.SentAt = &now
+
+		return e.repository.UpdateEmail(ctx, &email)
+	}
+
+	slog.Warn("failed to send email", "email", email.ID, "attempts", email.Attempts, "cause", err)
+
+	email.Error = truncate(err.Error(), emailMaxErrorLetters)
+	if email.Attempts >= rules.EmailMaxAttempts {
+		email.Status = entities.EmailFailed
+	} else {
+		email.NextAttemptAt = time.Now().Add(rules.EmailBackoff(email.Attempts))
+	}
+
+	return e.repository.UpdateEmail(ctx, &email)
+}
@@ -0,0 +1,291 @@
+package usecases
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"path"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/infrastructure/datastore"
+	"taskflow/infrastructure/filestore"
+	"time"
+)
+
+type ExportUseCases struct {
+	boards              BoardUseCases
+	boardRepository     datastore.BoardRepository
+	checklistRepository datastore.ChecklistRepository
+	commentRepository   datastore.CommentRepository
+	fieldRepository     datastore.CustomFieldRepository
+	labelRepository     datastore.LabelRepository
+	fileStorage         filestore.FileStorage
+
+	// environment is the environment of the instance, recorded in the archives
+	environment string
+}
+
+func NewExportUseCases(
+	boards BoardUseCases,
+	boardRepository datastore.BoardRepository,
+	checklistRepository datastore.ChecklistRepository,
+	commentRepository datastore.CommentRepository,
+	fieldRepository datastore.CustomFieldRepository,
+	labelRepository datastore.LabelRepository,
+	fileStorage filestore.FileStorage,
+	environment string,
+) ExportUseCases {
+	return ExportUseCases{
+		boards:              boards,
+		boardRepository:     boardRepository,
+		checklistRepository: checklistRepository,
+		commentRepository:   commentRepository,
+		fieldRepository:     fieldRepository,
+		labelRepository:     labelRepository,
+		fileStorage:         fileStorage,
+		environment:         environment,
+	}
+}
+
+// ExportBoard returns the archive of the board with all its content, archived items included, if the user is a member
+// of the board
+func (e ExportUseCases) ExportBoard(ctx context.Context, user *entities.User, id int) (*entities.Archive, error) {
+	board, err := e.boardRepository.GetBoardByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, e.boardRepository, board.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.withArchiveContent(ctx, board)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.newArchive(user, []entities.Board{*board}), nil
+}
+
+// ExportWorkspace returns the archive of the active and archived boards the user owns, with all their content
+func (e ExportUseCases) ExportWorkspace(ctx context.Context, user *entities.User) (*entities.Archive, error) {
+	boards := make([]entities.Board, 0)
+	for _, archived := range []bool{false, true} {
+		filter := entities.BoardFilter{
+			IDUser:   user.ID,
+			Archived: archived,
+			Sort:     entities.BoardSortCreated,
+			Limit:    rules.PageMaxLimit,
+		}
+
+		for {
+			page, err := e.boardRepository.GetBoards(ctx, filter)
+			if err != nil {
+				return nil, errors.Join(errors.New("failed to get boards"), err)
+			}
+
+			for _, board := range page {
+				if board.CreatedBy.ID == user.ID {
+					boards = append(boards, board)
+				}
+			}
+
+			if len(page) < filter.Limit {
+				break
+			}
+
+			filter.AfterID = page[len(page)-1].ID
+		}
+	}
+
+	for i := range boards {
+		err := e.withArchiveContent(ctx, &boards[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return e.newArchive(user, boards), nil
+}
+
+// WriteArchive writes the archive as a zip file, along with the files of its attachments. Attachments whose file is
+// missing are left out of the zip file, the import then skipping them.
+func (e ExportUseCases) WriteArchive(ctx context.Context, w io.Writer, archive *entities.Archive) error {
+	zipWriter := zip.NewWriter(w)
+
+	manifest, err := zipWriter.Create(entities.ArchiveManifest)
+	if err != nil {
+		return errors.Join(errors.New("failed to create archive manifest"), err)
+	}
+
+	encoder := json.NewEncoder(manifest)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(archive)
+	if err != nil {
+		return errors.Join(errors.New("failed to write archive manifest"), err)
+	}
+
+	for _, board := range archive.Boards {
+		for _, taskList := range board.TaskLists {
+			for _, task := range taskList.Tasks {
+				for _, attachment := range task.Attachments {
+					err = e.writeAttachment(ctx, zipWriter, attachment)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	err = zipWriter.Close()
+	if err != nil {
+		return errors.Join(errors.New("failed to close archive"), err)
+	}
+
+	return nil
+}
+
+// writeAttachment adds the file of the attachment to the zip file, unless it is missing
+func (e ExportUseCases) writeAttachment(
+	ctx context.Context,
+	zipWriter *zip.Writer,
+	attachment entities.Attachment,
+) error {
+	file, err := e.fileStorage.ServeFile(attachment.Path)
+	if err != nil {
+		slog.WarnContext(ctx, "skipped missing attachment file", "attachment", attachment.ID, "cause", err)
+		return nil
+	}
+	defer file.Close()
+
+	entry, err := zipWriter.CreateHeader(&zip.FileHeader{
+		Name:     path.Join(entities.ArchiveAttachmentsFolder, attachment.UUID),
+		Method:   zip.Deflate,
+		Modified: attachment.CreatedAt,
+	})
+	if err != nil {
+		return errors.Join(errors.New("failed to create archive attachment"), err)
+	}
+
+	_, err = io.Copy(entry, file)
+	if err != nil {
+		return errors.Join(errors.New("failed to write archive attachment"), err)
+	}
+
+	return nil
+}
+
+// withArchiveContent fills the members, labels, custom fields, task lists and tasks of the board, along with the
+// details, dependencies, checklists and comments of the tasks. Archived items are included, while the URLs of the
+// attachments are left out.
+func (e ExportUseCases) withArchiveContent(ctx context.Context, board *entities.Board) error {
+	var err error
+	board.Users, err = e.boardRepository.GetBoardMembers(ctx, board.ID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board members"), err)
+	}
+
+	board.Labels, err = e.labelRepository.GetLabelsByBoard(ctx, board.ID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board labels"), err)
+	}
+
+	board.CustomFields, err = e.fieldRepository.GetFieldsByBoard(ctx, board.ID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board custom fields"), err)
+	}
+
+	taskLists, err := e.boardRepository.GetTaskLists(ctx, board.ID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board task lists"), err)
+	}
+
+	tasks, err := e.boardRepository.GetTasksByBoard(ctx, board.ID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board tasks"), err)
+	}
+
+	err = e.boards.withTaskDetails(ctx, board.ID, tasks)
+	if err != nil {
+		return err
+	}
+
+	dependencies, err := e.boardRepository.GetDependenciesByBoard(ctx, board.ID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board dependencies"), err)
+	}
+
+	blockedBy := make(map[int][]int)
+	for _, dependency := range dependencies {
+		blockedBy[dependency.IDTask] = append(blockedBy[dependency.IDTask], dependency.IDBlocker)
+	}
+
+	tasksByList := make(map[int][]entities.Task)
+	for _, task := range tasks {
+		task.BlockedBy = blockedBy[task.ID]
+
+		task.Checklists, err = e.checklistRepository.GetChecklistsByTask(ctx, task.ID)
+		if err != nil {
+			return errors.Join(errors.New("failed to get task checklists"), err)
+		}
+
+		task.Comments, err = e.taskComments(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+
+		for i := range task.Attachments {
+			task.Attachments[i].URL = ""
+			task.Attachments[i].Thumbnails = nil
+		}
+
+		tasksByList[task.IDTaskList] = append(tasksByList[task.IDTaskList], task)
+	}
+
+	board.TaskLists = taskLists
+	for i := range board.TaskLists {
+		board.TaskLists[i].Tasks = tasksByList[board.TaskLists[i].ID]
+		if board.TaskLists[i].Tasks == nil {
+			board.TaskLists[i].Tasks = make([]entities.Task, 0)
+		}
+	}
+
+	return nil
+}
+
+// taskComments returns all the comments of the task, oldest first
+func (e ExportUseCases) taskComments(ctx context.Context, taskID int) ([]entities.Comment, error) {
+	comments := make([]entities.Comment, 0)
+	for {
+		afterID := 0
+		if len(comments) > 0 {
+			afterID = comments[len(comments)-1].ID
+		}
+
+		page, err := e.commentRepository.GetCommentsByTask(ctx, taskID, afterID, rules.PageMaxLimit)
+		if err != nil {
+			return nil, errors.Join(errors.New("failed to get task comments"), err)
+		}
+
+		comments = append(comments, page...)
+		if len(page) < rules.PageMaxLimit {
+			return comments, nil
+		}
+	}
+}
+
+func (e ExportUseCases) newArchive(user *entities.User, boards []entities.Board) *entities.Archive {
+	return &entities.Archive{
+		Format:      entities.ArchiveFormat,
+		Version:     entities.ArchiveVersion,
+		Environment: e.environment,
+		ExportedAt:  time.Now().UTC(),
+		ExportedBy:  user.Email,
+		Boards:      boards,
+	}
+}
@@ -0,0 +1,1178 @@
+package usecases
+
+import (
+	"archive/zip"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/util"
+	"taskflow/infrastructure/datastore"
+	"taskflow/infrastructure/filestore"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// trelloColors maps the label colors of Trello to their RGB values
+var trelloColors = map[string]string{
+	"green":  "#61bd4f",
+	"yellow": "#f2d600",
+	"orange": "#ff9f1a",
+	"red":    "#eb5a46",
+	"purple": "#c377e0",
+	"blue":   "#0079bf",
+	"sky":    "#00c2e0",
+	"lime":   "#51e898",
+	"pink":   "#ff78cb",
+	"black":  "#344563",
+}
+
+type ImportUseCases struct {
+	boardRepository datastore.BoardRepository
+	authRepository  datastore.AuthRepository
+	attachments     AttachmentUseCases
+	fileStorage     filestore.FileStorage
+	activity        ActivityUseCases
+
+	// attachmentsFolder is the only folder local attachments are copied from
+	attachmentsFolder string
+
+	// environment is the environment of the instance, compared with the one the archives were exported from
+	environment string
+}
+
+func NewImportUseCases(
+	boardRepository datastore.BoardRepository,
+	authRepository datastore.AuthRepository,
+	attachments AttachmentUseCases,
+	fileStorage filestore.FileStorage,
+	activity ActivityUseCases,
+	attachmentsFolder string,
+	environment string,
+) ImportUseCases {
+	return ImportUseCases{
+		boardRepository:   boardRepository,
+		authRepository:    authRepository,
+		attachments:       attachments,
+		fileStorage:       fileStorage,
+		activity:          activity,
+		attachmentsFolder: attachmentsFolder,
+		environment:       environment,
+	}
+}
+
+// ImportTrelloBoard creates a board owned by the user from a Trello board JSON export, all or nothing. Lists, cards,
+// labels, checklists, comments and due dates are mapped onto the board. Attachments are copied when they are local
+// files of the attachments folder. Nothing is created on a dry run, the report telling what would be.
+//
+// When matching users, the members of the export having an account with the same email become members of the board
+// and keep their assignments and comments. As anyone can write any email in an export, only the import command run
+// by the administrator matches users: the comments are otherwise credited to the user, naming their Trello author.
+func (i ImportUseCases) ImportTrelloBoard(
+	ctx context.Context,
+	user *entities.User,
+	data []byte,
+	dryRun bool,
+	matchUsers bool,
+) (*entities.ImportReport, status_codes.ImportStatusCode, error) {
+	if len(data) > rules.ImportMaxSize {
+		return nil, status_codes.ImportFileTooLarge, nil
+	}
+
+	var export entities.TrelloBoard
+	err := json.Unmarshal(data, &export)
+	if err != nil {
+		return nil, status_codes.ImportInvalidFile, nil
+	}
+
+	report := &entities.ImportReport{
+		DryRun:   dryRun,
+		Members:  make([]entities.User, 0),
+		Warnings: make([]string, 0),
+	}
+
+	members, err := i.matchTrelloMembers(ctx, user, export.Members, report, matchUsers)
+	if err != nil {
+		return nil, status_codes.ImportFailure, err
+	}
+
+	boardUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.ImportFailure, errors.Join(errors.New("failed to generate board UUID"), err)
+	}
+
+	board, cardTasks := trelloBoard(user, export, members, report)
+	board.UUID = boardUUID.String()
+
+	attachments, err := i.trelloAttachments(ctx, user, export.Cards, board, cardTasks, report, dryRun)
+	if err != nil {
+		return nil, status_codes.ImportFailure, err
+	}
+
+	withImportCounts(report, board)
+	report.Board = board
+	if dryRun {
+		return report, status_codes.ImportSuccess, nil
+	}
+
+	err = i.boardRepository.AddBoardWithContent(ctx, board)
+	if err != nil {
+		for _, attachment := range attachments {
+			deleteAttachmentFiles(ctx, i.fileStorage, &attachment)
+		}
+
+		return nil, status_codes.ImportFailure, errors.Join(errors.New("failed to save board"), err)
+	}
+
+	i.attachments.GenerateThumbnails(ctx, importedAttachments(board))
+
+	i.activity.Record(ctx, entities.Activity{
+		IDBoard:    board.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityBoard,
+		EntityID:   board.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("title", nil, board.Title).
+			set("description", nil, board.Description).
+			set("imported_from", nil, "trello"),
+	})
+
+	return report, status_codes.ImportSuccess, nil
+}
+
+// matchTrelloMembers returns the users having the email of the members of the export, by Trello member ID. The
+// importing user is matched too, without becoming a member of the board, and is the only one matched when not
+// matching users.
+func (i ImportUseCases) matchTrelloMembers(
+	ctx context.Context,
+	user *entities.User,
+	trelloMembers []entities.TrelloMember,
+	report *entities.ImportReport,
+	matchUsers bool,
+) (map[string]entities.User, error) {
+	members := make(map[string]entities.User, len(trelloMembers))
+	for _, member := range trelloMembers {
+		email := strings.ToLower(strings.TrimSpace(member.Email))
+		if email == "" {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("member %q has no email", member.Username))
+			continue
+		}
+
+		if email == strings.ToLower(user.Email) {
+			members[member.ID] = *user
+			continue
+		}
+
+		if !matchUsers {
+			continue
+		}
+
+		matched, err := i.authRepository.GetUserByEmail(ctx, email)
+		if err != nil {
+			if errors.Is(err, entities.ErrNotFound) {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("no account has the email %q", email))
+				continue
+			}
+
+			return nil, errors.Join(errors.New("failed to get user"), err)
+		}
+
+		members[member.ID] = *matched
+		if !slices.ContainsFunc(report.Members, func(u entities.User) bool { return u.ID == matched.ID }) {
+			report.Members = append(report.Members, *matched)
+		}
+	}
+
+	return members, nil
+}
+
+// trelloAttachments stores the files of the local attachments of the cards and adds the attachments to their task,
+// returning the stored attachments. On a dry run, the attachments are only checked.
+func (i ImportUseCases) trelloAttachments(
+	ctx context.Context,
+	user *entities.User,
+	cards []entities.TrelloCard,
+	board *entities.Board,
+	cardTasks map[string][2]int,
+	report *entities.ImportReport,
+	dryRun bool,
+) ([]entities.Attachment, error) {
+	stored := make([]entities.Attachment, 0)
+	for _, card := range cards {
+		index, ok := cardTasks[card.ID]
+		if !ok {
+			continue
+		}
+
+		task := &board.TaskLists[index[0]].Tasks[index[1]]
+		for _, trelloAttachment := range card.Attachments {
+			attachment, data, warning := i.readTrelloAttachment(trelloAttachment, dryRun)
+			if attachment == nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("attachment %q of card %q %s",
+					trelloAttachment.Name, card.Name, warning))
+				continue
+			}
+
+			attachment.CreatedBy = *user
+			if !dryRun {
+				err := i.storeAttachment(attachment, data)
+				if err != nil {
+					for _, attachment := range stored {
+						deleteAttachmentFiles(ctx, i.fileStorage, &attachment)
+					}
+
+					return nil, err
+				}
+
+				stored = append(stored, *attachment)
+			}
+
+			task.Attachments = append(task.Attachments, *attachment)
+		}
+	}
+
+	return stored, nil
+}
+
+// readTrelloAttachment reads the local file of the attachment, or only checks it on a dry run. A nil attachment is
+// returned along with why it is skipped when the file can't be copied.
+func (i ImportUseCases) readTrelloAttachment(
+	trelloAttachment entities.TrelloAttachment,
+	dryRun bool,
+) (*entities.Attachment, []byte, string) {
+	filePath, ok := i.localAttachmentPath(trelloAttachment.URL)
+	if !ok {
+		return nil, nil, "is not a local file of the attachments folder"
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, nil, "was not found"
+	}
+
+	if info.Size() == 0 || info.Size() > rules.AttachmentMaxSize {
+		return nil, nil, "is empty or too large"
+	}
+
+	fileName := strings.TrimSpace(trelloAttachment.Name)
+	if !rules.ValidateFileName(fileName) {
+		fileName = filepath.Base(filePath)
+	}
+
+	attachment := &entities.Attachment{
+		FileName:    fileName,
+		ContentType: trelloAttachment.MimeType,
+		Size:        info.Size(),
+	}
+
+	if dryRun {
+		return attachment, nil, ""
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, "could not be read"
+	}
+
+	attachment.ContentType = http.DetectContentType(data)
+	attachment.Size = int64(len(data))
+	return attachment, data, ""
+}
+
+// storeAttachment stores the file of the attachment, setting its UUID and path
+func (i ImportUseCases) storeAttachment(attachment *entities.Attachment, data []byte) error {
+	attachmentUUID, err := uuid.NewRandom()
+	if err != nil {
+		return errors.Join(errors.New("failed to generate attachment UUID"), err)
+	}
+
+	folder := attachmentFolder(attachmentUUID.String())
+	err = i.fileStorage.CreateAll(folder)
+	if err != nil {
+		return errors.Join(errors.New("failed to create attachment folder"), err)
+	}
+
+	attachment.UUID = attachmentUUID.String()
+	attachment.Path = folder + "/original"
+
+	err = i.fileStorage.UploadFile(attachment.Path, data)
+	if err != nil {
+		return errors.Join(errors.New("failed to store attachment file"), err)
+	}
+
+	return nil
+}
+
+// localAttachmentPath returns the path of the file the URL refers to, if it is a file URL or a path within the
+// attachments folder
+func (i ImportUseCases) localAttachmentPath(rawURL string) (string, bool) {
+	if i.attachmentsFolder == "" {
+		return "", false
+	}
+
+	filePath := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Scheme != "" {
+		if parsed.Scheme != "file" {
+			return "", false
+		}
+		filePath = parsed.Path
+	}
+
+	folder, err := filepath.Abs(i.attachmentsFolder)
+	if err != nil {
+		return "", false
+	}
+
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(folder, filePath)
+	}
+
+	relative, err := filepath.Rel(folder, filepath.Clean(filePath))
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return filepath.Join(folder, relative), true
+}
+
+// ImportArchive creates the boards of the archive, owned by the user, with new IDs and UUIDs but their creation times.
+// The whole archive is checked before any board is created, each board then being created all or nothing.
+//
+// When matching users, the users of the archive having an account with the same email become members of the boards
+// they belonged to, and keep their assignments and authorship. As anyone can write any email in an archive, only the
+// import command run by the administrator matches users. The content of the users not matched is credited to the
+// user, keeping the email of its author.
+func (i ImportUseCases) ImportArchive(
+	ctx context.Context,
+	user *entities.User,
+	file io.ReaderAt,
+	size int64,
+	matchUsers bool,
+) ([]entities.ImportReport, status_codes.ImportStatusCode, error) {
+	if size > rules.ArchiveMaxSize {
+		return nil, status_codes.ImportFileTooLarge, nil
+	}
+
+	zipReader, err := zip.NewReader(file, size)
+	if err != nil {
+		return nil, status_codes.ImportInvalidFile, nil
+	}
+
+	files := make(map[string]*zip.File, len(zipReader.File))
+	for _, zipFile := range zipReader.File {
+		files[zipFile.Name] = zipFile
+	}
+
+	archive, statusCode := readArchiveManifest(files[entities.ArchiveManifest])
+	if archive == nil {
+		return nil, statusCode, nil
+	}
+
+	for _, board := range archive.Boards {
+		if !validArchiveBoard(board) {
+			return nil, status_codes.ImportInvalidFile, nil
+		}
+	}
+
+	accounts, err := i.matchArchiveUsers(ctx, user, archive, matchUsers)
+	if err != nil {
+		return nil, status_codes.ImportFailure, err
+	}
+
+	boards := make([]*entities.Board, 0, len(archive.Boards))
+	reports := make([]entities.ImportReport, 0, len(archive.Boards))
+	stored := make([]entities.Attachment, 0)
+	for _, source := range archive.Boards {
+		report := entities.ImportReport{Members: make([]entities.User, 0), Warnings: make([]string, 0)}
+		if archive.Environment != i.environment {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("the archive was exported from the %q environment",
+				archive.Environment))
+		}
+
+		board := archiveBoard(user, source, accounts, &report)
+
+		boardUUID, err := uuid.NewRandom()
+		if err != nil {
+			return nil, status_codes.ImportFailure, errors.Join(errors.New("failed to generate board UUID"), err)
+		}
+		board.UUID = boardUUID.String()
+
+		attachments, err := i.archiveAttachments(files, board, &report)
+		stored = append(stored, attachments...)
+		if err != nil {
+			for _, attachment := range stored {
+				deleteAttachmentFiles(ctx, i.fileStorage, &attachment)
+			}
+
+			return nil, status_codes.ImportFailure, err
+		}
+
+		boards = append(boards, board)
+		reports = append(reports, report)
+	}
+
+	for index, board := range boards {
+		err = i.boardRepository.AddBoardWithContent(ctx, board)
+		if err != nil {
+			for _, notCreated := range boards[index:] {
+				for _, attachment := range importedAttachments(notCreated) {
+					deleteAttachmentFiles(ctx, i.fileStorage, &attachment)
+				}
+			}
+
+			return nil, status_codes.ImportFailure, errors.Join(errors.New("failed to save board"), err)
+		}
+
+		i.attachments.GenerateThumbnails(ctx, importedAttachments(board))
+
+		i.activity.Record(ctx, entities.Activity{
+			IDBoard:    board.ID,
+			Actor:      *user,
+			EntityType: entities.ActivityEntityBoard,
+			EntityID:   board.ID,
+			Action:     entities.ActivityCreated,
+			Changes: activityChanges{}.
+				set("title", nil, board.Title).
+				set("description", nil, board.Description).
+				set("imported_from", nil, "archive"),
+		})
+
+		withImportCounts(&reports[index], board)
+		reports[index].Board = board
+	}
+
+	return reports, status_codes.ImportSuccess, nil
+}
+
+// matchArchiveUsers returns the accounts having the email of the users the archive refers to, by lowercase email. The
+// emails without an account are mapped to nil. Only the email of the user is mapped when not matching users.
+func (i ImportUseCases) matchArchiveUsers(
+	ctx context.Context,
+	user *entities.User,
+	archive *entities.Archive,
+	matchUsers bool,
+) (map[string]*entities.User, error) {
+	accounts := map[string]*entities.User{strings.ToLower(user.Email): user}
+	if !matchUsers {
+		return accounts, nil
+	}
+
+	for _, board := range archive.Boards {
+		for _, archiveUser := range archiveBoardUsers(board) {
+			email := strings.ToLower(archiveUser.Email)
+			if _, found := accounts[email]; found {
+				continue
+			}
+
+			matched, err := i.authRepository.GetUserByEmail(ctx, email)
+			if err != nil && !errors.Is(err, entities.ErrNotFound) {
+				return nil, errors.Join(errors.New("failed to get user"), err)
+			}
+
+			accounts[email] = matched
+		}
+	}
+
+	return accounts, nil
+}
+
+// archiveAttachments stores the files of the attachments of the board read from the archive, returning the stored
+// attachments. Attachments whose file is missing or unreadable are removed from their task.
+func (i ImportUseCases) archiveAttachments(
+	files map[string]*zip.File,
+	board *entities.Board,
+	report *entities.ImportReport,
+) ([]entities.Attachment, error) {
+	stored := make([]entities.Attachment, 0)
+	for j := range board.TaskLists {
+		for k := range board.TaskLists[j].Tasks {
+			task := &board.TaskLists[j].Tasks[k]
+
+			attachments := make([]entities.Attachment, 0, len(task.Attachments))
+			for _, attachment := range task.Attachments {
+				zipFile, found := files[path.Join(entities.ArchiveAttachmentsFolder, attachment.UUID)]
+				if !found {
+					report.Warnings = append(report.Warnings, fmt.Sprintf("file of attachment %q of task %q is missing",
+						attachment.FileName, task.Name))
+					continue
+				}
+
+				data, err := readZipFile(zipFile, rules.AttachmentMaxSize)
+				if err != nil || len(data) == 0 {
+					report.Warnings = append(report.Warnings, fmt.Sprintf("file of attachment %q of task %q is unreadable",
+						attachment.FileName, task.Name))
+					continue
+				}
+
+				// The content type written in the archive is not trusted, as it decides how the file is served
+				attachment.ContentType = http.DetectContentType(data)
+				attachment.Size = int64(len(data))
+
+				err = i.storeAttachment(&attachment, data)
+				if err != nil {
+					return stored, err
+				}
+
+				stored = append(stored, attachment)
+				attachments = append(attachments, attachment)
+			}
+
+			task.Attachments = attachments
+		}
+	}
+
+	return stored, nil
+}
+
+// trelloBoard maps the export onto a board owned by the user, without the attachments. The task of each card is
+// returned too, as the indexes of its task list and of the task within it.
+func trelloBoard(
+	user *entities.User,
+	export entities.TrelloBoard,
+	members map[string]entities.User,
+	report *entities.ImportReport,
+) (*entities.Board, map[string][2]int) {
+	board := &entities.Board{
+		Title:       cutLetters(export.Name, rules.BoardTitleMaxLetters),
+		Description: strings.TrimSpace(export.Description),
+		CreatedBy:   *user,
+		Users:       report.Members,
+	}
+
+	if board.Title == "" {
+		board.Title = "Trello"
+	}
+
+	// Label names are unique per board, Trello labels of the same name are merged
+	labelNames := make(map[string]string, len(export.Labels))
+	for _, trelloLabel := range export.Labels {
+		color, ok := trelloColors[trelloLabel.Color]
+		if !ok {
+			color = rules.ImportDefaultLabelColor
+		}
+
+		name := cutLetters(trelloLabel.Name, rules.LabelNameMaxLetters)
+		if name == "" {
+			name = cmp.Or(trelloLabel.Color, "label")
+		}
+
+		labelNames[trelloLabel.ID] = name
+		if slices.ContainsFunc(board.Labels, func(label entities.Label) bool { return label.Name == name }) {
+			continue
+		}
+
+		if len(board.Labels) == rules.LabelMaxPerBoard {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("label %q exceeds the label limit", name))
+			delete(labelNames, trelloLabel.ID)
+			continue
+		}
+
+		board.Labels = append(board.Labels, entities.Label{Name: name, Color: color, CreatedBy: *user})
+	}
+
+	trelloLists := slices.Clone(export.Lists)
+	slices.SortStableFunc(trelloLists, func(a, b entities.TrelloList) int { return cmp.Compare(a.Position, b.Position) })
+
+	listIndexes := make(map[string]int, len(trelloLists))
+	for _, trelloList := range trelloLists {
+		taskList := entities.TaskList{
+			Name:      cutLetters(trelloList.Name, rules.BoardTitleMaxLetters),
+			CreatedBy: *user,
+			Tasks:     make([]entities.Task, 0),
+		}
+
+		if taskList.Name == "" {
+			taskList.Name = "List"
+		}
+
+		if trelloList.Closed {
+			taskList.StatusCode = entities.StatusArchived
+		}
+
+		listIndexes[trelloList.ID] = len(board.TaskLists)
+		board.TaskLists = append(board.TaskLists, taskList)
+	}
+
+	checklists := make(map[string][]entities.TrelloChecklist)
+	for _, checklist := range export.Checklists {
+		checklists[checklist.IDCard] = append(checklists[checklist.IDCard], checklist)
+	}
+
+	// The comments of the members without a matched account are credited to the user, naming their author
+	authors := make(map[string]string, len(export.Members))
+	for _, member := range export.Members {
+		name := cmp.Or(strings.TrimSpace(member.Email), strings.TrimSpace(member.FullName), member.Username)
+		authors[member.ID] = cutLetters(name, rules.ImportedAuthorMaxLetters)
+	}
+
+	comments := make(map[string][]entities.Comment)
+	for _, action := range export.Actions {
+		body := cutLetters(action.Data.Text, rules.CommentMaxLetters)
+		if action.Type != "commentCard" || body == "" {
+			continue
+		}
+
+		comment := entities.Comment{
+			Body:      body,
+			CreatedBy: *user,
+			CreatedAt: action.Date,
+		}
+
+		if author, ok := members[action.IDMember]; ok {
+			comment.CreatedBy = author
+		} else {
+			comment.ImportedAuthor = authors[action.IDMember]
+		}
+
+		comments[action.Data.Card.ID] = append(comments[action.Data.Card.ID], comment)
+	}
+
+	cards := slices.Clone(export.Cards)
+	slices.SortStableFunc(cards, func(a, b entities.TrelloCard) int { return cmp.Compare(a.Position, b.Position) })
+
+	cardTasks := make(map[string][2]int, len(cards))
+	for _, card := range cards {
+		index, ok := listIndexes[card.IDList]
+		if !ok {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("card %q is not on a list of the board", card.Name))
+			continue
+		}
+
+		task := trelloTask(user, card, members)
+		for _, labelID := range card.IDLabels {
+			if name, ok := labelNames[labelID]; ok {
+				task.Labels = append(task.Labels, entities.Label{Name: name})
+			}
+		}
+
+		task.Checklists = trelloChecklists(user, checklists[card.ID], members)
+
+		// Trello lists the most recent actions first
+		task.Comments = comments[card.ID]
+		slices.Reverse(task.Comments)
+
+		cardTasks[card.ID] = [2]int{index, len(board.TaskLists[index].Tasks)}
+		board.TaskLists[index].Tasks = append(board.TaskLists[index].Tasks, task)
+	}
+
+	return board, cardTasks
+}
+
+// trelloTask maps the card onto a task, without its labels, checklists and comments
+func trelloTask(user *entities.User, card entities.TrelloCard, members map[string]entities.User) entities.Task {
+	task := entities.Task{
+		Name:        cutLetters(card.Name, rules.TaskNameMaxLetters),
+		Description: strings.TrimSpace(card.Description),
+		DueDate:     card.Due,
+		CreatedBy:   *user,
+	}
+
+	// Long names keep their full text in the description
+	if task.Name != strings.TrimSpace(card.Name) {
+		task.Description = strings.TrimSpace(card.Name + "\n\n" + task.Description)
+	}
+
+	if task.Name == "" {
+		task.Name = "Card"
+	}
+
+	if card.DueComplete {
+		task.Status = entities.TaskFinished
+	}
+
+	if card.Closed {
+		task.StatusCode = entities.StatusArchived
+	}
+
+	for _, memberID := range card.IDMembers {
+		if member, ok := members[memberID]; ok {
+			task.Assignees = append(task.Assignees, member)
+		}
+	}
+
+	return task
+}
+
+// trelloChecklists maps the checklists of a card onto checklists of its task, the complete items being checked at
+// the time of the import
+func trelloChecklists(
+	user *entities.User,
+	trelloChecklists []entities.TrelloChecklist,
+	members map[string]entities.User,
+) []entities.Checklist {
+	now := time.Now()
+
+	slices.SortStableFunc(trelloChecklists, func(a, b entities.TrelloChecklist) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+
+	checklists := make([]entities.Checklist, 0, len(trelloChecklists))
+	for _, trelloChecklist := range trelloChecklists {
+		checklist := entities.Checklist{
+			Name:      cmp.Or(cutLetters(trelloChecklist.Name, rules.BoardTitleMaxLetters), "Checklist"),
+			CreatedBy: *user,
+			Items:     make([]entities.ChecklistItem, 0, len(trelloChecklist.CheckItems)),
+		}
+
+		slices.SortStableFunc(trelloChecklist.CheckItems, func(a, b entities.TrelloCheckItem) int {
+			return cmp.Compare(a.Position, b.Position)
+		})
+
+		for _, checkItem := range trelloChecklist.CheckItems {
+			item := entities.ChecklistItem{
+				Text:      cutLetters(checkItem.Name, rules.ChecklistItemMaxLetters),
+				DueDate:   checkItem.Due,
+				CreatedBy: *user,
+			}
+
+			if item.Text == "" {
+				continue
+			}
+
+			if checkItem.State == "complete" {
+				item.Checked = true
+				item.CheckedAt = &now
+			}
+
+			if member, ok := members[checkItem.IDMember]; ok {
+				item.Assignee = &member
+			}
+
+			checklist.Items = append(checklist.Items, item)
+		}
+
+		checklists = append(checklists, checklist)
+	}
+
+	return checklists
+}
+
+// readArchiveManifest reads the archive from its manifest file, returning a nil archive along with the status code
+// to send back when the manifest is missing or invalid, or its version unsupported
+func readArchiveManifest(zipFile *zip.File) (*entities.Archive, status_codes.ImportStatusCode) {
+	if zipFile == nil {
+		return nil, status_codes.ImportInvalidFile
+	}
+
+	data, err := readZipFile(zipFile, rules.ArchiveManifestMaxSize)
+	if err != nil {
+		return nil, status_codes.ImportInvalidFile
+	}
+
+	var archive entities.Archive
+	err = json.Unmarshal(data, &archive)
+	if err != nil || archive.Format != entities.ArchiveFormat {
+		return nil, status_codes.ImportInvalidFile
+	}
+
+	if archive.Version < 1 || archive.Version > entities.ArchiveVersion {
+		return nil, status_codes.ImportUnsupportedVersion
+	}
+
+	return &archive, status_codes.ImportSuccess
+}
+
+// readZipFile reads the file of the zip, failing if it is larger than the given size
+func readZipFile(zipFile *zip.File, maxSize int64) ([]byte, error) {
+	if zipFile.UncompressedSize64 > uint64(maxSize) {
+		return nil, errors.New("zip file too large")
+	}
+
+	reader, err := zipFile.Open()
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to open zip file"), err)
+	}
+	defer reader.Close()
+
+	// The declared size can't be trusted
+	data, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to read zip file"), err)
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, errors.New("zip file too large")
+	}
+
+	return data, nil
+}
+
+// validArchiveBoard checks the board of an archive against the rules its content is created with
+func validArchiveBoard(board entities.Board) bool {
+	if !rules.ValidateTitle(board.Title) || !validArchiveStatusCode(board.StatusCode) {
+		return false
+	}
+
+	if len(board.Labels) > rules.LabelMaxPerBoard || len(board.CustomFields) > rules.CustomFieldMaxPerBoard {
+		return false
+	}
+
+	labels := make(map[string]bool, len(board.Labels))
+	for _, label := range board.Labels {
+		if !rules.ValidateLabelName(label.Name) || !rules.ValidateLabelColor(label.Color) || labels[label.Name] {
+			return false
+		}
+		labels[label.Name] = true
+	}
+
+	fields := make(map[int]entities.CustomField, len(board.CustomFields))
+	fieldNames := make(map[string]bool, len(board.CustomFields))
+	for _, field := range board.CustomFields {
+		_, duplicate := fields[field.ID]
+		if duplicate || fieldNames[field.Name] || !rules.ValidateTitle(field.Name) ||
+			!rules.ValidateCustomFieldType(field.Type) || !rules.ValidateCustomFieldOptions(field.Type, field.Options) {
+			return false
+		}
+		fields[field.ID] = field
+		fieldNames[field.Name] = true
+	}
+
+	for _, taskList := range board.TaskLists {
+		if !rules.ValidateTitle(taskList.Name) || !validArchiveStatusCode(taskList.StatusCode) {
+			return false
+		}
+
+		for _, task := range taskList.Tasks {
+			if !validArchiveTask(task, labels, fields) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func validArchiveTask(task entities.Task, labels map[string]bool, fields map[int]entities.CustomField) bool {
+	if !rules.ValidateTitle(task.Name) || !rules.ValidatePriority(task.Priority) ||
+		!rules.ValidateEstimate(task.OriginalEstimate) || !validArchiveStatusCode(task.StatusCode) {
+		return false
+	}
+
+	if task.Recurrence != nil {
+		_, err := util.ParseRecurrenceRule(task.Recurrence.Rule)
+		if err != nil {
+			return false
+		}
+	}
+
+	for _, label := range task.Labels {
+		if !labels[label.Name] {
+			return false
+		}
+	}
+
+	for _, value := range task.Fields {
+		field, found := fields[value.IDField]
+		if !found || !rules.ValidateCustomFieldValue(field, value) {
+			return false
+		}
+	}
+
+	for _, checklist := range task.Checklists {
+		if !rules.ValidateTitle(checklist.Name) {
+			return false
+		}
+
+		for _, item := range checklist.Items {
+			if !rules.ValidateChecklistItem(item.Text) {
+				return false
+			}
+		}
+	}
+
+	for _, comment := range task.Comments {
+		if !rules.ValidateComment(comment.Body) {
+			return false
+		}
+	}
+
+	for _, attachment := range task.Attachments {
+		if !rules.ValidateFileName(attachment.FileName) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validArchiveStatusCode tells whether the status code is one archived items can have, deleted items not being
+// exported
+func validArchiveStatusCode(statusCode int) bool {
+	return statusCode == entities.StatusActive || statusCode == entities.StatusArchived
+}
+
+// archiveBoardUsers returns the users the board of an archive refers to
+func archiveBoardUsers(board entities.Board) []entities.User {
+	users := append([]entities.User{board.CreatedBy}, board.Users...)
+	for _, taskList := range board.TaskLists {
+		for _, task := range taskList.Tasks {
+			users = append(users, task.CreatedBy)
+			users = append(users, task.Assignees...)
+
+			for _, checklist := range task.Checklists {
+				for _, item := range checklist.Items {
+					if item.Assignee != nil {
+						users = append(users, *item.Assignee)
+					}
+				}
+			}
+
+			for _, comment := range task.Comments {
+				users = append(users, comment.CreatedBy)
+			}
+
+			for _, attachment := range task.Attachments {
+				users = append(users, attachment.CreatedBy)
+			}
+		}
+	}
+
+	return users
+}
+
+// archiveBoard maps the board of an archive onto a board owned by the user, keeping the IDs of the source for
+// the references between its custom fields, task lists and tasks. Users are replaced by the accounts their email is
+// mapped to: only the members keep their assignments, while the user becomes the author of the content of the users
+// without an account, which keeps the email of its author.
+func archiveBoard(
+	user *entities.User,
+	source entities.Board,
+	accounts map[string]*entities.User,
+	report *entities.ImportReport,
+) *entities.Board {
+	warned := make(map[string]bool)
+	account := func(archiveUser entities.User) *entities.User {
+		email := strings.ToLower(archiveUser.Email)
+		matched, looked := accounts[email]
+		if looked && matched == nil && !warned[email] {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("no account has the email %q", email))
+			warned[email] = true
+		}
+
+		return matched
+	}
+
+	board := &entities.Board{
+		Title:       source.Title,
+		Description: source.Description,
+		CreatedBy:   *user,
+		Users:       make([]entities.User, 0, len(source.Users)),
+		WIPWarnOnly: source.WIPWarnOnly,
+		StatusCode:  source.StatusCode,
+		CreatedAt:   source.CreatedAt,
+	}
+
+	// The owner of the source board becomes a member, as with duplicated boards
+	members := map[int]bool{user.ID: true}
+	sourceUsers := make(map[int]entities.User, len(source.Users)+1)
+	for _, archiveUser := range append([]entities.User{source.CreatedBy}, source.Users...) {
+		sourceUsers[archiveUser.ID] = archiveUser
+
+		matched := account(archiveUser)
+		if matched == nil || members[matched.ID] {
+			continue
+		}
+
+		members[matched.ID] = true
+		board.Users = append(board.Users, *matched)
+	}
+	report.Members = append(report.Members, board.Users...)
+
+	member := func(archiveUser entities.User) *entities.User {
+		matched := account(archiveUser)
+		if matched == nil || !members[matched.ID] {
+			return nil
+		}
+
+		return matched
+	}
+
+	// Content imported before keeps the author it was imported with
+	author := func(archiveUser entities.User, importedAuthor string) (entities.User, string) {
+		if matched := account(archiveUser); matched != nil {
+			return *matched, importedAuthor
+		}
+
+		return *user, cmp.Or(importedAuthor, cutLetters(archiveUser.Email, rules.ImportedAuthorMaxLetters))
+	}
+
+	for _, label := range source.Labels {
+		board.Labels = append(board.Labels, entities.Label{
+			Name:      label.Name,
+			Color:     label.Color,
+			CreatedAt: label.CreatedAt,
+		})
+	}
+
+	for _, field := range source.CustomFields {
+		board.CustomFields = append(board.CustomFields, entities.CustomField{
+			ID:        field.ID,
+			Name:      field.Name,
+			Type:      field.Type,
+			Options:   field.Options,
+			CreatedAt: field.CreatedAt,
+		})
+	}
+
+	for _, sourceList := range source.TaskLists {
+		taskList := entities.TaskList{
+			ID:          sourceList.ID,
+			Name:        sourceList.Name,
+			Description: sourceList.Description,
+			StatusCode:  sourceList.StatusCode,
+			CreatedAt:   sourceList.CreatedAt,
+			Tasks:       make([]entities.Task, 0, len(sourceList.Tasks)),
+		}
+
+		if rules.ValidateWIPLimit(sourceList.WIPLimit) {
+			taskList.WIPLimit = sourceList.WIPLimit
+		}
+
+		for _, sourceTask := range sourceList.Tasks {
+			task := entities.Task{
+				ID:               sourceTask.ID,
+				Name:             sourceTask.Name,
+				Description:      sourceTask.Description,
+				Status:           sourceTask.Status,
+				Priority:         sourceTask.Priority,
+				DueDate:          sourceTask.DueDate,
+				Recurrence:       sourceTask.Recurrence,
+				IDParent:         sourceTask.IDParent,
+				BlockedBy:        sourceTask.BlockedBy,
+				StatusCode:       sourceTask.StatusCode,
+				CreatedAt:        sourceTask.CreatedAt,
+				OriginalEstimate: sourceTask.OriginalEstimate,
+			}
+			task.CreatedBy, task.ImportedAuthor = author(sourceTask.CreatedBy, sourceTask.ImportedAuthor)
+
+			for _, assignee := range sourceTask.Assignees {
+				if matched := member(assignee); matched != nil {
+					task.Assignees = append(task.Assignees, *matched)
+				}
+			}
+
+			for _, label := range sourceTask.Labels {
+				task.Labels = append(task.Labels, entities.Label{Name: label.Name})
+			}
+
+			for _, value := range sourceTask.Fields {
+				if value.IDUser != 0 {
+					matched := member(sourceUsers[value.IDUser])
+					if matched == nil {
+						continue
+					}
+					value.IDUser = matched.ID
+				}
+
+				task.Fields = append(task.Fields, value)
+			}
+
+			for _, sourceChecklist := range sourceTask.Checklists {
+				checklist := entities.Checklist{
+					Name:      sourceChecklist.Name,
+					CreatedAt: sourceChecklist.CreatedAt,
+					Items:     make([]entities.ChecklistItem, 0, len(sourceChecklist.Items)),
+				}
+
+				for _, sourceItem := range sourceChecklist.Items {
+					item := entities.ChecklistItem{
+						Text:      sourceItem.Text,
+						Checked:   sourceItem.Checked,
+						CheckedAt: sourceItem.CheckedAt,
+						DueDate:   sourceItem.DueDate,
+						IDSubtask: sourceItem.IDSubtask,
+						CreatedAt: sourceItem.CreatedAt,
+					}
+
+					if sourceItem.Assignee != nil {
+						item.Assignee = member(*sourceItem.Assignee)
+					}
+
+					checklist.Items = append(checklist.Items, item)
+				}
+
+				task.Checklists = append(task.Checklists, checklist)
+			}
+
+			for _, sourceComment := range sourceTask.Comments {
+				comment := entities.Comment{
+					Body:      sourceComment.Body,
+					CreatedAt: sourceComment.CreatedAt,
+				}
+				comment.CreatedBy, comment.ImportedAuthor = author(sourceComment.CreatedBy, sourceComment.ImportedAuthor)
+
+				task.Comments = append(task.Comments, comment)
+			}
+
+			// The UUIDs of the attachments name their file in the archive until the files are stored
+			for _, sourceAttachment := range sourceTask.Attachments {
+				attachment := entities.Attachment{
+					UUID:        sourceAttachment.UUID,
+					FileName:    sourceAttachment.FileName,
+					ContentType: sourceAttachment.ContentType,
+					CreatedAt:   sourceAttachment.CreatedAt,
+				}
+				attachment.CreatedBy, attachment.ImportedAuthor = author(sourceAttachment.CreatedBy,
+					sourceAttachment.ImportedAuthor)
+
+				task.Attachments = append(task.Attachments, attachment)
+			}
+
+			taskList.Tasks = append(taskList.Tasks, task)
+		}
+
+		board.TaskLists = append(board.TaskLists, taskList)
+	}
+
+	return board
+}
+
+// withImportCounts counts what the board holds into the report
+func withImportCounts(report *entities.ImportReport, board *entities.Board) {
+	report.Labels = len(board.Labels)
+	report.TaskLists = len(board.TaskLists)
+	for _, taskList := range board.TaskLists {
+		report.Tasks += len(taskList.Tasks)
+		for _, task := range taskList.Tasks {
+			report.Checklists += len(task.Checklists)
+			report.Comments += len(task.Comments)
+			report.Attachments += len(task.Attachments)
+		}
+	}
+}
+
+// importedAttachments returns the attachments of the tasks of the imported board
+func importedAttachments(board *entities.Board) []entities.Attachment {
+	attachments := make([]entities.Attachment, 0)
+	for _, taskList := range board.TaskLists {
+		for _, task := range taskList.Tasks {
+			attachments = append(attachments, task.Attachments...)
+		}
+	}
+
+	return attachments
+}
+
+// cutLetters trims the text and cuts it to at most the given number of letters
+func cutLetters(text string, letters int) string {
+	text = strings.TrimSpace(text)
+	if runes := []rune(text); len(runes) > letters {
+		return strings.TrimSpace(string(runes[:letters]))
+	}
+
+	return text
+}
@@ -0,0 +1,130 @@
+package usecases
+
+import (
+	"archive/zip"
+	"bytes"
+	"path"
+	"path/filepath"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/filestore"
+	"testing"
+)
+
+// fakeFileStorage keeps the uploaded files in memory
+type fakeFileStorage struct {
+	filestore.FileStorage
+
+	files map[string][]byte
+}
+
+func (f *fakeFileStorage) CreateAll(_ string) error {
+	return nil
+}
+
+func (f *fakeFileStorage) UploadFile(path string, data []byte) error {
+	f.files[path] = data
+	return nil
+}
+
+func TestLocalAttachmentPath(t *testing.T) {
+	folder := t.TempDir()
+	i := ImportUseCases{attachmentsFolder: folder}
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "card/file.png", want: filepath.Join(folder, "card", "file.png")},
+		{url: "card/../file.png", want: filepath.Join(folder, "file.png")},
+		{url: filepath.Join(folder, "file.png"), want: filepath.Join(folder, "file.png")},
+		{url: "file://" + filepath.ToSlash(filepath.Join(folder, "file.png")), want: filepath.Join(folder, "file.png")},
+		{url: "../file.png"},
+		{url: "card/../../file.png"},
+		{url: ".."},
+		{url: "/etc/passwd"},
+		{url: "file:///etc/passwd"},
+		{url: "file://" + filepath.ToSlash(folder) + "/../file.png"},
+		{url: filepath.Dir(folder)},
+		{url: folder + "-other/file.png"},
+		{url: "https://trello.com/file.png"},
+	}
+
+	for _, tt := range tests {
+		got, ok := i.localAttachmentPath(tt.url)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("localAttachmentPath(%q) = %q, %v, want %q", tt.url, got, ok, tt.want)
+		}
+	}
+
+	i.attachmentsFolder = ""
+	if _, ok := i.localAttachmentPath("file.png"); ok {
+		t.Error("localAttachmentPath() without an attachments folder = true, want false")
+	}
+}
+
+func TestArchiveAttachmentsDetectContentType(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	html := []byte("<html><script>alert(1)</script></html>")
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`)
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, data := range map[string][]byte{"png": png, "html": html, "svg": svg} {
+		file, err := writer.Create(path.Join(entities.ArchiveAttachmentsFolder, name))
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		_, _ = file.Write(data)
+	}
+
+	err := writer.Close()
+	if err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	// The archive claims the scripts are images, and the image is a page
+	board := &entities.Board{TaskLists: []entities.TaskList{{Tasks: []entities.Task{{
+		Attachments: []entities.Attachment{
+			{UUID: "png", FileName: "image.png", ContentType: "text/html"},
+			{UUID: "html", FileName: "page.png", ContentType: "image/png"},
+			{UUID: "svg", FileName: "image.svg", ContentType: "image/png"},
+		},
+	}}}}}
+
+	storage := &fakeFileStorage{files: make(map[string][]byte)}
+	i := ImportUseCases{fileStorage: storage}
+	stored, err := i.archiveAttachments(files, board, &entities.ImportReport{})
+	if err != nil {
+		t.Fatalf("archiveAttachments() error = %v", err)
+	}
+
+	want := []string{"image/png", "text/html; charset=utf-8", "text/plain; charset=utf-8"}
+	if len(stored) != len(want) {
+		t.Fatalf("archiveAttachments() stored %d attachments, want %d", len(stored), len(want))
+	}
+
+	for j, attachment := range board.TaskLists[0].Tasks[0].Attachments {
+		if attachment.ContentType != want[j] {
+			t.Errorf("%s: content type = %q, want %q", attachment.FileName, attachment.ContentType, want[j])
+		}
+
+		if attachment.IsImage() != (j == 0) {
+			t.Errorf("%s: IsImage() = %v", attachment.FileName, attachment.IsImage())
+		}
+
+		if storage.files[attachment.Path] == nil {
+			t.Errorf("%s: file not stored at %q", attachment.FileName, attachment.Path)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/infrastructure/datastore"
+	"taskflow/infrastructure/scheduler"
+	"time"
+)
+
+// JobUseCases reports the background jobs and their run history to the operators
+type JobUseCases struct {
+	repository datastore.JobRepository
+	scheduler  *scheduler.Scheduler
+}
+
+func NewJobUseCases(repository datastore.JobRepository, scheduler *scheduler.Scheduler) JobUseCases {
+	return JobUseCases{
+		repository: repository,
+		scheduler:  scheduler,
+	}
+}
+
+func (j JobUseCases) GetJobs() []entities.Job {
+	return j.scheduler.Jobs()
+}
+
+// GetJobRuns returns the runs of the background jobs, newest first
+func (j JobUseCases) GetJobRuns(ctx context.Context, filter entities.JobRunFilter) ([]entities.JobRun, error) {
+	filter.Limit = rules.PageLimit(filter.Limit)
+	return j.repository.GetJobRuns(ctx, filter)
+}
+
+// DeleteOldRuns deletes the runs older than rules.JobRunRetention
+func (j JobUseCases) DeleteOldRuns(ctx context.Context) error {
+	deleted, err := j.repository.DeleteJobRuns(ctx, time.Now().Add(-rules.JobRunRetention))
+	if err != nil {
+		return errors.Join(errors.New("failed to delete old job runs"), err)
+	}
+
+	slog.InfoContext(ctx, "deleted old job runs", "count", deleted)
+	return nil
+}
@@ -0,0 +1,343 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/datastore"
+
+	"github.com/google/uuid"
+)
+
+type LabelUseCases struct {
+	repository      datastore.LabelRepository
+	boardRepository datastore.BoardRepository
+	activity        ActivityUseCases
+}
+
+func NewLabelUseCases(
+	repository datastore.LabelRepository,
+	boardRepository datastore.BoardRepository,
+	activity ActivityUseCases,
+) LabelUseCases {
+	return LabelUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		activity:        activity,
+	}
+}
+
+// GetLabels returns the labels of the board, by name
+func (l LabelUseCases) GetLabels(ctx context.Context, user *entities.User, boardID int) ([]entities.Label, error) {
+	board, err := l.boardRepository.GetBoardByID(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, l.boardRepository, board.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.repository.GetLabelsByBoard(ctx, board.ID)
+}
+
+// CreateLabel defines a label on the board. Label names are unique per board, ignoring case.
+func (l LabelUseCases) CreateLabel(
+	ctx context.Context,
+	user *entities.User,
+	label entities.Label,
+) (*entities.Label, status_codes.LabelStatusCode, error) {
+	board, statusCode, err := l.getBoard(ctx, user, label.IDBoard)
+	if board == nil {
+		return nil, statusCode, err
+	}
+
+	label.Name = strings.TrimSpace(label.Name)
+	if !rules.ValidateLabelName(label.Name) {
+		return nil, status_codes.LabelInvalidName, nil
+	}
+
+	label.Color = strings.ToLower(strings.TrimSpace(label.Color))
+	if !rules.ValidateLabelColor(label.Color) {
+		return nil, status_codes.LabelInvalidColor, nil
+	}
+
+	labels, err := l.repository.GetLabelsByBoard(ctx, board.ID)
+	if err != nil {
+		return nil, status_codes.LabelFailure, errors.Join(errors.New("failed to get labels"), err)
+	}
+
+	if len(labels) >= rules.LabelMaxPerBoard {
+		return nil, status_codes.LabelLimitReached, nil
+	}
+
+	if hasLabelNamed(labels, label.Name, 0) {
+		return nil, status_codes.LabelNameAlreadyExist, nil
+	}
+
+	labelUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.LabelFailure, errors.Join(errors.New("failed to generate label UUID"), err)
+	}
+
+	label.UUID = labelUUID.String()
+	label.CreatedBy = *user
+
+	err = l.repository.AddLabel(ctx, &label)
+	if err != nil {
+		return nil, status_codes.LabelFailure, errors.Join(errors.New("failed to save label"), err)
+	}
+
+	l.activity.Record(ctx, entities.Activity{
+		IDBoard:    board.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityLabel,
+		EntityID:   label.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("name", nil, label.Name).
+			set("color", nil, label.Color),
+	})
+
+	return &label, status_codes.LabelSuccess, nil
+}
+
+// UpdateLabel renames and recolors the label
+func (l LabelUseCases) UpdateLabel(
+	ctx context.Context,
+	user *entities.User,
+	label entities.Label,
+) (status_codes.LabelStatusCode, error) {
+	current, statusCode, err := l.getLabel(ctx, user, label.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	label.Name = strings.TrimSpace(label.Name)
+	if !rules.ValidateLabelName(label.Name) {
+		return status_codes.LabelInvalidName, nil
+	}
+
+	label.Color = strings.ToLower(strings.TrimSpace(label.Color))
+	if !rules.ValidateLabelColor(label.Color) {
+		return status_codes.LabelInvalidColor, nil
+	}
+
+	labels, err := l.repository.GetLabelsByBoard(ctx, current.IDBoard)
+	if err != nil {
+		return status_codes.LabelFailure, errors.Join(errors.New("failed to get labels"), err)
+	}
+
+	if hasLabelNamed(labels, label.Name, current.ID) {
+		return status_codes.LabelNameAlreadyExist, nil
+	}
+
+	err = l.repository.UpdateLabel(ctx, &label)
+	if err != nil {
+		return status_codes.LabelFailure, errors.Join(errors.New("failed to update label"), err)
+	}
+
+	l.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityLabel,
+		EntityID:   current.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set("name", current.Name, label.Name).
+			set("color", current.Color, label.Color),
+	})
+
+	return status_codes.LabelSuccess, nil
+}
+
+// DeleteLabel deletes the label, removing it from the tasks tagged with it
+func (l LabelUseCases) DeleteLabel(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.LabelStatusCode, error) {
+	current, statusCode, err := l.getLabel(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	err = l.repository.DeleteLabel(ctx, id)
+	if err != nil {
+		return status_codes.LabelFailure, errors.Join(errors.New("failed to delete label"), err)
+	}
+
+	l.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityLabel,
+		EntityID:   current.ID,
+		Action:     entities.ActivityDeleted,
+		Changes: activityChanges{}.
+			set("name", current.Name, nil).
+			set("color", current.Color, nil),
+	})
+
+	return status_codes.LabelSuccess, nil
+}
+
+// AddTaskLabel tags the task with a label of its board
+func (l LabelUseCases) AddTaskLabel(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	labelID int,
+) (status_codes.LabelStatusCode, error) {
+	task, label, statusCode, err := l.getTaskLabel(ctx, user, taskID, labelID)
+	if task == nil {
+		return statusCode, err
+	}
+
+	added, err := l.repository.AddTaskLabel(ctx, task.ID, label.ID)
+	if err != nil {
+		return status_codes.LabelFailure, errors.Join(errors.New("failed to add task label"), err)
+	}
+
+	if !added {
+		return status_codes.LabelAlreadyAdded, nil
+	}
+
+	l.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityLabel,
+		EntityID:   label.ID,
+		Action:     entities.ActivityAdded,
+		Changes:    activityChanges{}.set("name", nil, label.Name),
+	})
+
+	return status_codes.LabelSuccess, nil
+}
+
+// RemoveTaskLabel removes the label from the task
+func (l LabelUseCases) RemoveTaskLabel(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	labelID int,
+) (status_codes.LabelStatusCode, error) {
+	task, label, statusCode, err := l.getTaskLabel(ctx, user, taskID, labelID)
+	if task == nil {
+		return statusCode, err
+	}
+
+	removed, err := l.repository.RemoveTaskLabel(ctx, task.ID, label.ID)
+	if err != nil {
+		return status_codes.LabelFailure, errors.Join(errors.New("failed to remove task label"), err)
+	}
+
+	if !removed {
+		return status_codes.LabelNotAdded, nil
+	}
+
+	l.activity.Record(ctx, entities.Activity{
+		IDBoard:    task.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityLabel,
+		EntityID:   label.ID,
+		Action:     entities.ActivityRemoved,
+		Changes:    activityChanges{}.set("name", label.Name, nil),
+	})
+
+	return status_codes.LabelSuccess, nil
+}
+
+// getBoard returns the board if the user is a member of it. A nil board is returned along with the status code or
+// error to send back otherwise.
+func (l LabelUseCases) getBoard(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Board, status_codes.LabelStatusCode, error) {
+	board, err := l.boardRepository.GetBoardByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.LabelBoardNotFound, nil
+		}
+
+		return nil, status_codes.LabelFailure, errors.Join(errors.New("failed to get board"), err)
+	}
+
+	err = checkBoardMember(ctx, l.boardRepository, board.ID, user.ID)
+	if err != nil {
+		return nil, status_codes.LabelFailure, err
+	}
+
+	return board, status_codes.LabelSuccess, nil
+}
+
+// getLabel is the same as getBoard, for the board of the label
+func (l LabelUseCases) getLabel(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Label, status_codes.LabelStatusCode, error) {
+	label, err := l.repository.GetLabelByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.LabelNotFound, nil
+		}
+
+		return nil, status_codes.LabelFailure, errors.Join(errors.New("failed to get label"), err)
+	}
+
+	err = checkBoardMember(ctx, l.boardRepository, label.IDBoard, user.ID)
+	if err != nil {
+		return nil, status_codes.LabelFailure, err
+	}
+
+	return label, status_codes.LabelSuccess, nil
+}
+
+// getTaskLabel returns the task and the label of its board if the user is a member of the board. A nil task is
+// returned along with the status code or error to send back otherwise.
+func (l LabelUseCases) getTaskLabel(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	labelID int,
+) (*entities.Task, *entities.Label, status_codes.LabelStatusCode, error) {
+	task, err := l.boardRepository.GetTaskByID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, nil, status_codes.LabelTaskNotFound, nil
+		}
+
+		return nil, nil, status_codes.LabelFailure, errors.Join(errors.New("failed to get task"), err)
+	}
+
+	err = checkBoardMember(ctx, l.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, nil, status_codes.LabelFailure, err
+	}
+
+	label, err := l.repository.GetLabelByID(ctx, labelID)
+	if err != nil && !errors.Is(err, entities.ErrNotFound) {
+		return nil, nil, status_codes.LabelFailure, errors.Join(errors.New("failed to get label"), err)
+	}
+
+	if label == nil || label.IDBoard != task.IDBoard {
+		return nil, nil, status_codes.LabelNotFound, nil
+	}
+
+	return task, label, status_codes.LabelSuccess, nil
+}
+
+// hasLabelNamed tells whether a label other than the excluded one has the name
+func hasLabelNamed(labels []entities.Label, name string, excludedID int) bool {
+	return slices.ContainsFunc(labels, func(label entities.Label) bool {
+		return label.ID != excludedID && strings.EqualFold(label.Name, name)
+	})
+}
@@ -0,0 +1,779 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"taskflow/domain/emails"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/util"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+const (
+	// mentionExcerptLetters limits the text quoted in mention emails
+	mentionExcerptLetters = 500
+
+	// emailTimeLayout formats the dates shown in emails
+	emailTimeLayout = "Mon, Jan 2 2006 15:04 MST"
+)
+
+// NotificationUseCases fills the users' inboxes with the activities that concern them, and emails them according to
+// their preferences
+type NotificationUseCases struct {
+	repository         datastore.NotificationRepository
+	boardRepository    datastore.BoardRepository
+	authRepository     datastore.AuthRepository
+	activityRepository datastore.ActivityRepository
+	email              EmailUseCases
+	securityKey        string
+	publicURL          string
+}
+
+func NewNotificationUseCases(
+	repository datastore.NotificationRepository,
+	boardRepository datastore.BoardRepository,
+	authRepository datastore.AuthRepository,
+	activityRepository datastore.ActivityRepository,
+	email EmailUseCases,
+	securityKey string,
+	publicURL string,
+) NotificationUseCases {
+	return NotificationUseCases{
+		repository:         repository,
+		boardRepository:    boardRepository,
+		authRepository:     authRepository,
+		activityRepository: activityRepository,
+		email:              email,
+		securityKey:        securityKey,
+		publicURL:          strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// OnActivity notifies the users assigned to a task, mentioned in a task description or comment, or watching the task
+// or its board
+func (n NotificationUseCases) OnActivity(ctx context.Context, activity entities.Activity) {
+	// Users are notified at most once per activity, and never of their own activities
+	notified := map[int]bool{activity.Actor.ID: true}
+
+	var err error
+	switch activity.EntityType {
+	case entities.ActivityEntityAssignee:
+		if activity.Action == entities.ActivityAdded {
+			err = n.notifyAssigned(ctx, activity, notified)
+		}
+	case entities.ActivityEntityComment:
+		err = n.notifyMentioned(ctx, activity, "body", notified)
+		if err == nil && activity.Action == entities.ActivityCreated {
+			err = n.notifyWatchers(ctx, activity, entities.NotificationCommented, notified)
+		}
+	case entities.ActivityEntityTask:
+		if activity.Action == entities.ActivityDeleted {
+			return
+		}
+
+		err = n.notifyMentioned(ctx, activity, "description", notified)
+		if err == nil {
+			err = n.notifyWatchers(ctx, activity, entities.NotificationTaskChanged, notified)
+		}
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to send notifications", "activity", activity.ID, "cause", err)
+	}
+}
+
+// GetNotifications returns the notifications of the user's inbox, newest first
+func (n NotificationUseCases) GetNotifications(
+	ctx context.Context,
+	user *entities.User,
+	filter entities.NotificationFilter,
+) ([]entities.Notification, error) {
+	filter.IDUser = user.ID
+	filter.Limit = rules.PageLimit(filter.Limit)
+
+	return n.repository.GetNotifications(ctx, filter)
+}
+
+func (n NotificationUseCases) GetUnreadCount(ctx context.Context, user *entities.User) (int, error) {
+	return n.repository.CountUnreadNotifications(ctx, user.ID)
+}
+
+func (n NotificationUseCases) MarkRead(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.NotificationStatusCode, error) {
+	found, err := n.repository.MarkNotificationRead(ctx, user.ID, id, time.Now())
+	if err != nil {
+		return status_codes.NotificationFailure, errors.Join(errors.New("failed to mark notification as read"), err)
+	}
+
+	if !found {
+		return status_codes.NotificationNotFound, nil
+	}
+
+	return status_codes.NotificationSuccess, nil
+}
+
+func (n NotificationUseCases) MarkAllRead(
+	ctx context.Context,
+	user *entities.User,
+) (status_codes.NotificationStatusCode, error) {
+	err := n.repository.MarkAllNotificationsRead(ctx, user.ID, time.Now())
+	if err != nil {
+		return status_codes.NotificationFailure, errors.Join(errors.New("failed to mark notifications as read"), err)
+	}
+
+	return status_codes.NotificationSuccess, nil
+}
+
+// WatchBoard sends the comments and task changes of every task of the board to the user's inbox
+func (n NotificationUseCases) WatchBoard(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+) (status_codes.NotificationStatusCode, error) {
+	statusCode, err := n.checkBoardWatcher(ctx, user, boardID)
+	if statusCode != status_codes.NotificationSuccess || err != nil {
+		return statusCode, err
+	}
+
+	err = n.repository.WatchBoard(ctx, boardID, user.ID)
+	if err != nil {
+		return status_codes.NotificationFailure, errors.Join(errors.New("failed to watch board"), err)
+	}
+
+	return status_codes.NotificationSuccess, nil
+}
+
+func (n NotificationUseCases) UnwatchBoard(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+) (status_codes.NotificationStatusCode, error) {
+	statusCode, err := n.checkBoardWatcher(ctx, user, boardID)
+	if statusCode != status_codes.NotificationSuccess || err != nil {
+		return statusCode, err
+	}
+
+	err = n.repository.UnwatchBoard(ctx, boardID, user.ID)
+	if err != nil {
+		return status_codes.NotificationFailure, errors.Join(errors.New("failed to unwatch board"), err)
+	}
+
+	return status_codes.NotificationSuccess, nil
+}
+
+// WatchTask sends the comments and changes of the task to the user's inbox
+func (n NotificationUseCases) WatchTask(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+) (status_codes.NotificationStatusCode, error) {
+	statusCode, err := n.checkTaskWatcher(ctx, user, taskID)
+	if statusCode != status_codes.NotificationSuccess || err != nil {
+		return statusCode, err
+	}
+
+	err = n.repository.WatchTask(ctx, taskID, user.ID)
+	if err != nil {
+		return status_codes.NotificationFailure, errors.Join(errors.New("failed to watch task"), err)
+	}
+
+	return status_codes.NotificationSuccess, nil
+}
+
+func (n NotificationUseCases) UnwatchTask(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+) (status_codes.NotificationStatusCode, error) {
+	statusCode, err := n.checkTaskWatcher(ctx, user, taskID)
+	if statusCode != status_codes.NotificationSuccess || err != nil {
+		return statusCode, err
+	}
+
+	err = n.repository.UnwatchTask(ctx, taskID, user.ID)
+	if err != nil {
+		return status_codes.NotificationFailure, errors.Join(errors.New("failed to unwatch task"), err)
+	}
+
+	return status_codes.NotificationSuccess, nil
+}
+
+func (n NotificationUseCases) GetPreferences(
+	ctx context.Context,
+	user *entities.User,
+) (*entities.NotificationPreferences, error) {
+	return n.repository.GetPreferences(ctx, user.ID)
+}
+
+func (n NotificationUseCases) UpdatePreferences(
+	ctx context.Context,
+	user *entities.User,
+	preferences entities.NotificationPreferences,
+) (status_codes.NotificationStatusCode, error) {
+	preferences.IDUser = user.ID
+
+	err := n.repository.SavePreferences(ctx, &preferences)
+	if err != nil {
+		return status_codes.NotificationFailure, errors.Join(errors.New("failed to save preferences"), err)
+	}
+
+	return status_codes.NotificationSuccess, nil
+}
+
+// Unsubscribe turns off a notification type for the user of a signed unsubscribe link, without a session
+func (n NotificationUseCases) Unsubscribe(
+	ctx context.Context,
+	userID int,
+	notificationType entities.NotificationType,
+	signature string,
+) (status_codes.NotificationStatusCode, error) {
+	if !validNotificationType(notificationType) {
+		return status_codes.NotificationInvalidType, nil
+	}
+
+	if !util.CheckUnsubscribeSignature(userID, string(notificationType), signature, n.securityKey) {
+		return status_codes.NotificationInvalidSignature, nil
+	}
+
+	preferences, err := n.repository.GetPreferences(ctx, userID)
+	if err != nil {
+		return status_codes.NotificationFailure, errors.Join(errors.New("failed to get preferences"), err)
+	}
+
+	preferences.Unsubscribe(notificationType)
+
+	err = n.repository.SavePreferences(ctx, preferences)
+	if err != nil {
+		return status_codes.NotificationFailure, errors.Join(errors.New("failed to save preferences"), err)
+	}
+
+	return status_codes.NotificationSuccess, nil
+}
+
+// SendDueSoonReminders emails the assignees of the unfinished tasks due within rules.DueSoonWindow. Each task is only
+// reminded once per due date.
+func (n NotificationUseCases) SendDueSoonReminders(ctx context.Context) error {
+	tasks, err := n.boardRepository.GetTasksDueBefore(ctx, time.Now().Add(rules.DueSoonWindow))
+	if err != nil {
+		return errors.Join(errors.New("failed to get tasks due soon"), err)
+	}
+
+	boards := make(map[int]*entities.Board)
+	for _, task := range tasks {
+		board, found := boards[task.IDBoard]
+		if !found {
+			board, err = n.boardRepository.GetBoardByID(ctx, task.IDBoard)
+			if err != nil {
+				return errors.Join(errors.New("failed to get task board"), err)
+			}
+			boards[task.IDBoard] = board
+		}
+
+		assignees, err := n.boardRepository.GetTaskAssignees(ctx, task.ID)
+		if err != nil {
+			return errors.Join(errors.New("failed to get task assignees"), err)
+		}
+
+		dueDate := formatEmailTime(task.DueDate)
+		for _, assignee := range assignees {
+			err = n.notify(
+				ctx,
+				assignee,
+				entities.Notification{
+					Type:    entities.NotificationDueSoon,
+					IDBoard: task.IDBoard,
+					IDTask:  task.ID,
+					Message: fmt.Sprintf("%q is due on %s", task.Name, dueDate),
+				},
+				emails.TaskData{
+					BoardTitle: board.Title,
+					TaskName:   task.Name,
+					DueDate:    dueDate,
+				},
+			)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to send due soon reminder", "task", task.ID, "cause", err)
+			}
+		}
+
+		err = n.boardRepository.SetTaskReminded(ctx, task.ID, time.Now())
+		if err != nil {
+			return errors.Join(errors.New("failed to mark task as reminded"), err)
+		}
+	}
+
+	return nil
+}
+
+// SendDailyDigests emails a digest to the users who turned it on and were not sent one for a digest interval. A user
+// whose digest fails is left for the next run, while a user with nothing to read is marked as sent without an email.
+func (n NotificationUseCases) SendDailyDigests(ctx context.Context) error {
+	now := time.Now()
+
+	subscribers, err := n.repository.GetDigestSubscribers(ctx, now.Add(-rules.DigestInterval))
+	if err != nil {
+		return errors.Join(errors.New("failed to get digest subscribers"), err)
+	}
+
+	for _, subscriber := range subscribers {
+		err = n.sendDigest(ctx, subscriber, now)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to send daily digest", "user", subscriber.IDUser, "cause", err)
+			continue
+		}
+
+		err = n.repository.SetDigestSent(ctx, subscriber.IDUser, now)
+		if err != nil {
+			return errors.Join(errors.New("failed to mark digest as sent"), err)
+		}
+	}
+
+	return nil
+}
+
+// getAllBoards returns every board of the user, going through all the pages
+func (n NotificationUseCases) getAllBoards(ctx context.Context, userID int) ([]entities.Board, error) {
+	filter := entities.BoardFilter{IDUser: userID, Limit: rules.PageMaxLimit}
+	boards := make([]entities.Board, 0)
+	for {
+		page, err := n.boardRepository.GetBoards(ctx, filter)
+		if err != nil {
+			return nil, errors.Join(errors.New("failed to get boards"), err)
+		}
+
+		boards = append(boards, page...)
+		if len(page) < filter.Limit {
+			return boards, nil
+		}
+
+		filter.AfterID = page[len(page)-1].ID
+	}
+}
+
+// sendDigest queues the email listing, board by board and oldest first, the activities of the other members since
+// the last digest, or for a digest interval if none was sent. Only the latest rules.DigestMaxActivities of each board
+// are read, the user's own being then skipped, and no email is queued if no board has any left.
+func (n NotificationUseCases) sendDigest(
+	ctx context.Context,
+	subscriber entities.NotificationPreferences,
+	now time.Time,
+) error {
+	user, err := n.authRepository.GetUserByID(ctx, subscriber.IDUser)
+	if err != nil {
+		return errors.Join(errors.New("failed to get user"), err)
+	}
+
+	since := now.Add(-rules.DigestInterval)
+	if subscriber.DigestSentAt != nil {
+		since = *subscriber.DigestSentAt
+	}
+
+	boards, err := n.getAllBoards(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	data := emails.DigestData{
+		Recipient:      user.Email,
+		Boards:         make([]emails.DigestBoard, 0),
+		UnsubscribeURL: n.unsubscribeURL(user.ID, entities.NotificationDailyDigest),
+	}
+
+	for _, board := range boards {
+		activities, err := n.activityRepository.GetActivities(ctx, entities.ActivityFilter{
+			IDBoard: board.ID,
+			Since:   since,
+			Limit:   rules.DigestMaxActivities,
+		})
+		if err != nil {
+			return errors.Join(errors.New("failed to get board activities"), err)
+		}
+
+		// The activities are listed newest first, the digest reading them in order
+		slices.Reverse(activities)
+
+		digestBoard := emails.DigestBoard{Title: board.Title}
+		for _, activity := range activities {
+			if activity.Actor.ID == user.ID {
+				continue
+			}
+
+			digestBoard.Activities = append(digestBoard.Activities, emails.DigestEntry{
+				Time:    activity.CreatedAt.UTC().Format("Jan 2 15:04"),
+				Summary: activitySummary(activity),
+			})
+		}
+
+		if len(digestBoard.Activities) > 0 {
+			data.Boards = append(data.Boards, digestBoard)
+		}
+	}
+
+	if len(data.Boards) == 0 {
+		return nil
+	}
+
+	return n.email.Queue(ctx, user.Email, emails.TemplateDailyDigest, data, data.UnsubscribeURL)
+}
+
+func (n NotificationUseCases) notifyAssigned(
+	ctx context.Context,
+	activity entities.Activity,
+	notified map[int]bool,
+) error {
+	if notified[activity.EntityID] {
+		return nil
+	}
+
+	task, board, err := n.getTaskAndBoard(ctx, activity.IDTask)
+	if err != nil {
+		return err
+	}
+
+	assignee, err := n.authRepository.GetUserByID(ctx, activity.EntityID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get assignee"), err)
+	}
+
+	notified[assignee.ID] = true
+	return n.notify(
+		ctx,
+		*assignee,
+		activityNotification(activity, entities.NotificationAssigned, fmt.Sprintf(
+			"%s assigned you to %q",
+			activity.Actor.Email,
+			task.Name,
+		)),
+		emails.TaskData{
+			Actor:      activity.Actor.Email,
+			BoardTitle: board.Title,
+			TaskName:   task.Name,
+			DueDate:    formatEmailTime(task.DueDate),
+		},
+	)
+}
+
+// notifyMentioned notifies the board members newly mentioned in the given text field of the activity
+func (n NotificationUseCases) notifyMentioned(
+	ctx context.Context,
+	activity entities.Activity,
+	field string,
+	notified map[int]bool,
+) error {
+	change, found := activity.Changes[field]
+	if !found {
+		return nil
+	}
+
+	after, _ := change.After.(string)
+	before, _ := change.Before.(string)
+
+	mentions := newMentions(before, after)
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	task, board, err := n.getTaskAndBoard(ctx, activity.IDTask)
+	if err != nil {
+		return err
+	}
+
+	members, err := n.boardRepository.GetBoardMembers(ctx, board.ID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get board members"), err)
+	}
+
+	members = append(members, board.CreatedBy)
+	for _, member := range members {
+		if notified[member.ID] || !mentionsUser(mentions, member.Email) {
+			continue
+		}
+
+		notified[member.ID] = true
+		err = n.notify(
+			ctx,
+			member,
+			activityNotification(activity, entities.NotificationMentioned, fmt.Sprintf(
+				"%s mentioned you on %q",
+				activity.Actor.Email,
+				task.Name,
+			)),
+			emails.TaskData{
+				Actor:      activity.Actor.Email,
+				BoardTitle: board.Title,
+				TaskName:   task.Name,
+				Excerpt:    truncate(after, mentionExcerptLetters),
+			},
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to notify mention", "user", member.ID, "cause", err)
+		}
+	}
+
+	return nil
+}
+
+// notifyWatchers adds the activity to the inbox of the users watching its task or board
+func (n NotificationUseCases) notifyWatchers(
+	ctx context.Context,
+	activity entities.Activity,
+	notificationType entities.NotificationType,
+	notified map[int]bool,
+) error {
+	watchers, err := n.repository.GetWatchers(ctx, activity.IDBoard, activity.IDTask)
+	if err != nil {
+		return errors.Join(errors.New("failed to get watchers"), err)
+	}
+
+	if len(watchers) == 0 {
+		return nil
+	}
+
+	task, err := n.boardRepository.GetTaskByID(ctx, activity.IDTask)
+	if err != nil {
+		return errors.Join(errors.New("failed to get task"), err)
+	}
+
+	message := fmt.Sprintf("%s %s %q", activity.Actor.Email, activity.Action, task.Name)
+	if notificationType == entities.NotificationCommented {
+		message = fmt.Sprintf("%s commented on %q", activity.Actor.Email, task.Name)
+	}
+
+	for _, watcherID := range watchers {
+		if notified[watcherID] {
+			continue
+		}
+
+		// Watchers may have lost access to the board since they started watching it
+		member, err := n.boardRepository.IsBoardMember(ctx, activity.IDBoard, watcherID)
+		if err != nil {
+			return errors.Join(errors.New("failed to check board membership"), err)
+		}
+
+		if !member {
+			continue
+		}
+
+		notified[watcherID] = true
+		err = n.notify(
+			ctx,
+			entities.User{ID: watcherID},
+			activityNotification(activity, notificationType, message),
+			emails.TaskData{},
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to notify watcher", "user", watcherID, "cause", err)
+		}
+	}
+
+	return nil
+}
+
+// notify adds the notification to the recipient's inbox and queues an email about it, if the recipient wants this
+// type of email
+func (n NotificationUseCases) notify(
+	ctx context.Context,
+	recipient entities.User,
+	notification entities.Notification,
+	data emails.TaskData,
+) error {
+	notification.IDUser = recipient.ID
+	notification.Message = truncate(notification.Message, rules.NotificationMaxLetters)
+
+	err := n.repository.AddNotification(ctx, &notification)
+	if err != nil {
+		return errors.Join(errors.New("failed to add notification"), err)
+	}
+
+	if !notification.Type.Emailed() {
+		return nil
+	}
+
+	preferences, err := n.repository.GetPreferences(ctx, recipient.ID)
+	if err != nil {
+		return errors.Join(errors.New("failed to get preferences"), err)
+	}
+
+	if !preferences.Wants(notification.Type) {
+		return nil
+	}
+
+	data.Recipient = recipient.Email
+	data.UnsubscribeURL = n.unsubscribeURL(recipient.ID, notification.Type)
+
+	return n.email.Queue(ctx, recipient.Email, string(notification.Type), data, data.UnsubscribeURL)
+}
+
+// checkBoardWatcher checks that the board exists and the user can watch it
+func (n NotificationUseCases) checkBoardWatcher(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+) (status_codes.NotificationStatusCode, error) {
+	_, err := n.boardRepository.GetBoardByID(ctx, boardID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return status_codes.NotificationBoardNotFound, nil
+		}
+
+		return status_codes.NotificationFailure, errors.Join(errors.New("failed to get board"), err)
+	}
+
+	err = checkBoardMember(ctx, n.boardRepository, boardID, user.ID)
+	if err != nil {
+		return status_codes.NotificationFailure, err
+	}
+
+	return status_codes.NotificationSuccess, nil
+}
+
+// checkTaskWatcher checks that the task exists and the user can watch it
+func (n NotificationUseCases) checkTaskWatcher(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+) (status_codes.NotificationStatusCode, error) {
+	task, err := n.boardRepository.GetTaskByID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return status_codes.NotificationTaskNotFound, nil
+		}
+
+		return status_codes.NotificationFailure, errors.Join(errors.New("failed to get task"), err)
+	}
+
+	err = checkBoardMember(ctx, n.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return status_codes.NotificationFailure, err
+	}
+
+	return status_codes.NotificationSuccess, nil
+}
+
+func (n NotificationUseCases) getTaskAndBoard(ctx context.Context, taskID int) (*entities.Task, *entities.Board, error) {
+	task, err := n.boardRepository.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, nil, errors.Join(errors.New("failed to get task"), err)
+	}
+
+	board, err := n.boardRepository.GetBoardByID(ctx, task.IDBoard)
+	if err != nil {
+		return nil, nil, errors.Join(errors.New("failed to get board"), err)
+	}
+
+	return task, board, nil
+}
+
+// unsubscribeURL returns the signed link turning off the notification type for the user
+func (n NotificationUseCases) unsubscribeURL(userID int, notificationType entities.NotificationType) string {
+	query := url.Values{}
+	query.Set("user", fmt.Sprint(userID))
+	query.Set("type", string(notificationType))
+	query.Set("signature", util.SignUnsubscribe(userID, string(notificationType), n.securityKey))
+
+	return n.publicURL + "/api/notifications/unsubscribe?" + query.Encode()
+}
+
+// activityNotification returns the inbox notification of the given type about the activity
+func activityNotification(
+	activity entities.Activity,
+	notificationType entities.NotificationType,
+	message string,
+) entities.Notification {
+	actor := activity.Actor
+	return entities.Notification{
+		Type:       notificationType,
+		IDBoard:    activity.IDBoard,
+		IDTask:     activity.IDTask,
+		IDActivity: activity.ID,
+		Actor:      &actor,
+		Message:    message,
+	}
+}
+
+// newMentions returns the mentions of the text after a change that were not in the text before it
+func newMentions(before string, after string) []string {
+	previous := make(map[string]bool)
+	for _, mention := range rules.ParseMentions(before) {
+		previous[mention] = true
+	}
+
+	mentions := make([]string, 0)
+	for _, mention := range rules.ParseMentions(after) {
+		if !previous[mention] {
+			mentions = append(mentions, mention)
+		}
+	}
+
+	return mentions
+}
+
+func mentionsUser(mentions []string, email string) bool {
+	for _, mention := range mentions {
+		if rules.MentionMatches(mention, email) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validNotificationType(notificationType entities.NotificationType) bool {
+	switch notificationType {
+	case entities.NotificationAssigned,
+		entities.NotificationMentioned,
+		entities.NotificationDueSoon,
+		entities.NotificationDailyDigest,
+		entities.NotificationAll:
+		return true
+	default:
+		return false
+	}
+}
+
+// activitySummary describes the activity in a sentence, such as `ana@example.com moved task "Deploy"`
+func activitySummary(activity entities.Activity) string {
+	summary := fmt.Sprintf(
+		"%s %s %s",
+		activity.Actor.Email,
+		activity.Action,
+		strings.ReplaceAll(string(activity.EntityType), "_", " "),
+	)
+
+	for _, field := range []string{"name", "title", "email"} {
+		change, found := activity.Changes[field]
+		if !found {
+			continue
+		}
+
+		value := change.After
+		if value == nil {
+			value = change.Before
+		}
+
+		if text, ok := value.(string); ok && text != "" {
+			return summary + fmt.Sprintf(" %q", text)
+		}
+	}
+
+	return summary
+}
+
+func formatEmailTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(emailTimeLayout)
+}
@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/util"
+	"taskflow/infrastructure/datastore"
+)
+
+type SearchUseCases struct {
+	repository datastore.SearchRepository
+}
+
+func NewSearchUseCases(repository datastore.SearchRepository) SearchUseCases {
+	return SearchUseCases{
+		repository: repository,
+	}
+}
+
+// Search returns the boards, tasks and comments of the boards the user can access matching every word of the query,
+// the best ranked first. Matching words are highlighted in the titles and snippets.
+func (s SearchUseCases) Search(
+	ctx context.Context,
+	user *entities.User,
+	query string,
+	filter entities.SearchFilter,
+) ([]entities.SearchResult, status_codes.SearchStatusCode, error) {
+	filter.Terms = util.SearchTerms(query, rules.SearchMinTermLetters, rules.SearchMaxTerms)
+	if len(filter.Terms) == 0 {
+		return nil, status_codes.SearchInvalidQuery, nil
+	}
+
+	for _, resultType := range filter.Types {
+		switch resultType {
+		case entities.SearchResultBoard, entities.SearchResultTask, entities.SearchResultComment:
+		default:
+			return nil, status_codes.SearchInvalidType, nil
+		}
+	}
+
+	filter.IDUser = user.ID
+	filter.Limit = rules.PageLimit(filter.Limit)
+
+	results, err := s.repository.Search(ctx, filter)
+	if err != nil {
+		return nil, status_codes.SearchFailure, errors.Join(errors.New("failed to search"), err)
+	}
+
+	for i := range results {
+		results[i].Title = util.Mark(results[i].Title, filter.Terms)
+		results[i].Snippet = util.Highlight(results[i].Snippet, filter.Terms, rules.SearchSnippetLetters)
+	}
+
+	return results, status_codes.SearchSuccess, nil
+}
@@ -0,0 +1,585 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/datastore"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SprintUseCases struct {
+	repository      datastore.SprintRepository
+	boardRepository datastore.BoardRepository
+	activity        ActivityUseCases
+}
+
+func NewSprintUseCases(
+	repository datastore.SprintRepository,
+	boardRepository datastore.BoardRepository,
+	activity ActivityUseCases,
+) SprintUseCases {
+	return SprintUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		activity:        activity,
+	}
+}
+
+// GetSprints returns the sprints of the board, by start date. Only the members of the board can see them.
+func (s SprintUseCases) GetSprints(ctx context.Context, user *entities.User, boardID int) ([]entities.Sprint, error) {
+	board, err := s.boardRepository.GetBoardByID(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, s.boardRepository, board.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetSprintsByBoard(ctx, board.ID)
+}
+
+// GetSprint returns the sprint along with its tasks
+func (s SprintUseCases) GetSprint(ctx context.Context, user *entities.User, id int) (*entities.Sprint, error) {
+	sprint, err := s.getMemberSprint(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sprint.Tasks, err = s.repository.GetSprintTasks(ctx, sprint.IDBoard, sprint.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get sprint tasks"), err)
+	}
+
+	return sprint, nil
+}
+
+// CreateSprint plans a sprint on the board. Any member of the board can plan sprints.
+func (s SprintUseCases) CreateSprint(
+	ctx context.Context,
+	user *entities.User,
+	sprint entities.Sprint,
+) (*entities.Sprint, status_codes.SprintStatusCode, error) {
+	board, err := s.boardRepository.GetBoardByID(ctx, sprint.IDBoard)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.SprintBoardNotFound, nil
+		}
+
+		return nil, status_codes.SprintFailure, errors.Join(errors.New("failed to get board"), err)
+	}
+
+	err = checkBoardMember(ctx, s.boardRepository, board.ID, user.ID)
+	if err != nil {
+		return nil, status_codes.SprintFailure, err
+	}
+
+	statusCode := validateSprint(&sprint)
+	if statusCode != status_codes.SprintSuccess {
+		return nil, statusCode, nil
+	}
+
+	sprintUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.SprintFailure, errors.Join(errors.New("failed to generate sprint UUID"), err)
+	}
+
+	sprint.UUID = sprintUUID.String()
+	sprint.Status = entities.SprintPlanned
+	sprint.StartedAt = nil
+	sprint.ClosedAt = nil
+	sprint.TaskCount = 0
+	sprint.Tasks = nil
+	sprint.CreatedBy = *user
+
+	err = s.repository.AddSprint(ctx, &sprint)
+	if err != nil {
+		return nil, status_codes.SprintFailure, errors.Join(errors.New("failed to save sprint"), err)
+	}
+
+	s.activity.Record(ctx, entities.Activity{
+		IDBoard:    board.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntitySprint,
+		EntityID:   sprint.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("name", nil, sprint.Name).
+			set("start_date", nil, sprint.StartDate.Format(time.DateOnly)).
+			set("end_date", nil, sprint.EndDate.Format(time.DateOnly)),
+	})
+
+	return &sprint, status_codes.SprintSuccess, nil
+}
+
+// UpdateSprint updates the name, goal and dates of a sprint not closed yet
+func (s SprintUseCases) UpdateSprint(
+	ctx context.Context,
+	user *entities.User,
+	sprint entities.Sprint,
+) (status_codes.SprintStatusCode, error) {
+	current, statusCode, err := s.getSprint(ctx, user, sprint.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	if current.Status == entities.SprintClosed {
+		return status_codes.SprintAlreadyClosed, nil
+	}
+
+	statusCode = validateSprint(&sprint)
+	if statusCode != status_codes.SprintSuccess {
+		return statusCode, nil
+	}
+
+	err = s.repository.UpdateSprint(ctx, &sprint)
+	if err != nil {
+		return status_codes.SprintFailure, errors.Join(errors.New("failed to update sprint"), err)
+	}
+
+	s.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntitySprint,
+		EntityID:   current.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set("name", current.Name, sprint.Name).
+			set("goal", current.Goal, sprint.Goal).
+			set("start_date", current.StartDate.Format(time.DateOnly), sprint.StartDate.Format(time.DateOnly)).
+			set("end_date", current.EndDate.Format(time.DateOnly), sprint.EndDate.Format(time.DateOnly)),
+	})
+
+	return status_codes.SprintSuccess, nil
+}
+
+// DeleteSprint deletes a sprint, its tasks being left uncommitted
+func (s SprintUseCases) DeleteSprint(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.SprintStatusCode, error) {
+	current, statusCode, err := s.getSprint(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	err = s.repository.DeleteSprint(ctx, current.ID)
+	if err != nil {
+		return status_codes.SprintFailure, errors.Join(errors.New("failed to delete sprint"), err)
+	}
+
+	s.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntitySprint,
+		EntityID:   current.ID,
+		Action:     entities.ActivityDeleted,
+		Changes:    activityChanges{}.set("name", current.Name, nil),
+	})
+
+	return status_codes.SprintSuccess, nil
+}
+
+// StartSprint makes a planned sprint active. Only one sprint of a board is active at a time, SprintOtherActive being
+// returned while another one runs.
+func (s SprintUseCases) StartSprint(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.SprintStatusCode, error) {
+	current, statusCode, err := s.getSprint(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	if current.Status != entities.SprintPlanned {
+		return status_codes.SprintNotPlanned, nil
+	}
+
+	started, err := s.repository.StartSprint(ctx, current.ID, time.Now())
+	if err != nil {
+		return status_codes.SprintFailure, errors.Join(errors.New("failed to start sprint"), err)
+	}
+
+	if !started {
+		return status_codes.SprintOtherActive, nil
+	}
+
+	s.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntitySprint,
+		EntityID:   current.ID,
+		Action:     entities.ActivityStarted,
+		Changes:    activityChanges{}.set("status", current.Status, entities.SprintActive),
+	})
+
+	return status_codes.SprintSuccess, nil
+}
+
+// CloseSprint closes the active sprint, its unfinished tasks being carried over. They are committed to the target
+// sprint, which must be a planned sprint of the same board, or go back to the backlog when the target is zero.
+func (s SprintUseCases) CloseSprint(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	targetID int,
+) (*entities.Sprint, status_codes.SprintStatusCode, error) {
+	current, statusCode, err := s.getSprint(ctx, user, id)
+	if current == nil {
+		return nil, statusCode, err
+	}
+
+	if current.Status != entities.SprintActive {
+		return nil, status_codes.SprintNotActive, nil
+	}
+
+	if targetID != 0 {
+		target, err := s.repository.GetSprintByID(ctx, targetID)
+		if err != nil && !errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.SprintFailure, errors.Join(errors.New("failed to get target sprint"), err)
+		}
+
+		if target == nil || target.IDBoard != current.IDBoard || target.Status != entities.SprintPlanned {
+			return nil, status_codes.SprintInvalidTarget, nil
+		}
+	}
+
+	carried, closed, err := s.repository.CloseSprint(ctx, current.ID, targetID, time.Now())
+	if err != nil {
+		return nil, status_codes.SprintFailure, errors.Join(errors.New("failed to close sprint"), err)
+	}
+
+	if !closed {
+		return nil, status_codes.SprintNotActive, nil
+	}
+
+	changes := activityChanges{}.
+		set("status", current.Status, entities.SprintClosed).
+		set("carried_over", nil, len(carried))
+	if targetID != 0 {
+		changes.set("carry_over_to", nil, targetID)
+	}
+
+	s.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntitySprint,
+		EntityID:   current.ID,
+		Action:     entities.ActivityClosed,
+		Changes:    changes,
+	})
+
+	if targetID != 0 {
+		for _, taskID := range carried {
+			s.activity.Record(ctx, entities.Activity{
+				IDBoard:    current.IDBoard,
+				IDTask:     taskID,
+				Actor:      *user,
+				EntityType: entities.ActivityEntitySprint,
+				EntityID:   targetID,
+				Action:     entities.ActivityAdded,
+				Changes:    activityChanges{}.set("id_sprint", current.ID, targetID),
+			})
+		}
+	}
+
+	sprint, err := s.repository.GetSprintByID(ctx, current.ID)
+	if err != nil {
+		return nil, status_codes.SprintFailure, errors.Join(errors.New("failed to get sprint"), err)
+	}
+
+	return sprint, status_codes.SprintSuccess, nil
+}
+
+// AddTask commits a task of the board to a sprint not closed yet. A task is committed to one such sprint at most.
+func (s SprintUseCases) AddTask(
+	ctx context.Context,
+	user *entities.User,
+	sprintID int,
+	taskID int,
+) (status_codes.SprintStatusCode, error) {
+	sprint, task, statusCode, err := s.getSprintTask(ctx, user, sprintID, taskID)
+	if sprint == nil {
+		return statusCode, err
+	}
+
+	current, err := s.repository.GetTaskSprint(ctx, task.ID)
+	if err != nil {
+		return status_codes.SprintFailure, errors.Join(errors.New("failed to get task sprint"), err)
+	}
+
+	if current == sprint.ID {
+		return status_codes.SprintTaskAlreadyAdded, nil
+	}
+
+	if current != 0 {
+		return status_codes.SprintTaskInOtherSprint, nil
+	}
+
+	err = s.repository.AddSprintTask(ctx, sprint.ID, task.ID)
+	if err != nil {
+		return status_codes.SprintFailure, errors.Join(errors.New("failed to add sprint task"), err)
+	}
+
+	s.activity.Record(ctx, entities.Activity{
+		IDBoard:    sprint.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntitySprint,
+		EntityID:   sprint.ID,
+		Action:     entities.ActivityAdded,
+		Changes:    activityChanges{}.set("id_sprint", nil, sprint.ID),
+	})
+
+	return status_codes.SprintSuccess, nil
+}
+
+// RemoveTask takes a task out of a sprint not closed yet, the tasks of closed sprints being kept for their reports
+func (s SprintUseCases) RemoveTask(
+	ctx context.Context,
+	user *entities.User,
+	sprintID int,
+	taskID int,
+) (status_codes.SprintStatusCode, error) {
+	sprint, task, statusCode, err := s.getSprintTask(ctx, user, sprintID, taskID)
+	if sprint == nil {
+		return statusCode, err
+	}
+
+	removed, err := s.repository.RemoveSprintTask(ctx, sprint.ID, task.ID)
+	if err != nil {
+		return status_codes.SprintFailure, errors.Join(errors.New("failed to remove sprint task"), err)
+	}
+
+	if !removed {
+		return status_codes.SprintTaskNotInSprint, nil
+	}
+
+	s.activity.Record(ctx, entities.Activity{
+		IDBoard:    sprint.IDBoard,
+		IDTask:     task.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntitySprint,
+		EntityID:   sprint.ID,
+		Action:     entities.ActivityRemoved,
+		Changes:    activityChanges{}.set("id_sprint", sprint.ID, nil),
+	})
+
+	return status_codes.SprintSuccess, nil
+}
+
+// GetBurndown returns the tasks and estimate remaining at the end of every day of the sprint. A task remains from the
+// day it was committed until the day it was last finished. The remaining series are empty until the sprint starts,
+// and stop at the current day, or the day the sprint closed.
+func (s SprintUseCases) GetBurndown(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.SprintBurndown, error) {
+	sprint, err := s.getMemberSprint(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.repository.GetSprintTasks(ctx, sprint.IDBoard, sprint.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get sprint tasks"), err)
+	}
+
+	start := sprint.StartDate.UTC().Truncate(24 * time.Hour)
+	end := sprint.EndDate.UTC().Truncate(24 * time.Hour)
+
+	last := start.AddDate(0, 0, -1)
+	if sprint.StartedAt != nil {
+		last = time.Now().UTC().Truncate(24 * time.Hour)
+		if sprint.ClosedAt != nil {
+			last = sprint.ClosedAt.UTC().Truncate(24 * time.Hour)
+		}
+	}
+
+	burndown := &entities.SprintBurndown{
+		Days:              make([]string, 0),
+		RemainingTasks:    make([]int, 0),
+		RemainingEstimate: make([]int, 0),
+		IdealEstimate:     make([]float64, 0),
+	}
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		burndown.Days = append(burndown.Days, day.Format(time.DateOnly))
+		if day.After(last) {
+			continue
+		}
+
+		dayEnd := day.AddDate(0, 0, 1)
+		remainingTasks := 0
+		remainingEstimate := 0
+		for _, task := range tasks {
+			if !task.AddedAt.Before(dayEnd) || (task.CompletedAt != nil && task.CompletedAt.Before(dayEnd)) {
+				continue
+			}
+
+			remainingTasks++
+			remainingEstimate += task.Task.OriginalEstimate
+		}
+
+		burndown.RemainingTasks = append(burndown.RemainingTasks, remainingTasks)
+		burndown.RemainingEstimate = append(burndown.RemainingEstimate, remainingEstimate)
+	}
+
+	committed := 0
+	for _, task := range tasks {
+		if isCommittedAtStart(*sprint, task) {
+			committed += task.Task.OriginalEstimate
+		}
+	}
+
+	days := len(burndown.Days)
+	for i := range days {
+		ideal := float64(committed)
+		if days > 1 {
+			ideal = float64(committed) * float64(days-1-i) / float64(days-1)
+		}
+
+		burndown.IdealEstimate = append(burndown.IdealEstimate, math.Round(ideal*100)/100)
+	}
+
+	return burndown, nil
+}
+
+// GetReport sums up the tasks committed to the sprint when it started and after, along with the ones completed and
+// left unfinished
+func (s SprintUseCases) GetReport(ctx context.Context, user *entities.User, id int) (*entities.SprintReport, error) {
+	sprint, err := s.getMemberSprint(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sprint.Tasks, err = s.repository.GetSprintTasks(ctx, sprint.IDBoard, sprint.ID)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get sprint tasks"), err)
+	}
+
+	report := &entities.SprintReport{Sprint: *sprint}
+	for _, task := range sprint.Tasks {
+		scope := entities.SprintScope{Tasks: 1, Estimate: task.Task.OriginalEstimate}
+
+		if isCommittedAtStart(*sprint, task) {
+			report.Committed = report.Committed.Add(scope)
+		} else {
+			report.Added = report.Added.Add(scope)
+		}
+
+		if task.CompletedAt != nil {
+			report.Completed = report.Completed.Add(scope)
+		} else {
+			report.Unfinished = report.Unfinished.Add(scope)
+		}
+	}
+
+	return report, nil
+}
+
+// getMemberSprint returns the sprint if the user is a member of its board
+func (s SprintUseCases) getMemberSprint(ctx context.Context, user *entities.User, id int) (*entities.Sprint, error) {
+	sprint, err := s.repository.GetSprintByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, s.boardRepository, sprint.IDBoard, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+// getSprint is the same as getMemberSprint, a nil sprint being returned along with the status code or error to send
+// back when the sprint can't be changed by the user
+func (s SprintUseCases) getSprint(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Sprint, status_codes.SprintStatusCode, error) {
+	sprint, err := s.repository.GetSprintByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.SprintNotFound, nil
+		}
+
+		return nil, status_codes.SprintFailure, errors.Join(errors.New("failed to get sprint"), err)
+	}
+
+	err = checkBoardMember(ctx, s.boardRepository, sprint.IDBoard, user.ID)
+	if err != nil {
+		return nil, status_codes.SprintFailure, err
+	}
+
+	return sprint, status_codes.SprintSuccess, nil
+}
+
+// getSprintTask returns the sprint not closed yet, and the active task of its board. A nil sprint is returned along
+// with the status code or error to send back otherwise.
+func (s SprintUseCases) getSprintTask(
+	ctx context.Context,
+	user *entities.User,
+	sprintID int,
+	taskID int,
+) (*entities.Sprint, *entities.Task, status_codes.SprintStatusCode, error) {
+	sprint, statusCode, err := s.getSprint(ctx, user, sprintID)
+	if sprint == nil {
+		return nil, nil, statusCode, err
+	}
+
+	if sprint.Status == entities.SprintClosed {
+		return nil, nil, status_codes.SprintAlreadyClosed, nil
+	}
+
+	task, err := s.boardRepository.GetTaskByID(ctx, taskID)
+	if err != nil && !errors.Is(err, entities.ErrNotFound) {
+		return nil, nil, status_codes.SprintFailure, errors.Join(errors.New("failed to get task"), err)
+	}
+
+	if task == nil || task.IDBoard != sprint.IDBoard || task.StatusCode != entities.StatusActive {
+		return nil, nil, status_codes.SprintTaskNotFound, nil
+	}
+
+	return sprint, task, status_codes.SprintSuccess, nil
+}
+
+// validateSprint trims the name and goal of the sprint and truncates its dates to UTC days before checking them
+func validateSprint(sprint *entities.Sprint) status_codes.SprintStatusCode {
+	sprint.Name = strings.TrimSpace(sprint.Name)
+	if !rules.ValidateTitle(sprint.Name) {
+		return status_codes.SprintInvalidName
+	}
+
+	sprint.Goal = strings.TrimSpace(sprint.Goal)
+	if !rules.ValidateSprintGoal(sprint.Goal) {
+		return status_codes.SprintInvalidGoal
+	}
+
+	sprint.StartDate = sprint.StartDate.UTC().Truncate(24 * time.Hour)
+	sprint.EndDate = sprint.EndDate.UTC().Truncate(24 * time.Hour)
+	if !rules.ValidateSprintDates(sprint.StartDate, sprint.EndDate) {
+		return status_codes.SprintInvalidDates
+	}
+
+	return status_codes.SprintSuccess
+}
+
+// isCommittedAtStart tells whether the task was committed before the sprint started. All the tasks of a sprint not
+// started yet count as committed.
+func isCommittedAtStart(sprint entities.Sprint, task entities.SprintTask) bool {
+	return sprint.StartedAt == nil || !task.AddedAt.After(*sprint.StartedAt)
+}
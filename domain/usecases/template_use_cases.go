@@ -0,0 +1,374 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/datastore"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TemplateUseCases struct {
+	repository      datastore.TemplateRepository
+	boardRepository datastore.BoardRepository
+	labelRepository datastore.LabelRepository
+	fieldRepository datastore.CustomFieldRepository
+	activity        ActivityUseCases
+}
+
+func NewTemplateUseCases(
+	repository datastore.TemplateRepository,
+	boardRepository datastore.BoardRepository,
+	labelRepository datastore.LabelRepository,
+	fieldRepository datastore.CustomFieldRepository,
+	activity ActivityUseCases,
+) TemplateUseCases {
+	return TemplateUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		labelRepository: labelRepository,
+		fieldRepository: fieldRepository,
+		activity:        activity,
+	}
+}
+
+// GetTemplates returns the templates saved by the user, by name
+func (t TemplateUseCases) GetTemplates(ctx context.Context, user *entities.User) ([]entities.BoardTemplate, error) {
+	return t.repository.GetTemplatesByUser(ctx, user.ID)
+}
+
+// GetTemplate returns the template if the user saved it
+func (t TemplateUseCases) GetTemplate(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.BoardTemplate, error) {
+	template, err := t.repository.GetTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.CreatedBy.ID != user.ID {
+		return nil, entities.ErrForbidden
+	}
+
+	return template, nil
+}
+
+// SaveTemplate saves the task lists, labels and custom fields of the board as a template of the user, along with the
+// tasks when includeTasks is set. The due dates of the tasks are kept relative to the creation of the board. Archived
+// task lists and tasks are left out.
+func (t TemplateUseCases) SaveTemplate(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+	template entities.BoardTemplate,
+	includeTasks bool,
+) (*entities.BoardTemplate, status_codes.TemplateStatusCode, error) {
+	board, err := t.boardRepository.GetBoardByID(ctx, boardID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.TemplateBoardNotFound, nil
+		}
+
+		return nil, status_codes.TemplateFailure, errors.Join(errors.New("failed to get board"), err)
+	}
+
+	err = checkBoardMember(ctx, t.boardRepository, board.ID, user.ID)
+	if err != nil {
+		return nil, status_codes.TemplateFailure, err
+	}
+
+	template.Name = strings.TrimSpace(template.Name)
+	template.Description = strings.TrimSpace(template.Description)
+
+	if template.Name == "" {
+		template.Name = board.Title
+	}
+
+	if !rules.ValidateTemplateName(template.Name) {
+		return nil, status_codes.TemplateInvalidName, nil
+	}
+
+	templates, err := t.repository.GetTemplatesByUser(ctx, user.ID)
+	if err != nil {
+		return nil, status_codes.TemplateFailure, errors.Join(errors.New("failed to get templates"), err)
+	}
+
+	if len(templates) >= rules.TemplateMaxPerUser {
+		return nil, status_codes.TemplateLimitReached, nil
+	}
+
+	template.Content, err = t.boardContent(ctx, board, includeTasks)
+	if err != nil {
+		return nil, status_codes.TemplateFailure, err
+	}
+
+	templateUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.TemplateFailure, errors.Join(errors.New("failed to generate template UUID"), err)
+	}
+
+	template.UUID = templateUUID.String()
+	template.CreatedBy = *user
+
+	err = t.repository.AddTemplate(ctx, &template)
+	if err != nil {
+		return nil, status_codes.TemplateFailure, errors.Join(errors.New("failed to save template"), err)
+	}
+
+	return &template, status_codes.TemplateSuccess, nil
+}
+
+// DeleteTemplate deletes the template. Only the user who saved it can delete it.
+func (t TemplateUseCases) DeleteTemplate(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.TemplateStatusCode, error) {
+	template, statusCode, err := t.getTemplate(ctx, user, id)
+	if template == nil {
+		return statusCode, err
+	}
+
+	err = t.repository.DeleteTemplate(ctx, template.ID)
+	if err != nil {
+		return status_codes.TemplateFailure, errors.Join(errors.New("failed to delete template"), err)
+	}
+
+	return status_codes.TemplateSuccess, nil
+}
+
+// CreateBoard creates a board owned by the user from the template, with its task lists, labels, custom fields and
+// tasks, all or nothing. The tasks are due as many days after now as they were after the creation of the board the
+// template was saved from. The board takes the name of the template when no title is given.
+func (t TemplateUseCases) CreateBoard(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+	board entities.Board,
+) (*entities.Board, status_codes.TemplateStatusCode, error) {
+	template, statusCode, err := t.getTemplate(ctx, user, id)
+	if template == nil {
+		return nil, statusCode, err
+	}
+
+	board.Title = strings.TrimSpace(board.Title)
+	board.Description = strings.TrimSpace(board.Description)
+
+	if board.Title == "" {
+		board.Title = template.Name
+	}
+
+	if board.Description == "" {
+		board.Description = template.Description
+	}
+
+	if !rules.ValidateTitle(board.Title) {
+		return nil, status_codes.TemplateInvalidTitle, nil
+	}
+
+	boardUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.TemplateFailure, errors.Join(errors.New("failed to generate board UUID"), err)
+	}
+
+	board.UUID = boardUUID.String()
+	board.CreatedBy = *user
+	withTemplateContent(&board, template.Content, time.Now())
+
+	err = t.boardRepository.AddBoardWithContent(ctx, &board)
+	if err != nil {
+		return nil, status_codes.TemplateFailure, errors.Join(errors.New("failed to save board"), err)
+	}
+
+	t.activity.Record(ctx, entities.Activity{
+		IDBoard:    board.ID,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityBoard,
+		EntityID:   board.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("title", nil, board.Title).
+			set("description", nil, board.Description).
+			set("template", nil, template.Name),
+	})
+
+	return &board, status_codes.TemplateSuccess, nil
+}
+
+// boardContent returns the content of a template of the board
+func (t TemplateUseCases) boardContent(
+	ctx context.Context,
+	board *entities.Board,
+	includeTasks bool,
+) (entities.TemplateContent, error) {
+	var content entities.TemplateContent
+
+	labels, err := t.labelRepository.GetLabelsByBoard(ctx, board.ID)
+	if err != nil {
+		return content, errors.Join(errors.New("failed to get board labels"), err)
+	}
+
+	fields, err := t.fieldRepository.GetFieldsByBoard(ctx, board.ID)
+	if err != nil {
+		return content, errors.Join(errors.New("failed to get board custom fields"), err)
+	}
+
+	taskLists, err := t.boardRepository.GetTaskLists(ctx, board.ID)
+	if err != nil {
+		return content, errors.Join(errors.New("failed to get board task lists"), err)
+	}
+
+	taskLists = slices.DeleteFunc(taskLists, func(taskList entities.TaskList) bool {
+		return taskList.StatusCode != entities.StatusActive
+	})
+
+	tasksByList := make(map[int][]entities.TemplateTask)
+	if includeTasks {
+		tasks, err := t.boardRepository.GetTasksByBoard(ctx, board.ID)
+		if err != nil {
+			return content, errors.Join(errors.New("failed to get board tasks"), err)
+		}
+
+		labelsByTask, err := t.labelRepository.GetTaskLabelsByBoard(ctx, board.ID)
+		if err != nil {
+			return content, errors.Join(errors.New("failed to get task labels"), err)
+		}
+
+		for _, task := range tasks {
+			if task.StatusCode != entities.StatusActive {
+				continue
+			}
+
+			templateTask := entities.TemplateTask{
+				Name:             task.Name,
+				Description:      task.Description,
+				Priority:         task.Priority,
+				OriginalEstimate: task.OriginalEstimate,
+			}
+
+			if task.DueDate != nil {
+				days := max(int(task.DueDate.Sub(board.CreatedAt).Hours()/24), 0)
+				templateTask.DueInDays = &days
+			}
+
+			for _, label := range labelsByTask[task.ID] {
+				templateTask.Labels = append(templateTask.Labels, label.Name)
+			}
+
+			tasksByList[task.IDTaskList] = append(tasksByList[task.IDTaskList], templateTask)
+		}
+	}
+
+	content.Labels = make([]entities.TemplateLabel, 0, len(labels))
+	for _, label := range labels {
+		content.Labels = append(content.Labels, entities.TemplateLabel{Name: label.Name, Color: label.Color})
+	}
+
+	content.Fields = make([]entities.TemplateField, 0, len(fields))
+	for _, field := range fields {
+		content.Fields = append(content.Fields, entities.TemplateField{
+			Name:    field.Name,
+			Type:    field.Type,
+			Options: field.Options,
+		})
+	}
+
+	content.TaskLists = make([]entities.TemplateTaskList, 0, len(taskLists))
+	for _, taskList := range taskLists {
+		content.TaskLists = append(content.TaskLists, entities.TemplateTaskList{
+			Name:        taskList.Name,
+			Description: taskList.Description,
+			Tasks:       tasksByList[taskList.ID],
+		})
+	}
+
+	return content, nil
+}
+
+// getTemplate returns the template if the user saved it. A nil template is returned along with the status code or
+// error to send back otherwise.
+func (t TemplateUseCases) getTemplate(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.BoardTemplate, status_codes.TemplateStatusCode, error) {
+	template, err := t.repository.GetTemplateByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.TemplateNotFound, nil
+		}
+
+		return nil, status_codes.TemplateFailure, errors.Join(errors.New("failed to get template"), err)
+	}
+
+	if template.CreatedBy.ID != user.ID {
+		return nil, status_codes.TemplateFailure, entities.ErrForbidden
+	}
+
+	return template, status_codes.TemplateSuccess, nil
+}
+
+// withTemplateContent fills the labels, custom fields, task lists and tasks of the board from the template content,
+// the tasks being due relative to the given start
+func withTemplateContent(board *entities.Board, content entities.TemplateContent, start time.Time) {
+	board.Labels = make([]entities.Label, 0, len(content.Labels))
+	for _, label := range content.Labels {
+		board.Labels = append(board.Labels, entities.Label{
+			Name:      label.Name,
+			Color:     label.Color,
+			CreatedBy: board.CreatedBy,
+		})
+	}
+
+	board.CustomFields = make([]entities.CustomField, 0, len(content.Fields))
+	for _, field := range content.Fields {
+		board.CustomFields = append(board.CustomFields, entities.CustomField{
+			Name:      field.Name,
+			Type:      field.Type,
+			Options:   field.Options,
+			CreatedBy: board.CreatedBy,
+		})
+	}
+
+	board.TaskLists = make([]entities.TaskList, 0, len(content.TaskLists))
+	for _, templateList := range content.TaskLists {
+		taskList := entities.TaskList{
+			Name:        templateList.Name,
+			Description: templateList.Description,
+			CreatedBy:   board.CreatedBy,
+			Tasks:       make([]entities.Task, 0, len(templateList.Tasks)),
+		}
+
+		for _, templateTask := range templateList.Tasks {
+			task := entities.Task{
+				Name:             templateTask.Name,
+				Description:      templateTask.Description,
+				Priority:         templateTask.Priority,
+				CreatedBy:        board.CreatedBy,
+				Labels:           make([]entities.Label, 0, len(templateTask.Labels)),
+				OriginalEstimate: templateTask.OriginalEstimate,
+			}
+
+			if templateTask.DueInDays != nil {
+				dueDate := start.AddDate(0, 0, *templateTask.DueInDays)
+				task.DueDate = &dueDate
+			}
+
+			for _, name := range templateTask.Labels {
+				task.Labels = append(task.Labels, entities.Label{Name: name})
+			}
+
+			taskList.Tasks = append(taskList.Tasks, task)
+		}
+
+		board.TaskLists = append(board.TaskLists, taskList)
+	}
+}
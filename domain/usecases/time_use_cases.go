@@ -0,0 +1,520 @@
+package usecases
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/datastore"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// timeReportColumns are the headers of the time reports exported as CSV, one row per entry
+var timeReportColumns = []string{"Date", "Board", "Task", "User", "Started at", "Ended at", "Hours", "Note"}
+
+type TimeUseCases struct {
+	repository      datastore.TimeRepository
+	boardRepository datastore.BoardRepository
+	activity        ActivityUseCases
+}
+
+func NewTimeUseCases(
+	repository datastore.TimeRepository,
+	boardRepository datastore.BoardRepository,
+	activity ActivityUseCases,
+) TimeUseCases {
+	return TimeUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		activity:        activity,
+	}
+}
+
+// GetRunningTimer returns the entry of the timer running for the user, nil if none runs
+func (t TimeUseCases) GetRunningTimer(ctx context.Context, user *entities.User) (*entities.TimeEntry, error) {
+	entry, err := t.repository.GetRunningEntry(ctx, user.ID)
+	if errors.Is(err, entities.ErrNotFound) {
+		return nil, nil
+	}
+
+	return entry, err
+}
+
+// StartTimer starts tracking the time the user spends on the task. Only one timer runs per user: the running one is
+// returned along with TimeTimerRunning, and must be stopped first.
+func (t TimeUseCases) StartTimer(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+	note string,
+) (*entities.TimeEntry, status_codes.TimeStatusCode, error) {
+	task, statusCode, err := t.getTask(ctx, user, taskID)
+	if task == nil {
+		return nil, statusCode, err
+	}
+
+	if !rules.ValidateTimeNote(note) {
+		return nil, status_codes.TimeInvalidEntry, nil
+	}
+
+	entryUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.TimeFailure, errors.Join(errors.New("failed to generate time entry UUID"), err)
+	}
+
+	entry := entities.TimeEntry{
+		UUID:      entryUUID.String(),
+		IDTask:    task.ID,
+		IDBoard:   task.IDBoard,
+		User:      *user,
+		StartedAt: time.Now(),
+		Note:      note,
+	}
+
+	started, err := t.repository.StartTimer(ctx, &entry)
+	if err != nil {
+		return nil, status_codes.TimeFailure, errors.Join(errors.New("failed to start timer"), err)
+	}
+
+	if !started {
+		running, err := t.repository.GetRunningEntry(ctx, user.ID)
+		if err != nil && !errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.TimeFailure, errors.Join(errors.New("failed to get running timer"), err)
+		}
+
+		return running, status_codes.TimeTimerRunning, nil
+	}
+
+	t.activity.Record(ctx, entities.Activity{
+		IDBoard:    entry.IDBoard,
+		IDTask:     entry.IDTask,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTimeEntry,
+		EntityID:   entry.ID,
+		Action:     entities.ActivityStarted,
+		Changes:    activityChanges{}.set("note", nil, entry.Note),
+	})
+
+	return &entry, status_codes.TimeSuccess, nil
+}
+
+// StopTimer stops the timer running for the user, the time spent being rounded up to the minute
+func (t TimeUseCases) StopTimer(
+	ctx context.Context,
+	user *entities.User,
+) (*entities.TimeEntry, status_codes.TimeStatusCode, error) {
+	entry, err := t.repository.GetRunningEntry(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.TimeNoTimerRunning, nil
+		}
+
+		return nil, status_codes.TimeFailure, errors.Join(errors.New("failed to get running timer"), err)
+	}
+
+	now := time.Now()
+	entry.EndedAt = &now
+	entry.Minutes = max(int(math.Ceil(now.Sub(entry.StartedAt).Minutes())), 1)
+
+	stopped, err := t.repository.StopTimer(ctx, entry)
+	if err != nil {
+		return nil, status_codes.TimeFailure, errors.Join(errors.New("failed to stop timer"), err)
+	}
+
+	if !stopped {
+		return nil, status_codes.TimeNoTimerRunning, nil
+	}
+
+	t.activity.Record(ctx, entities.Activity{
+		IDBoard:    entry.IDBoard,
+		IDTask:     entry.IDTask,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTimeEntry,
+		EntityID:   entry.ID,
+		Action:     entities.ActivityStopped,
+		Changes:    activityChanges{}.set("minutes", nil, entry.Minutes),
+	})
+
+	return entry, status_codes.TimeSuccess, nil
+}
+
+// AddEntry records time the user spent on the task. The entry starts the given minutes ago when its start is not set.
+func (t TimeUseCases) AddEntry(
+	ctx context.Context,
+	user *entities.User,
+	entry entities.TimeEntry,
+) (*entities.TimeEntry, status_codes.TimeStatusCode, error) {
+	task, statusCode, err := t.getTask(ctx, user, entry.IDTask)
+	if task == nil {
+		return nil, statusCode, err
+	}
+
+	now := time.Now()
+	if entry.StartedAt.IsZero() {
+		entry.StartedAt = now.Add(-time.Duration(entry.Minutes) * time.Minute)
+	}
+
+	if !rules.ValidateTimeEntry(entry.Minutes, entry.Note) || entry.StartedAt.After(now) {
+		return nil, status_codes.TimeInvalidEntry, nil
+	}
+
+	entryUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.TimeFailure, errors.Join(errors.New("failed to generate time entry UUID"), err)
+	}
+
+	endedAt := entry.StartedAt.Add(time.Duration(entry.Minutes) * time.Minute)
+	entry.UUID = entryUUID.String()
+	entry.IDBoard = task.IDBoard
+	entry.User = *user
+	entry.EndedAt = &endedAt
+	entry.Manual = true
+
+	err = t.repository.AddEntry(ctx, &entry)
+	if err != nil {
+		return nil, status_codes.TimeFailure, errors.Join(errors.New("failed to save time entry"), err)
+	}
+
+	t.activity.Record(ctx, entities.Activity{
+		IDBoard:    entry.IDBoard,
+		IDTask:     entry.IDTask,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTimeEntry,
+		EntityID:   entry.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("started_at", nil, entry.StartedAt.UTC().Format(time.RFC3339)).
+			set("minutes", nil, entry.Minutes).
+			set("note", nil, entry.Note),
+	})
+
+	return &entry, status_codes.TimeSuccess, nil
+}
+
+// GetTaskEntries returns the time entries of the task, the latest started first. Only the members of the board can
+// see them.
+func (t TimeUseCases) GetTaskEntries(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+) ([]entities.TimeEntry, error) {
+	task, err := t.boardRepository.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBoardMember(ctx, t.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.repository.GetEntriesByTask(ctx, taskID)
+}
+
+// UpdateEntry updates the start, minutes and note of a stopped entry. Users can only update their own entries.
+func (t TimeUseCases) UpdateEntry(
+	ctx context.Context,
+	user *entities.User,
+	entry entities.TimeEntry,
+) (status_codes.TimeStatusCode, error) {
+	current, statusCode, err := t.getOwnEntry(ctx, user, entry.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	if current.EndedAt == nil {
+		return status_codes.TimeEntryRunning, nil
+	}
+
+	if entry.StartedAt.IsZero() {
+		entry.StartedAt = current.StartedAt
+	}
+
+	if !rules.ValidateTimeEntry(entry.Minutes, entry.Note) || entry.StartedAt.After(time.Now()) {
+		return status_codes.TimeInvalidEntry, nil
+	}
+
+	endedAt := entry.StartedAt.Add(time.Duration(entry.Minutes) * time.Minute)
+	entry.EndedAt = &endedAt
+
+	err = t.repository.UpdateEntry(ctx, &entry)
+	if err != nil {
+		return status_codes.TimeFailure, errors.Join(errors.New("failed to update time entry"), err)
+	}
+
+	t.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		IDTask:     current.IDTask,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTimeEntry,
+		EntityID:   current.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set(
+				"started_at",
+				current.StartedAt.UTC().Format(time.RFC3339),
+				entry.StartedAt.UTC().Format(time.RFC3339),
+			).
+			set("minutes", current.Minutes, entry.Minutes).
+			set("note", current.Note, entry.Note),
+	})
+
+	return status_codes.TimeSuccess, nil
+}
+
+// DeleteEntry deletes an entry, discarding it if its timer runs. Users can only delete their own entries.
+func (t TimeUseCases) DeleteEntry(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.TimeStatusCode, error) {
+	current, statusCode, err := t.getOwnEntry(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	err = t.repository.DeleteEntry(ctx, id)
+	if err != nil {
+		return status_codes.TimeFailure, errors.Join(errors.New("failed to delete time entry"), err)
+	}
+
+	t.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		IDTask:     current.IDTask,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityTimeEntry,
+		EntityID:   current.ID,
+		Action:     entities.ActivityDeleted,
+		Changes:    activityChanges{}.set("minutes", current.Minutes, nil),
+	})
+
+	return status_codes.TimeSuccess, nil
+}
+
+// GetReport sums up the time spent over the range by user and by task. A board report is open to the members of the
+// board, and may be narrowed to a user. Without a board, users can only report their own time.
+func (t TimeUseCases) GetReport(
+	ctx context.Context,
+	user *entities.User,
+	filter entities.TimeReportFilter,
+) (*entities.TimeReport, error) {
+	filter, err := t.checkReport(ctx, user, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := t.repository.GetReportEntries(
+		ctx,
+		filter.IDBoard,
+		filter.IDUser,
+		filter.From,
+		filter.To.AddDate(0, 0, 1),
+	)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to get time entries"), err)
+	}
+
+	report := &entities.TimeReport{
+		From:    filter.From.Format(time.DateOnly),
+		To:      filter.To.Format(time.DateOnly),
+		Users:   make([]entities.TimeReportUser, 0),
+		Tasks:   make([]entities.TimeReportTask, 0),
+		Entries: entries,
+	}
+
+	userIndexes := make(map[int]int)
+	taskIndexes := make(map[int]int)
+	for _, reportEntry := range entries {
+		entry := reportEntry.Entry
+		report.Minutes += entry.Minutes
+
+		i, found := userIndexes[entry.User.ID]
+		if !found {
+			i = len(report.Users)
+			userIndexes[entry.User.ID] = i
+			report.Users = append(report.Users, entities.TimeReportUser{User: entry.User})
+		}
+		report.Users[i].Minutes += entry.Minutes
+
+		i, found = taskIndexes[entry.IDTask]
+		if !found {
+			i = len(report.Tasks)
+			taskIndexes[entry.IDTask] = i
+			report.Tasks = append(report.Tasks, entities.TimeReportTask{
+				IDTask:           entry.IDTask,
+				IDBoard:          entry.IDBoard,
+				Name:             reportEntry.TaskName,
+				OriginalEstimate: reportEntry.TaskEstimate,
+			})
+		}
+		report.Tasks[i].Minutes += entry.Minutes
+	}
+
+	// The most time first
+	slices.SortStableFunc(report.Users, func(a, b entities.TimeReportUser) int {
+		return cmp.Compare(b.Minutes, a.Minutes)
+	})
+	slices.SortStableFunc(report.Tasks, func(a, b entities.TimeReportTask) int {
+		return cmp.Compare(b.Minutes, a.Minutes)
+	})
+
+	return report, nil
+}
+
+// ExportReport returns the entries of the report as CSV, one row per entry with its hours, for billing
+func (t TimeUseCases) ExportReport(
+	ctx context.Context,
+	user *entities.User,
+	filter entities.TimeReportFilter,
+) ([]byte, error) {
+	report, err := t.GetReport(ctx, user, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(utf8BOM)
+
+	writer := csv.NewWriter(&buffer)
+	writer.UseCRLF = true
+
+	err = writer.Write(timeReportColumns)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to write CSV header"), err)
+	}
+
+	for _, reportEntry := range report.Entries {
+		entry := reportEntry.Entry
+
+		var endedAt string
+		if entry.EndedAt != nil {
+			endedAt = entry.EndedAt.UTC().Format(time.DateTime)
+		}
+
+		err = writer.Write([]string{
+			entry.StartedAt.UTC().Format(time.DateOnly),
+			escapeCSVCell(reportEntry.BoardTitle),
+			escapeCSVCell(reportEntry.TaskName),
+			entry.User.Email,
+			entry.StartedAt.UTC().Format(time.DateTime),
+			endedAt,
+			strconv.FormatFloat(float64(entry.Minutes)/60, 'f', 2, 64),
+			escapeCSVCell(entry.Note),
+		})
+		if err != nil {
+			return nil, errors.Join(errors.New("failed to write CSV row"), err)
+		}
+	}
+
+	writer.Flush()
+	err = writer.Error()
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to write CSV"), err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// checkReport checks that the user can see the report, and returns its filter with the range of days. The range
+// defaults to the last TimeReportDefaultDays days, its days being truncated to UTC midnights.
+func (t TimeUseCases) checkReport(
+	ctx context.Context,
+	user *entities.User,
+	filter entities.TimeReportFilter,
+) (entities.TimeReportFilter, error) {
+	if filter.IDBoard != 0 {
+		board, err := t.boardRepository.GetBoardByID(ctx, filter.IDBoard)
+		if err != nil {
+			return filter, err
+		}
+
+		err = checkBoardMember(ctx, t.boardRepository, board.ID, user.ID)
+		if err != nil {
+			return filter, err
+		}
+	} else {
+		if filter.IDUser == 0 {
+			filter.IDUser = user.ID
+		}
+
+		if filter.IDUser != user.ID {
+			return filter, entities.ErrForbidden
+		}
+	}
+
+	if filter.To.IsZero() {
+		filter.To = time.Now()
+	}
+
+	filter.To = filter.To.UTC().Truncate(24 * time.Hour)
+	if filter.From.IsZero() {
+		filter.From = filter.To.AddDate(0, 0, 1-rules.TimeReportDefaultDays)
+	}
+
+	filter.From = filter.From.UTC().Truncate(24 * time.Hour)
+	if filter.From.After(filter.To) || filter.To.Sub(filter.From) >= rules.TimeReportMaxDays*24*time.Hour {
+		return filter, entities.ErrBadRequest
+	}
+
+	return filter, nil
+}
+
+// getTask returns the task if it is active and the user is a member of its board. A nil task is returned along with
+// the status code or error to send back otherwise.
+func (t TimeUseCases) getTask(
+	ctx context.Context,
+	user *entities.User,
+	taskID int,
+) (*entities.Task, status_codes.TimeStatusCode, error) {
+	task, err := t.boardRepository.GetTaskByID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.TimeTaskNotFound, nil
+		}
+
+		return nil, status_codes.TimeFailure, errors.Join(errors.New("failed to get task"), err)
+	}
+
+	if task.StatusCode != entities.StatusActive {
+		return nil, status_codes.TimeTaskNotFound, nil
+	}
+
+	err = checkBoardMember(ctx, t.boardRepository, task.IDBoard, user.ID)
+	if err != nil {
+		return nil, status_codes.TimeFailure, err
+	}
+
+	return task, status_codes.TimeSuccess, nil
+}
+
+// getOwnEntry returns the entry if the user made it. A nil entry is returned along with the status code or error to
+// send back otherwise.
+func (t TimeUseCases) getOwnEntry(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.TimeEntry, status_codes.TimeStatusCode, error) {
+	entry, err := t.repository.GetEntryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.TimeEntryNotFound, nil
+		}
+
+		return nil, status_codes.TimeFailure, errors.Join(errors.New("failed to get time entry"), err)
+	}
+
+	if entry.User.ID != user.ID {
+		return nil, status_codes.TimeFailure, entities.ErrForbidden
+	}
+
+	return entry, status_codes.TimeSuccess, nil
+}
@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+	"taskflow/domain/entities"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/datastore"
+	"testing"
+)
+
+// fakeTimeRepository keeps the time entries in memory, refusing to start a second timer for a user like the MySQL
+// repository does
+type fakeTimeRepository struct {
+	datastore.TimeRepository
+
+	entries []entities.TimeEntry
+}
+
+func (f *fakeTimeRepository) GetRunningEntry(_ context.Context, userID int) (*entities.TimeEntry, error) {
+	i := slices.IndexFunc(f.entries, func(entry entities.TimeEntry) bool {
+		return entry.User.ID == userID && entry.EndedAt == nil
+	})
+	if i < 0 {
+		return nil, entities.ErrNotFound
+	}
+
+	entry := f.entries[i]
+	return &entry, nil
+}
+
+func (f *fakeTimeRepository) StartTimer(ctx context.Context, entry *entities.TimeEntry) (bool, error) {
+	_, err := f.GetRunningEntry(ctx, entry.User.ID)
+	if err == nil {
+		return false, nil
+	}
+
+	entry.ID = len(f.entries) + 1
+	f.entries = append(f.entries, *entry)
+	return true, nil
+}
+
+func (f *fakeTimeRepository) StopTimer(_ context.Context, entry *entities.TimeEntry) (bool, error) {
+	for i := range f.entries {
+		if f.entries[i].ID == entry.ID && f.entries[i].EndedAt == nil {
+			f.entries[i].EndedAt = entry.EndedAt
+			f.entries[i].Minutes = entry.Minutes
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// activityRecorder keeps the activities it is notified of
+type activityRecorder struct {
+	activities *[]entities.Activity
+}
+
+func (r activityRecorder) OnActivity(_ context.Context, activity entities.Activity) {
+	*r.activities = append(*r.activities, activity)
+}
+
+func TestSingleRunningTimer(t *testing.T) {
+	boardRepository, _, activity := newWIPTestBoard(false)
+	repository := &fakeTimeRepository{}
+	times := NewTimeUseCases(repository, boardRepository, activity)
+
+	var activities []entities.Activity
+	activity.AddListener(activityRecorder{activities: &activities})
+
+	ctx := context.Background()
+	user := &entities.User{ID: 1}
+	other := &entities.User{ID: 2}
+
+	entry, statusCode, err := times.StartTimer(ctx, user, 1, "")
+	if err != nil || statusCode != status_codes.TimeSuccess {
+		t.Fatalf("StartTimer() status = %v, error = %v", statusCode, err)
+	}
+
+	// The running timer is returned instead of a second one
+	running, statusCode, err := times.StartTimer(ctx, user, 2, "")
+	if err != nil || statusCode != status_codes.TimeTimerRunning {
+		t.Fatalf("second StartTimer() status = %v, error = %v, want %v", statusCode, err, status_codes.TimeTimerRunning)
+	}
+
+	if running == nil || running.ID != entry.ID || running.IDTask != 1 {
+		t.Errorf("second StartTimer() = %+v, want the entry %d of the task 1", running, entry.ID)
+	}
+
+	// Timers are per user
+	_, statusCode, err = times.StartTimer(ctx, other, 2, "")
+	if err != nil || statusCode != status_codes.TimeSuccess {
+		t.Fatalf("StartTimer() of another user status = %v, error = %v", statusCode, err)
+	}
+
+	stopped, statusCode, err := times.StopTimer(ctx, user)
+	if err != nil || statusCode != status_codes.TimeSuccess {
+		t.Fatalf("StopTimer() status = %v, error = %v", statusCode, err)
+	}
+
+	if stopped.ID != entry.ID || stopped.EndedAt == nil || stopped.Minutes != 1 {
+		t.Errorf("StopTimer() = %+v, want the entry %d stopped after a minute", stopped, entry.ID)
+	}
+
+	_, statusCode, err = times.StopTimer(ctx, user)
+	if err != nil || statusCode != status_codes.TimeNoTimerRunning {
+		t.Errorf("second StopTimer() status = %v, error = %v, want %v", statusCode, err, status_codes.TimeNoTimerRunning)
+	}
+
+	_, statusCode, err = times.StartTimer(ctx, user, 2, "")
+	if err != nil || statusCode != status_codes.TimeSuccess {
+		t.Fatalf("StartTimer() after StopTimer() status = %v, error = %v", statusCode, err)
+	}
+
+	want := []entities.ActivityAction{
+		entities.ActivityStarted,
+		entities.ActivityStarted,
+		entities.ActivityStopped,
+		entities.ActivityStarted,
+	}
+
+	actions := make([]entities.ActivityAction, 0, len(activities))
+	for _, recorded := range activities {
+		if recorded.EntityType != entities.ActivityEntityTimeEntry {
+			t.Errorf("activity entity type = %q, want %q", recorded.EntityType, entities.ActivityEntityTimeEntry)
+		}
+
+		actions = append(actions, recorded.Action)
+	}
+
+	if !slices.Equal(actions, want) {
+		t.Errorf("activities = %v, want %v", actions, want)
+	}
+}
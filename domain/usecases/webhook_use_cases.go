@@ -0,0 +1,639 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/util"
+	"taskflow/infrastructure/datastore"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// webhookWorkers is the number of goroutines sending webhook deliveries in background
+	webhookWorkers = 2
+
+	// webhookPollInterval is how often the workers look for due deliveries when they are not woken up
+	webhookPollInterval = 10 * time.Second
+
+	// webhookBatchSize is the number of due deliveries a worker fetches at once
+	webhookBatchSize = 20
+
+	// webhookSecretSize is the number of random bytes of the webhook secrets
+	webhookSecretSize = 32
+
+	// webhookMaxErrorLetters limits the error or response body stored with a failed delivery
+	webhookMaxErrorLetters = 1024
+)
+
+type WebhookUseCases struct {
+	repository      datastore.WebhookRepository
+	boardRepository datastore.BoardRepository
+	client          *http.Client
+	wake            chan struct{}
+	activity        ActivityUseCases
+}
+
+func NewWebhookUseCases(
+	repository datastore.WebhookRepository,
+	boardRepository datastore.BoardRepository,
+	activity ActivityUseCases,
+) WebhookUseCases {
+	w := WebhookUseCases{
+		repository:      repository,
+		boardRepository: boardRepository,
+		client:          newWebhookClient(),
+		wake:            make(chan struct{}, 1),
+		activity:        activity,
+	}
+
+	for range webhookWorkers {
+		go w.deliveryWorker()
+	}
+
+	return w
+}
+
+// OnActivity queues a delivery of the activity to every enabled webhook of the board subscribed to its event
+func (w WebhookUseCases) OnActivity(ctx context.Context, activity entities.Activity) {
+	webhooks, err := w.repository.GetWebhooksByBoard(ctx, activity.IDBoard)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get board webhooks", "board", activity.IDBoard, "cause", err)
+		return
+	}
+
+	event := activity.Event()
+	queued := false
+	for _, webhook := range webhooks {
+		if !webhook.Enabled || !webhook.Accepts(event) {
+			continue
+		}
+
+		err = w.queueDelivery(ctx, webhook, activity)
+		if err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to queue webhook delivery",
+				"webhook", webhook.ID,
+				"activity", activity.ID,
+				"cause", err,
+			)
+			continue
+		}
+
+		queued = true
+	}
+
+	if queued {
+		w.wakeWorkers()
+	}
+}
+
+// GetWebhooks returns all the webhooks of the board, without their secrets. Only the board owner can see them.
+func (w WebhookUseCases) GetWebhooks(ctx context.Context, user *entities.User, boardID int) ([]entities.Webhook, error) {
+	err := w.checkBoardOwner(ctx, user, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := w.repository.GetWebhooksByBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// CreateWebhook registers a webhook on the board. Only the board owner can create it.
+//
+// The generated secret is only returned here, so the receiver can be configured to verify the signatures.
+func (w WebhookUseCases) CreateWebhook(
+	ctx context.Context,
+	user *entities.User,
+	webhook entities.Webhook,
+) (*entities.Webhook, status_codes.WebhookStatusCode, error) {
+	statusCode, err := w.getOwnedBoard(ctx, user, webhook.IDBoard)
+	if statusCode != status_codes.WebhookSuccess || err != nil {
+		return nil, statusCode, err
+	}
+
+	if !rules.ValidateWebhookURL(webhook.URL) {
+		return nil, status_codes.WebhookInvalidURL, nil
+	}
+
+	if webhook.Events == nil {
+		webhook.Events = make([]string, 0)
+	}
+
+	if !rules.ValidateWebhookEvents(webhook.Events) {
+		return nil, status_codes.WebhookInvalidEvents, nil
+	}
+
+	// The webhooks are listed whole, so their number is bounded like the labels and custom fields of a board
+	webhooks, err := w.repository.GetWebhooksByBoard(ctx, webhook.IDBoard)
+	if err != nil {
+		return nil, status_codes.WebhookFailure, errors.Join(errors.New("failed to get webhooks"), err)
+	}
+
+	if len(webhooks) >= rules.WebhookMaxPerBoard {
+		return nil, status_codes.WebhookLimitReached, nil
+	}
+
+	webhookUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.WebhookFailure, errors.Join(errors.New("failed to generate webhook UUID"), err)
+	}
+
+	secret, err := util.GenerateSecret(webhookSecretSize)
+	if err != nil {
+		return nil, status_codes.WebhookFailure, errors.Join(errors.New("failed to generate webhook secret"), err)
+	}
+
+	webhook.UUID = webhookUUID.String()
+	webhook.Secret = secret
+	webhook.Enabled = true
+	webhook.ConsecutiveFailures = 0
+	webhook.CreatedBy = *user
+
+	err = w.repository.AddWebhook(ctx, &webhook)
+	if err != nil {
+		return nil, status_codes.WebhookFailure, errors.Join(errors.New("failed to save webhook"), err)
+	}
+
+	w.activity.Record(ctx, entities.Activity{
+		IDBoard:    webhook.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityWebhook,
+		EntityID:   webhook.ID,
+		Action:     entities.ActivityCreated,
+		Changes: activityChanges{}.
+			set("host", nil, webhookHost(webhook.URL)).
+			set("events", nil, strings.Join(webhook.Events, ",")).
+			set("enabled", nil, webhook.Enabled),
+	})
+
+	return &webhook, status_codes.WebhookSuccess, nil
+}
+
+// UpdateWebhook updates the URL, events and enabled flag of a webhook. Enabling a webhook that was disabled after
+// too many failures clears its failures count.
+func (w WebhookUseCases) UpdateWebhook(
+	ctx context.Context,
+	user *entities.User,
+	webhook entities.Webhook,
+) (status_codes.WebhookStatusCode, error) {
+	current, statusCode, err := w.getOwnedWebhook(ctx, user, webhook.ID)
+	if current == nil {
+		return statusCode, err
+	}
+
+	if !rules.ValidateWebhookURL(webhook.URL) {
+		return status_codes.WebhookInvalidURL, nil
+	}
+
+	if webhook.Events == nil {
+		webhook.Events = make([]string, 0)
+	}
+
+	if !rules.ValidateWebhookEvents(webhook.Events) {
+		return status_codes.WebhookInvalidEvents, nil
+	}
+
+	err = w.repository.UpdateWebhook(ctx, &webhook)
+	if err != nil {
+		return status_codes.WebhookFailure, errors.Join(errors.New("failed to update webhook"), err)
+	}
+
+	if webhook.Enabled && !current.Enabled {
+		w.wakeWorkers()
+	}
+
+	w.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityWebhook,
+		EntityID:   current.ID,
+		Action:     entities.ActivityUpdated,
+		Changes: activityChanges{}.
+			set("host", webhookHost(current.URL), webhookHost(webhook.URL)).
+			set("events", strings.Join(current.Events, ","), strings.Join(webhook.Events, ",")).
+			set("enabled", current.Enabled, webhook.Enabled),
+	})
+
+	return status_codes.WebhookSuccess, nil
+}
+
+// DeleteWebhook deletes a webhook along with its deliveries
+func (w WebhookUseCases) DeleteWebhook(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (status_codes.WebhookStatusCode, error) {
+	current, statusCode, err := w.getOwnedWebhook(ctx, user, id)
+	if current == nil {
+		return statusCode, err
+	}
+
+	err = w.repository.DeleteWebhook(ctx, id)
+	if err != nil {
+		return status_codes.WebhookFailure, errors.Join(errors.New("failed to delete webhook"), err)
+	}
+
+	w.activity.Record(ctx, entities.Activity{
+		IDBoard:    current.IDBoard,
+		Actor:      *user,
+		EntityType: entities.ActivityEntityWebhook,
+		EntityID:   current.ID,
+		Action:     entities.ActivityDeleted,
+		Changes:    activityChanges{}.set("host", webhookHost(current.URL), nil),
+	})
+
+	return status_codes.WebhookSuccess, nil
+}
+
+// GetDeliveries returns the deliveries of a webhook, newest first
+func (w WebhookUseCases) GetDeliveries(
+	ctx context.Context,
+	user *entities.User,
+	webhookID int,
+	beforeID int,
+	limit int,
+) ([]entities.WebhookDelivery, error) {
+	webhook, err := w.repository.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.checkBoardOwner(ctx, user, webhook.IDBoard)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.repository.GetDeliveries(ctx, webhookID, beforeID, rules.PageLimit(limit))
+}
+
+// Redeliver queues a new delivery with the same payload as the given one. The payload keeps the original delivery
+// UUID, so receivers can tell it is the same event.
+func (w WebhookUseCases) Redeliver(
+	ctx context.Context,
+	user *entities.User,
+	deliveryID int,
+) (*entities.WebhookDelivery, status_codes.WebhookStatusCode, error) {
+	delivery, err := w.repository.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.WebhookDeliveryNotFound, nil
+		}
+
+		return nil, status_codes.WebhookFailure, errors.Join(errors.New("failed to get delivery"), err)
+	}
+
+	webhook, statusCode, err := w.getOwnedWebhook(ctx, user, delivery.IDWebhook)
+	if webhook == nil {
+		return nil, statusCode, err
+	}
+
+	deliveryUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status_codes.WebhookFailure, errors.Join(errors.New("failed to generate delivery UUID"), err)
+	}
+
+	redelivery := entities.WebhookDelivery{
+		UUID:          deliveryUUID.String(),
+		IDWebhook:     delivery.IDWebhook,
+		IDActivity:    delivery.IDActivity,
+		Event:         delivery.Event,
+		Payload:       delivery.Payload,
+		Status:        entities.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+	}
+
+	err = w.repository.AddDelivery(ctx, &redelivery)
+	if err != nil {
+		return nil, status_codes.WebhookFailure, errors.Join(errors.New("failed to save delivery"), err)
+	}
+
+	w.wakeWorkers()
+
+	return &redelivery, status_codes.WebhookSuccess, nil
+}
+
+func (w WebhookUseCases) queueDelivery(ctx context.Context, webhook entities.Webhook, activity entities.Activity) error {
+	deliveryUUID, err := uuid.NewRandom()
+	if err != nil {
+		return errors.Join(errors.New("failed to generate delivery UUID"), err)
+	}
+
+	payload, err := json.Marshal(entities.WebhookPayload{
+		ID:        deliveryUUID.String(),
+		Event:     activity.Event(),
+		Activity:  activity,
+		CreatedAt: activity.CreatedAt,
+	})
+	if err != nil {
+		return errors.Join(errors.New("failed to marshal webhook payload"), err)
+	}
+
+	return w.repository.AddDelivery(ctx, &entities.WebhookDelivery{
+		UUID:          deliveryUUID.String(),
+		IDWebhook:     webhook.ID,
+		IDActivity:    activity.ID,
+		Event:         activity.Event(),
+		Payload:       payload,
+		Status:        entities.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+	})
+}
+
+// wakeWorkers tells the workers there are new deliveries, without waiting for the next poll
+func (w WebhookUseCases) wakeWorkers() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w WebhookUseCases) deliveryWorker() {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.wake:
+		}
+
+		w.sendDueDeliveries(context.Background())
+	}
+}
+
+// sendDueDeliveries sends the deliveries whose next attempt is due, until none is left
+func (w WebhookUseCases) sendDueDeliveries(ctx context.Context) {
+	for {
+		deliveries, err := w.repository.GetDueDeliveries(ctx, webhookBatchSize)
+		if err != nil {
+			slog.Error("failed to get due webhook deliveries", "cause", err)
+			return
+		}
+
+		sent := 0
+		for _, delivery := range deliveries {
+			// The lease covers the request timeout, so the delivery is retried if the server stops while sending it
+			claimed, err := w.repository.ClaimDelivery(ctx, delivery.ID, time.Now().Add(2*rules.WebhookTimeout))
+			if err != nil {
+				slog.Error("failed to claim webhook delivery", "delivery", delivery.ID, "cause", err)
+				continue
+			}
+
+			if !claimed {
+				continue
+			}
+
+			err = w.sendDelivery(ctx, delivery)
+			if err != nil {
+				slog.Error("failed to send webhook delivery", "delivery", delivery.ID, "cause", err)
+			}
+			sent++
+		}
+
+		if len(deliveries) < webhookBatchSize || sent == 0 {
+			return
+		}
+	}
+}
+
+// sendDelivery posts the delivery payload to the webhook and saves the outcome of the attempt
+func (w WebhookUseCases) sendDelivery(ctx context.Context, delivery entities.WebhookDelivery) error {
+	webhook, err := w.repository.GetWebhookByID(ctx, delivery.IDWebhook)
+	if err != nil {
+		return errors.Join(errors.New("failed to get webhook"), err)
+	}
+
+	delivery.Attempts++
+	delivery.ResponseCode, err = postSigned(
+		ctx,
+		w.client,
+		webhook.URL,
+		webhook.Secret,
+		delivery.Event,
+		delivery.UUID,
+		delivery.Payload,
+	)
+	if err == nil {
+		now := time.Now()
+		delivery.Status = entities.WebhookDeliverySucceeded
+		delivery.Error = ""
+		delivery.DeliveredAt = &now
+
+		err = w.repository.UpdateDelivery(ctx, &delivery)
+		if err != nil {
+			return errors.Join(errors.New("failed to update delivery"), err)
+		}
+
+		return w.repository.ResetWebhookFailures(ctx, webhook.ID)
+	}
+
+	delivery.Error = truncate(err.Error(), webhookMaxErrorLetters)
+	if delivery.Attempts >= rules.WebhookMaxAttempts {
+		delivery.Status = entities.WebhookDeliveryFailed
+	} else {
+		delivery.NextAttemptAt = time.Now().Add(rules.WebhookBackoff(delivery.Attempts))
+	}
+
+	err = w.repository.UpdateDelivery(ctx, &delivery)
+	if err != nil {
+		return errors.Join(errors.New("failed to update delivery"), err)
+	}
+
+	disabled, err := w.repository.AddWebhookFailure(ctx, webhook.ID, rules.WebhookMaxConsecutiveFailures)
+	if err != nil {
+		return errors.Join(errors.New("failed to count webhook failure"), err)
+	}
+
+	if disabled && webhook.Enabled {
+		slog.Warn("webhook disabled after too many failures", "webhook", webhook.ID, "url", webhook.URL)
+	}
+
+	return nil
+}
+
+// postSigned posts the payload signed with the secret. Any response other than 2xx is an error.
+func postSigned(
+	ctx context.Context,
+	client *http.Client,
+	url string,
+	secret string,
+	event string,
+	deliveryUUID string,
+	payload []byte,
+) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "taskflow-webhook")
+	request.Header.Set("X-Taskflow-Event", event)
+	request.Header.Set("X-Taskflow-Delivery", deliveryUUID)
+	request.Header.Set("X-Taskflow-Signature", "sha256="+util.SignPayload(payload, secret))
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, webhookMaxErrorLetters))
+		return response.StatusCode, fmt.Errorf("unexpected response %s: %s", response.Status, body)
+	}
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<20))
+	return response.StatusCode, nil
+}
+
+// errWebhookAddress is returned when a webhook host resolves to an address webhooks can't be sent to
+var errWebhookAddress = errors.New("webhook address is not public")
+
+// newWebhookClient returns the client posting webhooks, which does not follow redirects nor use proxies. It only
+// connects to public addresses, the resolved address being checked when connecting so DNS rebinding can't reach
+// internal services.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: rules.WebhookTimeout,
+		Control: webhookDialControl,
+	}
+
+	return &http.Client{
+		Timeout: rules.WebhookTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: rules.WebhookTimeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// webhookDialControl refuses the connections to the addresses rejected by rules.IsPublicAddress
+func webhookDialControl(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	if !rules.IsPublicAddress(addr) {
+		return fmt.Errorf("%w: %s", errWebhookAddress, addr)
+	}
+
+	return nil
+}
+
+// getOwnedBoard checks that the board exists and the user owns it
+func (w WebhookUseCases) getOwnedBoard(
+	ctx context.Context,
+	user *entities.User,
+	boardID int,
+) (status_codes.WebhookStatusCode, error) {
+	err := w.checkBoardOwner(ctx, user, boardID)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return status_codes.WebhookBoardNotFound, nil
+		}
+
+		return status_codes.WebhookFailure, err
+	}
+
+	return status_codes.WebhookSuccess, nil
+}
+
+// getOwnedWebhook returns the webhook if the user owns its board. A nil webhook is returned along with the status
+// code or error to send back otherwise.
+func (w WebhookUseCases) getOwnedWebhook(
+	ctx context.Context,
+	user *entities.User,
+	id int,
+) (*entities.Webhook, status_codes.WebhookStatusCode, error) {
+	webhook, err := w.repository.GetWebhookByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, entities.ErrNotFound) {
+			return nil, status_codes.WebhookNotFound, nil
+		}
+
+		return nil, status_codes.WebhookFailure, errors.Join(errors.New("failed to get webhook"), err)
+	}
+
+	err = w.checkBoardOwner(ctx, user, webhook.IDBoard)
+	if err != nil {
+		return nil, status_codes.WebhookFailure, err
+	}
+
+	return webhook, status_codes.WebhookSuccess, nil
+}
+
+// checkBoardOwner returns entities.ErrForbidden if the user is not the owner of the board
+func (w WebhookUseCases) checkBoardOwner(ctx context.Context, user *entities.User, boardID int) error {
+	board, err := w.boardRepository.GetBoardByID(ctx, boardID)
+	if err != nil {
+		return err
+	}
+
+	if board.CreatedBy.ID != user.ID {
+		return entities.ErrForbidden
+	}
+
+	return nil
+}
+
+// truncate cuts the string to at most size bytes, without splitting a UTF-8 sequence
+// webhookHost returns the host a webhook posts to, as recorded in the activities seen by every member of the board:
+// the path and query of the URL may hold the token of the receiver
+func webhookHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Host
+}
+
+func truncate(s string, size int) string {
+	if len(s) <= size {
+		return s
+	}
+
+	for size > 0 && !utf8.RuneStart(s[size]) {
+		size--
+	}
+
+	return s[:size]
+}
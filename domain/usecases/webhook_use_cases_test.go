@@ -0,0 +1,294 @@
+package usecases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/infrastructure/datastore"
+	"testing"
+	"time"
+)
+
+// fakeWebhookRepository keeps a single webhook and the last saved delivery in memory, disabling the webhook like the
+// MySQL repository does
+type fakeWebhookRepository struct {
+	datastore.WebhookRepository
+
+	mu       sync.Mutex
+	webhook  entities.Webhook
+	delivery entities.WebhookDelivery
+	failures int
+}
+
+func (f *fakeWebhookRepository) GetWebhookByID(_ context.Context, _ int) (*entities.Webhook, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	webhook := f.webhook
+	return &webhook, nil
+}
+
+func (f *fakeWebhookRepository) UpdateDelivery(_ context.Context, delivery *entities.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.delivery = *delivery
+	return nil
+}
+
+func (f *fakeWebhookRepository) ResetWebhookFailures(_ context.Context, _ int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures = 0
+	return nil
+}
+
+func (f *fakeWebhookRepository) AddWebhookFailure(_ context.Context, _ int, maxFailures int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures++
+	if f.failures >= maxFailures {
+		f.webhook.Enabled = false
+	}
+
+	return !f.webhook.Enabled, nil
+}
+
+// newTestWebhookUseCases returns the use cases posting to the receiver with a plain client, the test receivers
+// listening on the loopback address the webhook client refuses
+func newTestWebhookUseCases(receiver *httptest.Server) (WebhookUseCases, *fakeWebhookRepository) {
+	repository := &fakeWebhookRepository{
+		webhook: entities.Webhook{ID: 1, URL: receiver.URL, Secret: "secret", Enabled: true},
+	}
+
+	return WebhookUseCases{repository: repository, client: receiver.Client()}, repository
+}
+
+func TestSendDeliverySignsPayload(t *testing.T) {
+	payload := []byte(`{"event":"task.created"}`)
+
+	var signature, event, deliveryUUID string
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get("X-Taskflow-Signature")
+		event = r.Header.Get("X-Taskflow-Event")
+		deliveryUUID = r.Header.Get("X-Taskflow-Delivery")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	w, repository := newTestWebhookUseCases(receiver)
+	err := w.sendDelivery(context.Background(), entities.WebhookDelivery{
+		ID:        1,
+		UUID:      "delivery-uuid",
+		IDWebhook: 1,
+		Event:     "task.created",
+		Payload:   payload,
+		Status:    entities.WebhookDeliveryPending,
+	})
+	if err != nil {
+		t.Fatalf("sendDelivery() error = %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(payload)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if signature != want {
+		t.Errorf("signature = %q, want %q", signature, want)
+	}
+
+	if event != "task.created" || deliveryUUID != "delivery-uuid" || string(body) != string(payload) {
+		t.Errorf("event = %q, delivery = %q, body = %q", event, deliveryUUID, body)
+	}
+
+	delivery := repository.delivery
+	if delivery.Status != entities.WebhookDeliverySucceeded || delivery.Attempts != 1 ||
+		delivery.ResponseCode != http.StatusNoContent || delivery.DeliveredAt == nil {
+		t.Errorf("delivery = %+v, want succeeded after 1 attempt", delivery)
+	}
+}
+
+func TestSendDeliveryRetriesWithBackoff(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	w, repository := newTestWebhookUseCases(receiver)
+	delivery := entities.WebhookDelivery{ID: 1, IDWebhook: 1, Status: entities.WebhookDeliveryPending}
+
+	for attempt := 1; attempt < rules.WebhookMaxAttempts; attempt++ {
+		before := time.Now()
+		err := w.sendDelivery(context.Background(), delivery)
+		if err != nil {
+			t.Fatalf("sendDelivery() error = %v", err)
+		}
+
+		delivery = repository.delivery
+		if delivery.Status != entities.WebhookDeliveryPending || delivery.Attempts != attempt {
+			t.Fatalf("attempt %d: delivery = %+v, want pending", attempt, delivery)
+		}
+
+		if delivery.ResponseCode != http.StatusInternalServerError || delivery.Error == "" {
+			t.Errorf("attempt %d: response code = %d, error = %q", attempt, delivery.ResponseCode, delivery.Error)
+		}
+
+		wait := delivery.NextAttemptAt.Sub(before)
+		backoff := rules.WebhookBackoff(attempt)
+		if wait < backoff || wait > backoff+time.Minute {
+			t.Errorf("attempt %d: next attempt in %v, want %v", attempt, wait, backoff)
+		}
+	}
+
+	err := w.sendDelivery(context.Background(), delivery)
+	if err != nil {
+		t.Fatalf("sendDelivery() error = %v", err)
+	}
+
+	if repository.delivery.Status != entities.WebhookDeliveryFailed {
+		t.Errorf("status = %q after %d attempts, want failed", repository.delivery.Status, rules.WebhookMaxAttempts)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: rules.WebhookBaseBackoff},
+		{attempts: 2, want: 2 * rules.WebhookBaseBackoff},
+		{attempts: 3, want: 4 * rules.WebhookBaseBackoff},
+		{attempts: 20, want: rules.WebhookMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := rules.WebhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("WebhookBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestSendDeliveryDisablesWebhook(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	w, repository := newTestWebhookUseCases(receiver)
+	send := func() {
+		t.Helper()
+
+		err := w.sendDelivery(context.Background(), entities.WebhookDelivery{ID: 1, IDWebhook: 1})
+		if err != nil {
+			t.Fatalf("sendDelivery() error = %v", err)
+		}
+	}
+
+	// A success in between resets the consecutive failures
+	for range rules.WebhookMaxConsecutiveFailures - 1 {
+		send()
+	}
+
+	failing.Store(false)
+	send()
+	failing.Store(true)
+
+	for range rules.WebhookMaxConsecutiveFailures - 1 {
+		send()
+	}
+
+	if !repository.webhook.Enabled {
+		t.Fatalf("webhook disabled after %d failures in a row", rules.WebhookMaxConsecutiveFailures-1)
+	}
+
+	send()
+	if repository.webhook.Enabled {
+		t.Errorf("webhook enabled after %d failures in a row", rules.WebhookMaxConsecutiveFailures)
+	}
+}
+
+func TestWebhookClientRefusesInternalAddresses(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	_, err := postSigned(context.Background(), newWebhookClient(), receiver.URL, "secret", "task.created", "id", nil)
+	if !errors.Is(err, errWebhookAddress) {
+		t.Errorf("postSigned() to %s error = %v, want %v", receiver.URL, err, errWebhookAddress)
+	}
+}
+
+func TestWebhookDialControl(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{address: "93.184.216.34:443", allowed: true},
+		{address: "[2606:2800:220:1:248:1893:25c8:1946]:443", allowed: true},
+		{address: "127.0.0.1:80", allowed: false},
+		{address: "[::1]:80", allowed: false},
+		{address: "10.1.2.3:80", allowed: false},
+		{address: "172.16.0.1:80", allowed: false},
+		{address: "192.168.1.1:80", allowed: false},
+		{address: "169.254.169.254:80", allowed: false},
+		{address: "[fe80::1]:80", allowed: false},
+		{address: "[fd00::1]:80", allowed: false},
+		{address: "0.0.0.0:80", allowed: false},
+		{address: "[::ffff:127.0.0.1]:80", allowed: false},
+		{address: "224.0.0.1:80", allowed: false},
+	}
+
+	for _, tt := range tests {
+		err := webhookDialControl("tcp", tt.address, nil)
+		if (err == nil) != tt.allowed {
+			t.Errorf("webhookDialControl(%q) error = %v, want allowed = %v", tt.address, err, tt.allowed)
+		}
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{url: "https://example.com/hooks", valid: true},
+		{url: "http://93.184.216.34:8080/hooks", valid: true},
+		{url: "ftp://example.com/hooks", valid: false},
+		{url: "https://localhost/hooks", valid: false},
+		{url: "http://127.0.0.1:8067/api", valid: false},
+		{url: "http://[::1]/hooks", valid: false},
+		{url: "http://169.254.169.254/latest/meta-data", valid: false},
+		{url: "http://10.0.0.5/hooks", valid: false},
+	}
+
+	for _, tt := range tests {
+		if got := rules.ValidateWebhookURL(tt.url); got != tt.valid {
+			t.Errorf("ValidateWebhookURL(%q) = %v, want %v", tt.url, got, tt.valid)
+		}
+	}
+
+	if rules.IsPublicAddress(netip.Addr{}) {
+		t.Error("IsPublicAddress() of the zero address = true, want false")
+	}
+}
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronScheduleErrors(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@weekdays",
+	}
+
+	for _, spec := range specs {
+		_, err := ParseCronSchedule(spec)
+		if err == nil {
+			t.Errorf("ParseCronSchedule(%q) error = nil, want an error", spec)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// 2025-01-01 is a Wednesday
+	date := func(month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		{"every minute", "* * * * *", date(1, 1, 10, 30), date(1, 1, 10, 31)},
+		{"strictly after", "30 10 * * *", date(1, 1, 10, 30), date(1, 2, 10, 30)},
+		{"seconds truncated", "* * * * *", date(1, 1, 10, 30).Add(45 * time.Second), date(1, 1, 10, 31)},
+		{"minute step", "*/15 * * * *", date(1, 1, 10, 31), date(1, 1, 10, 45)},
+		{"hour range", "0 8-18 * * *", date(1, 1, 18, 30), date(1, 2, 8, 0)},
+		{"list", "0 6,18 * * *", date(1, 1, 7, 0), date(1, 1, 18, 0)},
+		{"start with step", "5/20 * * * *", date(1, 1, 10, 26), date(1, 1, 10, 45)},
+		{"weekdays", "0 9 * * 1-5", date(1, 3, 10, 0), date(1, 6, 9, 0)},
+		{"sunday as 7", "0 0 * * 7", date(1, 1, 0, 0), date(1, 5, 0, 0)},
+		{"sunday as 0", "0 0 * * 0", date(1, 1, 0, 0), date(1, 5, 0, 0)},
+		{"month", "0 0 1 3 *", date(1, 1, 0, 0), date(3, 1, 0, 0)},
+		{"day 31 skips short months", "0 0 31 * *", date(1, 31, 0, 0), date(3, 31, 0, 0)},
+		{"leap day", "0 0 29 2 *", date(1, 1, 0, 0), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"yearly", "@yearly", date(1, 1, 0, 0), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"weekly", "@weekly", date(1, 1, 0, 0), date(1, 5, 0, 0)},
+		{"hourly", "@hourly", date(1, 1, 10, 0), date(1, 1, 11, 0)},
+
+		// Both restricted: the day of month or the day of week matches
+		{"restricted days OR'ed", "0 0 15 * 1", date(1, 1, 0, 0), date(1, 6, 0, 0)},
+		{"restricted days OR'ed dom", "0 0 3 * 1", date(1, 1, 0, 0), date(1, 3, 0, 0)},
+
+		// A field starting with * is unrestricted, so both must match: odd days that are Mondays
+		{"day of month step AND'ed", "0 0 */2 * 1", date(1, 1, 0, 0), date(1, 13, 0, 0)},
+		{"day of week step AND'ed", "0 0 13 * */1", date(1, 1, 0, 0), date(1, 13, 0, 0)},
+		{"day of week star AND'ed", "0 0 13 * *", date(1, 1, 0, 0), date(1, 13, 0, 0)},
+
+		{"never", "0 0 30 2 *", date(1, 1, 0, 0), time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseCronSchedule(%q) error = %v", tt.spec, err)
+			}
+
+			got := schedule.Next(tt.after)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+		})
+	}
+}
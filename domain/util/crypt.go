@@ -1,9 +1,16 @@
 package util
 
 import (
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/rand"
+	"taskflow/domain/entities"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,27 +33,42 @@ func Hash(informationToHash string) (string, error) {
 
 // GetUserIDFromToken extracts the user ID from a PASETO token string
 func GetUserIDFromToken(token string, pasetoSecurityKey string) (int, bool, error) {
+	authToken, err := ParseAuthToken(token, pasetoSecurityKey)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if time.Now().After(authToken.ExpiresAt) {
+		return 0, true, nil
+	}
+
+	return authToken.IDUser, false, nil
+}
+
+// ParseAuthToken decrypts a PASETO token string generated by GetNewAuthToken
+//
+// The expiration is not checked
+func ParseAuthToken(token string, pasetoSecurityKey string) (*entities.AuthToken, error) {
 	symmetricKey := []byte(pasetoSecurityKey)
-	now := time.Now()
 
 	var payload paseto.JSONToken
 	var footer string
 	_, err := paseto.Parse(token, &payload, &footer, symmetricKey, nil)
 	if err != nil {
-		return 0, false, errors.Join(errors.New("failed to parse token"), err)
-	}
-
-	if now.After(payload.Expiration) {
-		return 0, true, nil
+		return nil, errors.Join(errors.New("failed to parse token"), err)
 	}
 
 	var userID int
 	err = json.Unmarshal([]byte(payload.Subject), &userID)
 	if err != nil {
-		return 0, false, errors.Join(errors.New("failed to parse unmarshal token payload"), err)
+		return nil, errors.Join(errors.New("failed to parse unmarshal token payload"), err)
 	}
 
-	return userID, false, nil
+	return &entities.AuthToken{
+		ID:        payload.Jti,
+		IDUser:    userID,
+		ExpiresAt: payload.Expiration,
+	}, nil
 }
 
 // GetNewAuthToken generates a PASETO token for the provided user
@@ -91,6 +113,63 @@ func GetNewAuthToken(userID int, userUUID string, pasetoSecurityKey string) (str
 	return encrypted, nil
 }
 
+// SignFileURL returns the HMAC-SHA256 signature granting access to the given attachment until the given expiration
+func SignFileURL(attachmentID int, expiresAt time.Time, securityKey string) string {
+	mac := hmac.New(sha256.New, []byte(securityKey))
+	_, _ = fmt.Fprintf(mac, "attachment:%d:%d", attachmentID, expiresAt.Unix())
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// CheckFileURLSignature verifies that the signature was generated by SignFileURL for the same attachment and expiration
+//
+// The expiration itself is not checked
+func CheckFileURLSignature(attachmentID int, expiresAt time.Time, signature string, securityKey string) bool {
+	expected := SignFileURL(attachmentID, expiresAt, securityKey)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// SignUnsubscribe returns the HMAC-SHA256 signature of an unsubscribe link, letting the user turn off the given
+// notification type without logging in
+func SignUnsubscribe(userID int, notificationType string, securityKey string) string {
+	mac := hmac.New(sha256.New, []byte(securityKey))
+	_, _ = fmt.Fprintf(mac, "unsubscribe:%d:%s", userID, notificationType)
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// CheckUnsubscribeSignature verifies that the signature was generated by SignUnsubscribe for the same user and type
+func CheckUnsubscribeSignature(userID int, notificationType string, signature string, securityKey string) bool {
+	expected := SignUnsubscribe(userID, notificationType, securityKey)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// GenerateSecret returns a random hex string with the given number of bytes, suitable for secrets and tokens
+func GenerateSecret(size int) (string, error) {
+	buffer := make([]byte, size)
+	_, err := cryptorand.Read(buffer)
+	if err != nil {
+		return "", errors.Join(errors.New("failed to read random bytes"), err)
+	}
+
+	return hex.EncodeToString(buffer), nil
+}
+
+// HashToken returns the hex encoded SHA-256 of a random token, stored in place of the token so that it can be looked
+// up without being readable from the database
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SignPayload returns the hex encoded HMAC-SHA256 of the payload with the given secret
+func SignPayload(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func GenerateRandomPassword() string {
 	charset := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	seededRand := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icalLineOctets is the maximum length of an iCalendar line, longer lines being folded
+const icalLineOctets = 75
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// ICalendar builds an iCalendar (RFC 5545) document, escaping the text values and folding the long lines
+type ICalendar struct {
+	builder strings.Builder
+}
+
+// NewICalendar starts a published calendar with the given product identifier and display name
+func NewICalendar(productID string, name string) *ICalendar {
+	c := &ICalendar{}
+	c.Property("BEGIN", "VCALENDAR")
+	c.Property("VERSION", "2.0")
+	c.Text("PRODID", productID)
+	c.Property("CALSCALE", "GREGORIAN")
+	c.Property("METHOD", "PUBLISH")
+	c.Text("X-WR-CALNAME", name)
+
+	return c
+}
+
+// Property writes a property whose value is already formatted
+func (c *ICalendar) Property(name string, value string) {
+	line := name + ":" + value
+
+	// Folded lines go on with a space, which counts in the length of the line
+	limit := icalLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		c.builder.WriteString(line[:cut])
+		c.builder.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icalLineOctets - 1
+	}
+
+	c.builder.WriteString(line)
+	c.builder.WriteString("\r\n")
+}
+
+// Text writes a text property, escaping its value
+func (c *ICalendar) Text(name string, value string) {
+	c.Property(name, icalTextEscaper.Replace(value))
+}
+
+// Time writes a date-time property in UTC
+func (c *ICalendar) Time(name string, t time.Time) {
+	c.Property(name, t.UTC().Format("20060102T150405Z"))
+}
+
+// Bytes ends the calendar and returns the document
+func (c *ICalendar) Bytes() []byte {
+	c.Property("END", "VCALENDAR")
+	return []byte(c.builder.String())
+}
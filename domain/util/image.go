@@ -0,0 +1,112 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// MaxImagePixels is the biggest source image (width * height) accepted for thumbnail generation, so a small file
+// can't be used to allocate huge amounts of memory when decoded
+const MaxImagePixels = 50_000_000
+
+// MakeThumbnail decodes a PNG, JPEG or GIF image and returns it scaled down to fit in a square of the given dimension,
+// keeping its aspect ratio. Images smaller than the dimension are not scaled up.
+//
+// The thumbnail is encoded as JPEG for JPEG sources and as PNG otherwise, to keep transparency. Returns the encoded
+// thumbnail, its content type and its final width and height.
+func MakeThumbnail(data []byte, dimension int) ([]byte, string, int, int, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", 0, 0, errors.Join(errors.New("failed to decode image config"), err)
+	}
+
+	if cfg.Width*cfg.Height > MaxImagePixels {
+		return nil, "", 0, 0, errors.New("image is too large")
+	}
+
+	var src image.Image
+	switch format {
+	case "png":
+		src, err = png.Decode(bytes.NewReader(data))
+	case "jpeg":
+		src, err = jpeg.Decode(bytes.NewReader(data))
+	case "gif":
+		src, err = gif.Decode(bytes.NewReader(data))
+	default:
+		return nil, "", 0, 0, errors.New("unsupported image format: " + format)
+	}
+	if err != nil {
+		return nil, "", 0, 0, errors.Join(errors.New("failed to decode image"), err)
+	}
+
+	thumbnail := ResizeToFit(src, dimension)
+
+	var buffer bytes.Buffer
+	contentType := "image/png"
+	if format == "jpeg" {
+		contentType = "image/jpeg"
+		err = jpeg.Encode(&buffer, thumbnail, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buffer, thumbnail)
+	}
+	if err != nil {
+		return nil, "", 0, 0, errors.Join(errors.New("failed to encode thumbnail"), err)
+	}
+
+	bounds := thumbnail.Bounds()
+	return buffer.Bytes(), contentType, bounds.Dx(), bounds.Dy(), nil
+}
+
+// ResizeToFit scales the image down to fit in a square of the given dimension, averaging the source pixels covered by
+// each destination pixel (box filter)
+func ResizeToFit(src image.Image, dimension int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= 0 || height <= 0 || (width <= dimension && height <= dimension) {
+		return src
+	}
+
+	dstWidth, dstHeight := dimension, dimension
+	if width > height {
+		dstHeight = max(1, height*dimension/width)
+	} else {
+		dstWidth = max(1, width*dimension/height)
+	}
+
+	dst := image.NewRGBA64(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		srcY0 := bounds.Min.Y + y*height/dstHeight
+		srcY1 := max(srcY0+1, bounds.Min.Y+(y+1)*height/dstHeight)
+
+		for x := 0; x < dstWidth; x++ {
+			srcX0 := bounds.Min.X + x*width/dstWidth
+			srcX1 := max(srcX0+1, bounds.Min.X+(x+1)*width/dstWidth)
+
+			var r, g, b, a, n uint64
+			for sy := srcY0; sy < srcY1; sy++ {
+				for sx := srcX0; sx < srcX1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
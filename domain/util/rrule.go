@@ -0,0 +1,207 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RecurrenceDaily   = "DAILY"
+	RecurrenceWeekly  = "WEEKLY"
+	RecurrenceMonthly = "MONTHLY"
+)
+
+// maxRecurrenceInterval bounds INTERVAL, so computing the next occurrence stays cheap
+const maxRecurrenceInterval = 1000
+
+var rruleWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// RecurrenceRule is a parsed subset of an iCalendar RRULE
+type RecurrenceRule struct {
+	frequency string
+	interval  int
+
+	// weekdays is the bit set of the BYDAY weekdays of a weekly rule, indexed by time.Weekday
+	weekdays uint8
+
+	// monthDay is the BYMONTHDAY of a monthly rule, -1 being the last day of the month
+	monthDay int
+}
+
+// ParseRecurrenceRule parses an RRULE such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH"
+//
+// Only FREQ (DAILY, WEEKLY or MONTHLY), INTERVAL, BYDAY (weekly rules) and BYMONTHDAY (required by monthly rules, 1 to
+// 31 or -1 for the last day) are supported
+func ParseRecurrenceRule(rule string) (RecurrenceRule, error) {
+	r := RecurrenceRule{interval: 1}
+
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+	if rule == "" {
+		return r, errors.New("empty recurrence rule")
+	}
+
+	for _, part := range strings.Split(rule, ";") {
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return r, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.frequency = value
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err == nil && (r.interval < 1 || r.interval > maxRecurrenceInterval) {
+				err = fmt.Errorf("interval must be between 1 and %d", maxRecurrenceInterval)
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				index := slices.Index(rruleWeekdays, day)
+				if index < 0 {
+					return r, fmt.Errorf("invalid weekday %q", day)
+				}
+
+				r.weekdays |= 1 << index
+			}
+		case "BYMONTHDAY":
+			r.monthDay, err = strconv.Atoi(value)
+			if err == nil && (r.monthDay == 0 || r.monthDay < -1 || r.monthDay > 31) {
+				err = errors.New("month day must be between 1 and 31, or -1")
+			}
+		default:
+			return r, fmt.Errorf("unsupported recurrence rule part %q", key)
+		}
+
+		if err != nil {
+			return r, errors.Join(fmt.Errorf("invalid %s", key), err)
+		}
+	}
+
+	switch r.frequency {
+	case RecurrenceDaily:
+		if r.weekdays != 0 || r.monthDay != 0 {
+			return r, errors.New("daily rules do not accept BYDAY nor BYMONTHDAY")
+		}
+	case RecurrenceWeekly:
+		if r.monthDay != 0 {
+			return r, errors.New("weekly rules do not accept BYMONTHDAY")
+		}
+	case RecurrenceMonthly:
+		if r.weekdays != 0 {
+			return r, errors.New("monthly rules do not accept BYDAY")
+		}
+
+		if r.monthDay == 0 {
+			return r, errors.New("monthly rules require BYMONTHDAY")
+		}
+	default:
+		return r, fmt.Errorf("unsupported frequency %q", r.frequency)
+	}
+
+	return r, nil
+}
+
+// String returns the rule in its canonical RRULE form
+func (r RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + r.frequency}
+
+	if r.interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.interval))
+	}
+
+	if r.weekdays != 0 {
+		days := make([]string, 0, len(rruleWeekdays))
+
+		// Weeks start on Monday
+		for i := range rruleWeekdays {
+			weekday := (i + 1) % len(rruleWeekdays)
+			if r.weekdays&(1<<weekday) != 0 {
+				days = append(days, rruleWeekdays[weekday])
+			}
+		}
+
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if r.monthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.monthDay))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence strictly after the given one, keeping its time of day
+func (r RecurrenceRule) Next(after time.Time) time.Time {
+	switch r.frequency {
+	case RecurrenceDaily:
+		return after.AddDate(0, 0, r.interval)
+	case RecurrenceWeekly:
+		return r.nextWeekly(after)
+	case RecurrenceMonthly:
+		return r.nextMonthly(after)
+	default:
+		return time.Time{}
+	}
+}
+
+// nextWeekly returns the next matching weekday, in the weeks that are a multiple of the interval away from the week of
+// the given occurrence
+func (r RecurrenceRule) nextWeekly(after time.Time) time.Time {
+	weekdays := r.weekdays
+	if weekdays == 0 {
+		weekdays = 1 << after.Weekday()
+	}
+
+	week := startOfWeek(after)
+	for i := 1; i <= 7*(r.interval+1); i++ {
+		day := after.AddDate(0, 0, i)
+
+		weeks := int(startOfWeek(day).Sub(week).Hours()) / (24 * 7)
+		if weeks%r.interval == 0 && weekdays&(1<<day.Weekday()) != 0 {
+			return day
+		}
+	}
+
+	return time.Time{}
+}
+
+// nextMonthly returns the month day of the first month, every interval months from the given occurrence, where it
+// comes after the occurrence. Months shorter than the day use their last day.
+func (r RecurrenceRule) nextMonthly(after time.Time) time.Time {
+	for months := 0; months <= 2*r.interval; months += r.interval {
+		month := time.Date(after.Year(), after.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+		last := month.AddDate(0, 1, -1).Day()
+
+		day := r.monthDay
+		if day == -1 || day > last {
+			day = last
+		}
+
+		next := time.Date(
+			month.Year(),
+			month.Month(),
+			day,
+			after.Hour(),
+			after.Minute(),
+			after.Second(),
+			after.Nanosecond(),
+			after.Location(),
+		)
+		if next.After(after) {
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+// startOfWeek returns the Monday of the week of the given time, as a UTC date
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
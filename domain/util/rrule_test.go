@@ -0,0 +1,160 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{rule: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{rule: "rrule:freq=daily;interval=1", want: "FREQ=DAILY"},
+		{rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=TH,MO", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH"},
+		{rule: "FREQ=WEEKLY;BYDAY=SU,SA", want: "FREQ=WEEKLY;BYDAY=SA,SU"},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=-1", want: "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		{rule: "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=31", want: "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=31"},
+		{rule: "", wantErr: true},
+		{rule: "FREQ=YEARLY", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=1001", wantErr: true},
+		{rule: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{rule: "FREQ=MONTHLY", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=0", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=-2", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYDAY=MO;BYMONTHDAY=1", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=3", wantErr: true},
+		{rule: "FREQ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		rule, err := ParseRecurrenceRule(tt.rule)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRecurrenceRule(%q) error = nil, want an error", tt.rule)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseRecurrenceRule(%q) error = %v", tt.rule, err)
+			continue
+		}
+
+		if got := rule.String(); got != tt.want {
+			t.Errorf("ParseRecurrenceRule(%q).String() = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+	}
+
+	// Each occurrence is computed from the previous one, 2025-01-06 being a Monday
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "daily",
+			rule:  "FREQ=DAILY",
+			start: date(2025, 1, 31),
+			want:  []time.Time{date(2025, 2, 1), date(2025, 2, 2)},
+		},
+		{
+			name:  "every 3 days",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: date(2025, 2, 27),
+			want:  []time.Time{date(2025, 3, 2), date(2025, 3, 5)},
+		},
+		{
+			name:  "weekly on the same weekday",
+			rule:  "FREQ=WEEKLY",
+			start: date(2025, 1, 8),
+			want:  []time.Time{date(2025, 1, 15), date(2025, 1, 22)},
+		},
+		{
+			name:  "weekly on weekdays",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,TH",
+			start: date(2025, 1, 6),
+			want:  []time.Time{date(2025, 1, 9), date(2025, 1, 13), date(2025, 1, 16)},
+		},
+		{
+			name:  "every 2 weeks on weekdays",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start: date(2025, 1, 6),
+			want:  []time.Time{date(2025, 1, 9), date(2025, 1, 20), date(2025, 1, 23), date(2025, 2, 3)},
+		},
+		{
+			name:  "every 2 weeks on Sunday, the last day of the week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU",
+			start: date(2025, 1, 6),
+			want:  []time.Time{date(2025, 1, 12), date(2025, 1, 26)},
+		},
+		{
+			name:  "every 3 weeks across years",
+			rule:  "FREQ=WEEKLY;INTERVAL=3",
+			start: date(2024, 12, 23),
+			want:  []time.Time{date(2025, 1, 13), date(2025, 2, 3)},
+		},
+		{
+			name:  "monthly on the 31st clamped to short months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: date(2025, 1, 31),
+			want:  []time.Time{date(2025, 2, 28), date(2025, 3, 31), date(2025, 4, 30), date(2025, 5, 31)},
+		},
+		{
+			name:  "monthly on the 30th in a leap year",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=30",
+			start: date(2028, 1, 30),
+			want:  []time.Time{date(2028, 2, 29), date(2028, 3, 30)},
+		},
+		{
+			name:  "monthly on the last day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: date(2024, 12, 31),
+			want:  []time.Time{date(2025, 1, 31), date(2025, 2, 28), date(2025, 3, 31), date(2025, 4, 30)},
+		},
+		{
+			name:  "monthly from before the day in the month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=15",
+			start: date(2025, 1, 10),
+			want:  []time.Time{date(2025, 1, 15), date(2025, 2, 15)},
+		},
+		{
+			name:  "quarterly on the last day",
+			rule:  "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=-1",
+			start: date(2025, 2, 28),
+			want:  []time.Time{date(2025, 5, 31), date(2025, 8, 31), date(2025, 11, 30), date(2026, 2, 28)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule(%q) error = %v", tt.rule, err)
+			}
+
+			occurrence := tt.start
+			for _, want := range tt.want {
+				got := rule.Next(occurrence)
+				if !got.Equal(want) {
+					t.Fatalf("Next(%v) = %v, want %v", occurrence, got, want)
+				}
+
+				occurrence = got
+			}
+		})
+	}
+}
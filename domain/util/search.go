@@ -0,0 +1,130 @@
+package util
+
+import (
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchTerms splits the query into its distinct lowercase words, keeping only letters and digits so that the terms
+// can't carry any search operator. Words shorter than minLetters are dropped and at most maxTerms are returned.
+func SearchTerms(query string, minLetters int, maxTerms int) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !isWordRune(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if utf8.RuneCountInString(word) < minLetters || slices.Contains(terms, word) {
+			continue
+		}
+
+		terms = append(terms, word)
+		if len(terms) == maxTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+// Highlight returns an excerpt of about the given number of letters of the text, starting a little before the first
+// word beginning with one of the terms. The text is HTML escaped and every matching word is wrapped in <mark> tags.
+// The excerpt starts at the beginning of the text when no word matches or when the whole text fits.
+func Highlight(text string, terms []string, letters int) string {
+	runes := []rune(text)
+	matches := matchWords(runes, terms)
+
+	start := 0
+	if len(matches) > 0 && len(runes) > letters && matches[0][0] > letters/4 {
+		start = matches[0][0] - letters/4
+
+		// Avoid cutting a word in two
+		for start < matches[0][0] && isWordRune(runes[start-1]) {
+			start++
+		}
+	}
+
+	end := min(start+letters, len(runes))
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+
+	position := start
+	for _, match := range matches {
+		if match[1] <= start || match[0] >= end {
+			continue
+		}
+
+		matchStart := max(match[0], start)
+		matchEnd := min(match[1], end)
+		b.WriteString(html.EscapeString(string(runes[position:matchStart])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[matchStart:matchEnd])))
+		b.WriteString("</mark>")
+		position = matchEnd
+	}
+
+	b.WriteString(html.EscapeString(string(runes[position:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+// Mark returns the whole text HTML escaped, every word beginning with one of the terms being wrapped in <mark> tags
+func Mark(text string, terms []string) string {
+	return Highlight(text, terms, utf8.RuneCountInString(text))
+}
+
+// matchWords returns the start and end rune offsets of the words beginning with one of the terms, in order
+func matchWords(runes []rune, terms []string) [][2]int {
+	lowerTerms := make([][]rune, 0, len(terms))
+	for _, term := range terms {
+		lowerTerms = append(lowerTerms, []rune(strings.ToLower(term)))
+	}
+
+	matches := make([][2]int, 0)
+	for i := 0; i < len(runes); i++ {
+		if !isWordRune(runes[i]) || (i > 0 && isWordRune(runes[i-1])) {
+			continue
+		}
+
+		end := i
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+
+		if slices.ContainsFunc(lowerTerms, func(term []rune) bool { return hasRunePrefix(runes[i:end], term) }) {
+			matches = append(matches, [2]int{i, end})
+		}
+
+		i = end
+	}
+
+	return matches
+}
+
+// hasRunePrefix tells whether the word starts with the lowercase prefix, ignoring case
+func hasRunePrefix(word []rune, prefix []rune) bool {
+	if len(prefix) == 0 || len(word) < len(prefix) {
+		return false
+	}
+
+	for i, r := range prefix {
+		if unicode.ToLower(word[i]) != r {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
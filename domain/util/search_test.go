@@ -0,0 +1,105 @@
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "Release Notes", want: []string{"release", "notes"}},
+		{query: "  notes NOTES notes ", want: []string{"notes"}},
+		{query: `"release" +notes -draft*`, want: []string{"release", "notes", "draft"}},
+		{query: "a to the", want: []string{"the"}},
+		{query: "one two three four five six", want: []string{"one", "two", "three", "four", "five"}},
+		{query: "café 2025", want: []string{"café", "2025"}},
+		{query: "+-*()", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		got := SearchTerms(tt.query, 3, 5)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SearchTerms(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		terms   []string
+		letters int
+		want    string
+	}{
+		{
+			name:    "whole text fits",
+			text:    "Prepare the release notes",
+			terms:   []string{"notes"},
+			letters: 25,
+			want:    "Prepare the release <mark>notes</mark>",
+		},
+		{
+			name:    "word prefix",
+			text:    "Noted the release",
+			terms:   []string{"note"},
+			letters: 40,
+			want:    "<mark>Noted</mark> the release",
+		},
+		{
+			name:    "inside a word",
+			text:    "Denote it",
+			terms:   []string{"note"},
+			letters: 40,
+			want:    "Denote it",
+		},
+		{
+			name:    "several terms",
+			text:    "Fix the release notes",
+			terms:   []string{"fix", "notes"},
+			letters: 40,
+			want:    "<mark>Fix</mark> the release <mark>notes</mark>",
+		},
+		{
+			name:    "escaped",
+			text:    "<b>notes</b> & more",
+			terms:   []string{"notes"},
+			letters: 40,
+			want:    "&lt;b&gt;<mark>notes</mark>&lt;/b&gt; &amp; more",
+		},
+		{
+			name:    "excerpt not cutting the word before the first match",
+			text:    "one two three four five six seven eight notes nine ten eleven twelve",
+			terms:   []string{"notes"},
+			letters: 20,
+			want:    "…<mark>notes</mark> nine ten eleve…",
+		},
+		{
+			name:    "excerpt from the start without match",
+			text:    "one two three four five",
+			terms:   []string{"notes"},
+			letters: 7,
+			want:    "one two…",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Highlight(tt.text, tt.terms, tt.letters)
+			if got != tt.want {
+				t.Errorf("Highlight(%q, %q, %d) = %q, want %q", tt.text, tt.terms, tt.letters, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMark(t *testing.T) {
+	text := "Prepare the release notes for the next version of the application"
+	want := "Prepare the release <mark>notes</mark> for the next version of the application"
+	if got := Mark(text, []string{"notes"}); got != want {
+		t.Errorf("Mark(%q) = %q, want %q", text, got, want)
+	}
+}
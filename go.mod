@@ -10,6 +10,7 @@ require (
 	github.com/gorilla/mux v1.8.1
 	github.com/kardianos/service v1.2.4
 	github.com/o1egl/paseto v1.0.0
+	go.uber.org/zap v1.27.1
 	golang.org/x/crypto v0.42.0
 )
 
@@ -21,6 +22,5 @@ require (
 	github.com/felixge/httpsnoop v1.0.3 // indirect
 	github.com/pkg/errors v0.8.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
-	go.uber.org/zap v1.27.1 // indirect
 	golang.org/x/sys v0.36.0 // indirect
 )
@@ -0,0 +1,202 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/datastore"
+	"taskflow/infrastructure/datastore/repositories"
+	"taskflow/infrastructure/filestore/hdstore"
+	"taskflow/infrastructure/pubsub/membroker"
+)
+
+// ImportTrelloBoard imports the Trello board JSON export at the file path for the user with the given email, outside
+// of the server, matching the members of the export to the accounts having their email. The activity of the imported
+// board is not sent to the webhooks nor notified, and the thumbnails of the imported images may not be generated
+// before the process exits.
+func ImportTrelloBoard(
+	ctx context.Context,
+	config entities.Config,
+	email string,
+	filePath string,
+	dryRun bool,
+) (*entities.ImportReport, error) {
+	importUseCases, user, err := newImportUseCases(ctx, config, email)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to read export file"), err)
+	}
+
+	report, statusCode, err := importUseCases.ImportTrelloBoard(ctx, user, data, dryRun, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != status_codes.ImportSuccess {
+		return nil, fmt.Errorf("failed to import board: %s", statusCode)
+	}
+
+	return report, nil
+}
+
+// ImportArchive imports the boards of the archive at the file path for the user with the given email, outside of the
+// server, matching the users of the archive to the accounts having their email the same way as ImportTrelloBoard
+func ImportArchive(
+	ctx context.Context,
+	config entities.Config,
+	email string,
+	filePath string,
+) ([]entities.ImportReport, error) {
+	importUseCases, user, err := newImportUseCases(ctx, config, email)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to open archive"), err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to read archive size"), err)
+	}
+
+	reports, statusCode, err := importUseCases.ImportArchive(ctx, user, file, info.Size(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != status_codes.ImportSuccess {
+		return nil, fmt.Errorf("failed to import archive: %s", statusCode)
+	}
+
+	return reports, nil
+}
+
+// ExportArchive writes the archive of the board with the given ID to the file path, or of the workspace of the user
+// with the given email when the ID is zero, outside of the server
+func ExportArchive(ctx context.Context, config entities.Config, email string, boardID int, filePath string) error {
+	repoSettings, err := repositories.NewRepositorySettings(config)
+	if err != nil {
+		return errors.Join(errors.New("failed to create settings repository"), err)
+	}
+
+	authRepository := repositories.NewAuthRepository(repoSettings)
+	boardRepository := repositories.NewBoardRepository(repoSettings)
+	checklistRepository := repositories.NewChecklistRepository(repoSettings)
+	customFieldRepository := repositories.NewCustomFieldRepository(repoSettings)
+	labelRepository := repositories.NewLabelRepository(repoSettings)
+	fileStorage := hdstore.NewHDFileStorage(config)
+
+	boardUseCases := usecases.NewBoardUseCases(
+		boardRepository,
+		repositories.NewAttachmentRepository(repoSettings),
+		authRepository,
+		checklistRepository,
+		customFieldRepository,
+		labelRepository,
+		fileStorage,
+		newActivityUseCases(repoSettings, boardRepository),
+		rules.TrashRetention(config.Trash.RetentionDays),
+	)
+	exportUseCases := usecases.NewExportUseCases(
+		boardUseCases,
+		boardRepository,
+		checklistRepository,
+		repositories.NewCommentRepository(repoSettings),
+		customFieldRepository,
+		labelRepository,
+		fileStorage,
+		config.Environment,
+	)
+
+	user, err := authRepository.GetUserByEmail(ctx, email)
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to get user %q", email), err)
+	}
+
+	var archive *entities.Archive
+	if boardID == 0 {
+		archive, err = exportUseCases.ExportWorkspace(ctx, user)
+	} else {
+		archive, err = exportUseCases.ExportBoard(ctx, user, boardID)
+	}
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return errors.Join(errors.New("failed to create archive"), err)
+	}
+	defer file.Close()
+
+	err = exportUseCases.WriteArchive(ctx, file, archive)
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// newImportUseCases returns the import use cases along with the user with the given email
+func newImportUseCases(
+	ctx context.Context,
+	config entities.Config,
+	email string,
+) (usecases.ImportUseCases, *entities.User, error) {
+	repoSettings, err := repositories.NewRepositorySettings(config)
+	if err != nil {
+		return usecases.ImportUseCases{}, nil, errors.Join(errors.New("failed to create settings repository"), err)
+	}
+
+	authRepository := repositories.NewAuthRepository(repoSettings)
+	boardRepository := repositories.NewBoardRepository(repoSettings)
+	attachmentRepository := repositories.NewAttachmentRepository(repoSettings)
+	fileStorage := hdstore.NewHDFileStorage(config)
+
+	activityUseCases := newActivityUseCases(repoSettings, boardRepository)
+	attachmentUseCases := usecases.NewAttachmentUseCases(
+		attachmentRepository,
+		boardRepository,
+		fileStorage,
+		config.Paseto.SecurityKey,
+		activityUseCases,
+	)
+	importUseCases := usecases.NewImportUseCases(
+		boardRepository,
+		authRepository,
+		attachmentUseCases,
+		fileStorage,
+		activityUseCases,
+		config.Import.AttachmentsFolder,
+		config.Environment,
+	)
+
+	user, err := authRepository.GetUserByEmail(ctx, email)
+	if err != nil {
+		return usecases.ImportUseCases{}, nil, errors.Join(fmt.Errorf("failed to get user %q", email), err)
+	}
+
+	return importUseCases, user, nil
+}
+
+// newActivityUseCases returns activity use cases whose events reach no subscriber, as no server runs
+func newActivityUseCases(
+	repoSettings datastore.RepositorySettings,
+	boardRepository datastore.BoardRepository,
+) usecases.ActivityUseCases {
+	activityRepository := repositories.NewActivityRepository(repoSettings)
+	return usecases.NewActivityUseCases(activityRepository, boardRepository, membroker.NewMemoryBroker())
+}
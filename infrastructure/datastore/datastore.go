@@ -28,8 +28,463 @@ type AuthRepository interface {
 	GetUserByUUID(ctx context.Context, uuid uuid.UUID) (*entities.User, error)
 	DeleteUser(ctx context.Context, id int) error
 	CheckUserCredentials(ctx context.Context, credentials entities.UserCredentials) (bool, error)
+
+	// RevokeToken rejects the session token until it expires
+	RevokeToken(ctx context.Context, token entities.AuthToken) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+
+	// DeleteExpiredTokens forgets the revoked tokens that expired before the given time, returning how many were deleted
+	DeleteExpiredTokens(ctx context.Context, before time.Time) (int, error)
 }
 
 type BoardRepository interface {
-	GetBoards(ctx context.Context) ([]entities.Board, error)
+	// GetBoards returns the boards the user of the filter owns or is a member of, matching the filter
+	GetBoards(ctx context.Context, filter entities.BoardFilter) ([]entities.Board, error)
+	GetBoardByID(ctx context.Context, id int) (*entities.Board, error)
+	AddBoard(ctx context.Context, board *entities.Board) error
+	UpdateBoard(ctx context.Context, board *entities.Board) error
+
+	// DeleteBoard moves the board to the trash, along with its task lists and tasks
+	DeleteBoard(ctx context.Context, id int) error
+
+	// SetBoardStatusCode archives, unarchives or restores the board
+	SetBoardStatusCode(ctx context.Context, id int, statusCode int) error
+
+	// AddBoardWithContent adds the board along with its members, labels, custom fields, task lists and their tasks,
+	// all or nothing. The labels of the tasks are referred to by name, while custom fields, parent tasks, blockers,
+	// recurrence lists and checklist item subtasks are referred to by the IDs the board comes with. The assignees,
+	// custom field values, checklists, comments and attachments of the tasks are added too, the files of the
+	// attachments being already stored. Creation times are kept when set.
+	AddBoardWithContent(ctx context.Context, board *entities.Board) error
+
+	// DuplicateBoard copies the task lists, labels and custom fields of the source board into the new board, along
+	// with what the options include, all or nothing. The attachments hold the copies of the attachments by source
+	// attachment ID, with the task ID of the source and the paths of the already copied files.
+	DuplicateBoard(
+		ctx context.Context,
+		sourceID int,
+		board *entities.Board,
+		options entities.DuplicateOptions,
+		attachments map[int]entities.Attachment,
+	) error
+
+	GetBoardMembers(ctx context.Context, boardID int) ([]entities.User, error)
+	AddBoardMember(ctx context.Context, boardID int, userID int) error
+	RemoveBoardMember(ctx context.Context, boardID int, userID int) error
+	IsBoardMember(ctx context.Context, boardID int, userID int) (bool, error)
+
+	GetTaskLists(ctx context.Context, boardID int) ([]entities.TaskList, error)
+	GetTaskListByID(ctx context.Context, id int) (*entities.TaskList, error)
+	AddTaskList(ctx context.Context, taskList *entities.TaskList) error
+	UpdateTaskList(ctx context.Context, taskList *entities.TaskList) error
+	MoveTaskList(ctx context.Context, id int, position int) error
+
+	// DeleteTaskList moves the task list to the trash, along with its tasks
+	DeleteTaskList(ctx context.Context, id int) error
+	SetTaskListStatusCode(ctx context.Context, id int, statusCode int) error
+
+	GetTasksByBoard(ctx context.Context, boardID int) ([]entities.Task, error)
+
+	// GetTasks returns the tasks of the board matching the filter
+	GetTasks(ctx context.Context, filter entities.TaskFilter) ([]entities.Task, error)
+	GetTaskByID(ctx context.Context, id int) (*entities.Task, error)
+	AddTask(ctx context.Context, task *entities.Task) error
+
+	// AddTasks adds the tasks at the end of their task list, along with their assignees, labels and custom field
+	// values, all or nothing
+	AddTasks(ctx context.Context, tasks []entities.Task) error
+
+	UpdateTask(ctx context.Context, task *entities.Task) error
+	MoveTask(ctx context.Context, id int, taskListID int, position int) error
+
+	// DeleteTask moves the task to the trash
+	DeleteTask(ctx context.Context, id int) error
+	SetTaskStatusCode(ctx context.Context, id int, statusCode int) error
+
+	GetTaskAssignees(ctx context.Context, taskID int) ([]entities.User, error)
+
+	// GetAssigneesByBoard returns the assignees of every task of the board, by task ID
+	GetAssigneesByBoard(ctx context.Context, boardID int) (map[int][]entities.User, error)
+	AddTaskAssignee(ctx context.Context, taskID int, userID int) error
+	RemoveTaskAssignee(ctx context.Context, taskID int, userID int) error
+	IsTaskAssignee(ctx context.Context, taskID int, userID int) (bool, error)
+
+	// GetTasksDueBefore returns the unfinished active tasks due before the given time whose assignees were not reminded
+	// yet
+	GetTasksDueBefore(ctx context.Context, until time.Time) ([]entities.Task, error)
+
+	// SetTaskReminded marks the assignees of the task as reminded of its due date
+	SetTaskReminded(ctx context.Context, taskID int, remindedAt time.Time) error
+
+	// SetTaskRecurrence sets the recurrence of the task, or removes it when nil
+	SetTaskRecurrence(ctx context.Context, taskID int, recurrence *entities.Recurrence) error
+
+	// GetRecurringTasksDue returns the active recurring tasks whose due date passed, soonest first
+	GetRecurringTasksDue(ctx context.Context, now time.Time) ([]entities.Task, error)
+
+	// AddTaskOccurrence creates the next occurrence of a recurring task, which takes over the recurrence of the
+	// previous one along with its assignees, labels and checklists. It returns false when the previous occurrence
+	// already lost its recurrence.
+	AddTaskOccurrence(ctx context.Context, previousID int, task *entities.Task) (bool, error)
+
+	// FlagOverdueTasks flags the unfinished active tasks whose due date passed, returning the flagged tasks
+	FlagOverdueTasks(ctx context.Context, now time.Time) ([]entities.Task, error)
+
+	// GetTaskDependencies returns the dependencies of the task, both its blockers and the tasks it blocks
+	GetTaskDependencies(ctx context.Context, taskID int) ([]entities.TaskDependency, error)
+	GetDependenciesByBoard(ctx context.Context, boardID int) ([]entities.TaskDependency, error)
+
+	// AddTaskDependency adds the dependency unless it would close a cycle, in which case false is returned. Additions
+	// are serialized per board, so concurrent additions can't close a cycle either.
+	AddTaskDependency(ctx context.Context, boardID int, dependency entities.TaskDependency) (bool, error)
+	RemoveTaskDependency(ctx context.Context, taskID int, blockerID int) error
+
+	// CountUnfinishedBlockers returns how many blockers of the task are neither finished nor in the trash
+	CountUnfinishedBlockers(ctx context.Context, taskID int) (int, error)
+
+	// GetTrash returns the deleted items the user can restore, the last deleted first
+	GetTrash(ctx context.Context, userID int) ([]entities.TrashItem, error)
+	GetTrashItem(ctx context.Context, itemType entities.TrashItemType, id int) (*entities.TrashItem, error)
+
+	// PurgeTrash deletes for good the items deleted before the given time, returning the paths of the files of their
+	// attachments and how many items were purged
+	PurgeTrash(ctx context.Context, before time.Time) ([]string, int, error)
+}
+
+type LabelRepository interface {
+	GetLabelsByBoard(ctx context.Context, boardID int) ([]entities.Label, error)
+	GetLabelByID(ctx context.Context, id int) (*entities.Label, error)
+	AddLabel(ctx context.Context, label *entities.Label) error
+	UpdateLabel(ctx context.Context, label *entities.Label) error
+
+	// DeleteLabel deletes the label, removing it from the tasks tagged with it
+	DeleteLabel(ctx context.Context, id int) error
+
+	GetLabelsByTask(ctx context.Context, taskID int) ([]entities.Label, error)
+
+	// GetTaskLabelsByBoard returns the labels of every task of the board, by task ID
+	GetTaskLabelsByBoard(ctx context.Context, boardID int) (map[int][]entities.Label, error)
+
+	// AddTaskLabel tags the task with the label, returning false if it already was
+	AddTaskLabel(ctx context.Context, taskID int, labelID int) (bool, error)
+
+	// RemoveTaskLabel removes the label from the task, returning false if the task wasn't tagged with it
+	RemoveTaskLabel(ctx context.Context, taskID int, labelID int) (bool, error)
+}
+
+type TemplateRepository interface {
+	GetTemplatesByUser(ctx context.Context, userID int) ([]entities.BoardTemplate, error)
+	GetTemplateByID(ctx context.Context, id int) (*entities.BoardTemplate, error)
+	AddTemplate(ctx context.Context, template *entities.BoardTemplate) error
+	DeleteTemplate(ctx context.Context, id int) error
+}
+
+type SearchRepository interface {
+	// Search returns the active boards, tasks and comments matching the filter, the best ranked first. The snippet of
+	// the results holds the whole description or comment body.
+	Search(ctx context.Context, filter entities.SearchFilter) ([]entities.SearchResult, error)
+}
+
+type AttachmentRepository interface {
+	AddAttachment(ctx context.Context, attachment *entities.Attachment) error
+	GetAttachmentByID(ctx context.Context, id int) (*entities.Attachment, error)
+	GetAttachmentsByTask(ctx context.Context, taskID int) ([]entities.Attachment, error)
+
+	// GetAttachmentsByBoard returns the attachments of the tasks of the board, leaving out those in the trash
+	GetAttachmentsByBoard(ctx context.Context, boardID int) ([]entities.Attachment, error)
+
+	DeleteAttachment(ctx context.Context, id int) error
+	AddThumbnail(ctx context.Context, attachmentID int, thumbnail entities.Thumbnail) error
+	GetThumbnail(ctx context.Context, attachmentID int, size entities.ThumbnailSize) (*entities.Thumbnail, error)
+}
+
+type ActivityRepository interface {
+	AddActivity(ctx context.Context, activity *entities.Activity) error
+	GetActivities(ctx context.Context, filter entities.ActivityFilter) ([]entities.Activity, error)
+}
+
+type CommentRepository interface {
+	// GetCommentsByTask returns the comments of the task posted after the one with the given ID, oldest first
+	GetCommentsByTask(ctx context.Context, taskID int, afterID int, limit int) ([]entities.Comment, error)
+	GetCommentByID(ctx context.Context, id int) (*entities.Comment, error)
+	AddComment(ctx context.Context, comment *entities.Comment) error
+	UpdateComment(ctx context.Context, comment *entities.Comment) error
+	DeleteComment(ctx context.Context, id int) error
+}
+
+type WebhookRepository interface {
+	GetWebhooksByBoard(ctx context.Context, boardID int) ([]entities.Webhook, error)
+	GetWebhookByID(ctx context.Context, id int) (*entities.Webhook, error)
+	AddWebhook(ctx context.Context, webhook *entities.Webhook) error
+	UpdateWebhook(ctx context.Context, webhook *entities.Webhook) error
+	DeleteWebhook(ctx context.Context, id int) error
+
+	// ResetWebhookFailures clears the consecutive failures count of the webhook after a successful delivery
+	ResetWebhookFailures(ctx context.Context, id int) error
+
+	// AddWebhookFailure increments the consecutive failures count of the webhook, disabling it once the count
+	// reaches maxFailures. Returns whether the webhook is now disabled.
+	AddWebhookFailure(ctx context.Context, id int, maxFailures int) (bool, error)
+
+	AddDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id int) (*entities.WebhookDelivery, error)
+	GetDeliveries(ctx context.Context, webhookID int, beforeID int, limit int) ([]entities.WebhookDelivery, error)
+
+	// GetDueDeliveries returns the pending deliveries whose next attempt is due, oldest first
+	GetDueDeliveries(ctx context.Context, limit int) ([]entities.WebhookDelivery, error)
+
+	// ClaimDelivery postpones the next attempt of a due delivery to the given time, so no other worker picks it
+	// while it is being sent. Returns false if the delivery was already claimed.
+	ClaimDelivery(ctx context.Context, id int, until time.Time) (bool, error)
+
+	// UpdateDelivery saves the result of a delivery attempt
+	UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+}
+
+type NotificationRepository interface {
+	// GetPreferences returns the notification preferences of the user, or the defaults if never saved
+	GetPreferences(ctx context.Context, userID int) (*entities.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, preferences *entities.NotificationPreferences) error
+
+	// GetDigestSubscribers returns the preferences of the users subscribed to the daily digest whose last digest was
+	// sent before the given time
+	GetDigestSubscribers(ctx context.Context, sentBefore time.Time) ([]entities.NotificationPreferences, error)
+	SetDigestSent(ctx context.Context, userID int, sentAt time.Time) error
+
+	AddNotification(ctx context.Context, notification *entities.Notification) error
+
+	// GetNotifications returns the notifications matching the filter, newest first
+	GetNotifications(ctx context.Context, filter entities.NotificationFilter) ([]entities.Notification, error)
+	CountUnreadNotifications(ctx context.Context, userID int) (int, error)
+
+	// MarkNotificationRead marks the notification of the user as read. Returns false if the user has no such
+	// notification.
+	MarkNotificationRead(ctx context.Context, userID int, id int, readAt time.Time) (bool, error)
+	MarkAllNotificationsRead(ctx context.Context, userID int, readAt time.Time) error
+
+	WatchBoard(ctx context.Context, boardID int, userID int) error
+	UnwatchBoard(ctx context.Context, boardID int, userID int) error
+	WatchTask(ctx context.Context, taskID int, userID int) error
+	UnwatchTask(ctx context.Context, taskID int, userID int) error
+
+	// GetWatchers returns the IDs of the users watching the task or its board
+	GetWatchers(ctx context.Context, boardID int, taskID int) ([]int, error)
+}
+
+type EmailRepository interface {
+	AddEmail(ctx context.Context, email *entities.Email) error
+
+	// GetDueEmails returns the pending emails whose next attempt is due, oldest first
+	GetDueEmails(ctx context.Context, limit int) ([]entities.Email, error)
+
+	// ClaimEmail postpones the next attempt of a due email to the given time, so no other worker picks it while it is
+	// being sent. Returns false if the email was already claimed.
+	ClaimEmail(ctx context.Context, id int, until time.Time) (bool, error)
+
+	// UpdateEmail saves the result of a sending attempt
+	UpdateEmail(ctx context.Context, email *entities.Email) error
+}
+
+type JobRepository interface {
+	// Lock takes the named lock without waiting, telling whether it was acquired. The lock is held until the returned
+	// function is called, or the connection holding it is lost.
+	Lock(ctx context.Context, name string) (unlock func(), acquired bool, err error)
+
+	// AddJobRun saves the run, unless the job already ran for the same scheduled time, telling whether it was saved
+	AddJobRun(ctx context.Context, run *entities.JobRun) (bool, error)
+	UpdateJobRun(ctx context.Context, run *entities.JobRun) error
+
+	// GetJobRuns returns the runs matching the filter, newest first
+	GetJobRuns(ctx context.Context, filter entities.JobRunFilter) ([]entities.JobRun, error)
+
+	// DeleteJobRuns deletes the runs started before the given time, returning how many were deleted
+	DeleteJobRuns(ctx context.Context, before time.Time) (int, error)
+}
+
+type ChecklistRepository interface {
+	// GetChecklistsByTask returns the checklists of the task along with their items, in order
+	GetChecklistsByTask(ctx context.Context, taskID int) ([]entities.Checklist, error)
+	GetChecklistByID(ctx context.Context, id int) (*entities.Checklist, error)
+	AddChecklist(ctx context.Context, checklist *entities.Checklist) error
+	UpdateChecklist(ctx context.Context, checklist *entities.Checklist) error
+	MoveChecklist(ctx context.Context, id int, position int) error
+	DeleteChecklist(ctx context.Context, id int) error
+
+	GetItemByID(ctx context.Context, id int) (*entities.ChecklistItem, error)
+	AddItem(ctx context.Context, item *entities.ChecklistItem) error
+	UpdateItem(ctx context.Context, item *entities.ChecklistItem) error
+	MoveItem(ctx context.Context, id int, checklistID int, position int) error
+	DeleteItem(ctx context.Context, id int) error
+
+	// PromoteItem creates the subtask of the item, with the item assignee. It returns false when the item was already
+	// promoted, in which case nothing is created.
+	PromoteItem(ctx context.Context, itemID int, subtask *entities.Task) (bool, error)
+
+	// SetSubtaskItemsChecked checks the items promoted into the subtask, or unchecks them when checkedAt is nil
+	SetSubtaskItemsChecked(ctx context.Context, subtaskID int, checkedAt *time.Time) error
+
+	// GetProgressByBoard returns the checklist progress of every task of the board having checklist items, by task ID
+	GetProgressByBoard(ctx context.Context, boardID int) (map[int]entities.Progress, error)
+}
+
+type CustomFieldRepository interface {
+	GetFieldsByBoard(ctx context.Context, boardID int) ([]entities.CustomField, error)
+	GetFieldByID(ctx context.Context, id int) (*entities.CustomField, error)
+	AddField(ctx context.Context, field *entities.CustomField) error
+
+	// UpdateField updates the name and options of the field, removing the given options from the values of the tasks
+	UpdateField(ctx context.Context, field *entities.CustomField, removedOptions []string) error
+	DeleteField(ctx context.Context, id int) error
+
+	GetValuesByTask(ctx context.Context, taskID int) ([]entities.CustomFieldValue, error)
+
+	// GetValuesByBoard returns the custom field values of every task of the board, by task ID
+	GetValuesByBoard(ctx context.Context, boardID int) (map[int][]entities.CustomFieldValue, error)
+	SetValue(ctx context.Context, taskID int, value entities.CustomFieldValue) error
+	DeleteValue(ctx context.Context, taskID int, fieldID int) error
+}
+
+type CalendarRepository interface {
+	GetFeed(ctx context.Context, userID int) (*entities.CalendarFeed, error)
+	GetFeedByTokenHash(ctx context.Context, tokenHash string) (*entities.CalendarFeed, error)
+
+	// SaveFeed adds or updates the feed of the user along with its boards, keeping the token hash of an existing feed
+	// unless a new one is set
+	SaveFeed(ctx context.Context, feed *entities.CalendarFeed) error
+
+	// SetFeedTokenHash replaces the token hash of the feed, revoking the previous token. It returns false when the user
+	// has no feed.
+	SetFeedTokenHash(ctx context.Context, userID int, tokenHash string) (bool, error)
+
+	// DeleteFeed deletes the feed of the user, revoking its token. It returns false when the user has no feed.
+	DeleteFeed(ctx context.Context, userID int) (bool, error)
+
+	// GetFeedTasks returns the active tasks listed by the feed, due after the given time on the active boards the user
+	// of the feed owns or is a member of, soonest due first
+	GetFeedTasks(
+		ctx context.Context,
+		feed *entities.CalendarFeed,
+		dueAfter time.Time,
+		limit int,
+	) ([]entities.CalendarTask, error)
+}
+
+// AnalyticsRepository computes the board reports from the activities of the tasks. Completions are the updates
+// finishing a task, the last one counting for the tasks finished again after being reopened.
+type AnalyticsRepository interface {
+	// GetCumulativeFlow returns how many tasks each task list of the board held at the end of every day from the first
+	// to the last given day. Days and task lists without tasks are left out.
+	GetCumulativeFlow(
+		ctx context.Context,
+		boardID int,
+		from time.Time,
+		to time.Time,
+	) ([]entities.CumulativeFlowPoint, error)
+
+	// GetFlowTimes returns the times of the finished tasks of the board completed in the given period. Tasks start
+	// when first moved into the given task list, or into any other task list when zero.
+	GetFlowTimes(
+		ctx context.Context,
+		boardID int,
+		startListID int,
+		from time.Time,
+		until time.Time,
+	) ([]entities.TaskFlowTimes, error)
+
+	// GetWeeklyThroughput returns how many finished tasks of the board were completed each week of the given period,
+	// weeks without completions being left out
+	GetWeeklyThroughput(
+		ctx context.Context,
+		boardID int,
+		from time.Time,
+		until time.Time,
+	) ([]entities.WeeklyThroughput, error)
+
+	// GetOverdueByAssignee returns how many unfinished active tasks of the board were due before the given time, by
+	// assignee, the most overdue first
+	GetOverdueByAssignee(ctx context.Context, boardID int, now time.Time) ([]entities.OverdueCount, error)
+}
+
+type AutomationRepository interface {
+	// GetRulesByBoard returns the rules of the board, in creation order
+	GetRulesByBoard(ctx context.Context, boardID int) ([]entities.AutomationRule, error)
+	GetRuleByID(ctx context.Context, id int) (*entities.AutomationRule, error)
+	AddRule(ctx context.Context, rule *entities.AutomationRule) error
+
+	// UpdateRule updates the name, trigger, actions and enabled flag of the rule
+	UpdateRule(ctx context.Context, rule *entities.AutomationRule) error
+
+	// DeleteRule deletes the rule along with its executions
+	DeleteRule(ctx context.Context, id int) error
+
+	AddExecution(ctx context.Context, execution *entities.AutomationExecution) error
+
+	// GetExecutions returns the executions of the rule logged before the one with the given ID, newest first
+	GetExecutions(ctx context.Context, ruleID int, beforeID int, limit int) ([]entities.AutomationExecution, error)
+
+	// DeleteExecutions deletes the executions logged before the given time, returning how many were deleted
+	DeleteExecutions(ctx context.Context, before time.Time) (int, error)
+}
+
+type TimeRepository interface {
+	GetEntryByID(ctx context.Context, id int) (*entities.TimeEntry, error)
+
+	// GetEntriesByTask returns the time entries of the task, the latest started first
+	GetEntriesByTask(ctx context.Context, taskID int) ([]entities.TimeEntry, error)
+
+	// GetRunningEntry returns the entry of the timer running for the user, or entities.ErrNotFound if none runs
+	GetRunningEntry(ctx context.Context, userID int) (*entities.TimeEntry, error)
+
+	// StartTimer adds the running entry, returning false if a timer already runs for its user
+	StartTimer(ctx context.Context, entry *entities.TimeEntry) (bool, error)
+
+	// StopTimer sets the end and minutes of the running entry, returning false if it was already stopped
+	StopTimer(ctx context.Context, entry *entities.TimeEntry) (bool, error)
+
+	AddEntry(ctx context.Context, entry *entities.TimeEntry) error
+
+	// UpdateEntry updates the start, end, minutes and note of the entry
+	UpdateEntry(ctx context.Context, entry *entities.TimeEntry) error
+	DeleteEntry(ctx context.Context, id int) error
+
+	// GetReportEntries returns the stopped entries started in the range, on the tasks not in the trash, filtered by
+	// board and user when not zero. Entries are ordered by start.
+	GetReportEntries(
+		ctx context.Context,
+		boardID int,
+		userID int,
+		from time.Time,
+		until time.Time,
+	) ([]entities.TimeReportEntry, error)
+}
+
+type SprintRepository interface {
+	// GetSprintsByBoard returns the sprints of the board with their task counts, by start date
+	GetSprintsByBoard(ctx context.Context, boardID int) ([]entities.Sprint, error)
+	GetSprintByID(ctx context.Context, id int) (*entities.Sprint, error)
+	AddSprint(ctx context.Context, sprint *entities.Sprint) error
+
+	// UpdateSprint updates the name, goal and dates of the sprint
+	UpdateSprint(ctx context.Context, sprint *entities.Sprint) error
+
+	// DeleteSprint deletes the sprint, the tasks committed to it being left uncommitted
+	DeleteSprint(ctx context.Context, id int) error
+
+	// StartSprint makes the planned sprint active, returning false if it is no longer planned or another sprint of
+	// its board is active
+	StartSprint(ctx context.Context, id int, startedAt time.Time) (bool, error)
+
+	// CloseSprint closes the active sprint, flagging its unfinished tasks as carried over and committing them to the
+	// target sprint when not zero. The carried over tasks are returned, along with false if the sprint was no longer
+	// active.
+	CloseSprint(ctx context.Context, id int, targetID int, closedAt time.Time) ([]int, bool, error)
+
+	// GetSprintTasks returns the tasks committed to the sprint of the board, not in the trash, in commitment order
+	GetSprintTasks(ctx context.Context, boardID int, sprintID int) ([]entities.SprintTask, error)
+
+	// GetTaskSprint returns the ID of the sprint not closed yet the task is committed to, 0 if none
+	GetTaskSprint(ctx context.Context, taskID int) (int, error)
+
+	AddSprintTask(ctx context.Context, sprintID int, taskID int) error
+	RemoveSprintTask(ctx context.Context, sprintID int, taskID int) (bool, error)
 }
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+)
+
+type activityRepository struct {
+	conn func() *sql.DB
+}
+
+func NewActivityRepository(settings datastore.RepositorySettings) datastore.ActivityRepository {
+	return activityRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r activityRepository) AddActivity(ctx context.Context, activity *entities.Activity) error {
+	const query = `
+		INSERT INTO activities (board_id, task_id, user_id, entity_type, entity_id, action, changes) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	changes, err := json.Marshal(activity.Changes)
+	if err != nil {
+		return errors.Join(errors.New("failed to marshal activity changes"), err)
+	}
+
+	var taskID sql.NullInt64
+	if activity.IDTask != 0 {
+		taskID = sql.NullInt64{Int64: int64(activity.IDTask), Valid: true}
+	}
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		activity.IDBoard,
+		taskID,
+		activity.Actor.ID,
+		activity.EntityType,
+		activity.EntityID,
+		activity.Action,
+		changes,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	activity.ID = int(id)
+	return nil
+}
+
+// GetActivities returns the activities matching the filter, newest first unless filtering by entities.ActivityFilter
+// AfterID
+func (r activityRepository) GetActivities(
+	ctx context.Context,
+	filter entities.ActivityFilter,
+) ([]entities.Activity, error) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	if filter.IDBoard != 0 {
+		conditions = append(conditions, "a.board_id = ?")
+		args = append(args, filter.IDBoard)
+	}
+
+	if filter.IDTask != 0 {
+		conditions = append(conditions, "a.task_id = ?")
+		args = append(args, filter.IDTask)
+	}
+
+	if filter.IDActor != 0 {
+		conditions = append(conditions, "a.user_id = ?")
+		args = append(args, filter.IDActor)
+	}
+
+	if filter.Action != "" {
+		conditions = append(conditions, "a.action = ?")
+		args = append(args, filter.Action)
+	}
+
+	if filter.BeforeID != 0 {
+		conditions = append(conditions, "a.id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "a.created_at >= ?")
+		args = append(args, filter.Since)
+	}
+
+	order := "DESC"
+	if filter.AfterID != 0 {
+		conditions = append(conditions, "a.id > ?")
+		args = append(args, filter.AfterID)
+		order = "ASC"
+	}
+
+	query := `
+	SELECT a.id,
+	       a.board_id,
+	       a.task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       a.entity_type,
+	       a.entity_id,
+	       a.action,
+	       a.changes,
+	       a.created_at
+	FROM activities a
+	    INNER JOIN users u ON u.id = a.user_id
+	`
+
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+
+	query += "ORDER BY a.id " + order + " LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	activities := make([]entities.Activity, 0)
+	for rows.Next() {
+		var activity entities.Activity
+		var taskID sql.NullInt64
+		var changes []byte
+		err = rows.Scan(
+			&activity.ID,
+			&activity.IDBoard,
+			&taskID,
+			&activity.Actor.ID,
+			&activity.Actor.UUID,
+			&activity.Actor.Email,
+			&activity.EntityType,
+			&activity.EntityID,
+			&activity.Action,
+			&changes,
+			&activity.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		activity.IDTask = int(taskID.Int64)
+		if len(changes) > 0 {
+			err = json.Unmarshal(changes, &activity.Changes)
+			if err != nil {
+				return nil, errors.Join(errors.New("failed to unmarshal activity changes"), err)
+			}
+		}
+
+		activities = append(activities, activity)
+	}
+
+	return activities, nil
+}
@@ -0,0 +1,288 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+// completionsQuery selects the last completion time of the tasks of a board, completions being the updates finishing
+// the task. The tasks never finished by an update, as created, imported or duplicated already finished, are completed
+// when created, the callers keeping only the tasks still finished.
+const completionsQuery = `
+	SELECT t.id AS task_id, COALESCE(MAX(a.created_at), t.created_at) AS completed_at
+	FROM tasks t
+	    INNER JOIN task_lists l ON l.id = t.task_list_id
+	    LEFT JOIN activities a ON a.task_id = t.id
+	        AND a.entity_type = 'task'
+	        AND a.action = 'updated'
+	        AND a.changes ->> '$.status.after' = '1'
+	WHERE l.board_id = ?
+	GROUP BY t.id, t.created_at
+`
+
+type analyticsRepository struct {
+	conn func() *sql.DB
+}
+
+func NewAnalyticsRepository(settings datastore.RepositorySettings) datastore.AnalyticsRepository {
+	return analyticsRepository{
+		conn: settings.Connection,
+	}
+}
+
+// GetCumulativeFlow rebuilds the periods each task spent in each task list from the activities of the board. A task
+// enters the task list it was created in, which is the one its first move left, then the task lists it is moved to.
+// Archiving or deleting the task takes it out of the board until it is unarchived or restored into its last task
+// list.
+func (r analyticsRepository) GetCumulativeFlow(
+	ctx context.Context,
+	boardID int,
+	from time.Time,
+	to time.Time,
+) ([]entities.CumulativeFlowPoint, error) {
+	const query = `
+	WITH RECURSIVE days (day) AS (
+	    SELECT CAST(? AS DATE)
+	    UNION ALL
+	    SELECT day + INTERVAL 1 DAY FROM days WHERE day < CAST(? AS DATE)
+	),
+	events AS (
+	    SELECT a.id,
+	           a.task_id,
+	           a.action,
+	           a.created_at,
+	           CAST(a.changes ->> '$.id_task_list.before' AS SIGNED) AS previous_list_id,
+	           CAST(a.changes ->> '$.id_task_list.after' AS SIGNED)  AS task_list_id
+	    FROM activities a
+	    WHERE a.board_id = ?
+	      AND a.entity_type = 'task'
+	      AND (a.action IN ('archived', 'unarchived', 'deleted', 'restored')
+	        OR a.action = 'moved' AND JSON_CONTAINS_PATH(a.changes, 'one', '$.id_task_list'))
+	),
+	timeline AS (
+	    SELECT t.id AS task_id,
+	           0 AS seq,
+	           t.created_at AS changed_at,
+	           FALSE AS leaves,
+	           COALESCE((SELECT e.previous_list_id
+	                     FROM events e
+	                     WHERE e.task_id = t.id AND e.action = 'moved'
+	                     ORDER BY e.id
+	                     LIMIT 1), t.task_list_id) AS task_list_id
+	    FROM tasks t
+	        INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    WHERE tl.board_id = ?
+	    UNION ALL
+	    SELECT e.task_id,
+	           e.id,
+	           e.created_at,
+	           e.action IN ('archived', 'deleted'),
+	           IF(e.action = 'moved', e.task_list_id, NULL)
+	    FROM events e
+	),
+	-- Rows unarchiving or restoring a task have no task list, and share their group with the last row having one
+	grouped AS (
+	    SELECT task_id,
+	           seq,
+	           changed_at,
+	           leaves,
+	           task_list_id,
+	           COUNT(task_list_id) OVER (PARTITION BY task_id ORDER BY seq) AS list_group
+	    FROM timeline
+	),
+	periods AS (
+	    SELECT IF(leaves, NULL, FIRST_VALUE(task_list_id) OVER (PARTITION BY task_id, list_group ORDER BY seq))
+	               AS task_list_id,
+	           changed_at AS entered_at,
+	           LEAD(changed_at) OVER (PARTITION BY task_id ORDER BY seq) AS left_at
+	    FROM grouped
+	)
+	SELECT d.day, p.task_list_id, COUNT(*)
+	FROM days d
+	    INNER JOIN periods p ON p.entered_at < d.day + INTERVAL 1 DAY
+	        AND (p.left_at IS NULL OR p.left_at >= d.day + INTERVAL 1 DAY)
+	WHERE p.task_list_id IS NOT NULL
+	GROUP BY d.day, p.task_list_id
+	ORDER BY d.day, p.task_list_id
+	`
+
+	rows, err := r.conn().QueryContext(
+		ctx,
+		query,
+		from.Format(time.DateOnly),
+		to.Format(time.DateOnly),
+		boardID,
+		boardID,
+	)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	points := make([]entities.CumulativeFlowPoint, 0)
+	for rows.Next() {
+		var point entities.CumulativeFlowPoint
+		err = rows.Scan(&point.Day, &point.IDTaskList, &point.Tasks)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+func (r analyticsRepository) GetFlowTimes(
+	ctx context.Context,
+	boardID int,
+	startListID int,
+	from time.Time,
+	until time.Time,
+) ([]entities.TaskFlowTimes, error) {
+	const query = `
+	SELECT t.id,
+	       t.created_at,
+	       (SELECT MIN(m.created_at)
+	        FROM activities m
+	        WHERE m.task_id = t.id
+	          AND m.entity_type = 'task'
+	          AND m.action = 'moved'
+	          AND JSON_CONTAINS_PATH(m.changes, 'one', '$.id_task_list')
+	          AND (? = 0 OR CAST(m.changes ->> '$.id_task_list.after' AS SIGNED) = ?)
+	          AND m.created_at <= c.completed_at) AS started_at,
+	       c.completed_at
+	FROM (` + completionsQuery + `) c
+	    INNER JOIN tasks t ON t.id = c.task_id
+	WHERE t.status = ? AND t.status_code <> ? AND c.completed_at >= ? AND c.completed_at < ?
+	ORDER BY c.completed_at, t.id
+	`
+
+	rows, err := r.conn().QueryContext(
+		ctx,
+		query,
+		startListID,
+		startListID,
+		boardID,
+		entities.TaskFinished,
+		entities.StatusDeleted,
+		from,
+		until,
+	)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	times := make([]entities.TaskFlowTimes, 0)
+	for rows.Next() {
+		var taskTimes entities.TaskFlowTimes
+		var startedAt sql.NullTime
+		err = rows.Scan(&taskTimes.IDTask, &taskTimes.CreatedAt, &startedAt, &taskTimes.CompletedAt)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		if startedAt.Valid {
+			taskTimes.StartedAt = &startedAt.Time
+		}
+
+		times = append(times, taskTimes)
+	}
+
+	return times, nil
+}
+
+func (r analyticsRepository) GetWeeklyThroughput(
+	ctx context.Context,
+	boardID int,
+	from time.Time,
+	until time.Time,
+) ([]entities.WeeklyThroughput, error) {
+	const query = `
+	SELECT DATE(c.completed_at) - INTERVAL WEEKDAY(c.completed_at) DAY AS week,
+	       COUNT(*)
+	FROM (` + completionsQuery + `) c
+	    INNER JOIN tasks t ON t.id = c.task_id
+	WHERE t.status = ? AND t.status_code <> ? AND c.completed_at >= ? AND c.completed_at < ?
+	GROUP BY week
+	ORDER BY week
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, boardID, entities.TaskFinished, entities.StatusDeleted, from, until)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	weeks := make([]entities.WeeklyThroughput, 0)
+	for rows.Next() {
+		var week time.Time
+		var throughput entities.WeeklyThroughput
+		err = rows.Scan(&week, &throughput.Completed)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		throughput.Week = week.Format(time.DateOnly)
+		weeks = append(weeks, throughput)
+	}
+
+	return weeks, nil
+}
+
+func (r analyticsRepository) GetOverdueByAssignee(
+	ctx context.Context,
+	boardID int,
+	now time.Time,
+) ([]entities.OverdueCount, error) {
+	const query = `
+	SELECT u.id,
+	       u.uuid,
+	       u.email,
+	       COUNT(*) AS tasks
+	FROM tasks t
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    LEFT JOIN task_assignees ta ON ta.task_id = t.id
+	    LEFT JOIN users u ON u.id = ta.user_id
+	WHERE tl.board_id = ?
+	  AND t.status = ?
+	  AND t.due_date < ?
+	  AND t.status_code = ? AND tl.status_code = ?
+	GROUP BY u.id, u.uuid, u.email
+	ORDER BY tasks DESC, u.id
+	`
+
+	active := entities.StatusActive
+	rows, err := r.conn().QueryContext(ctx, query, boardID, entities.TaskNotFinished, now, active, active)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	counts := make([]entities.OverdueCount, 0)
+	for rows.Next() {
+		var count entities.OverdueCount
+		var userID sql.NullInt64
+		var userUUID, email sql.NullString
+		err = rows.Scan(&userID, &userUUID, &email, &count.Tasks)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		if userID.Valid {
+			count.Assignee = &entities.User{
+				ID:    int(userID.Int64),
+				UUID:  userUUID.String,
+				Email: email.String,
+			}
+		}
+
+		counts = append(counts, count)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,318 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+)
+
+type attachmentRepository struct {
+	conn func() *sql.DB
+}
+
+func NewAttachmentRepository(settings datastore.RepositorySettings) datastore.AttachmentRepository {
+	return attachmentRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r attachmentRepository) AddAttachment(ctx context.Context, attachment *entities.Attachment) error {
+	const query = `
+		INSERT INTO attachments (uuid, task_id, file_name, content_type, size, path, user_id) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		attachment.UUID,
+		attachment.IDTask,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.Path,
+		attachment.CreatedBy.ID,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	attachment.ID = int(id)
+	return nil
+}
+
+func (r attachmentRepository) GetAttachmentByID(ctx context.Context, id int) (*entities.Attachment, error) {
+	const query = `
+	SELECT a.id,
+	       a.uuid,
+	       a.task_id,
+	       a.file_name,
+	       a.content_type,
+	       a.size,
+	       a.path,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       a.status_code,
+	       a.created_at,
+	       a.modified_at,
+	       a.imported_author
+	FROM attachments a
+	    INNER JOIN users u ON u.id = a.user_id
+	WHERE a.id = ?
+	`
+
+	var attachment entities.Attachment
+	var importedAuthor sql.NullString
+	err := r.conn().QueryRowContext(ctx, query, id).Scan(
+		&attachment.ID,
+		&attachment.UUID,
+		&attachment.IDTask,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.Path,
+		&attachment.CreatedBy.ID,
+		&attachment.CreatedBy.UUID,
+		&attachment.CreatedBy.Email,
+		&attachment.StatusCode,
+		&attachment.CreatedAt,
+		&attachment.ModifiedAt,
+		&importedAuthor,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	attachment.ImportedAuthor = importedAuthor.String
+
+	thumbnails, err := r.getThumbnails(ctx, "attachment_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.Thumbnails = thumbnails[attachment.ID]
+	return &attachment, nil
+}
+
+func (r attachmentRepository) GetAttachmentsByTask(ctx context.Context, taskID int) ([]entities.Attachment, error) {
+	return r.getAttachments(ctx, "a.task_id = ?", taskID)
+}
+
+func (r attachmentRepository) GetAttachmentsByBoard(ctx context.Context, boardID int) ([]entities.Attachment, error) {
+	return r.getAttachments(
+		ctx,
+		`a.task_id IN (
+			SELECT t.id
+			FROM tasks t
+			    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+			WHERE tl.board_id = ? AND tl.status_code <> ? AND t.status_code <> ?
+		)`,
+		boardID,
+		entities.StatusDeleted,
+		entities.StatusDeleted,
+	)
+}
+
+func (r attachmentRepository) DeleteAttachment(ctx context.Context, id int) error {
+	const query = `
+		DELETE FROM attachments WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r attachmentRepository) AddThumbnail(
+	ctx context.Context,
+	attachmentID int,
+	thumbnail entities.Thumbnail,
+) error {
+	const query = `
+		INSERT INTO attachment_thumbnails (attachment_id, size, width, height, content_type, path) 
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE width = VALUES(width),
+		                        height = VALUES(height),
+		                        content_type = VALUES(content_type),
+		                        path = VALUES(path)
+	`
+
+	_, err := r.conn().ExecContext(
+		ctx,
+		query,
+		attachmentID,
+		thumbnail.Size,
+		thumbnail.Width,
+		thumbnail.Height,
+		thumbnail.ContentType,
+		thumbnail.Path,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r attachmentRepository) GetThumbnail(
+	ctx context.Context,
+	attachmentID int,
+	size entities.ThumbnailSize,
+) (*entities.Thumbnail, error) {
+	const query = `
+	SELECT size, width, height, content_type, path
+	FROM attachment_thumbnails
+	WHERE attachment_id = ?
+	  AND size = ?
+	`
+
+	var thumbnail entities.Thumbnail
+	err := r.conn().QueryRowContext(ctx, query, attachmentID, size).Scan(
+		&thumbnail.Size,
+		&thumbnail.Width,
+		&thumbnail.Height,
+		&thumbnail.ContentType,
+		&thumbnail.Path,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return &thumbnail, nil
+}
+
+// getAttachments returns the attachments matching the given condition, along with their thumbnails
+func (r attachmentRepository) getAttachments(
+	ctx context.Context,
+	condition string,
+	args ...any,
+) ([]entities.Attachment, error) {
+	query := `
+	SELECT a.id,
+	       a.uuid,
+	       a.task_id,
+	       a.file_name,
+	       a.content_type,
+	       a.size,
+	       a.path,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       a.status_code,
+	       a.created_at,
+	       a.modified_at,
+	       a.imported_author
+	FROM attachments a
+	    INNER JOIN users u ON u.id = a.user_id
+	WHERE ` + condition + `
+	ORDER BY a.created_at
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	attachments := make([]entities.Attachment, 0)
+	for rows.Next() {
+		var attachment entities.Attachment
+		var importedAuthor sql.NullString
+		err = rows.Scan(
+			&attachment.ID,
+			&attachment.UUID,
+			&attachment.IDTask,
+			&attachment.FileName,
+			&attachment.ContentType,
+			&attachment.Size,
+			&attachment.Path,
+			&attachment.CreatedBy.ID,
+			&attachment.CreatedBy.UUID,
+			&attachment.CreatedBy.Email,
+			&attachment.StatusCode,
+			&attachment.CreatedAt,
+			&attachment.ModifiedAt,
+			&importedAuthor,
+		)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		attachment.ImportedAuthor = importedAuthor.String
+		attachments = append(attachments, attachment)
+	}
+
+	thumbnails, err := r.getThumbnails(
+		ctx,
+		"attachment_id IN (SELECT a.id FROM attachments a WHERE "+condition+")",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range attachments {
+		attachments[i].Thumbnails = thumbnails[attachments[i].ID]
+	}
+
+	return attachments, nil
+}
+
+// getThumbnails returns the thumbnails matching the given condition, grouped by attachment ID
+func (r attachmentRepository) getThumbnails(
+	ctx context.Context,
+	condition string,
+	args ...any,
+) (map[int][]entities.Thumbnail, error) {
+	query := `
+	SELECT attachment_id, size, width, height, content_type, path
+	FROM attachment_thumbnails
+	WHERE ` + condition + `
+	ORDER BY width
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	thumbnails := make(map[int][]entities.Thumbnail)
+	for rows.Next() {
+		var attachmentID int
+		var thumbnail entities.Thumbnail
+		err = rows.Scan(
+			&attachmentID,
+			&thumbnail.Size,
+			&thumbnail.Width,
+			&thumbnail.Height,
+			&thumbnail.ContentType,
+			&thumbnail.Path,
+		)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		thumbnails[attachmentID] = append(thumbnails[attachmentID], thumbnail)
+	}
+
+	return thumbnails, nil
+}
@@ -7,6 +7,7 @@ import (
 	"taskflow/domain/entities"
 	"taskflow/domain/util"
 	"taskflow/infrastructure/datastore"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -133,3 +134,48 @@ func (r authRepository) CheckUserCredentials(
 
 	return valid, nil
 }
+
+func (r authRepository) RevokeToken(ctx context.Context, token entities.AuthToken) error {
+	const query = `
+		INSERT IGNORE INTO revoked_tokens (token_id, user_id, expires_at) VALUES (?, ?, ?)
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, token.ID, token.IDUser, token.ExpiresAt)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r authRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	const query = `
+		SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE token_id = ?)
+	`
+
+	var revoked bool
+	err := r.conn().QueryRowContext(ctx, query, tokenID).Scan(&revoked)
+	if err != nil {
+		return false, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return revoked, nil
+}
+
+func (r authRepository) DeleteExpiredTokens(ctx context.Context, before time.Time) (int, error) {
+	const query = `
+		DELETE FROM revoked_tokens WHERE expires_at < ?
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,331 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+type automationRepository struct {
+	conn func() *sql.DB
+}
+
+func NewAutomationRepository(settings datastore.RepositorySettings) datastore.AutomationRepository {
+	return automationRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r automationRepository) GetRulesByBoard(ctx context.Context, boardID int) ([]entities.AutomationRule, error) {
+	const query = `
+	SELECT a.id,
+	       a.uuid,
+	       a.board_id,
+	       a.name,
+	       a.trigger_type,
+	       a.trigger_list_id,
+	       a.trigger_label_id,
+	       a.actions,
+	       a.secret,
+	       a.enabled,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       a.created_at,
+	       a.modified_at
+	FROM automation_rules a
+	    INNER JOIN users u ON u.id = a.user_id
+	WHERE a.board_id = ?
+	ORDER BY a.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, boardID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	automationRules := make([]entities.AutomationRule, 0)
+	for rows.Next() {
+		rule, err := scanAutomationRule(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		automationRules = append(automationRules, *rule)
+	}
+
+	return automationRules, nil
+}
+
+func (r automationRepository) GetRuleByID(ctx context.Context, id int) (*entities.AutomationRule, error) {
+	const query = `
+	SELECT a.id,
+	       a.uuid,
+	       a.board_id,
+	       a.name,
+	       a.trigger_type,
+	       a.trigger_list_id,
+	       a.trigger_label_id,
+	       a.actions,
+	       a.secret,
+	       a.enabled,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       a.created_at,
+	       a.modified_at
+	FROM automation_rules a
+	    INNER JOIN users u ON u.id = a.user_id
+	WHERE a.id = ?
+	`
+
+	rule, err := scanAutomationRule(r.conn().QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return rule, nil
+}
+
+func (r automationRepository) AddRule(ctx context.Context, rule *entities.AutomationRule) error {
+	const query = `
+		INSERT INTO automation_rules (uuid, board_id, name, trigger_type, trigger_list_id, trigger_label_id, actions,
+		                              secret, enabled, user_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	actions, err := json.Marshal(rule.Actions)
+	if err != nil {
+		return errors.Join(errors.New("failed to marshal automation actions"), err)
+	}
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		rule.UUID,
+		rule.IDBoard,
+		rule.Name,
+		rule.Trigger.Type,
+		rule.Trigger.IDTaskList,
+		rule.Trigger.IDLabel,
+		actions,
+		rule.Secret,
+		rule.Enabled,
+		rule.CreatedBy.ID,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	rule.ID = int(id)
+	return nil
+}
+
+func (r automationRepository) UpdateRule(ctx context.Context, rule *entities.AutomationRule) error {
+	const query = `
+		UPDATE automation_rules
+		SET name = ?,
+		    trigger_type = ?,
+		    trigger_list_id = ?,
+		    trigger_label_id = ?,
+		    actions = ?,
+		    enabled = ?
+		WHERE id = ?
+	`
+
+	actions, err := json.Marshal(rule.Actions)
+	if err != nil {
+		return errors.Join(errors.New("failed to marshal automation actions"), err)
+	}
+
+	_, err = r.conn().ExecContext(
+		ctx,
+		query,
+		rule.Name,
+		rule.Trigger.Type,
+		rule.Trigger.IDTaskList,
+		rule.Trigger.IDLabel,
+		actions,
+		rule.Enabled,
+		rule.ID,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r automationRepository) DeleteRule(ctx context.Context, id int) error {
+	const query = `
+		DELETE FROM automation_rules WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r automationRepository) AddExecution(ctx context.Context, execution *entities.AutomationExecution) error {
+	const query = `
+		INSERT INTO automation_executions (rule_id, activity_id, task_id, status, depth, results, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	results, err := json.Marshal(execution.Results)
+	if err != nil {
+		return errors.Join(errors.New("failed to marshal automation results"), err)
+	}
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		execution.IDRule,
+		execution.IDActivity,
+		execution.IDTask,
+		execution.Status,
+		execution.Depth,
+		results,
+		execution.Error,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	execution.ID = int(id)
+	return nil
+}
+
+func (r automationRepository) GetExecutions(
+	ctx context.Context,
+	ruleID int,
+	beforeID int,
+	limit int,
+) ([]entities.AutomationExecution, error) {
+	const query = `
+	SELECT id,
+	       rule_id,
+	       activity_id,
+	       task_id,
+	       status,
+	       depth,
+	       results,
+	       error,
+	       created_at
+	FROM automation_executions
+	WHERE rule_id = ?
+	  AND (? = 0 OR id < ?)
+	ORDER BY id DESC
+	LIMIT ?
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, ruleID, beforeID, beforeID, limit)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	executions := make([]entities.AutomationExecution, 0)
+	for rows.Next() {
+		var execution entities.AutomationExecution
+		var results []byte
+		var executionError sql.NullString
+		err = rows.Scan(
+			&execution.ID,
+			&execution.IDRule,
+			&execution.IDActivity,
+			&execution.IDTask,
+			&execution.Status,
+			&execution.Depth,
+			&results,
+			&executionError,
+			&execution.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		execution.Results = make([]entities.AutomationActionResult, 0)
+		err = json.Unmarshal(results, &execution.Results)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		execution.Error = executionError.String
+		executions = append(executions, execution)
+	}
+
+	return executions, nil
+}
+
+func (r automationRepository) DeleteExecutions(ctx context.Context, before time.Time) (int, error) {
+	const query = `
+		DELETE FROM automation_executions WHERE created_at < ?
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return int(deleted), nil
+}
+
+func scanAutomationRule(row scanner) (*entities.AutomationRule, error) {
+	var rule entities.AutomationRule
+	var actions []byte
+	err := row.Scan(
+		&rule.ID,
+		&rule.UUID,
+		&rule.IDBoard,
+		&rule.Name,
+		&rule.Trigger.Type,
+		&rule.Trigger.IDTaskList,
+		&rule.Trigger.IDLabel,
+		&actions,
+		&rule.Secret,
+		&rule.Enabled,
+		&rule.CreatedBy.ID,
+		&rule.CreatedBy.UUID,
+		&rule.CreatedBy.Email,
+		&rule.CreatedAt,
+		&rule.ModifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Actions = make([]entities.AutomationAction, 0)
+	err = json.Unmarshal(actions, &rule.Actions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
@@ -1,11 +1,16 @@
 package repositories
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"taskflow/domain/entities"
 	"taskflow/infrastructure/datastore"
+	"time"
 )
 
 type boardRepository struct {
@@ -18,50 +23,2761 @@ func NewBoardRepository(settings datastore.RepositorySettings) datastore.BoardRe
 	}
 }
 
-func (r boardRepository) GetBoards(ctx context.Context) ([]entities.Board, error) {
-	const query = `
-	SELECT id, 
-	       uuid, 
-	       title,
-	       description,
+func (r boardRepository) GetBoards(ctx context.Context, filter entities.BoardFilter) ([]entities.Board, error) {
+	conditions := []string{
+		"(b.user_id = ? OR b.id IN (SELECT board_id FROM board_users WHERE user_id = ?))",
+		"b.status_code = ?",
+	}
+	args := []any{filter.IDUser, filter.IDUser, entities.StatusActive}
+	if filter.Archived {
+		args[2] = entities.StatusArchived
+	}
+
+	if filter.Text != "" {
+		conditions = append(conditions, "b.title LIKE ?")
+		args = append(args, likePattern(filter.Text))
+	}
+
+	keys := sortKeys{"%[1]s.created_at"}
+	switch filter.Sort {
+	case entities.BoardSortModified:
+		keys = sortKeys{"%[1]s.modified_at"}
+	case entities.BoardSortTitle:
+		keys = sortKeys{"%[1]s.title"}
+	}
+
+	if filter.AfterID != 0 {
+		conditions = append(conditions, keys.after("b", "boards", filter.Descending))
+		args = append(args, filter.AfterID)
+	}
+
+	query := `
+	SELECT b.id,
+	       b.uuid,
+	       b.title,
+	       b.description,
 	       u.id,
 	       u.uuid,
 	       u.email,
 	       u.created_at,
 	       u.modified_at,
-	       status_code, 
-	       modified_at, 
-	       created_at 
-	FROM boards 
-	    LEFT JOIN users u ON u.id = user_id		
-	`
-	boards := make([]entities.Board, 0)
-	rows, err := r.conn().QueryContext(ctx, query)
+	       b.wip_warn_only,
+	       b.status_code,
+	       b.modified_at,
+	       b.created_at
+	FROM boards b
+	    INNER JOIN users u ON u.id = b.user_id
+	WHERE ` + strings.Join(conditions, " AND ") + "\n"
+
+	query += keys.orderBy("b", filter.Descending) + " LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, errors.Join(entities.ErrExecuteQuery, err)
 	}
 	defer rows.Close()
 
+	boards := make([]entities.Board, 0)
 	for rows.Next() {
 		var board entities.Board
+		var description sql.NullString
 		err = rows.Scan(
-			board.ID,
-			board.Title,
-			board.Description,
-			board.CreatedBy.ID,
-			board.CreatedBy.UUID,
-			board.CreatedBy.Email,
-			board.CreatedBy.CreatedAt,
-			board.CreatedBy.ModifiedAt,
-			board.StatusCode,
-			board.ModifiedAt,
-			board.CreatedAt,
+			&board.ID,
+			&board.UUID,
+			&board.Title,
+			&description,
+			&board.CreatedBy.ID,
+			&board.CreatedBy.UUID,
+			&board.CreatedBy.Email,
+			&board.CreatedBy.CreatedAt,
+			&board.CreatedBy.ModifiedAt,
+			&board.WIPWarnOnly,
+			&board.StatusCode,
+			&board.ModifiedAt,
+			&board.CreatedAt,
 		)
 		if err != nil {
 			return nil, errors.Join(entities.ErrScan, err)
 		}
+
+		board.Description = description.String
 		boards = append(boards, board)
 	}
 
 	return boards, nil
 }
+
+func (r boardRepository) GetBoardByID(ctx context.Context, id int) (*entities.Board, error) {
+	const query = `
+	SELECT b.id,
+	       b.uuid,
+	       b.title,
+	       b.description,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       u.created_at,
+	       u.modified_at,
+	       b.wip_warn_only,
+	       b.status_code,
+	       b.modified_at,
+	       b.created_at
+	FROM boards b
+	    INNER JOIN users u ON u.id = b.user_id
+	WHERE b.id = ? AND b.status_code <> ?
+	`
+
+	var board entities.Board
+	var description sql.NullString
+	err := r.conn().QueryRowContext(ctx, query, id, entities.StatusDeleted).Scan(
+		&board.ID,
+		&board.UUID,
+		&board.Title,
+		&description,
+		&board.CreatedBy.ID,
+		&board.CreatedBy.UUID,
+		&board.CreatedBy.Email,
+		&board.CreatedBy.CreatedAt,
+		&board.CreatedBy.ModifiedAt,
+		&board.WIPWarnOnly,
+		&board.StatusCode,
+		&board.ModifiedAt,
+		&board.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	board.Description = description.String
+	return &board, nil
+}
+
+func (r boardRepository) AddBoard(ctx context.Context, board *entities.Board) error {
+	const query = `
+		INSERT INTO boards (uuid, title, description, wip_warn_only, user_id) VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		board.UUID,
+		board.Title,
+		board.Description,
+		board.WIPWarnOnly,
+		board.CreatedBy.ID,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	board.ID = int(id)
+	return nil
+}
+
+func (r boardRepository) UpdateBoard(ctx context.Context, board *entities.Board) error {
+	const query = `
+		UPDATE boards SET title = ?, description = ?, wip_warn_only = ? WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, board.Title, board.Description, board.WIPWarnOnly, board.ID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// DeleteBoard moves the board to the trash. It is only deleted for good when the trash is purged.
+func (r boardRepository) DeleteBoard(ctx context.Context, id int) error {
+	const query = `
+		UPDATE boards SET status_code = ?, deleted_at = CURRENT_TIMESTAMP WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, entities.StatusDeleted, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// SetBoardStatusCode archives, unarchives or restores the board
+func (r boardRepository) SetBoardStatusCode(ctx context.Context, id int, statusCode int) error {
+	const query = `
+		UPDATE boards SET status_code = ?, deleted_at = NULL WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, statusCode, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// AddBoardWithContent inserts the labels, custom fields, task lists and tasks in the order of the board, which gives
+// their positions. The IDs the custom fields, task lists and tasks come with are mapped to the inserted ones, to add
+// the custom field values, parent tasks, recurrence lists, dependencies and subtasks of checklist items referring to
+// them once every task is inserted.
+func (r boardRepository) AddBoardWithContent(ctx context.Context, board *entities.Board) error {
+	const boardQuery = `
+		INSERT INTO boards (uuid, title, description, wip_warn_only, status_code, user_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	const labelQuery = `
+		INSERT INTO labels (uuid, board_id, name, color, user_id, created_at) VALUES (UUID(), ?, ?, ?, ?, ?)
+	`
+
+	const fieldQuery = `
+		INSERT INTO custom_fields (uuid, board_id, name, type, options, position, user_id, created_at)
+		VALUES (UUID(), ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	const memberQuery = `
+		INSERT INTO board_users (board_id, user_id) VALUES (?, ?)
+	`
+
+	const taskListQuery = `
+		INSERT INTO task_lists (uuid, board_id, name, description, position, wip_limit, status_code, user_id,
+		                        created_at)
+		VALUES (UUID(), ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	const taskQuery = `
+		INSERT INTO tasks (uuid, task_list_id, name, description, status, priority, due_date, original_estimate,
+		                   recurrence_rule, position, status_code, user_id, imported_author, created_at)
+		VALUES (UUID(), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	const taskLabelQuery = `
+		INSERT INTO task_labels (task_id, label_id) VALUES (?, ?)
+	`
+
+	const assigneeQuery = `
+		INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)
+	`
+
+	const valueQuery = `
+		INSERT INTO task_field_values
+		    (task_id, field_id, value_text, value_number, value_date, value_options, value_user_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	return withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		userID := board.CreatedBy.ID
+
+		boardID, err := insertID(
+			ctx,
+			tx,
+			boardQuery,
+			board.UUID,
+			board.Title,
+			board.Description,
+			board.WIPWarnOnly,
+			board.StatusCode,
+			userID,
+			orNow(board.CreatedAt),
+		)
+		if err != nil {
+			return err
+		}
+
+		board.ID = boardID
+		for _, member := range board.Users {
+			_, err = tx.ExecContext(ctx, memberQuery, boardID, member.ID)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+		}
+
+		labelIDs := make(map[string]int, len(board.Labels))
+		for i, label := range board.Labels {
+			label.ID, err = insertID(
+				ctx,
+				tx,
+				labelQuery,
+				boardID,
+				label.Name,
+				label.Color,
+				userID,
+				orNow(label.CreatedAt),
+			)
+			if err != nil {
+				return err
+			}
+
+			label.IDBoard = boardID
+			labelIDs[label.Name] = label.ID
+			board.Labels[i] = label
+		}
+
+		fieldIDs := make(map[int]int, len(board.CustomFields))
+		for i, field := range board.CustomFields {
+			options, err := json.Marshal(field.Options)
+			if err != nil {
+				return errors.Join(errors.New("failed to marshal custom field options"), err)
+			}
+
+			sourceID := field.ID
+			field.ID, err = insertID(
+				ctx,
+				tx,
+				fieldQuery,
+				boardID,
+				field.Name,
+				field.Type,
+				options,
+				i,
+				userID,
+				orNow(field.CreatedAt),
+			)
+			if err != nil {
+				return err
+			}
+
+			fieldIDs[sourceID] = field.ID
+			field.IDBoard = boardID
+			board.CustomFields[i] = field
+		}
+
+		listIDs := make(map[int]int, len(board.TaskLists))
+		taskIDs := make(map[int]int)
+		for i, taskList := range board.TaskLists {
+			sourceID := taskList.ID
+			taskList.ID, err = insertID(
+				ctx,
+				tx,
+				taskListQuery,
+				boardID,
+				taskList.Name,
+				taskList.Description,
+				i,
+				taskList.WIPLimit,
+				taskList.StatusCode,
+				userID,
+				orNow(taskList.CreatedAt),
+			)
+			if err != nil {
+				return err
+			}
+
+			listIDs[sourceID] = taskList.ID
+			taskList.IDBoard = boardID
+			for j, task := range taskList.Tasks {
+				var recurrenceRule sql.NullString
+				if task.Recurrence != nil {
+					recurrenceRule = sql.NullString{String: task.Recurrence.Rule, Valid: true}
+				}
+
+				task.CreatedBy.ID = cmp.Or(task.CreatedBy.ID, userID)
+
+				sourceTaskID := task.ID
+				task.ID, err = insertID(
+					ctx,
+					tx,
+					taskQuery,
+					taskList.ID,
+					task.Name,
+					task.Description,
+					task.Status,
+					task.Priority,
+					task.DueDate,
+					task.OriginalEstimate,
+					recurrenceRule,
+					j,
+					task.StatusCode,
+					task.CreatedBy.ID,
+					nullIfEmpty(task.ImportedAuthor),
+					orNow(task.CreatedAt),
+				)
+				if err != nil {
+					return err
+				}
+
+				taskIDs[sourceTaskID] = task.ID
+
+				for _, assignee := range task.Assignees {
+					_, err = tx.ExecContext(ctx, assigneeQuery, task.ID, assignee.ID)
+					if err != nil {
+						return errors.Join(entities.ErrExecuteQuery, err)
+					}
+				}
+
+				for _, label := range task.Labels {
+					labelID, ok := labelIDs[label.Name]
+					if !ok {
+						return fmt.Errorf("unknown label %q", label.Name)
+					}
+
+					_, err = tx.ExecContext(ctx, taskLabelQuery, task.ID, labelID)
+					if err != nil {
+						return errors.Join(entities.ErrExecuteQuery, err)
+					}
+				}
+
+				for k, value := range task.Fields {
+					fieldID, ok := fieldIDs[value.IDField]
+					if !ok {
+						return fmt.Errorf("unknown custom field %d", value.IDField)
+					}
+
+					args, err := fieldValueArgs(value)
+					if err != nil {
+						return err
+					}
+
+					_, err = tx.ExecContext(ctx, valueQuery, append([]any{task.ID, fieldID}, args...)...)
+					if err != nil {
+						return errors.Join(entities.ErrExecuteQuery, err)
+					}
+
+					task.Fields[k].IDField = fieldID
+				}
+
+				err = addTaskContent(ctx, tx, &task)
+				if err != nil {
+					return err
+				}
+
+				task.IDTaskList = taskList.ID
+				task.IDBoard = boardID
+				taskList.Tasks[j] = task
+			}
+
+			board.TaskLists[i] = taskList
+		}
+
+		return linkBoardTasks(ctx, tx, board, listIDs, taskIDs)
+	})
+}
+
+// linkBoardTasks sets the references between the tasks of the new board once they are all inserted, mapping the
+// source IDs of the tasks and task lists to the inserted ones. References to tasks or task lists out of the board are
+// dropped.
+func linkBoardTasks(ctx context.Context, tx *sql.Tx, board *entities.Board, listIDs, taskIDs map[int]int) error {
+	const recurrenceListQuery = `
+		UPDATE tasks SET recurrence_list_id = ? WHERE id = ?
+	`
+
+	const parentQuery = `
+		UPDATE tasks SET parent_task_id = ? WHERE id = ?
+	`
+
+	const dependencyQuery = `
+		INSERT IGNORE INTO task_dependencies (task_id, blocker_task_id, user_id) VALUES (?, ?, ?)
+	`
+
+	const subtaskQuery = `
+		UPDATE checklist_items SET subtask_id = ? WHERE id = ?
+	`
+
+	for i := range board.TaskLists {
+		for j := range board.TaskLists[i].Tasks {
+			task := &board.TaskLists[i].Tasks[j]
+
+			if task.Recurrence != nil && task.Recurrence.IDTaskList != 0 {
+				task.Recurrence.IDTaskList = listIDs[task.Recurrence.IDTaskList]
+				if task.Recurrence.IDTaskList != 0 {
+					_, err := tx.ExecContext(ctx, recurrenceListQuery, task.Recurrence.IDTaskList, task.ID)
+					if err != nil {
+						return errors.Join(entities.ErrExecuteQuery, err)
+					}
+				}
+			}
+
+			if task.IDParent != 0 {
+				task.IDParent = taskIDs[task.IDParent]
+				if task.IDParent != 0 {
+					_, err := tx.ExecContext(ctx, parentQuery, task.IDParent, task.ID)
+					if err != nil {
+						return errors.Join(entities.ErrExecuteQuery, err)
+					}
+				}
+			}
+
+			blockedBy := make([]int, 0, len(task.BlockedBy))
+			for _, sourceID := range task.BlockedBy {
+				blockerID, ok := taskIDs[sourceID]
+				if !ok {
+					continue
+				}
+
+				_, err := tx.ExecContext(ctx, dependencyQuery, task.ID, blockerID, board.CreatedBy.ID)
+				if err != nil {
+					return errors.Join(entities.ErrExecuteQuery, err)
+				}
+
+				blockedBy = append(blockedBy, blockerID)
+			}
+			task.BlockedBy = blockedBy
+
+			for k := range task.Checklists {
+				for l := range task.Checklists[k].Items {
+					item := &task.Checklists[k].Items[l]
+					if item.IDSubtask == 0 {
+						continue
+					}
+
+					item.IDSubtask = taskIDs[item.IDSubtask]
+					if item.IDSubtask != 0 {
+						_, err := tx.ExecContext(ctx, subtaskQuery, item.IDSubtask, item.ID)
+						if err != nil {
+							return errors.Join(entities.ErrExecuteQuery, err)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// addTaskContent adds the checklists, comments and attachments of the new task. The files of the attachments must
+// already be stored.
+func addTaskContent(ctx context.Context, tx *sql.Tx, task *entities.Task) error {
+	const checklistQuery = `
+		INSERT INTO checklists (uuid, task_id, name, position, user_id, created_at) VALUES (UUID(), ?, ?, ?, ?, ?)
+	`
+
+	const itemQuery = `
+		INSERT INTO checklist_items (uuid, checklist_id, text, position, checked_at, assignee_id, due_date, user_id,
+		                             created_at)
+		VALUES (UUID(), ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	const commentQuery = `
+		INSERT INTO task_comments (uuid, task_id, user_id, imported_author, body, created_at)
+		VALUES (UUID(), ?, ?, ?, ?, ?)
+	`
+
+	const attachmentQuery = `
+		INSERT INTO attachments (uuid, task_id, file_name, content_type, size, path, user_id, imported_author,
+		                         created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	var err error
+	for i, checklist := range task.Checklists {
+		checklist.ID, err = insertID(
+			ctx,
+			tx,
+			checklistQuery,
+			task.ID,
+			checklist.Name,
+			i,
+			task.CreatedBy.ID,
+			orNow(checklist.CreatedAt),
+		)
+		if err != nil {
+			return err
+		}
+
+		for j, item := range checklist.Items {
+			var assigneeID sql.NullInt64
+			if item.Assignee != nil {
+				assigneeID = sql.NullInt64{Int64: int64(item.Assignee.ID), Valid: true}
+			}
+
+			item.ID, err = insertID(
+				ctx,
+				tx,
+				itemQuery,
+				checklist.ID,
+				item.Text,
+				j,
+				item.CheckedAt,
+				assigneeID,
+				item.DueDate,
+				task.CreatedBy.ID,
+				orNow(item.CreatedAt),
+			)
+			if err != nil {
+				return err
+			}
+
+			item.IDChecklist = checklist.ID
+			checklist.Items[j] = item
+		}
+
+		checklist.IDTask = task.ID
+		task.Checklists[i] = checklist
+	}
+
+	for i, comment := range task.Comments {
+		comment.CreatedAt = orNow(comment.CreatedAt)
+
+		comment.ID, err = insertID(
+			ctx,
+			tx,
+			commentQuery,
+			task.ID,
+			comment.CreatedBy.ID,
+			nullIfEmpty(comment.ImportedAuthor),
+			comment.Body,
+			comment.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		comment.IDTask = task.ID
+		task.Comments[i] = comment
+	}
+
+	for i, attachment := range task.Attachments {
+		attachment.ID, err = insertID(
+			ctx,
+			tx,
+			attachmentQuery,
+			attachment.UUID,
+			task.ID,
+			attachment.FileName,
+			attachment.ContentType,
+			attachment.Size,
+			attachment.Path,
+			attachment.CreatedBy.ID,
+			nullIfEmpty(attachment.ImportedAuthor),
+			orNow(attachment.CreatedAt),
+		)
+		if err != nil {
+			return err
+		}
+
+		attachment.IDTask = task.ID
+		task.Attachments[i] = attachment
+	}
+
+	return nil
+}
+
+// DuplicateBoard copies the rows one by one to map the IDs of the source board to the IDs of the copies. Labels and
+// custom fields are mapped by name instead, being unique per board. User references to non members, such as
+// assignees, are only copied along with the members.
+func (r boardRepository) DuplicateBoard(
+	ctx context.Context,
+	sourceID int,
+	board *entities.Board,
+	options entities.DuplicateOptions,
+	attachments map[int]entities.Attachment,
+) error {
+	const boardQuery = `
+		INSERT INTO boards (uuid, title, description, wip_warn_only, user_id) VALUES (?, ?, ?, ?, ?)
+	`
+
+	// The owner of the source board becomes a member of the copy
+	const membersQuery = `
+		INSERT INTO board_users (board_id, user_id)
+		SELECT ?, user_id FROM board_users WHERE board_id = ? AND user_id <> ?
+		UNION
+		SELECT ?, user_id FROM boards WHERE id = ? AND user_id <> ?
+	`
+
+	const labelsQuery = `
+		INSERT INTO labels (uuid, board_id, name, color, user_id)
+		SELECT UUID(), ?, name, color, user_id FROM labels WHERE board_id = ?
+	`
+
+	const fieldsQuery = `
+		INSERT INTO custom_fields (uuid, board_id, name, type, options, position, user_id)
+		SELECT UUID(), ?, name, type, options, position, user_id FROM custom_fields WHERE board_id = ?
+	`
+
+	const taskListsQuery = `
+		SELECT id FROM task_lists WHERE board_id = ? AND status_code <> ? ORDER BY position, id
+	`
+
+	const copyTaskListQuery = `
+		INSERT INTO task_lists (uuid, board_id, name, description, position, wip_limit, status_code, user_id)
+		SELECT UUID(), ?, name, description, position, wip_limit, status_code, user_id FROM task_lists WHERE id = ?
+	`
+
+	const tasksQuery = `
+	SELECT t.id,
+	       t.task_list_id,
+	       t.recurrence_list_id,
+	       t.parent_task_id
+	FROM tasks t
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	WHERE tl.board_id = ? AND tl.status_code <> ? AND t.status_code <> ?
+	ORDER BY t.id
+	`
+
+	const copyTaskQuery = `
+		INSERT INTO tasks (uuid, task_list_id, name, description, position, status, priority, due_date,
+		                   original_estimate, recurrence_rule, recurrence_list_id, status_code, user_id)
+		SELECT UUID(), ?, name, description, position, status, priority, due_date, original_estimate, recurrence_rule,
+		       ?, status_code, user_id
+		FROM tasks
+		WHERE id = ?
+	`
+
+	const parentQuery = `
+		UPDATE tasks SET parent_task_id = ? WHERE id = ?
+	`
+
+	const assigneesQuery = `
+		INSERT INTO task_assignees (task_id, user_id) SELECT ?, user_id FROM task_assignees WHERE task_id = ?
+	`
+
+	const taskLabelsQuery = `
+		INSERT INTO task_labels (task_id, label_id)
+		SELECT ?, nl.id
+		FROM task_labels tl
+		    INNER JOIN labels l ON l.id = tl.label_id
+		    INNER JOIN labels nl ON nl.board_id = ? AND nl.name = l.name
+		WHERE tl.task_id = ?
+	`
+
+	const valuesQuery = `
+		INSERT INTO task_field_values
+		    (task_id, field_id, value_text, value_number, value_date, value_options, value_user_id)
+		SELECT ?, nf.id, v.value_text, v.value_number, v.value_date, v.value_options, v.value_user_id
+		FROM task_field_values v
+		    INNER JOIN custom_fields f ON f.id = v.field_id
+		    INNER JOIN custom_fields nf ON nf.board_id = ? AND nf.name = f.name
+		WHERE v.task_id = ? AND (v.value_user_id IS NULL OR ?)
+	`
+
+	const checklistsQuery = `
+		SELECT id FROM checklists WHERE task_id = ?
+	`
+
+	const copyChecklistQuery = `
+		INSERT INTO checklists (uuid, task_id, name, position, user_id)
+		SELECT UUID(), ?, name, position, user_id FROM checklists WHERE id = ?
+	`
+
+	const itemsQuery = `
+		SELECT id, COALESCE(subtask_id, 0) FROM checklist_items WHERE checklist_id = ?
+	`
+
+	const copyItemQuery = `
+		INSERT INTO checklist_items (uuid, checklist_id, text, position, checked_at, assignee_id, due_date,
+		                             subtask_id, user_id)
+		SELECT UUID(), ?, text, position, checked_at, IF(?, assignee_id, NULL), due_date, ?, user_id
+		FROM checklist_items
+		WHERE id = ?
+	`
+
+	const dependenciesQuery = `
+		SELECT d.task_id, d.blocker_task_id
+		FROM task_dependencies d
+		    INNER JOIN tasks t ON t.id = d.task_id
+		    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+		WHERE tl.board_id = ?
+	`
+
+	const copyDependencyQuery = `
+		INSERT INTO task_dependencies (task_id, blocker_task_id, user_id)
+		SELECT ?, ?, user_id FROM task_dependencies WHERE task_id = ? AND blocker_task_id = ?
+	`
+
+	const copyAttachmentQuery = `
+		INSERT INTO attachments (uuid, task_id, file_name, content_type, size, path, user_id)
+		SELECT ?, ?, file_name, content_type, size, ?, user_id FROM attachments WHERE id = ?
+	`
+
+	const copyThumbnailQuery = `
+		INSERT INTO attachment_thumbnails (attachment_id, size, width, height, content_type, path)
+		SELECT ?, size, width, height, content_type, ? FROM attachment_thumbnails WHERE attachment_id = ? AND size = ?
+	`
+
+	type sourceTask struct {
+		id               int
+		taskListID       int
+		recurrenceListID sql.NullInt64
+		parentID         sql.NullInt64
+	}
+
+	return withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		userID := board.CreatedBy.ID
+
+		boardID, err := insertID(
+			ctx,
+			tx,
+			boardQuery,
+			board.UUID,
+			board.Title,
+			board.Description,
+			board.WIPWarnOnly,
+			userID,
+		)
+		if err != nil {
+			return err
+		}
+
+		if options.Members {
+			_, err = tx.ExecContext(ctx, membersQuery, boardID, sourceID, userID, boardID, sourceID, userID)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, labelsQuery, boardID, sourceID)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		_, err = tx.ExecContext(ctx, fieldsQuery, boardID, sourceID)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		taskListIDs, err := queryIDs(ctx, tx, taskListsQuery, sourceID, entities.StatusDeleted)
+		if err != nil {
+			return err
+		}
+
+		taskListCopies := make(map[int]int, len(taskListIDs))
+		for _, taskListID := range taskListIDs {
+			taskListCopies[taskListID], err = insertID(ctx, tx, copyTaskListQuery, boardID, taskListID)
+			if err != nil {
+				return err
+			}
+		}
+
+		board.ID = boardID
+		if !options.Tasks {
+			return nil
+		}
+
+		rows, err := tx.QueryContext(ctx, tasksQuery, sourceID, entities.StatusDeleted, entities.StatusDeleted)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		tasks := make([]sourceTask, 0)
+		for rows.Next() {
+			var task sourceTask
+			err = rows.Scan(&task.id, &task.taskListID, &task.recurrenceListID, &task.parentID)
+			if err != nil {
+				_ = rows.Close()
+				return errors.Join(entities.ErrScan, err)
+			}
+			tasks = append(tasks, task)
+		}
+
+		err = rows.Close()
+		if err != nil {
+			return errors.Join(entities.ErrScan, err)
+		}
+
+		taskCopies := make(map[int]int, len(tasks))
+		for _, task := range tasks {
+			var recurrenceListID sql.NullInt64
+			if copyID, ok := taskListCopies[int(task.recurrenceListID.Int64)]; ok && task.recurrenceListID.Valid {
+				recurrenceListID = sql.NullInt64{Int64: int64(copyID), Valid: true}
+			}
+
+			taskListID := taskListCopies[task.taskListID]
+			taskCopies[task.id], err = insertID(ctx, tx, copyTaskQuery, taskListID, recurrenceListID, task.id)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, task := range tasks {
+			copyID := taskCopies[task.id]
+			if parentID, ok := taskCopies[int(task.parentID.Int64)]; ok && task.parentID.Valid {
+				_, err = tx.ExecContext(ctx, parentQuery, parentID, copyID)
+				if err != nil {
+					return errors.Join(entities.ErrExecuteQuery, err)
+				}
+			}
+
+			if options.Members {
+				_, err = tx.ExecContext(ctx, assigneesQuery, copyID, task.id)
+				if err != nil {
+					return errors.Join(entities.ErrExecuteQuery, err)
+				}
+			}
+
+			_, err = tx.ExecContext(ctx, taskLabelsQuery, copyID, boardID, task.id)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+
+			_, err = tx.ExecContext(ctx, valuesQuery, copyID, boardID, task.id, options.Members)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+
+			checklistIDs, err := queryIDs(ctx, tx, checklistsQuery, task.id)
+			if err != nil {
+				return err
+			}
+
+			for _, checklistID := range checklistIDs {
+				checklistCopyID, err := insertID(ctx, tx, copyChecklistQuery, copyID, checklistID)
+				if err != nil {
+					return err
+				}
+
+				items, err := queryIDPairs(ctx, tx, itemsQuery, checklistID)
+				if err != nil {
+					return err
+				}
+
+				for _, item := range items {
+					var subtaskID sql.NullInt64
+					if subtaskCopyID, ok := taskCopies[item[1]]; ok {
+						subtaskID = sql.NullInt64{Int64: int64(subtaskCopyID), Valid: true}
+					}
+
+					_, err = tx.ExecContext(ctx, copyItemQuery, checklistCopyID, options.Members, subtaskID, item[0])
+					if err != nil {
+						return errors.Join(entities.ErrExecuteQuery, err)
+					}
+				}
+			}
+		}
+
+		dependencies, err := queryIDPairs(ctx, tx, dependenciesQuery, sourceID)
+		if err != nil {
+			return err
+		}
+
+		for _, dependency := range dependencies {
+			taskID, blockerID := taskCopies[dependency[0]], taskCopies[dependency[1]]
+			if taskID == 0 || blockerID == 0 {
+				continue
+			}
+
+			_, err = tx.ExecContext(
+				ctx,
+				copyDependencyQuery,
+				taskID,
+				blockerID,
+				dependency[0],
+				dependency[1],
+			)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+		}
+
+		for sourceAttachmentID, attachment := range attachments {
+			taskID, ok := taskCopies[attachment.IDTask]
+			if !ok {
+				return fmt.Errorf("attachment %d is not on a task of the board", sourceAttachmentID)
+			}
+
+			attachmentID, err := insertID(
+				ctx,
+				tx,
+				copyAttachmentQuery,
+				attachment.UUID,
+				taskID,
+				attachment.Path,
+				sourceAttachmentID,
+			)
+			if err != nil {
+				return err
+			}
+
+			for _, thumbnail := range attachment.Thumbnails {
+				_, err = tx.ExecContext(
+					ctx,
+					copyThumbnailQuery,
+					attachmentID,
+					thumbnail.Path,
+					sourceAttachmentID,
+					thumbnail.Size,
+				)
+				if err != nil {
+					return errors.Join(entities.ErrExecuteQuery, err)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r boardRepository) GetBoardMembers(ctx context.Context, boardID int) ([]entities.User, error) {
+	const query = `
+	SELECT u.id,
+	       u.uuid,
+	       u.email,
+	       u.created_at,
+	       u.modified_at
+	FROM board_users bu
+	    INNER JOIN users u ON u.id = bu.user_id
+	WHERE bu.board_id = ?
+	ORDER BY bu.created_at
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, boardID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	users := make([]entities.User, 0)
+	for rows.Next() {
+		var user entities.User
+		err = rows.Scan(&user.ID, &user.UUID, &user.Email, &user.CreatedAt, &user.ModifiedAt)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (r boardRepository) AddBoardMember(ctx context.Context, boardID int, userID int) error {
+	const query = `
+		INSERT INTO board_users (board_id, user_id) VALUES (?, ?)
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, boardID, userID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r boardRepository) RemoveBoardMember(ctx context.Context, boardID int, userID int) error {
+	const query = `
+		DELETE FROM board_users WHERE board_id = ? AND user_id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, boardID, userID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r boardRepository) IsBoardMember(ctx context.Context, boardID int, userID int) (bool, error) {
+	const query = `
+	SELECT EXISTS(
+	    SELECT 1 FROM boards WHERE id = ? AND user_id = ?
+	    UNION ALL
+	    SELECT 1 FROM board_users WHERE board_id = ? AND user_id = ?
+	)
+	`
+
+	var member bool
+	err := r.conn().QueryRowContext(ctx, query, boardID, userID, boardID, userID).Scan(&member)
+	if err != nil {
+		return false, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return member, nil
+}
+
+func (r boardRepository) GetTaskLists(ctx context.Context, boardID int) ([]entities.TaskList, error) {
+	const query = `
+	SELECT tl.id,
+	       tl.uuid,
+	       tl.board_id,
+	       tl.name,
+	       tl.description,
+	       tl.position,
+	       tl.wip_limit,
+	       (SELECT COUNT(*) FROM tasks t WHERE t.task_list_id = tl.id AND t.status_code = ?) AS task_count,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       tl.status_code,
+	       tl.created_at,
+	       tl.modified_at
+	FROM task_lists tl
+	    INNER JOIN users u ON u.id = tl.user_id
+	WHERE tl.board_id = ? AND tl.status_code <> ?
+	ORDER BY tl.position, tl.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, entities.StatusActive, boardID, entities.StatusDeleted)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	taskLists := make([]entities.TaskList, 0)
+	for rows.Next() {
+		taskList, err := scanTaskList(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		taskLists = append(taskLists, *taskList)
+	}
+
+	return taskLists, nil
+}
+
+func (r boardRepository) GetTaskListByID(ctx context.Context, id int) (*entities.TaskList, error) {
+	const query = `
+	SELECT tl.id,
+	       tl.uuid,
+	       tl.board_id,
+	       tl.name,
+	       tl.description,
+	       tl.position,
+	       tl.wip_limit,
+	       (SELECT COUNT(*) FROM tasks t WHERE t.task_list_id = tl.id AND t.status_code = ?) AS task_count,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       tl.status_code,
+	       tl.created_at,
+	       tl.modified_at
+	FROM task_lists tl
+	    INNER JOIN boards b ON b.id = tl.board_id
+	    INNER JOIN users u ON u.id = tl.user_id
+	WHERE tl.id = ? AND tl.status_code <> ? AND b.status_code <> ?
+	`
+
+	deleted := entities.StatusDeleted
+	row := r.conn().QueryRowContext(ctx, query, entities.StatusActive, id, deleted, deleted)
+	taskList, err := scanTaskList(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return taskList, nil
+}
+
+// AddTaskList inserts the task list at the end of the board
+func (r boardRepository) AddTaskList(ctx context.Context, taskList *entities.TaskList) error {
+	const query = `
+		INSERT INTO task_lists (uuid, board_id, name, description, wip_limit, user_id, position)
+		SELECT ?, ?, ?, ?, ?, ?, COALESCE(MAX(position) + 1, 0) FROM task_lists WHERE board_id = ?
+	`
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		taskList.UUID,
+		taskList.IDBoard,
+		taskList.Name,
+		taskList.Description,
+		taskList.WIPLimit,
+		taskList.CreatedBy.ID,
+		taskList.IDBoard,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	taskList.ID = int(id)
+	return nil
+}
+
+func (r boardRepository) UpdateTaskList(ctx context.Context, taskList *entities.TaskList) error {
+	const query = `
+		UPDATE task_lists SET name = ?, description = ?, wip_limit = ? WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, taskList.Name, taskList.Description, taskList.WIPLimit, taskList.ID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// MoveTaskList places the task list at the given position, shifting the following lists of the board
+func (r boardRepository) MoveTaskList(ctx context.Context, id int, position int) error {
+	const shiftQuery = `
+		UPDATE task_lists
+		SET position = position + 1
+		WHERE board_id = (SELECT board_id FROM (SELECT board_id FROM task_lists WHERE id = ?) AS tl)
+		  AND position >= ?
+		  AND id <> ?
+	`
+
+	const moveQuery = `
+		UPDATE task_lists SET position = ? WHERE id = ?
+	`
+
+	return withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, shiftQuery, id, position, id)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		_, err = tx.ExecContext(ctx, moveQuery, position, id)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteTaskList moves the task list to the trash. It is only deleted for good when the trash is purged.
+func (r boardRepository) DeleteTaskList(ctx context.Context, id int) error {
+	const query = `
+		UPDATE task_lists SET status_code = ?, deleted_at = CURRENT_TIMESTAMP WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, entities.StatusDeleted, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// SetTaskListStatusCode archives, unarchives or restores the task list
+func (r boardRepository) SetTaskListStatusCode(ctx context.Context, id int, statusCode int) error {
+	const query = `
+		UPDATE task_lists SET status_code = ?, deleted_at = NULL WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, statusCode, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r boardRepository) GetTasksByBoard(ctx context.Context, boardID int) ([]entities.Task, error) {
+	const query = `
+	SELECT t.id,
+	       t.uuid,
+	       t.task_list_id,
+	       tl.board_id,
+	       t.name,
+	       t.description,
+	       t.position,
+	       t.status,
+	       t.priority,
+	       t.due_date,
+	       t.overdue_at,
+	       t.original_estimate,
+	       t.recurrence_rule,
+	       t.recurrence_list_id,
+	       t.parent_task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       t.status_code,
+	       t.created_at,
+	       t.modified_at,
+	       t.imported_author
+	FROM tasks t
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN users u ON u.id = t.user_id
+	WHERE tl.board_id = ? AND t.status_code <> ? AND tl.status_code <> ?
+	ORDER BY t.position, t.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, boardID, entities.StatusDeleted, entities.StatusDeleted)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	tasks := make([]entities.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, nil
+}
+
+// GetTasks returns the tasks of the board matching the filter
+func (r boardRepository) GetTasks(ctx context.Context, filter entities.TaskFilter) ([]entities.Task, error) {
+	conditions := []string{"tl.board_id = ?"}
+	args := []any{filter.IDBoard}
+
+	if filter.Archived {
+		conditions = append(
+			conditions,
+			"t.status_code <> ? AND tl.status_code <> ? AND (t.status_code = ? OR tl.status_code = ?)",
+		)
+		args = append(args, entities.StatusDeleted, entities.StatusDeleted, entities.StatusArchived, entities.StatusArchived)
+	} else {
+		conditions = append(conditions, "t.status_code = ? AND tl.status_code = ?")
+		args = append(args, entities.StatusActive, entities.StatusActive)
+	}
+
+	if filter.IDAssignee != 0 {
+		conditions = append(conditions, "t.id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)")
+		args = append(args, filter.IDAssignee)
+	}
+
+	if filter.IDLabel != 0 {
+		conditions = append(conditions, "t.id IN (SELECT task_id FROM task_labels WHERE label_id = ?)")
+		args = append(args, filter.IDLabel)
+	}
+
+	if filter.DueAfter != nil {
+		conditions = append(conditions, "t.due_date >= ?")
+		args = append(args, *filter.DueAfter)
+	}
+
+	if filter.DueBefore != nil {
+		conditions = append(conditions, "t.due_date < ?")
+		args = append(args, *filter.DueBefore)
+	}
+
+	if filter.Status != nil {
+		conditions = append(conditions, "t.status = ?")
+		args = append(args, *filter.Status)
+	}
+
+	if filter.Text != "" {
+		conditions = append(conditions, "(t.name LIKE ? OR t.description LIKE ?)")
+		args = append(args, likePattern(filter.Text), likePattern(filter.Text))
+	}
+
+	for _, field := range filter.Fields {
+		condition, fieldArgs := fieldValueCondition(field)
+		conditions = append(conditions, condition)
+		args = append(args, fieldArgs...)
+	}
+
+	keys := taskSortKeys(filter)
+	if filter.AfterID != 0 {
+		conditions = append(conditions, keys.after("t", "tasks", filter.Descending))
+		args = append(args, filter.AfterID)
+	}
+
+	query := `
+	SELECT t.id,
+	       t.uuid,
+	       t.task_list_id,
+	       tl.board_id,
+	       t.name,
+	       t.description,
+	       t.position,
+	       t.status,
+	       t.priority,
+	       t.due_date,
+	       t.overdue_at,
+	       t.original_estimate,
+	       t.recurrence_rule,
+	       t.recurrence_list_id,
+	       t.parent_task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       t.status_code,
+	       t.created_at,
+	       t.modified_at,
+	       t.imported_author
+	FROM tasks t
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN users u ON u.id = t.user_id
+	WHERE ` + strings.Join(conditions, " AND ") + "\n"
+
+	query += keys.orderBy("t", filter.Descending) + " LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	tasks := make([]entities.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, nil
+}
+
+func (r boardRepository) GetTaskByID(ctx context.Context, id int) (*entities.Task, error) {
+	const query = `
+	SELECT t.id,
+	       t.uuid,
+	       t.task_list_id,
+	       tl.board_id,
+	       t.name,
+	       t.description,
+	       t.position,
+	       t.status,
+	       t.priority,
+	       t.due_date,
+	       t.overdue_at,
+	       t.original_estimate,
+	       t.recurrence_rule,
+	       t.recurrence_list_id,
+	       t.parent_task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       t.status_code,
+	       t.created_at,
+	       t.modified_at,
+	       t.imported_author
+	FROM tasks t
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN boards b ON b.id = tl.board_id
+	    INNER JOIN users u ON u.id = t.user_id
+	WHERE t.id = ? AND t.status_code <> ? AND tl.status_code <> ? AND b.status_code <> ?
+	`
+
+	deleted := entities.StatusDeleted
+	task, err := scanTask(r.conn().QueryRowContext(ctx, query, id, deleted, deleted, deleted))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return task, nil
+}
+
+// AddTask inserts the task at the end of its task list
+func (r boardRepository) AddTask(ctx context.Context, task *entities.Task) error {
+	const query = `
+		INSERT INTO tasks (uuid, task_list_id, name, description, status, priority, due_date, original_estimate,
+		                   user_id, position)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(MAX(position) + 1, 0) FROM tasks WHERE task_list_id = ?
+	`
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		task.UUID,
+		task.IDTaskList,
+		task.Name,
+		task.Description,
+		task.Status,
+		task.Priority,
+		task.DueDate,
+		task.OriginalEstimate,
+		task.CreatedBy.ID,
+		task.IDTaskList,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	task.ID = int(id)
+	return nil
+}
+
+func (r boardRepository) AddTasks(ctx context.Context, tasks []entities.Task) error {
+	const taskQuery = `
+		INSERT INTO tasks (uuid, task_list_id, name, description, status, priority, due_date, original_estimate,
+		                   user_id, position)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(MAX(position) + 1, 0) FROM tasks WHERE task_list_id = ?
+	`
+
+	const assigneeQuery = `
+		INSERT IGNORE INTO task_assignees (task_id, user_id) VALUES (?, ?)
+	`
+
+	const labelQuery = `
+		INSERT IGNORE INTO task_labels (task_id, label_id) VALUES (?, ?)
+	`
+
+	const valueQuery = `
+		INSERT INTO task_field_values
+		    (task_id, field_id, value_text, value_number, value_date, value_options, value_user_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	return withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		for i, task := range tasks {
+			var err error
+			task.ID, err = insertID(
+				ctx,
+				tx,
+				taskQuery,
+				task.UUID,
+				task.IDTaskList,
+				task.Name,
+				task.Description,
+				task.Status,
+				task.Priority,
+				task.DueDate,
+				task.OriginalEstimate,
+				task.CreatedBy.ID,
+				task.IDTaskList,
+			)
+			if err != nil {
+				return err
+			}
+
+			for _, assignee := range task.Assignees {
+				_, err = tx.ExecContext(ctx, assigneeQuery, task.ID, assignee.ID)
+				if err != nil {
+					return errors.Join(entities.ErrExecuteQuery, err)
+				}
+			}
+
+			for _, label := range task.Labels {
+				_, err = tx.ExecContext(ctx, labelQuery, task.ID, label.ID)
+				if err != nil {
+					return errors.Join(entities.ErrExecuteQuery, err)
+				}
+			}
+
+			for _, value := range task.Fields {
+				args, err := fieldValueArgs(value)
+				if err != nil {
+					return err
+				}
+
+				_, err = tx.ExecContext(ctx, valueQuery, append([]any{task.ID, value.IDField}, args...)...)
+				if err != nil {
+					return errors.Join(entities.ErrExecuteQuery, err)
+				}
+			}
+
+			tasks[i] = task
+		}
+
+		return nil
+	})
+}
+
+// UpdateTask updates the task. Changing the due date allows the assignees to be reminded again.
+func (r boardRepository) UpdateTask(ctx context.Context, task *entities.Task) error {
+	const query = `
+		UPDATE tasks 
+		SET name = ?, 
+		    description = ?, 
+		    status = ?, 
+		    priority = ?,
+		    original_estimate = ?,
+		    reminded_at = IF(due_date <=> ?, reminded_at, NULL),
+		    overdue_at = IF(due_date <=> ? AND status = ?, overdue_at, NULL),
+		    due_date = ? 
+		WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(
+		ctx,
+		query,
+		task.Name,
+		task.Description,
+		task.Status,
+		task.Priority,
+		task.OriginalEstimate,
+		task.DueDate,
+		task.DueDate,
+		entities.TaskNotFinished,
+		task.DueDate,
+		task.ID,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// MoveTask places the task at the given position of the task list, shifting the following tasks of the list
+func (r boardRepository) MoveTask(ctx context.Context, id int, taskListID int, position int) error {
+	const shiftQuery = `
+		UPDATE tasks SET position = position + 1 WHERE task_list_id = ? AND position >= ? AND id <> ?
+	`
+
+	const moveQuery = `
+		UPDATE tasks SET task_list_id = ?, position = ? WHERE id = ?
+	`
+
+	return withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, shiftQuery, taskListID, position, id)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		_, err = tx.ExecContext(ctx, moveQuery, taskListID, position, id)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteTask moves the task to the trash. It is only deleted for good when the trash is purged.
+func (r boardRepository) DeleteTask(ctx context.Context, id int) error {
+	const query = `
+		UPDATE tasks SET status_code = ?, deleted_at = CURRENT_TIMESTAMP WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, entities.StatusDeleted, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// SetTaskStatusCode archives, unarchives or restores the task
+func (r boardRepository) SetTaskStatusCode(ctx context.Context, id int, statusCode int) error {
+	const query = `
+		UPDATE tasks SET status_code = ?, deleted_at = NULL WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, statusCode, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r boardRepository) GetTaskAssignees(ctx context.Context, taskID int) ([]entities.User, error) {
+	const query = `
+	SELECT u.id,
+	       u.uuid,
+	       u.email,
+	       u.created_at,
+	       u.modified_at
+	FROM task_assignees ta
+	    INNER JOIN users u ON u.id = ta.user_id
+	WHERE ta.task_id = ?
+	ORDER BY ta.created_at, u.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	users := make([]entities.User, 0)
+	for rows.Next() {
+		var user entities.User
+		err = rows.Scan(&user.ID, &user.UUID, &user.Email, &user.CreatedAt, &user.ModifiedAt)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (r boardRepository) GetAssigneesByBoard(ctx context.Context, boardID int) (map[int][]entities.User, error) {
+	const query = `
+	SELECT ta.task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       u.created_at,
+	       u.modified_at
+	FROM task_assignees ta
+	    INNER JOIN tasks t ON t.id = ta.task_id
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN users u ON u.id = ta.user_id
+	WHERE tl.board_id = ?
+	ORDER BY ta.created_at, u.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, boardID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	assignees := make(map[int][]entities.User)
+	for rows.Next() {
+		var taskID int
+		var user entities.User
+		err = rows.Scan(&taskID, &user.ID, &user.UUID, &user.Email, &user.CreatedAt, &user.ModifiedAt)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		assignees[taskID] = append(assignees[taskID], user)
+	}
+
+	return assignees, nil
+}
+
+func (r boardRepository) AddTaskAssignee(ctx context.Context, taskID int, userID int) error {
+	const query = `
+		INSERT INTO task_assignees (task_id, user_id) VALUES (?, ?)
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, taskID, userID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r boardRepository) RemoveTaskAssignee(ctx context.Context, taskID int, userID int) error {
+	const query = `
+		DELETE FROM task_assignees WHERE task_id = ? AND user_id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, taskID, userID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r boardRepository) IsTaskAssignee(ctx context.Context, taskID int, userID int) (bool, error) {
+	const query = `
+		SELECT EXISTS(SELECT 1 FROM task_assignees WHERE task_id = ? AND user_id = ?)
+	`
+
+	var assignee bool
+	err := r.conn().QueryRowContext(ctx, query, taskID, userID).Scan(&assignee)
+	if err != nil {
+		return false, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return assignee, nil
+}
+
+// GetTasksDueBefore returns the unfinished tasks due between now and the given time whose assignees were not reminded
+// yet, soonest first
+func (r boardRepository) GetTasksDueBefore(ctx context.Context, until time.Time) ([]entities.Task, error) {
+	const query = `
+	SELECT t.id,
+	       t.uuid,
+	       t.task_list_id,
+	       tl.board_id,
+	       t.name,
+	       t.description,
+	       t.position,
+	       t.status,
+	       t.priority,
+	       t.due_date,
+	       t.overdue_at,
+	       t.original_estimate,
+	       t.recurrence_rule,
+	       t.recurrence_list_id,
+	       t.parent_task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       t.status_code,
+	       t.created_at,
+	       t.modified_at,
+	       t.imported_author
+	FROM tasks t
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN boards b ON b.id = tl.board_id
+	    INNER JOIN users u ON u.id = t.user_id
+	WHERE t.status = ?
+	  AND t.reminded_at IS NULL
+	  AND t.due_date > CURRENT_TIMESTAMP
+	  AND t.due_date <= ?
+	  AND t.status_code = ? AND tl.status_code = ? AND b.status_code = ?
+	ORDER BY t.due_date, t.id
+	`
+
+	active := entities.StatusActive
+	rows, err := r.conn().QueryContext(ctx, query, entities.TaskNotFinished, until, active, active, active)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	tasks := make([]entities.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, nil
+}
+
+func (r boardRepository) SetTaskReminded(ctx context.Context, taskID int, remindedAt time.Time) error {
+	const query = `
+		UPDATE tasks SET reminded_at = ? WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, remindedAt, taskID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// FlagOverdueTasks flags the unfinished tasks whose due date passed, returning the flagged tasks
+func (r boardRepository) FlagOverdueTasks(ctx context.Context, now time.Time) ([]entities.Task, error) {
+	const selectQuery = `
+	SELECT t.id,
+	       t.uuid,
+	       t.task_list_id,
+	       tl.board_id,
+	       t.name,
+	       t.description,
+	       t.position,
+	       t.status,
+	       t.priority,
+	       t.due_date,
+	       t.overdue_at,
+	       t.original_estimate,
+	       t.recurrence_rule,
+	       t.recurrence_list_id,
+	       t.parent_task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       t.status_code,
+	       t.created_at,
+	       t.modified_at,
+	       t.imported_author
+	FROM tasks t
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN users u ON u.id = t.user_id
+	WHERE t.status = ? AND t.overdue_at IS NULL AND t.due_date <= ? AND t.status_code = ?
+	ORDER BY t.due_date, t.id
+	FOR UPDATE
+	`
+
+	const flagQuery = `
+		UPDATE tasks SET overdue_at = ? WHERE id = ?
+	`
+
+	tasks := make([]entities.Task, 0)
+	err := withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, selectQuery, entities.TaskNotFinished, now, entities.StatusActive)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		for rows.Next() {
+			task, err := scanTask(rows)
+			if err != nil {
+				_ = rows.Close()
+				return errors.Join(entities.ErrScan, err)
+			}
+			tasks = append(tasks, *task)
+		}
+
+		err = rows.Close()
+		if err != nil {
+			return errors.Join(entities.ErrScan, err)
+		}
+
+		for i := range tasks {
+			_, err = tx.ExecContext(ctx, flagQuery, now, tasks[i].ID)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+
+			tasks[i].OverdueAt = &now
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (r boardRepository) SetTaskRecurrence(ctx context.Context, taskID int, recurrence *entities.Recurrence) error {
+	const query = `
+		UPDATE tasks SET recurrence_rule = ?, recurrence_list_id = ? WHERE id = ?
+	`
+
+	rule, listID := recurrenceColumns(recurrence)
+
+	_, err := r.conn().ExecContext(ctx, query, rule, listID, taskID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// GetRecurringTasksDue returns the recurring tasks whose due date passed, soonest first
+func (r boardRepository) GetRecurringTasksDue(ctx context.Context, now time.Time) ([]entities.Task, error) {
+	const query = `
+	SELECT t.id,
+	       t.uuid,
+	       t.task_list_id,
+	       tl.board_id,
+	       t.name,
+	       t.description,
+	       t.position,
+	       t.status,
+	       t.priority,
+	       t.due_date,
+	       t.overdue_at,
+	       t.original_estimate,
+	       t.recurrence_rule,
+	       t.recurrence_list_id,
+	       t.parent_task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       t.status_code,
+	       t.created_at,
+	       t.modified_at,
+	       t.imported_author
+	FROM tasks t
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN boards b ON b.id = tl.board_id
+	    INNER JOIN users u ON u.id = t.user_id
+	WHERE t.recurrence_rule IS NOT NULL
+	  AND t.due_date <= ?
+	  AND t.status_code = ? AND tl.status_code = ? AND b.status_code = ?
+	ORDER BY t.due_date, t.id
+	`
+
+	active := entities.StatusActive
+	rows, err := r.conn().QueryContext(ctx, query, now, active, active, active)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	tasks := make([]entities.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, nil
+}
+
+// AddTaskOccurrence creates the next occurrence of a recurring task, with the assignees and checklists of the previous
+// occurrence. Checklist items are copied unchecked, without due dates nor subtasks. The recurrence moves from the
+// previous occurrence to the new one, so false is returned when the previous occurrence already lost it.
+func (r boardRepository) AddTaskOccurrence(ctx context.Context, previousID int, task *entities.Task) (bool, error) {
+	const claimQuery = `
+		UPDATE tasks SET recurrence_rule = NULL, recurrence_list_id = NULL WHERE id = ? AND recurrence_rule IS NOT NULL
+	`
+
+	const insertQuery = `
+		INSERT INTO tasks (uuid, task_list_id, name, description, status, priority, due_date, original_estimate,
+		                   recurrence_rule, recurrence_list_id, user_id, position)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(MAX(position) + 1, 0) FROM tasks WHERE task_list_id = ?
+	`
+
+	const assigneesQuery = `
+		INSERT INTO task_assignees (task_id, user_id) SELECT ?, user_id FROM task_assignees WHERE task_id = ?
+	`
+
+	const labelsQuery = `
+		INSERT INTO task_labels (task_id, label_id) SELECT ?, label_id FROM task_labels WHERE task_id = ?
+	`
+
+	const checklistsQuery = `
+		SELECT id FROM checklists WHERE task_id = ?
+	`
+
+	const copyChecklistQuery = `
+		INSERT INTO checklists (uuid, task_id, name, position, user_id)
+		SELECT UUID(), ?, name, position, user_id FROM checklists WHERE id = ?
+	`
+
+	const copyItemsQuery = `
+		INSERT INTO checklist_items (uuid, checklist_id, text, position, assignee_id, user_id)
+		SELECT UUID(), ?, text, position, assignee_id, user_id FROM checklist_items WHERE checklist_id = ?
+	`
+
+	added := false
+	err := withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, claimQuery, previousID)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		claimed, err := result.RowsAffected()
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		if claimed == 0 {
+			return nil
+		}
+
+		rule, listID := recurrenceColumns(task.Recurrence)
+		result, err = tx.ExecContext(
+			ctx,
+			insertQuery,
+			task.UUID,
+			task.IDTaskList,
+			task.Name,
+			task.Description,
+			task.Status,
+			task.Priority,
+			task.DueDate,
+			task.OriginalEstimate,
+			rule,
+			listID,
+			task.CreatedBy.ID,
+			task.IDTaskList,
+		)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		_, err = tx.ExecContext(ctx, assigneesQuery, id, previousID)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		_, err = tx.ExecContext(ctx, labelsQuery, id, previousID)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		checklistIDs, err := queryIDs(ctx, tx, checklistsQuery, previousID)
+		if err != nil {
+			return err
+		}
+
+		for _, checklistID := range checklistIDs {
+			result, err = tx.ExecContext(ctx, copyChecklistQuery, id, checklistID)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+
+			copyID, err := result.LastInsertId()
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+
+			_, err = tx.ExecContext(ctx, copyItemsQuery, copyID, checklistID)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+		}
+
+		task.ID = int(id)
+		added = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return added, nil
+}
+
+func (r boardRepository) GetTaskDependencies(ctx context.Context, taskID int) ([]entities.TaskDependency, error) {
+	const query = `
+	SELECT d.task_id,
+	       d.blocker_task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       d.created_at
+	FROM task_dependencies d
+	    INNER JOIN users u ON u.id = d.user_id
+	WHERE d.task_id = ? OR d.blocker_task_id = ?
+	ORDER BY d.created_at, d.blocker_task_id
+	`
+
+	return r.queryDependencies(ctx, query, taskID, taskID)
+}
+
+func (r boardRepository) GetDependenciesByBoard(ctx context.Context, boardID int) ([]entities.TaskDependency, error) {
+	const query = `
+	SELECT d.task_id,
+	       d.blocker_task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       d.created_at
+	FROM task_dependencies d
+	    INNER JOIN tasks t ON t.id = d.task_id
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN users u ON u.id = d.user_id
+	WHERE tl.board_id = ?
+	ORDER BY d.created_at, d.task_id, d.blocker_task_id
+	`
+
+	return r.queryDependencies(ctx, query, boardID)
+}
+
+// AddTaskDependency locks the board row so the reachability check and the insertion can't interleave with another
+// addition on the board
+func (r boardRepository) AddTaskDependency(
+	ctx context.Context,
+	boardID int,
+	dependency entities.TaskDependency,
+) (bool, error) {
+	const lockQuery = `
+		SELECT id FROM boards WHERE id = ? FOR UPDATE
+	`
+
+	// Counts the paths going from the task to the blocker through the tasks it blocks
+	const cycleQuery = `
+	WITH RECURSIVE blocked (id) AS (
+	    SELECT task_id FROM task_dependencies WHERE blocker_task_id = ?
+	    UNION
+	    SELECT d.task_id FROM task_dependencies d INNER JOIN blocked b ON d.blocker_task_id = b.id
+	)
+	SELECT COUNT(*) FROM blocked WHERE id = ?
+	`
+
+	const insertQuery = `
+		INSERT INTO task_dependencies (task_id, blocker_task_id, user_id) VALUES (?, ?, ?)
+	`
+
+	added := false
+	err := withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		var id int
+		err := tx.QueryRowContext(ctx, lockQuery, boardID).Scan(&id)
+		if err != nil {
+			return errors.Join(entities.ErrQueryRow, err)
+		}
+
+		var paths int
+		err = tx.QueryRowContext(ctx, cycleQuery, dependency.IDTask, dependency.IDBlocker).Scan(&paths)
+		if err != nil {
+			return errors.Join(entities.ErrQueryRow, err)
+		}
+
+		if paths > 0 {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, insertQuery, dependency.IDTask, dependency.IDBlocker, dependency.CreatedBy.ID)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		added = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return added, nil
+}
+
+func (r boardRepository) RemoveTaskDependency(ctx context.Context, taskID int, blockerID int) error {
+	const query = `
+		DELETE FROM task_dependencies WHERE task_id = ? AND blocker_task_id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, taskID, blockerID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r boardRepository) CountUnfinishedBlockers(ctx context.Context, taskID int) (int, error) {
+	const query = `
+	SELECT COUNT(*)
+	FROM task_dependencies d
+	    INNER JOIN tasks t ON t.id = d.blocker_task_id
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	WHERE d.task_id = ? AND t.status <> ? AND t.status_code <> ? AND tl.status_code <> ?
+	`
+
+	var count int
+	err := r.conn().
+		QueryRowContext(ctx, query, taskID, entities.TaskFinished, entities.StatusDeleted, entities.StatusDeleted).
+		Scan(&count)
+	if err != nil {
+		return 0, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return count, nil
+}
+
+// GetTrash lists the deleted boards the user owns, along with the deleted task lists and tasks of the boards out of
+// the trash the user is a member of. The tasks of deleted task lists are left out, as they are restored along with
+// their list.
+func (r boardRepository) GetTrash(ctx context.Context, userID int) ([]entities.TrashItem, error) {
+	const query = `
+	(SELECT 'board',
+	        b.id,
+	        b.id,
+	        0,
+	        b.title,
+	        u.id,
+	        u.uuid,
+	        u.email,
+	        b.deleted_at AS deleted_at
+	 FROM boards b
+	     INNER JOIN users u ON u.id = b.user_id
+	 WHERE b.status_code = ? AND b.user_id = ?)
+	UNION ALL
+	(SELECT 'task_list',
+	        tl.id,
+	        b.id,
+	        0,
+	        tl.name,
+	        u.id,
+	        u.uuid,
+	        u.email,
+	        tl.deleted_at
+	 FROM task_lists tl
+	     INNER JOIN boards b ON b.id = tl.board_id
+	     INNER JOIN users u ON u.id = tl.user_id
+	 WHERE tl.status_code = ? AND b.status_code <> ?
+	   AND (b.user_id = ? OR b.id IN (SELECT board_id FROM board_users WHERE user_id = ?)))
+	UNION ALL
+	(SELECT 'task',
+	        t.id,
+	        b.id,
+	        t.task_list_id,
+	        t.name,
+	        u.id,
+	        u.uuid,
+	        u.email,
+	        t.deleted_at
+	 FROM tasks t
+	     INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	     INNER JOIN boards b ON b.id = tl.board_id
+	     INNER JOIN users u ON u.id = t.user_id
+	 WHERE t.status_code = ? AND tl.status_code <> ? AND b.status_code <> ?
+	   AND (b.user_id = ? OR b.id IN (SELECT board_id FROM board_users WHERE user_id = ?)))
+	ORDER BY deleted_at DESC
+	`
+
+	deleted := entities.StatusDeleted
+	rows, err := r.conn().QueryContext(
+		ctx,
+		query,
+		deleted,
+		userID,
+		deleted,
+		deleted,
+		userID,
+		userID,
+		deleted,
+		deleted,
+		deleted,
+		userID,
+		userID,
+	)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	items := make([]entities.TrashItem, 0)
+	for rows.Next() {
+		item, err := scanTrashItem(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		items = append(items, *item)
+	}
+
+	return items, nil
+}
+
+func (r boardRepository) GetTrashItem(
+	ctx context.Context,
+	itemType entities.TrashItemType,
+	id int,
+) (*entities.TrashItem, error) {
+	const boardQuery = `
+	SELECT 'board',
+	       b.id,
+	       b.id,
+	       0,
+	       b.title,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       b.deleted_at
+	FROM boards b
+	    INNER JOIN users u ON u.id = b.user_id
+	WHERE b.id = ? AND b.status_code = ?
+	`
+
+	const taskListQuery = `
+	SELECT 'task_list',
+	       tl.id,
+	       tl.board_id,
+	       0,
+	       tl.name,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       tl.deleted_at
+	FROM task_lists tl
+	    INNER JOIN users u ON u.id = tl.user_id
+	WHERE tl.id = ? AND tl.status_code = ?
+	`
+
+	const taskQuery = `
+	SELECT 'task',
+	       t.id,
+	       tl.board_id,
+	       t.task_list_id,
+	       t.name,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       t.deleted_at
+	FROM tasks t
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN users u ON u.id = t.user_id
+	WHERE t.id = ? AND t.status_code = ?
+	`
+
+	var query string
+	switch itemType {
+	case entities.TrashBoard:
+		query = boardQuery
+	case entities.TrashTaskList:
+		query = taskListQuery
+	case entities.TrashTask:
+		query = taskQuery
+	default:
+		return nil, entities.ErrNotFound
+	}
+
+	item, err := scanTrashItem(r.conn().QueryRowContext(ctx, query, id, entities.StatusDeleted))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return item, nil
+}
+
+// PurgeTrash locks the attachments of the purged items while reading their paths, so that none of them can be
+// restored meanwhile. The task lists, tasks and attachments of the purged boards go along through the foreign keys.
+func (r boardRepository) PurgeTrash(ctx context.Context, before time.Time) ([]string, int, error) {
+	const purged = `
+	    (t.status_code = ? AND t.deleted_at < ?)
+	 OR (tl.status_code = ? AND tl.deleted_at < ?)
+	 OR (b.status_code = ? AND b.deleted_at < ?)
+	`
+
+	const pathsQuery = `
+	(SELECT a.path
+	 FROM attachments a
+	     INNER JOIN tasks t ON t.id = a.task_id
+	     INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	     INNER JOIN boards b ON b.id = tl.board_id
+	 WHERE ` + purged + `
+	 FOR UPDATE)
+	UNION ALL
+	(SELECT th.path
+	 FROM attachment_thumbnails th
+	     INNER JOIN attachments a ON a.id = th.attachment_id
+	     INNER JOIN tasks t ON t.id = a.task_id
+	     INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	     INNER JOIN boards b ON b.id = tl.board_id
+	 WHERE ` + purged + `
+	 FOR UPDATE)
+	`
+
+	deleteQueries := []string{
+		"DELETE FROM boards WHERE status_code = ? AND deleted_at < ?",
+		"DELETE FROM task_lists WHERE status_code = ? AND deleted_at < ?",
+		"DELETE FROM tasks WHERE status_code = ? AND deleted_at < ?",
+	}
+
+	deleted := entities.StatusDeleted
+	purgedArgs := []any{deleted, before, deleted, before, deleted, before}
+
+	paths := make([]string, 0)
+	count := 0
+	err := withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, pathsQuery, append(purgedArgs, purgedArgs...)...)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		for rows.Next() {
+			var path string
+			err = rows.Scan(&path)
+			if err != nil {
+				_ = rows.Close()
+				return errors.Join(entities.ErrScan, err)
+			}
+			paths = append(paths, path)
+		}
+
+		err = rows.Close()
+		if err != nil {
+			return errors.Join(entities.ErrScan, err)
+		}
+
+		for _, query := range deleteQueries {
+			result, err := tx.ExecContext(ctx, query, deleted, before)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+
+			count += int(affected)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return paths, count, nil
+}
+
+// queryDependencies returns the dependencies selected by the query
+func (r boardRepository) queryDependencies(
+	ctx context.Context,
+	query string,
+	args ...any,
+) ([]entities.TaskDependency, error) {
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	dependencies := make([]entities.TaskDependency, 0)
+	for rows.Next() {
+		var dependency entities.TaskDependency
+		err = rows.Scan(
+			&dependency.IDTask,
+			&dependency.IDBlocker,
+			&dependency.CreatedBy.ID,
+			&dependency.CreatedBy.UUID,
+			&dependency.CreatedBy.Email,
+			&dependency.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		dependencies = append(dependencies, dependency)
+	}
+
+	return dependencies, nil
+}
+
+// queryIDs returns the IDs selected by the query within the transaction
+func queryIDs(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// queryIDPairs returns the pairs of IDs selected by the query within the transaction
+func queryIDPairs(ctx context.Context, tx *sql.Tx, query string, args ...any) ([][2]int, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	pairs := make([][2]int, 0)
+	for rows.Next() {
+		var pair [2]int
+		err = rows.Scan(&pair[0], &pair[1])
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, rows.Err()
+}
+
+// orNow returns the time, or the current time when it is zero
+func orNow(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Now()
+	}
+
+	return t
+}
+
+// nullIfEmpty returns the text, or NULL when it is empty
+func nullIfEmpty(text string) sql.NullString {
+	return sql.NullString{String: text, Valid: text != ""}
+}
+
+// insertID runs the insert query within the transaction, returning the ID of the inserted row
+func insertID(ctx context.Context, tx *sql.Tx, query string, args ...any) (int, error) {
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return int(id), nil
+}
+
+// recurrenceColumns returns the values of the recurrence columns of a task
+func recurrenceColumns(recurrence *entities.Recurrence) (sql.NullString, sql.NullInt64) {
+	var rule sql.NullString
+	var listID sql.NullInt64
+
+	if recurrence != nil {
+		rule = sql.NullString{String: recurrence.Rule, Valid: true}
+		if recurrence.IDTaskList != 0 {
+			listID = sql.NullInt64{Int64: int64(recurrence.IDTaskList), Valid: true}
+		}
+	}
+
+	return rule, listID
+}
+
+// taskSortKeys returns the keys sorting tasks, the tasks without due date or without value of the sorted custom field
+// coming last in both directions
+func taskSortKeys(filter entities.TaskFilter) sortKeys {
+	switch filter.Sort {
+	case entities.TaskSortDueDate:
+		if filter.Descending {
+			return sortKeys{"%[1]s.due_date IS NOT NULL", "COALESCE(%[1]s.due_date, '1000-01-01')"}
+		}
+
+		return sortKeys{"%[1]s.due_date IS NULL", "COALESCE(%[1]s.due_date, '1000-01-01')"}
+	case entities.TaskSortPriority:
+		return sortKeys{"%[1]s.priority"}
+	case entities.TaskSortModified:
+		return sortKeys{"%[1]s.modified_at"}
+	case entities.TaskSortField:
+		value, missing := fieldSortValue(*filter.SortField)
+		if filter.Descending {
+			return sortKeys{value + " IS NOT NULL", "COALESCE(" + value + ", " + missing + ")"}
+		}
+
+		return sortKeys{value + " IS NULL", "COALESCE(" + value + ", " + missing + ")"}
+	default:
+		return sortKeys{"(SELECT position FROM task_lists WHERE id = %[1]s.task_list_id)", "%[1]s.position"}
+	}
+}
+
+// fieldSortValue returns the sort key expression of the task value of the custom field, along with the value standing
+// for the missing ones so that the key can be compared. Users sort by email and select fields by the position of
+// their first option among the field options.
+func fieldSortValue(field entities.CustomField) (string, string) {
+	const value = "(SELECT %s FROM task_field_values v WHERE v.task_id = %%[1]s.id AND v.field_id = %d)"
+	const optionPosition = `(SELECT o.position
+	        FROM custom_fields f,
+	             JSON_TABLE(f.options, '$[*]' COLUMNS (id VARCHAR(36) PATH '$.id', position FOR ORDINALITY)) o
+	        WHERE f.id = v.field_id AND o.id = v.value_options->>'$[0]')`
+
+	switch field.Type {
+	case entities.CustomFieldNumber, entities.CustomFieldCheckbox:
+		return fmt.Sprintf(value, "v.value_number", field.ID), "0"
+	case entities.CustomFieldDate:
+		return fmt.Sprintf(value, "v.value_date", field.ID), "'1000-01-01'"
+	case entities.CustomFieldUser:
+		return fmt.Sprintf(value, "(SELECT email FROM users WHERE id = v.value_user_id)", field.ID), "''"
+	case entities.CustomFieldSingleSelect, entities.CustomFieldMultiSelect:
+		return fmt.Sprintf(value, optionPosition, field.ID), "0"
+	default:
+		return fmt.Sprintf(value, "v.value_text", field.ID), "''"
+	}
+}
+
+// fieldValueCondition returns the condition matching the tasks whose value of the custom field equals the filter one,
+// along with its arguments. Unchecked checkboxes also match the tasks without value.
+func fieldValueCondition(field entities.TaskFieldFilter) (string, []any) {
+	const condition = "t.id IN (SELECT task_id FROM task_field_values WHERE field_id = ? AND %s)"
+	value := field.Value
+
+	switch {
+	case field.Type == entities.CustomFieldCheckbox && value.Checked != nil && !*value.Checked:
+		return "t.id NOT IN (SELECT task_id FROM task_field_values WHERE field_id = ? AND value_number = 1)",
+			[]any{field.IDField}
+	case field.Type == entities.CustomFieldCheckbox:
+		return fmt.Sprintf(condition, "value_number = 1"), []any{field.IDField}
+	case value.Number != nil:
+		return fmt.Sprintf(condition, "value_number = ?"), []any{field.IDField, *value.Number}
+	case value.Date != nil:
+		return fmt.Sprintf(condition, "value_date >= ? AND value_date < ?"),
+			[]any{field.IDField, *value.Date, value.Date.AddDate(0, 0, 1)}
+	case len(value.Options) > 0:
+		return fmt.Sprintf(condition, "JSON_CONTAINS(value_options, JSON_ARRAY(?))"),
+			[]any{field.IDField, value.Options[0]}
+	case value.IDUser != 0:
+		return fmt.Sprintf(condition, "value_user_id = ?"), []any{field.IDField, value.IDUser}
+	case value.Text != nil:
+		return fmt.Sprintf(condition, "value_text = ?"), []any{field.IDField, *value.Text}
+	default:
+		return fmt.Sprintf(condition, "FALSE"), []any{field.IDField}
+	}
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTrashItem(row scanner) (*entities.TrashItem, error) {
+	var item entities.TrashItem
+	err := row.Scan(
+		&item.Type,
+		&item.ID,
+		&item.IDBoard,
+		&item.IDTaskList,
+		&item.Title,
+		&item.CreatedBy.ID,
+		&item.CreatedBy.UUID,
+		&item.CreatedBy.Email,
+		&item.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func scanTaskList(row scanner) (*entities.TaskList, error) {
+	var taskList entities.TaskList
+	var description sql.NullString
+	err := row.Scan(
+		&taskList.ID,
+		&taskList.UUID,
+		&taskList.IDBoard,
+		&taskList.Name,
+		&description,
+		&taskList.Position,
+		&taskList.WIPLimit,
+		&taskList.TaskCount,
+		&taskList.CreatedBy.ID,
+		&taskList.CreatedBy.UUID,
+		&taskList.CreatedBy.Email,
+		&taskList.StatusCode,
+		&taskList.CreatedAt,
+		&taskList.ModifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	taskList.Description = description.String
+	return &taskList, nil
+}
+
+// scanTask scans the columns of a task, followed by the columns of the extra destinations if any
+func scanTask(row scanner, extra ...any) (*entities.Task, error) {
+	var task entities.Task
+	var description sql.NullString
+	var dueDate sql.NullTime
+	var overdueAt sql.NullTime
+	var recurrenceRule sql.NullString
+	var recurrenceListID sql.NullInt64
+	var parentID sql.NullInt64
+	var importedAuthor sql.NullString
+	dest := []any{
+		&task.ID,
+		&task.UUID,
+		&task.IDTaskList,
+		&task.IDBoard,
+		&task.Name,
+		&description,
+		&task.Position,
+		&task.Status,
+		&task.Priority,
+		&dueDate,
+		&overdueAt,
+		&task.OriginalEstimate,
+		&recurrenceRule,
+		&recurrenceListID,
+		&parentID,
+		&task.CreatedBy.ID,
+		&task.CreatedBy.UUID,
+		&task.CreatedBy.Email,
+		&task.StatusCode,
+		&task.CreatedAt,
+		&task.ModifiedAt,
+		&importedAuthor,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Description = description.String
+	task.IDParent = int(parentID.Int64)
+	task.ImportedAuthor = importedAuthor.String
+	if dueDate.Valid {
+		task.DueDate = &dueDate.Time
+	}
+
+	if overdueAt.Valid {
+		task.OverdueAt = &overdueAt.Time
+	}
+
+	if recurrenceRule.Valid {
+		task.Recurrence = &entities.Recurrence{
+			Rule:       recurrenceRule.String,
+			IDTaskList: int(recurrenceListID.Int64),
+		}
+	}
+
+	return &task, nil
+}
@@ -0,0 +1,254 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+type calendarRepository struct {
+	conn func() *sql.DB
+}
+
+func NewCalendarRepository(settings datastore.RepositorySettings) datastore.CalendarRepository {
+	return calendarRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r calendarRepository) GetFeed(ctx context.Context, userID int) (*entities.CalendarFeed, error) {
+	const query = `
+	SELECT user_id,
+	       token_hash,
+	       assigned,
+	       finished,
+	       component,
+	       created_at,
+	       modified_at
+	FROM calendar_feeds
+	WHERE user_id = ?
+	`
+
+	return r.getFeed(ctx, query, userID)
+}
+
+func (r calendarRepository) GetFeedByTokenHash(ctx context.Context, tokenHash string) (*entities.CalendarFeed, error) {
+	const query = `
+	SELECT user_id,
+	       token_hash,
+	       assigned,
+	       finished,
+	       component,
+	       created_at,
+	       modified_at
+	FROM calendar_feeds
+	WHERE token_hash = ?
+	`
+
+	return r.getFeed(ctx, query, tokenHash)
+}
+
+func (r calendarRepository) SaveFeed(ctx context.Context, feed *entities.CalendarFeed) error {
+	const feedQuery = `
+	INSERT INTO calendar_feeds (user_id, token_hash, assigned, finished, component)
+	VALUES (?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE token_hash = IF(VALUES(token_hash) = '', token_hash, VALUES(token_hash)),
+	                        assigned   = VALUES(assigned),
+	                        finished   = VALUES(finished),
+	                        component  = VALUES(component)
+	`
+
+	const deleteBoardsQuery = `DELETE FROM calendar_feed_boards WHERE user_id = ?`
+	const boardQuery = `INSERT INTO calendar_feed_boards (user_id, board_id) VALUES (?, ?)`
+
+	return withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(
+			ctx,
+			feedQuery,
+			feed.IDUser,
+			feed.TokenHash,
+			feed.Assigned,
+			feed.Finished,
+			feed.Component,
+		)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		_, err = tx.ExecContext(ctx, deleteBoardsQuery, feed.IDUser)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		for _, boardID := range feed.IDBoards {
+			_, err = tx.ExecContext(ctx, boardQuery, feed.IDUser, boardID)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r calendarRepository) SetFeedTokenHash(ctx context.Context, userID int, tokenHash string) (bool, error) {
+	const query = `UPDATE calendar_feeds SET token_hash = ? WHERE user_id = ?`
+
+	result, err := r.conn().ExecContext(ctx, query, tokenHash, userID)
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r calendarRepository) DeleteFeed(ctx context.Context, userID int) (bool, error) {
+	const query = `DELETE FROM calendar_feeds WHERE user_id = ?`
+
+	result, err := r.conn().ExecContext(ctx, query, userID)
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r calendarRepository) GetFeedTasks(
+	ctx context.Context,
+	feed *entities.CalendarFeed,
+	dueAfter time.Time,
+	limit int,
+) ([]entities.CalendarTask, error) {
+	const query = `
+	SELECT t.id,
+	       t.uuid,
+	       t.task_list_id,
+	       tl.board_id,
+	       t.name,
+	       t.description,
+	       t.position,
+	       t.status,
+	       t.priority,
+	       t.due_date,
+	       t.overdue_at,
+	       t.original_estimate,
+	       t.recurrence_rule,
+	       t.recurrence_list_id,
+	       t.parent_task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       t.status_code,
+	       t.created_at,
+	       t.modified_at,
+	       t.imported_author,
+	       b.title,
+	       tl.name
+	FROM tasks t
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN boards b ON b.id = tl.board_id
+	    INNER JOIN users u ON u.id = t.user_id
+	WHERE t.due_date > ?
+	  AND (? OR t.status = ?)
+	  AND t.status_code = ? AND tl.status_code = ? AND b.status_code = ?
+	  AND (b.user_id = ? OR EXISTS(SELECT 1 FROM board_users bu WHERE bu.board_id = b.id AND bu.user_id = ?))
+	  AND ((? AND EXISTS(SELECT 1 FROM task_assignees ta WHERE ta.task_id = t.id AND ta.user_id = ?))
+	    OR EXISTS(SELECT 1 FROM calendar_feed_boards cb WHERE cb.user_id = ? AND cb.board_id = b.id))
+	ORDER BY t.due_date, t.id
+	LIMIT ?
+	`
+
+	active := entities.StatusActive
+	userID := feed.IDUser
+	rows, err := r.conn().QueryContext(
+		ctx,
+		query,
+		dueAfter,
+		feed.Finished,
+		entities.TaskNotFinished,
+		active,
+		active,
+		active,
+		userID,
+		userID,
+		feed.Assigned,
+		userID,
+		userID,
+		limit,
+	)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	tasks := make([]entities.CalendarTask, 0)
+	for rows.Next() {
+		var boardTitle, taskListName string
+		task, err := scanTask(rows, &boardTitle, &taskListName)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		tasks = append(tasks, entities.CalendarTask{
+			Task:         *task,
+			BoardTitle:   boardTitle,
+			TaskListName: taskListName,
+		})
+	}
+
+	return tasks, nil
+}
+
+// getFeed returns the feed of the single row query, along with its boards
+func (r calendarRepository) getFeed(ctx context.Context, query string, args ...any) (*entities.CalendarFeed, error) {
+	const boardsQuery = `SELECT board_id FROM calendar_feed_boards WHERE user_id = ? ORDER BY board_id`
+
+	var feed entities.CalendarFeed
+	err := r.conn().QueryRowContext(ctx, query, args...).Scan(
+		&feed.IDUser,
+		&feed.TokenHash,
+		&feed.Assigned,
+		&feed.Finished,
+		&feed.Component,
+		&feed.CreatedAt,
+		&feed.ModifiedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	rows, err := r.conn().QueryContext(ctx, boardsQuery, feed.IDUser)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	feed.IDBoards = make([]int, 0)
+	for rows.Next() {
+		var boardID int
+		err = rows.Scan(&boardID)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		feed.IDBoards = append(feed.IDBoards, boardID)
+	}
+
+	return &feed, nil
+}
@@ -0,0 +1,544 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+type checklistRepository struct {
+	conn func() *sql.DB
+}
+
+func NewChecklistRepository(settings datastore.RepositorySettings) datastore.ChecklistRepository {
+	return checklistRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r checklistRepository) GetChecklistsByTask(ctx context.Context, taskID int) ([]entities.Checklist, error) {
+	const checklistsQuery = `
+	SELECT c.id,
+	       c.uuid,
+	       c.task_id,
+	       c.name,
+	       c.position,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       c.created_at,
+	       c.modified_at
+	FROM checklists c
+	    INNER JOIN users u ON u.id = c.user_id
+	WHERE c.task_id = ?
+	ORDER BY c.position, c.id
+	`
+
+	const itemsQuery = `
+	SELECT i.id,
+	       i.uuid,
+	       i.checklist_id,
+	       i.text,
+	       i.position,
+	       i.checked_at,
+	       a.id,
+	       a.uuid,
+	       a.email,
+	       i.due_date,
+	       i.subtask_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       i.created_at,
+	       i.modified_at
+	FROM checklist_items i
+	    INNER JOIN checklists c ON c.id = i.checklist_id
+	    INNER JOIN users u ON u.id = i.user_id
+	    LEFT JOIN users a ON a.id = i.assignee_id
+	WHERE c.task_id = ?
+	ORDER BY i.position, i.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, checklistsQuery, taskID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	checklists := make([]entities.Checklist, 0)
+	for rows.Next() {
+		checklist, err := scanChecklist(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		checklists = append(checklists, *checklist)
+	}
+
+	itemRows, err := r.conn().QueryContext(ctx, itemsQuery, taskID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer itemRows.Close()
+
+	itemsByChecklist := make(map[int][]entities.ChecklistItem)
+	for itemRows.Next() {
+		item, err := scanChecklistItem(itemRows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		itemsByChecklist[item.IDChecklist] = append(itemsByChecklist[item.IDChecklist], *item)
+	}
+
+	for i := range checklists {
+		checklists[i].Items = itemsByChecklist[checklists[i].ID]
+		if checklists[i].Items == nil {
+			checklists[i].Items = make([]entities.ChecklistItem, 0)
+		}
+	}
+
+	return checklists, nil
+}
+
+func (r checklistRepository) GetChecklistByID(ctx context.Context, id int) (*entities.Checklist, error) {
+	const query = `
+	SELECT c.id,
+	       c.uuid,
+	       c.task_id,
+	       c.name,
+	       c.position,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       c.created_at,
+	       c.modified_at
+	FROM checklists c
+	    INNER JOIN users u ON u.id = c.user_id
+	WHERE c.id = ?
+	`
+
+	checklist, err := scanChecklist(r.conn().QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return checklist, nil
+}
+
+// AddChecklist adds the checklist after the other checklists of its task
+func (r checklistRepository) AddChecklist(ctx context.Context, checklist *entities.Checklist) error {
+	const query = `
+		INSERT INTO checklists (uuid, task_id, name, user_id, position)
+		SELECT ?, ?, ?, ?, COALESCE(MAX(position) + 1, 0) FROM checklists WHERE task_id = ?
+	`
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		checklist.UUID,
+		checklist.IDTask,
+		checklist.Name,
+		checklist.CreatedBy.ID,
+		checklist.IDTask,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	checklist.ID = int(id)
+	return nil
+}
+
+func (r checklistRepository) UpdateChecklist(ctx context.Context, checklist *entities.Checklist) error {
+	const query = `
+		UPDATE checklists SET name = ? WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, checklist.Name, checklist.ID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// MoveChecklist places the checklist at the given position, shifting the following checklists of the task
+func (r checklistRepository) MoveChecklist(ctx context.Context, id int, position int) error {
+	const shiftQuery = `
+		UPDATE checklists
+		SET position = position + 1
+		WHERE task_id = (SELECT task_id FROM (SELECT task_id FROM checklists WHERE id = ?) AS c)
+		  AND position >= ?
+		  AND id <> ?
+	`
+
+	const moveQuery = `
+		UPDATE checklists SET position = ? WHERE id = ?
+	`
+
+	return withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, shiftQuery, id, position, id)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		_, err = tx.ExecContext(ctx, moveQuery, position, id)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		return nil
+	})
+}
+
+func (r checklistRepository) DeleteChecklist(ctx context.Context, id int) error {
+	const query = `
+		DELETE FROM checklists WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r checklistRepository) GetItemByID(ctx context.Context, id int) (*entities.ChecklistItem, error) {
+	const query = `
+	SELECT i.id,
+	       i.uuid,
+	       i.checklist_id,
+	       i.text,
+	       i.position,
+	       i.checked_at,
+	       a.id,
+	       a.uuid,
+	       a.email,
+	       i.due_date,
+	       i.subtask_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       i.created_at,
+	       i.modified_at
+	FROM checklist_items i
+	    INNER JOIN users u ON u.id = i.user_id
+	    LEFT JOIN users a ON a.id = i.assignee_id
+	WHERE i.id = ?
+	`
+
+	item, err := scanChecklistItem(r.conn().QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return item, nil
+}
+
+// AddItem adds the item at the end of its checklist
+func (r checklistRepository) AddItem(ctx context.Context, item *entities.ChecklistItem) error {
+	const query = `
+		INSERT INTO checklist_items (uuid, checklist_id, text, assignee_id, due_date, user_id, position)
+		SELECT ?, ?, ?, ?, ?, ?, COALESCE(MAX(position) + 1, 0) FROM checklist_items WHERE checklist_id = ?
+	`
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		item.UUID,
+		item.IDChecklist,
+		item.Text,
+		assigneeColumn(item.Assignee),
+		item.DueDate,
+		item.CreatedBy.ID,
+		item.IDChecklist,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	item.ID = int(id)
+	return nil
+}
+
+// UpdateItem updates the text, check, assignee and due date of the item
+func (r checklistRepository) UpdateItem(ctx context.Context, item *entities.ChecklistItem) error {
+	const query = `
+		UPDATE checklist_items SET text = ?, checked_at = ?, assignee_id = ?, due_date = ? WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(
+		ctx,
+		query,
+		item.Text,
+		item.CheckedAt,
+		assigneeColumn(item.Assignee),
+		item.DueDate,
+		item.ID,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// MoveItem places the item at the given position of a checklist, shifting the following items of the checklist
+func (r checklistRepository) MoveItem(ctx context.Context, id int, checklistID int, position int) error {
+	const shiftQuery = `
+		UPDATE checklist_items SET position = position + 1 WHERE checklist_id = ? AND position >= ? AND id <> ?
+	`
+
+	const moveQuery = `
+		UPDATE checklist_items SET checklist_id = ?, position = ? WHERE id = ?
+	`
+
+	return withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, shiftQuery, checklistID, position, id)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		_, err = tx.ExecContext(ctx, moveQuery, checklistID, position, id)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		return nil
+	})
+}
+
+func (r checklistRepository) DeleteItem(ctx context.Context, id int) error {
+	const query = `
+		DELETE FROM checklist_items WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// PromoteItem creates the subtask at the end of its task list and links the item to it, unless the item was already
+// promoted, in which case false is returned
+func (r checklistRepository) PromoteItem(ctx context.Context, itemID int, subtask *entities.Task) (bool, error) {
+	const insertQuery = `
+		INSERT INTO tasks (uuid, task_list_id, name, description, status, due_date, parent_task_id, user_id, position)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(MAX(position) + 1, 0) FROM tasks WHERE task_list_id = ?
+	`
+
+	const assigneeQuery = `
+		INSERT INTO task_assignees (task_id, user_id)
+		SELECT ?, assignee_id FROM checklist_items WHERE id = ? AND assignee_id IS NOT NULL
+	`
+
+	const linkQuery = `
+		UPDATE checklist_items SET subtask_id = ? WHERE id = ? AND subtask_id IS NULL
+	`
+
+	errPromoted := errors.New("item already promoted")
+
+	err := withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(
+			ctx,
+			insertQuery,
+			subtask.UUID,
+			subtask.IDTaskList,
+			subtask.Name,
+			subtask.Description,
+			subtask.Status,
+			subtask.DueDate,
+			subtask.IDParent,
+			subtask.CreatedBy.ID,
+			subtask.IDTaskList,
+		)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		_, err = tx.ExecContext(ctx, assigneeQuery, id, itemID)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		result, err = tx.ExecContext(ctx, linkQuery, id, itemID)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		linked, err := result.RowsAffected()
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		// Rolls the subtask back
+		if linked == 0 {
+			return errPromoted
+		}
+
+		subtask.ID = int(id)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errPromoted) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SetSubtaskItemsChecked checks the items promoted into the subtask, or unchecks them when checkedAt is nil
+func (r checklistRepository) SetSubtaskItemsChecked(ctx context.Context, subtaskID int, checkedAt *time.Time) error {
+	const query = `
+		UPDATE checklist_items SET checked_at = ? WHERE subtask_id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, checkedAt, subtaskID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r checklistRepository) GetProgressByBoard(ctx context.Context, boardID int) (map[int]entities.Progress, error) {
+	const query = `
+	SELECT c.task_id,
+	       COUNT(i.checked_at),
+	       COUNT(*)
+	FROM checklist_items i
+	    INNER JOIN checklists c ON c.id = i.checklist_id
+	    INNER JOIN tasks t ON t.id = c.task_id
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	WHERE tl.board_id = ?
+	GROUP BY c.task_id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, boardID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	progressByTask := make(map[int]entities.Progress)
+	for rows.Next() {
+		var taskID int
+		var progress entities.Progress
+		err = rows.Scan(&taskID, &progress.Done, &progress.Total)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		progressByTask[taskID] = progress
+	}
+
+	return progressByTask, nil
+}
+
+func scanChecklist(row scanner) (*entities.Checklist, error) {
+	var checklist entities.Checklist
+	err := row.Scan(
+		&checklist.ID,
+		&checklist.UUID,
+		&checklist.IDTask,
+		&checklist.Name,
+		&checklist.Position,
+		&checklist.CreatedBy.ID,
+		&checklist.CreatedBy.UUID,
+		&checklist.CreatedBy.Email,
+		&checklist.CreatedAt,
+		&checklist.ModifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &checklist, nil
+}
+
+func scanChecklistItem(row scanner) (*entities.ChecklistItem, error) {
+	var item entities.ChecklistItem
+	var checkedAt, dueDate sql.NullTime
+	var assigneeID, subtaskID sql.NullInt64
+	var assigneeUUID, assigneeEmail sql.NullString
+	err := row.Scan(
+		&item.ID,
+		&item.UUID,
+		&item.IDChecklist,
+		&item.Text,
+		&item.Position,
+		&checkedAt,
+		&assigneeID,
+		&assigneeUUID,
+		&assigneeEmail,
+		&dueDate,
+		&subtaskID,
+		&item.CreatedBy.ID,
+		&item.CreatedBy.UUID,
+		&item.CreatedBy.Email,
+		&item.CreatedAt,
+		&item.ModifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkedAt.Valid {
+		item.Checked = true
+		item.CheckedAt = &checkedAt.Time
+	}
+
+	if assigneeID.Valid {
+		item.Assignee = &entities.User{
+			ID:    int(assigneeID.Int64),
+			UUID:  assigneeUUID.String,
+			Email: assigneeEmail.String,
+		}
+	}
+
+	if dueDate.Valid {
+		item.DueDate = &dueDate.Time
+	}
+
+	item.IDSubtask = int(subtaskID.Int64)
+	return &item, nil
+}
+
+// assigneeColumn returns the value of the assignee column of a checklist item
+func assigneeColumn(assignee *entities.User) sql.NullInt64 {
+	if assignee == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: int64(assignee.ID), Valid: true}
+}
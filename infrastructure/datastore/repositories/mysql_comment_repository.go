@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+)
+
+type commentRepository struct {
+	conn func() *sql.DB
+}
+
+func NewCommentRepository(settings datastore.RepositorySettings) datastore.CommentRepository {
+	return commentRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r commentRepository) GetCommentsByTask(
+	ctx context.Context,
+	taskID int,
+	afterID int,
+	limit int,
+) ([]entities.Comment, error) {
+	const query = `
+	SELECT c.id,
+	       c.uuid,
+	       c.task_id,
+	       c.body,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       c.status_code,
+	       c.created_at,
+	       c.modified_at,
+	       c.imported_author
+	FROM task_comments c
+	    INNER JOIN users u ON u.id = c.user_id
+	WHERE c.task_id = ?
+	  AND c.id > ?
+	ORDER BY c.id
+	LIMIT ?
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, taskID, afterID, limit)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	comments := make([]entities.Comment, 0)
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		comments = append(comments, *comment)
+	}
+
+	return comments, nil
+}
+
+func (r commentRepository) GetCommentByID(ctx context.Context, id int) (*entities.Comment, error) {
+	const query = `
+	SELECT c.id,
+	       c.uuid,
+	       c.task_id,
+	       c.body,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       c.status_code,
+	       c.created_at,
+	       c.modified_at,
+	       c.imported_author
+	FROM task_comments c
+	    INNER JOIN users u ON u.id = c.user_id
+	WHERE c.id = ?
+	`
+
+	comment, err := scanComment(r.conn().QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return comment, nil
+}
+
+func (r commentRepository) AddComment(ctx context.Context, comment *entities.Comment) error {
+	const query = `
+		INSERT INTO task_comments (uuid, task_id, user_id, body) VALUES (?, ?, ?, ?)
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, comment.UUID, comment.IDTask, comment.CreatedBy.ID, comment.Body)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	comment.ID = int(id)
+	return nil
+}
+
+func (r commentRepository) UpdateComment(ctx context.Context, comment *entities.Comment) error {
+	const query = `
+		UPDATE task_comments SET body = ? WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, comment.Body, comment.ID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r commentRepository) DeleteComment(ctx context.Context, id int) error {
+	const query = `
+		DELETE FROM task_comments WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func scanComment(row scanner) (*entities.Comment, error) {
+	var comment entities.Comment
+	var importedAuthor sql.NullString
+	err := row.Scan(
+		&comment.ID,
+		&comment.UUID,
+		&comment.IDTask,
+		&comment.Body,
+		&comment.CreatedBy.ID,
+		&comment.CreatedBy.UUID,
+		&comment.CreatedBy.Email,
+		&comment.StatusCode,
+		&comment.CreatedAt,
+		&comment.ModifiedAt,
+		&importedAuthor,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.ImportedAuthor = importedAuthor.String
+	return &comment, nil
+}
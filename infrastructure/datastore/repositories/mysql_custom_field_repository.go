@@ -0,0 +1,394 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+)
+
+type customFieldRepository struct {
+	conn func() *sql.DB
+}
+
+func NewCustomFieldRepository(settings datastore.RepositorySettings) datastore.CustomFieldRepository {
+	return customFieldRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r customFieldRepository) GetFieldsByBoard(ctx context.Context, boardID int) ([]entities.CustomField, error) {
+	const query = `
+	SELECT f.id,
+	       f.uuid,
+	       f.board_id,
+	       f.name,
+	       f.type,
+	       f.options,
+	       f.position,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       f.created_at,
+	       f.modified_at
+	FROM custom_fields f
+	    INNER JOIN users u ON u.id = f.user_id
+	WHERE f.board_id = ?
+	ORDER BY f.position, f.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, boardID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	fields := make([]entities.CustomField, 0)
+	for rows.Next() {
+		field, err := scanCustomField(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		fields = append(fields, *field)
+	}
+
+	return fields, nil
+}
+
+func (r customFieldRepository) GetFieldByID(ctx context.Context, id int) (*entities.CustomField, error) {
+	const query = `
+	SELECT f.id,
+	       f.uuid,
+	       f.board_id,
+	       f.name,
+	       f.type,
+	       f.options,
+	       f.position,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       f.created_at,
+	       f.modified_at
+	FROM custom_fields f
+	    INNER JOIN users u ON u.id = f.user_id
+	WHERE f.id = ?
+	`
+
+	field, err := scanCustomField(r.conn().QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return field, nil
+}
+
+// AddField adds the field after the other fields of its board
+func (r customFieldRepository) AddField(ctx context.Context, field *entities.CustomField) error {
+	const query = `
+		INSERT INTO custom_fields (uuid, board_id, name, type, options, user_id, position)
+		SELECT ?, ?, ?, ?, ?, ?, COALESCE(MAX(position) + 1, 0) FROM custom_fields WHERE board_id = ?
+	`
+
+	options, err := json.Marshal(field.Options)
+	if err != nil {
+		return errors.Join(errors.New("failed to marshal custom field options"), err)
+	}
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		field.UUID,
+		field.IDBoard,
+		field.Name,
+		field.Type,
+		options,
+		field.CreatedBy.ID,
+		field.IDBoard,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	field.ID = int(id)
+	return nil
+}
+
+// UpdateField updates the name and options of the field, removing the deleted options from the task values
+func (r customFieldRepository) UpdateField(
+	ctx context.Context,
+	field *entities.CustomField,
+	removedOptions []string,
+) error {
+	const updateQuery = `
+		UPDATE custom_fields SET name = ?, options = ? WHERE id = ?
+	`
+
+	const deleteValuesQuery = `
+		DELETE FROM task_field_values
+		WHERE field_id = ? AND JSON_LENGTH(value_options) = 1 AND JSON_CONTAINS(value_options, JSON_ARRAY(?))
+	`
+
+	const removeOptionQuery = `
+		UPDATE task_field_values
+		SET value_options = JSON_REMOVE(value_options, JSON_UNQUOTE(JSON_SEARCH(value_options, 'one', ?)))
+		WHERE field_id = ? AND JSON_CONTAINS(value_options, JSON_ARRAY(?))
+	`
+
+	options, err := json.Marshal(field.Options)
+	if err != nil {
+		return errors.Join(errors.New("failed to marshal custom field options"), err)
+	}
+
+	return withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, updateQuery, field.Name, options, field.ID)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		// Values left without options are deleted, the others lose the option
+		for _, optionID := range removedOptions {
+			_, err = tx.ExecContext(ctx, deleteValuesQuery, field.ID, optionID)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+
+			_, err = tx.ExecContext(ctx, removeOptionQuery, optionID, field.ID, optionID)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r customFieldRepository) DeleteField(ctx context.Context, id int) error {
+	const query = `
+		DELETE FROM custom_fields WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r customFieldRepository) GetValuesByTask(ctx context.Context, taskID int) ([]entities.CustomFieldValue, error) {
+	const query = `
+	SELECT v.task_id,
+	       f.id,
+	       f.type,
+	       v.value_text,
+	       v.value_number,
+	       v.value_date,
+	       v.value_options,
+	       v.value_user_id
+	FROM task_field_values v
+	    INNER JOIN custom_fields f ON f.id = v.field_id
+	WHERE v.task_id = ?
+	ORDER BY f.position, f.id
+	`
+
+	valuesByTask, err := r.queryValues(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := valuesByTask[taskID]
+	if values == nil {
+		values = make([]entities.CustomFieldValue, 0)
+	}
+
+	return values, nil
+}
+
+func (r customFieldRepository) GetValuesByBoard(
+	ctx context.Context,
+	boardID int,
+) (map[int][]entities.CustomFieldValue, error) {
+	const query = `
+	SELECT v.task_id,
+	       f.id,
+	       f.type,
+	       v.value_text,
+	       v.value_number,
+	       v.value_date,
+	       v.value_options,
+	       v.value_user_id
+	FROM task_field_values v
+	    INNER JOIN custom_fields f ON f.id = v.field_id
+	WHERE f.board_id = ?
+	ORDER BY f.position, f.id
+	`
+
+	return r.queryValues(ctx, query, boardID)
+}
+
+// SetValue sets the value of the field on the task, replacing any previous value
+func (r customFieldRepository) SetValue(ctx context.Context, taskID int, value entities.CustomFieldValue) error {
+	const query = `
+		INSERT INTO task_field_values
+		    (task_id, field_id, value_text, value_number, value_date, value_options, value_user_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE value_text    = VALUES(value_text),
+		                        value_number  = VALUES(value_number),
+		                        value_date    = VALUES(value_date),
+		                        value_options = VALUES(value_options),
+		                        value_user_id = VALUES(value_user_id)
+	`
+
+	args, err := fieldValueArgs(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.conn().ExecContext(ctx, query, append([]any{taskID, value.IDField}, args...)...)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// fieldValueArgs returns the text, number, date, options and user ID columns of the value
+func fieldValueArgs(value entities.CustomFieldValue) ([]any, error) {
+	var text sql.NullString
+	if value.Text != nil {
+		text = sql.NullString{String: *value.Text, Valid: true}
+	}
+
+	// Checkboxes are stored as numbers, 1 being checked
+	var number sql.NullFloat64
+	if value.Number != nil {
+		number = sql.NullFloat64{Float64: *value.Number, Valid: true}
+	} else if value.Checked != nil {
+		number = sql.NullFloat64{Valid: true}
+		if *value.Checked {
+			number.Float64 = 1
+		}
+	}
+
+	var options []byte
+	if value.Options != nil {
+		var err error
+		options, err = json.Marshal(value.Options)
+		if err != nil {
+			return nil, errors.Join(errors.New("failed to marshal custom field value options"), err)
+		}
+	}
+
+	var userID sql.NullInt64
+	if value.IDUser != 0 {
+		userID = sql.NullInt64{Int64: int64(value.IDUser), Valid: true}
+	}
+
+	return []any{text, number, value.Date, options, userID}, nil
+}
+
+func (r customFieldRepository) DeleteValue(ctx context.Context, taskID int, fieldID int) error {
+	const query = `
+		DELETE FROM task_field_values WHERE task_id = ? AND field_id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, taskID, fieldID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// queryValues returns the custom field values selected by the query, by task ID
+func (r customFieldRepository) queryValues(
+	ctx context.Context,
+	query string,
+	args ...any,
+) (map[int][]entities.CustomFieldValue, error) {
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	values := make(map[int][]entities.CustomFieldValue)
+	for rows.Next() {
+		var taskID int
+		var fieldType entities.CustomFieldType
+		var value entities.CustomFieldValue
+		var text sql.NullString
+		var number sql.NullFloat64
+		var date sql.NullTime
+		var options []byte
+		var userID sql.NullInt64
+		err = rows.Scan(&taskID, &value.IDField, &fieldType, &text, &number, &date, &options, &userID)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		switch {
+		case fieldType == entities.CustomFieldCheckbox:
+			checked := number.Float64 != 0
+			value.Checked = &checked
+		case number.Valid:
+			value.Number = &number.Float64
+		case text.Valid:
+			value.Text = &text.String
+		case date.Valid:
+			value.Date = &date.Time
+		case options != nil:
+			err = json.Unmarshal(options, &value.Options)
+			if err != nil {
+				return nil, errors.Join(entities.ErrScan, err)
+			}
+		}
+
+		value.IDUser = int(userID.Int64)
+		values[taskID] = append(values[taskID], value)
+	}
+
+	return values, nil
+}
+
+func scanCustomField(row scanner) (*entities.CustomField, error) {
+	var field entities.CustomField
+	var options []byte
+	err := row.Scan(
+		&field.ID,
+		&field.UUID,
+		&field.IDBoard,
+		&field.Name,
+		&field.Type,
+		&options,
+		&field.Position,
+		&field.CreatedBy.ID,
+		&field.CreatedBy.UUID,
+		&field.CreatedBy.Email,
+		&field.CreatedAt,
+		&field.ModifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil {
+		err = json.Unmarshal(options, &field.Options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &field, nil
+}
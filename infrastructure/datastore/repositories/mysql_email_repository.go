@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+type emailRepository struct {
+	conn func() *sql.DB
+}
+
+func NewEmailRepository(settings datastore.RepositorySettings) datastore.EmailRepository {
+	return emailRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r emailRepository) AddEmail(ctx context.Context, email *entities.Email) error {
+	const query = `
+		INSERT INTO email_queue (recipient, subject, text_body, html_body, unsubscribe_url, status, next_attempt_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		email.To,
+		email.Subject,
+		email.Text,
+		email.HTML,
+		email.UnsubscribeURL,
+		email.Status,
+		email.NextAttemptAt,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	email.ID = int(id)
+	return nil
+}
+
+func (r emailRepository) GetDueEmails(ctx context.Context, limit int) ([]entities.Email, error) {
+	const query = `
+	SELECT id,
+	       recipient,
+	       subject,
+	       text_body,
+	       html_body,
+	       unsubscribe_url,
+	       status,
+	       attempts,
+	       error,
+	       next_attempt_at,
+	       sent_at,
+	       created_at
+	FROM email_queue
+	WHERE status = ?
+	  AND next_attempt_at <= CURRENT_TIMESTAMP
+	ORDER BY next_attempt_at
+	LIMIT ?
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, entities.EmailPending, limit)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	emails := make([]entities.Email, 0)
+	for rows.Next() {
+		var email entities.Email
+		var unsubscribeURL, emailError sql.NullString
+		var sentAt sql.NullTime
+		err = rows.Scan(
+			&email.ID,
+			&email.To,
+			&email.Subject,
+			&email.Text,
+			&email.HTML,
+			&unsubscribeURL,
+			&email.Status,
+			&email.Attempts,
+			&emailError,
+			&email.NextAttemptAt,
+			&sentAt,
+			&email.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		email.UnsubscribeURL = unsubscribeURL.String
+		email.Error = emailError.String
+		if sentAt.Valid {
+			email.SentAt = &sentAt.Time
+		}
+
+		emails = append(emails, email)
+	}
+
+	return emails, nil
+}
+
+func (r emailRepository) ClaimEmail(ctx context.Context, id int, until time.Time) (bool, error) {
+	const query = `
+		UPDATE email_queue 
+		SET next_attempt_at = ? 
+		WHERE id = ? 
+		  AND status = ? 
+		  AND next_attempt_at <= CURRENT_TIMESTAMP
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, until, id, entities.EmailPending)
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r emailRepository) UpdateEmail(ctx context.Context, email *entities.Email) error {
+	const query = `
+		UPDATE email_queue 
+		SET status = ?, 
+		    attempts = ?, 
+		    error = ?, 
+		    next_attempt_at = ?, 
+		    sent_at = ? 
+		WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(
+		ctx,
+		query,
+		email.Status,
+		email.Attempts,
+		email.Error,
+		email.NextAttemptAt,
+		email.SentAt,
+		email.ID,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+// lockReleaseTimeout bounds the release of a lock, which must happen even when the job context was canceled
+const lockReleaseTimeout = 5 * time.Second
+
+type jobRepository struct {
+	conn func() *sql.DB
+}
+
+func NewJobRepository(settings datastore.RepositorySettings) datastore.JobRepository {
+	return jobRepository{
+		conn: settings.Connection,
+	}
+}
+
+// Lock takes a MySQL named lock. Named locks belong to a session, so a connection is kept out of the pool while the
+// lock is held.
+func (r jobRepository) Lock(ctx context.Context, name string) (func(), bool, error) {
+	const lockQuery = `
+		SELECT GET_LOCK(?, 0)
+	`
+
+	const unlockQuery = `
+		SELECT RELEASE_LOCK(?)
+	`
+
+	conn, err := r.conn().Conn(ctx)
+	if err != nil {
+		return nil, false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	var acquired sql.NullInt64
+	err = conn.QueryRowContext(ctx, lockQuery, name).Scan(&acquired)
+	if err != nil {
+		_ = conn.Close()
+		return nil, false, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	if acquired.Int64 != 1 {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), lockReleaseTimeout)
+		defer cancel()
+
+		var released sql.NullInt64
+		err := conn.QueryRowContext(ctx, unlockQuery, name).Scan(&released)
+		if err != nil {
+			slog.Error("failed to release lock", "name", name, "cause", err)
+		}
+
+		_ = conn.Close()
+	}
+
+	return unlock, true, nil
+}
+
+func (r jobRepository) AddJobRun(ctx context.Context, run *entities.JobRun) (bool, error) {
+	const query = `
+		INSERT IGNORE INTO job_runs (job, instance, status, scheduled_at, started_at) VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, run.Job, run.Instance, run.Status, run.ScheduledAt, run.StartedAt)
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	added, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	if added == 0 {
+		return false, nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	run.ID = int(id)
+	return true, nil
+}
+
+func (r jobRepository) UpdateJobRun(ctx context.Context, run *entities.JobRun) error {
+	const query = `
+		UPDATE job_runs SET status = ?, error = ?, finished_at = ? WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, run.Status, run.Error, run.FinishedAt, run.ID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r jobRepository) GetJobRuns(ctx context.Context, filter entities.JobRunFilter) ([]entities.JobRun, error) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	if filter.Job != "" {
+		conditions = append(conditions, "job = ?")
+		args = append(args, filter.Job)
+	}
+
+	if filter.BeforeID != 0 {
+		conditions = append(conditions, "id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	query := `
+	SELECT id,
+	       job,
+	       instance,
+	       status,
+	       error,
+	       scheduled_at,
+	       started_at,
+	       finished_at
+	FROM job_runs
+	`
+
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + "\n"
+	}
+
+	query += "ORDER BY id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	runs := make([]entities.JobRun, 0)
+	for rows.Next() {
+		var run entities.JobRun
+		var runError sql.NullString
+		var finishedAt sql.NullTime
+		err = rows.Scan(
+			&run.ID,
+			&run.Job,
+			&run.Instance,
+			&run.Status,
+			&runError,
+			&run.ScheduledAt,
+			&run.StartedAt,
+			&finishedAt,
+		)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		run.Error = runError.String
+		if finishedAt.Valid {
+			run.FinishedAt = &finishedAt.Time
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+func (r jobRepository) DeleteJobRuns(ctx context.Context, before time.Time) (int, error) {
+	const query = `
+		DELETE FROM job_runs WHERE started_at < ?
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return int(deleted), nil
+}
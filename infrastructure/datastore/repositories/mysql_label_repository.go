@@ -0,0 +1,283 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+)
+
+type labelRepository struct {
+	conn func() *sql.DB
+}
+
+func NewLabelRepository(settings datastore.RepositorySettings) datastore.LabelRepository {
+	return labelRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r labelRepository) GetLabelsByBoard(ctx context.Context, boardID int) ([]entities.Label, error) {
+	const query = `
+	SELECT l.id,
+	       l.uuid,
+	       l.board_id,
+	       l.name,
+	       l.color,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       l.created_at,
+	       l.modified_at
+	FROM labels l
+	    INNER JOIN users u ON u.id = l.user_id
+	WHERE l.board_id = ?
+	ORDER BY l.name, l.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, boardID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	labels := make([]entities.Label, 0)
+	for rows.Next() {
+		label, err := scanLabel(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		labels = append(labels, *label)
+	}
+
+	return labels, nil
+}
+
+func (r labelRepository) GetLabelByID(ctx context.Context, id int) (*entities.Label, error) {
+	const query = `
+	SELECT l.id,
+	       l.uuid,
+	       l.board_id,
+	       l.name,
+	       l.color,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       l.created_at,
+	       l.modified_at
+	FROM labels l
+	    INNER JOIN users u ON u.id = l.user_id
+	WHERE l.id = ?
+	`
+
+	label, err := scanLabel(r.conn().QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return label, nil
+}
+
+func (r labelRepository) AddLabel(ctx context.Context, label *entities.Label) error {
+	const query = `
+		INSERT INTO labels (uuid, board_id, name, color, user_id) VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, label.UUID, label.IDBoard, label.Name, label.Color, label.CreatedBy.ID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	label.ID = int(id)
+	return nil
+}
+
+func (r labelRepository) UpdateLabel(ctx context.Context, label *entities.Label) error {
+	const query = `
+		UPDATE labels SET name = ?, color = ? WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, label.Name, label.Color, label.ID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r labelRepository) DeleteLabel(ctx context.Context, id int) error {
+	const query = `
+		DELETE FROM labels WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r labelRepository) GetLabelsByTask(ctx context.Context, taskID int) ([]entities.Label, error) {
+	const query = `
+	SELECT tl.task_id,
+	       l.id,
+	       l.uuid,
+	       l.board_id,
+	       l.name,
+	       l.color,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       l.created_at,
+	       l.modified_at
+	FROM task_labels tl
+	    INNER JOIN labels l ON l.id = tl.label_id
+	    INNER JOIN users u ON u.id = l.user_id
+	WHERE tl.task_id = ?
+	ORDER BY l.name, l.id
+	`
+
+	labelsByTask, err := r.queryTaskLabels(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := labelsByTask[taskID]
+	if labels == nil {
+		labels = make([]entities.Label, 0)
+	}
+
+	return labels, nil
+}
+
+func (r labelRepository) GetTaskLabelsByBoard(ctx context.Context, boardID int) (map[int][]entities.Label, error) {
+	const query = `
+	SELECT tl.task_id,
+	       l.id,
+	       l.uuid,
+	       l.board_id,
+	       l.name,
+	       l.color,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       l.created_at,
+	       l.modified_at
+	FROM task_labels tl
+	    INNER JOIN labels l ON l.id = tl.label_id
+	    INNER JOIN users u ON u.id = l.user_id
+	WHERE l.board_id = ?
+	ORDER BY l.name, l.id
+	`
+
+	return r.queryTaskLabels(ctx, query, boardID)
+}
+
+// AddTaskLabel tags the task with the label, returning false if it already was
+func (r labelRepository) AddTaskLabel(ctx context.Context, taskID int, labelID int) (bool, error) {
+	const query = `
+		INSERT IGNORE INTO task_labels (task_id, label_id) VALUES (?, ?)
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, taskID, labelID)
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	added, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return added > 0, nil
+}
+
+// RemoveTaskLabel removes the label from the task, returning false if the task wasn't tagged with it
+func (r labelRepository) RemoveTaskLabel(ctx context.Context, taskID int, labelID int) (bool, error) {
+	const query = `
+		DELETE FROM task_labels WHERE task_id = ? AND label_id = ?
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, taskID, labelID)
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return removed > 0, nil
+}
+
+// queryTaskLabels returns the labels selected by the query, by task ID
+func (r labelRepository) queryTaskLabels(
+	ctx context.Context,
+	query string,
+	args ...any,
+) (map[int][]entities.Label, error) {
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	labels := make(map[int][]entities.Label)
+	for rows.Next() {
+		var taskID int
+		var label entities.Label
+		err = rows.Scan(
+			&taskID,
+			&label.ID,
+			&label.UUID,
+			&label.IDBoard,
+			&label.Name,
+			&label.Color,
+			&label.CreatedBy.ID,
+			&label.CreatedBy.UUID,
+			&label.CreatedBy.Email,
+			&label.CreatedAt,
+			&label.ModifiedAt,
+		)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		labels[taskID] = append(labels[taskID], label)
+	}
+
+	return labels, nil
+}
+
+func scanLabel(row scanner) (*entities.Label, error) {
+	var label entities.Label
+	err := row.Scan(
+		&label.ID,
+		&label.UUID,
+		&label.IDBoard,
+		&label.Name,
+		&label.Color,
+		&label.CreatedBy.ID,
+		&label.CreatedBy.UUID,
+		&label.CreatedBy.Email,
+		&label.CreatedAt,
+		&label.ModifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &label, nil
+}
@@ -0,0 +1,418 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+type notificationRepository struct {
+	conn func() *sql.DB
+}
+
+func NewNotificationRepository(settings datastore.RepositorySettings) datastore.NotificationRepository {
+	return notificationRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r notificationRepository) GetPreferences(
+	ctx context.Context,
+	userID int,
+) (*entities.NotificationPreferences, error) {
+	const query = `
+	SELECT user_id,
+	       assigned,
+	       mentioned,
+	       due_soon,
+	       daily_digest,
+	       digest_sent_at
+	FROM notification_preferences
+	WHERE user_id = ?
+	`
+
+	preferences, err := scanPreferences(r.conn().QueryRowContext(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			defaults := entities.DefaultNotificationPreferences(userID)
+			return &defaults, nil
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return preferences, nil
+}
+
+func (r notificationRepository) SavePreferences(
+	ctx context.Context,
+	preferences *entities.NotificationPreferences,
+) error {
+	const query = `
+		INSERT INTO notification_preferences (user_id, assigned, mentioned, due_soon, daily_digest) 
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE assigned     = VALUES(assigned),
+		                        mentioned    = VALUES(mentioned),
+		                        due_soon     = VALUES(due_soon),
+		                        daily_digest = VALUES(daily_digest)
+	`
+
+	_, err := r.conn().ExecContext(
+		ctx,
+		query,
+		preferences.IDUser,
+		preferences.Assigned,
+		preferences.Mentioned,
+		preferences.DueSoon,
+		preferences.DailyDigest,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r notificationRepository) GetDigestSubscribers(
+	ctx context.Context,
+	sentBefore time.Time,
+) ([]entities.NotificationPreferences, error) {
+	const query = `
+	SELECT user_id,
+	       assigned,
+	       mentioned,
+	       due_soon,
+	       daily_digest,
+	       digest_sent_at
+	FROM notification_preferences
+	WHERE daily_digest
+	  AND (digest_sent_at IS NULL OR digest_sent_at < ?)
+	ORDER BY user_id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, sentBefore)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	subscribers := make([]entities.NotificationPreferences, 0)
+	for rows.Next() {
+		preferences, err := scanPreferences(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		subscribers = append(subscribers, *preferences)
+	}
+
+	return subscribers, nil
+}
+
+func (r notificationRepository) SetDigestSent(ctx context.Context, userID int, sentAt time.Time) error {
+	const query = `
+		UPDATE notification_preferences SET digest_sent_at = ? WHERE user_id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, sentAt, userID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func scanPreferences(row scanner) (*entities.NotificationPreferences, error) {
+	var preferences entities.NotificationPreferences
+	var digestSentAt sql.NullTime
+	err := row.Scan(
+		&preferences.IDUser,
+		&preferences.Assigned,
+		&preferences.Mentioned,
+		&preferences.DueSoon,
+		&preferences.DailyDigest,
+		&digestSentAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if digestSentAt.Valid {
+		preferences.DigestSentAt = &digestSentAt.Time
+	}
+
+	return &preferences, nil
+}
+
+func (r notificationRepository) AddNotification(ctx context.Context, notification *entities.Notification) error {
+	const query = `
+		INSERT INTO notifications (user_id, type, board_id, task_id, activity_id, actor_id, message) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	var taskID, activityID, actorID sql.NullInt64
+	if notification.IDTask != 0 {
+		taskID = sql.NullInt64{Int64: int64(notification.IDTask), Valid: true}
+	}
+	if notification.IDActivity != 0 {
+		activityID = sql.NullInt64{Int64: int64(notification.IDActivity), Valid: true}
+	}
+	if notification.Actor != nil {
+		actorID = sql.NullInt64{Int64: int64(notification.Actor.ID), Valid: true}
+	}
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		notification.IDUser,
+		notification.Type,
+		notification.IDBoard,
+		taskID,
+		activityID,
+		actorID,
+		notification.Message,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	notification.ID = int(id)
+	return nil
+}
+
+func (r notificationRepository) GetNotifications(
+	ctx context.Context,
+	filter entities.NotificationFilter,
+) ([]entities.Notification, error) {
+	conditions := []string{"n.user_id = ?"}
+	args := []any{filter.IDUser}
+
+	if filter.Unread {
+		conditions = append(conditions, "n.read_at IS NULL")
+	}
+
+	if filter.BeforeID != 0 {
+		conditions = append(conditions, "n.id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	query := `
+	SELECT n.id,
+	       n.user_id,
+	       n.type,
+	       n.board_id,
+	       n.task_id,
+	       n.activity_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       n.message,
+	       n.read_at,
+	       n.created_at
+	FROM notifications n
+	    LEFT JOIN users u ON u.id = n.actor_id
+	WHERE ` + strings.Join(conditions, " AND ") + `
+	ORDER BY n.id DESC
+	LIMIT ?
+	`
+	args = append(args, filter.Limit)
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	notifications := make([]entities.Notification, 0)
+	for rows.Next() {
+		var notification entities.Notification
+		var taskID, activityID, actorID sql.NullInt64
+		var actorUUID, actorEmail sql.NullString
+		var readAt sql.NullTime
+		err = rows.Scan(
+			&notification.ID,
+			&notification.IDUser,
+			&notification.Type,
+			&notification.IDBoard,
+			&taskID,
+			&activityID,
+			&actorID,
+			&actorUUID,
+			&actorEmail,
+			&notification.Message,
+			&readAt,
+			&notification.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		notification.IDTask = int(taskID.Int64)
+		notification.IDActivity = int(activityID.Int64)
+		if actorID.Valid {
+			notification.Actor = &entities.User{
+				ID:    int(actorID.Int64),
+				UUID:  actorUUID.String,
+				Email: actorEmail.String,
+			}
+		}
+		if readAt.Valid {
+			notification.ReadAt = &readAt.Time
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+func (r notificationRepository) CountUnreadNotifications(ctx context.Context, userID int) (int, error) {
+	const query = `
+		SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL
+	`
+
+	var count int
+	err := r.conn().QueryRowContext(ctx, query, userID).Scan(&count)
+	if err != nil {
+		return 0, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return count, nil
+}
+
+func (r notificationRepository) MarkNotificationRead(
+	ctx context.Context,
+	userID int,
+	id int,
+	readAt time.Time,
+) (bool, error) {
+	const updateQuery = `
+		UPDATE notifications SET read_at = COALESCE(read_at, ?) WHERE id = ? AND user_id = ?
+	`
+
+	const existsQuery = `
+		SELECT EXISTS(SELECT 1 FROM notifications WHERE id = ? AND user_id = ?)
+	`
+
+	result, err := r.conn().ExecContext(ctx, updateQuery, readAt, id, userID)
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	if affected == 1 {
+		return true, nil
+	}
+
+	// Already read notifications are not affected by the update
+	var exists bool
+	err = r.conn().QueryRowContext(ctx, existsQuery, id, userID).Scan(&exists)
+	if err != nil {
+		return false, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return exists, nil
+}
+
+func (r notificationRepository) MarkAllNotificationsRead(ctx context.Context, userID int, readAt time.Time) error {
+	const query = `
+		UPDATE notifications SET read_at = ? WHERE user_id = ? AND read_at IS NULL
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, readAt, userID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r notificationRepository) WatchBoard(ctx context.Context, boardID int, userID int) error {
+	const query = `
+		INSERT IGNORE INTO board_watchers (board_id, user_id) VALUES (?, ?)
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, boardID, userID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r notificationRepository) UnwatchBoard(ctx context.Context, boardID int, userID int) error {
+	const query = `
+		DELETE FROM board_watchers WHERE board_id = ? AND user_id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, boardID, userID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r notificationRepository) WatchTask(ctx context.Context, taskID int, userID int) error {
+	const query = `
+		INSERT IGNORE INTO task_watchers (task_id, user_id) VALUES (?, ?)
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, taskID, userID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r notificationRepository) UnwatchTask(ctx context.Context, taskID int, userID int) error {
+	const query = `
+		DELETE FROM task_watchers WHERE task_id = ? AND user_id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, taskID, userID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r notificationRepository) GetWatchers(ctx context.Context, boardID int, taskID int) ([]int, error) {
+	const query = `
+	SELECT user_id FROM board_watchers WHERE board_id = ?
+	UNION
+	SELECT user_id FROM task_watchers WHERE task_id = ?
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, boardID, taskID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	watchers := make([]int, 0)
+	for rows.Next() {
+		var userID int
+		err = rows.Scan(&userID)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		watchers = append(watchers, userID)
+	}
+
+	return watchers, nil
+}
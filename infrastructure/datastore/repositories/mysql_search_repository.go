@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+)
+
+type searchRepository struct {
+	conn func() *sql.DB
+}
+
+func NewSearchRepository(settings datastore.RepositorySettings) datastore.SearchRepository {
+	return searchRepository{
+		conn: settings.Connection,
+	}
+}
+
+// Search matches the terms against the full-text indexes of the boards, tasks and comments, the best ranked first
+func (r searchRepository) Search(ctx context.Context, filter entities.SearchFilter) ([]entities.SearchResult, error) {
+	const boardsQuery = `
+	SELECT 'board',
+	       b.id,
+	       b.id,
+	       NULL,
+	       b.title,
+	       b.description,
+	       MATCH(b.title, b.description) AGAINST (? IN BOOLEAN MODE) AS score,
+	       b.modified_at
+	FROM boards b
+	WHERE MATCH(b.title, b.description) AGAINST (? IN BOOLEAN MODE)
+	  AND (b.user_id = ? OR b.id IN (SELECT board_id FROM board_users WHERE user_id = ?))
+	  AND b.status_code = ?
+	`
+
+	const tasksQuery = `
+	SELECT 'task',
+	       t.id,
+	       b.id,
+	       t.id,
+	       t.name,
+	       t.description,
+	       MATCH(t.name, t.description) AGAINST (? IN BOOLEAN MODE) AS score,
+	       t.modified_at
+	FROM tasks t
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN boards b ON b.id = tl.board_id
+	WHERE MATCH(t.name, t.description) AGAINST (? IN BOOLEAN MODE)
+	  AND (b.user_id = ? OR b.id IN (SELECT board_id FROM board_users WHERE user_id = ?))
+	  AND t.status_code = ? AND tl.status_code = ? AND b.status_code = ?
+	`
+
+	const commentsQuery = `
+	SELECT 'comment',
+	       c.id,
+	       b.id,
+	       t.id,
+	       t.name,
+	       c.body,
+	       MATCH(c.body) AGAINST (? IN BOOLEAN MODE) AS score,
+	       c.modified_at
+	FROM task_comments c
+	    INNER JOIN tasks t ON t.id = c.task_id
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN boards b ON b.id = tl.board_id
+	WHERE MATCH(c.body) AGAINST (? IN BOOLEAN MODE)
+	  AND (b.user_id = ? OR b.id IN (SELECT board_id FROM board_users WHERE user_id = ?))
+	  AND t.status_code = ? AND tl.status_code = ? AND b.status_code = ?
+	`
+
+	// Only the active items are searched
+	active := entities.StatusActive
+	sources := []struct {
+		resultType entities.SearchResultType
+		query      string
+		statusArgs []any
+	}{
+		{entities.SearchResultBoard, boardsQuery, []any{active}},
+		{entities.SearchResultTask, tasksQuery, []any{active, active, active}},
+		{entities.SearchResultComment, commentsQuery, []any{active, active, active}},
+	}
+
+	match := booleanQuery(filter.Terms)
+	parts := make([]string, 0, len(sources))
+	args := make([]any, 0)
+	for _, source := range sources {
+		if len(filter.Types) > 0 && !slices.Contains(filter.Types, source.resultType) {
+			continue
+		}
+
+		query := source.query
+		args = append(args, match, match, filter.IDUser, filter.IDUser)
+		args = append(args, source.statusArgs...)
+		if filter.IDBoard != 0 {
+			query += "  AND b.id = ?\n"
+			args = append(args, filter.IDBoard)
+		}
+
+		parts = append(parts, "("+query+")")
+	}
+
+	query := strings.Join(parts, "\nUNION ALL\n") + "\nORDER BY score DESC, modified_at DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	results := make([]entities.SearchResult, 0)
+	for rows.Next() {
+		var result entities.SearchResult
+		var taskID sql.NullInt64
+		var text sql.NullString
+		err = rows.Scan(
+			&result.Type,
+			&result.ID,
+			&result.IDBoard,
+			&taskID,
+			&result.Title,
+			&text,
+			&result.Score,
+			&result.ModifiedAt,
+		)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		result.IDTask = int(taskID.Int64)
+		result.Snippet = text.String
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// booleanQuery returns the boolean mode search requiring every term as a word prefix, such as "+release* +notes*"
+func booleanQuery(terms []string) string {
+	required := make([]string, 0, len(terms))
+	for _, term := range terms {
+		required = append(required, "+"+term+"*")
+	}
+
+	return strings.Join(required, " ")
+}
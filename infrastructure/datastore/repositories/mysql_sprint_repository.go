@@ -0,0 +1,448 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+// sprintsQuery selects the columns scanned by scanSprint
+const sprintsQuery = `
+	SELECT s.id,
+	       s.uuid,
+	       s.board_id,
+	       s.name,
+	       s.goal,
+	       s.start_date,
+	       s.end_date,
+	       s.status,
+	       s.started_at,
+	       s.closed_at,
+	       (SELECT COUNT(*)
+	        FROM sprint_tasks st
+	            INNER JOIN tasks t ON t.id = st.task_id
+	        WHERE st.sprint_id = s.id AND t.status_code <> ?),
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       s.created_at,
+	       s.modified_at
+	FROM sprints s
+	    INNER JOIN users u ON u.id = s.user_id
+`
+
+type sprintRepository struct {
+	conn func() *sql.DB
+}
+
+func NewSprintRepository(settings datastore.RepositorySettings) datastore.SprintRepository {
+	return sprintRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r sprintRepository) GetSprintsByBoard(ctx context.Context, boardID int) ([]entities.Sprint, error) {
+	const query = sprintsQuery + `
+	WHERE s.board_id = ?
+	ORDER BY s.start_date, s.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, entities.StatusDeleted, boardID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	sprints := make([]entities.Sprint, 0)
+	for rows.Next() {
+		sprint, err := scanSprint(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		sprints = append(sprints, *sprint)
+	}
+
+	return sprints, nil
+}
+
+func (r sprintRepository) GetSprintByID(ctx context.Context, id int) (*entities.Sprint, error) {
+	const query = sprintsQuery + `
+	WHERE s.id = ?
+	`
+
+	sprint, err := scanSprint(r.conn().QueryRowContext(ctx, query, entities.StatusDeleted, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return sprint, nil
+}
+
+func (r sprintRepository) AddSprint(ctx context.Context, sprint *entities.Sprint) error {
+	const query = `
+		INSERT INTO sprints (uuid, board_id, name, goal, start_date, end_date, status, user_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		sprint.UUID,
+		sprint.IDBoard,
+		sprint.Name,
+		sprint.Goal,
+		sprint.StartDate,
+		sprint.EndDate,
+		sprint.Status,
+		sprint.CreatedBy.ID,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	sprint.ID = int(id)
+	return nil
+}
+
+func (r sprintRepository) UpdateSprint(ctx context.Context, sprint *entities.Sprint) error {
+	const query = `
+		UPDATE sprints SET name = ?, goal = ?, start_date = ?, end_date = ? WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, sprint.Name, sprint.Goal, sprint.StartDate, sprint.EndDate, sprint.ID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r sprintRepository) DeleteSprint(ctx context.Context, id int) error {
+	const query = `
+		DELETE FROM sprints WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+// StartSprint locks the board row while looking for an active sprint, so two sprints of a board can't be started
+// concurrently
+func (r sprintRepository) StartSprint(ctx context.Context, id int, startedAt time.Time) (bool, error) {
+	const lockQuery = `
+		SELECT b.id FROM boards b INNER JOIN sprints s ON s.board_id = b.id WHERE s.id = ? FOR UPDATE
+	`
+
+	const activeQuery = `
+		SELECT COUNT(*) FROM sprints WHERE board_id = ? AND status = ?
+	`
+
+	const updateQuery = `
+		UPDATE sprints SET status = ?, started_at = ? WHERE id = ? AND status = ?
+	`
+
+	started := false
+	err := withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		var boardID int
+		err := tx.QueryRowContext(ctx, lockQuery, id).Scan(&boardID)
+		if err != nil {
+			return errors.Join(entities.ErrQueryRow, err)
+		}
+
+		var active int
+		err = tx.QueryRowContext(ctx, activeQuery, boardID, entities.SprintActive).Scan(&active)
+		if err != nil {
+			return errors.Join(entities.ErrQueryRow, err)
+		}
+
+		if active > 0 {
+			return nil
+		}
+
+		result, err := tx.ExecContext(ctx, updateQuery, entities.SprintActive, startedAt, id, entities.SprintPlanned)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		started = updated > 0
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return started, nil
+}
+
+func (r sprintRepository) CloseSprint(
+	ctx context.Context,
+	id int,
+	targetID int,
+	closedAt time.Time,
+) ([]int, bool, error) {
+	const lockQuery = `
+		SELECT status FROM sprints WHERE id = ? FOR UPDATE
+	`
+
+	const unfinishedQuery = `
+		SELECT st.task_id
+		FROM sprint_tasks st
+		    INNER JOIN tasks t ON t.id = st.task_id
+		WHERE st.sprint_id = ? AND t.status <> ? AND t.status_code <> ?
+		ORDER BY st.added_at, st.task_id
+	`
+
+	const carryQuery = `
+		UPDATE sprint_tasks SET carried_over = TRUE WHERE sprint_id = ? AND task_id = ?
+	`
+
+	const commitQuery = `
+		INSERT IGNORE INTO sprint_tasks (sprint_id, task_id, added_at) VALUES (?, ?, ?)
+	`
+
+	const closeQuery = `
+		UPDATE sprints SET status = ?, closed_at = ? WHERE id = ?
+	`
+
+	var carried []int
+	closed := false
+	err := withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		var status entities.SprintStatus
+		err := tx.QueryRowContext(ctx, lockQuery, id).Scan(&status)
+		if err != nil {
+			return errors.Join(entities.ErrQueryRow, err)
+		}
+
+		if status != entities.SprintActive {
+			return nil
+		}
+
+		rows, err := tx.QueryContext(ctx, unfinishedQuery, id, entities.TaskFinished, entities.StatusDeleted)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		carried = make([]int, 0)
+		for rows.Next() {
+			var taskID int
+			err = rows.Scan(&taskID)
+			if err != nil {
+				_ = rows.Close()
+				return errors.Join(entities.ErrScan, err)
+			}
+			carried = append(carried, taskID)
+		}
+
+		err = rows.Close()
+		if err != nil {
+			return errors.Join(entities.ErrScan, err)
+		}
+
+		for _, taskID := range carried {
+			_, err = tx.ExecContext(ctx, carryQuery, id, taskID)
+			if err != nil {
+				return errors.Join(entities.ErrExecuteQuery, err)
+			}
+
+			if targetID != 0 {
+				_, err = tx.ExecContext(ctx, commitQuery, targetID, taskID, closedAt)
+				if err != nil {
+					return errors.Join(entities.ErrExecuteQuery, err)
+				}
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, closeQuery, entities.SprintClosed, closedAt, id)
+		if err != nil {
+			return errors.Join(entities.ErrExecuteQuery, err)
+		}
+
+		closed = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return carried, closed, nil
+}
+
+func (r sprintRepository) GetSprintTasks(
+	ctx context.Context,
+	boardID int,
+	sprintID int,
+) ([]entities.SprintTask, error) {
+	const query = `
+	SELECT t.id,
+	       t.uuid,
+	       t.task_list_id,
+	       tl.board_id,
+	       t.name,
+	       t.description,
+	       t.position,
+	       t.status,
+	       t.priority,
+	       t.due_date,
+	       t.overdue_at,
+	       t.original_estimate,
+	       t.recurrence_rule,
+	       t.recurrence_list_id,
+	       t.parent_task_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       t.status_code,
+	       t.created_at,
+	       t.modified_at,
+	       t.imported_author,
+	       st.added_at,
+	       st.carried_over,
+	       IF(t.status = ?, c.completed_at, NULL)
+	FROM sprint_tasks st
+	    INNER JOIN tasks t ON t.id = st.task_id
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN users u ON u.id = t.user_id
+	    LEFT JOIN (` + completionsQuery + `) c ON c.task_id = t.id
+	WHERE st.sprint_id = ? AND t.status_code <> ?
+	ORDER BY st.added_at, t.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, entities.TaskFinished, boardID, sprintID, entities.StatusDeleted)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	tasks := make([]entities.SprintTask, 0)
+	for rows.Next() {
+		var sprintTask entities.SprintTask
+		var completedAt sql.NullTime
+		task, err := scanTask(rows, &sprintTask.AddedAt, &sprintTask.CarriedOver, &completedAt)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		if completedAt.Valid {
+			sprintTask.CompletedAt = &completedAt.Time
+		}
+
+		sprintTask.Task = *task
+		tasks = append(tasks, sprintTask)
+	}
+
+	return tasks, nil
+}
+
+func (r sprintRepository) GetTaskSprint(ctx context.Context, taskID int) (int, error) {
+	const query = `
+		SELECT s.id
+		FROM sprint_tasks st
+		    INNER JOIN sprints s ON s.id = st.sprint_id
+		WHERE st.task_id = ? AND s.status <> ?
+		ORDER BY s.id
+		LIMIT 1
+	`
+
+	var sprintID int
+	err := r.conn().QueryRowContext(ctx, query, taskID, entities.SprintClosed).Scan(&sprintID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+
+		return 0, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return sprintID, nil
+}
+
+func (r sprintRepository) AddSprintTask(ctx context.Context, sprintID int, taskID int) error {
+	const query = `
+		INSERT INTO sprint_tasks (sprint_id, task_id) VALUES (?, ?)
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, sprintID, taskID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r sprintRepository) RemoveSprintTask(ctx context.Context, sprintID int, taskID int) (bool, error) {
+	const query = `
+		DELETE FROM sprint_tasks WHERE sprint_id = ? AND task_id = ?
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, sprintID, taskID)
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return removed > 0, nil
+}
+
+func scanSprint(row scanner) (*entities.Sprint, error) {
+	var sprint entities.Sprint
+	var goal sql.NullString
+	var startedAt sql.NullTime
+	var closedAt sql.NullTime
+	err := row.Scan(
+		&sprint.ID,
+		&sprint.UUID,
+		&sprint.IDBoard,
+		&sprint.Name,
+		&goal,
+		&sprint.StartDate,
+		&sprint.EndDate,
+		&sprint.Status,
+		&startedAt,
+		&closedAt,
+		&sprint.TaskCount,
+		&sprint.CreatedBy.ID,
+		&sprint.CreatedBy.UUID,
+		&sprint.CreatedBy.Email,
+		&sprint.CreatedAt,
+		&sprint.ModifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if startedAt.Valid {
+		sprint.StartedAt = &startedAt.Time
+	}
+
+	if closedAt.Valid {
+		sprint.ClosedAt = &closedAt.Time
+	}
+
+	sprint.Goal = goal.String
+	return &sprint, nil
+}
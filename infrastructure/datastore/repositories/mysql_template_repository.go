@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+)
+
+type templateRepository struct {
+	conn func() *sql.DB
+}
+
+func NewTemplateRepository(settings datastore.RepositorySettings) datastore.TemplateRepository {
+	return templateRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r templateRepository) GetTemplatesByUser(ctx context.Context, userID int) ([]entities.BoardTemplate, error) {
+	const query = `
+	SELECT bt.id,
+	       bt.uuid,
+	       bt.name,
+	       bt.description,
+	       bt.content,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       bt.created_at,
+	       bt.modified_at
+	FROM board_templates bt
+	    INNER JOIN users u ON u.id = bt.user_id
+	WHERE bt.user_id = ?
+	ORDER BY bt.name, bt.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	templates := make([]entities.BoardTemplate, 0)
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		templates = append(templates, *template)
+	}
+
+	return templates, nil
+}
+
+func (r templateRepository) GetTemplateByID(ctx context.Context, id int) (*entities.BoardTemplate, error) {
+	const query = `
+	SELECT bt.id,
+	       bt.uuid,
+	       bt.name,
+	       bt.description,
+	       bt.content,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       bt.created_at,
+	       bt.modified_at
+	FROM board_templates bt
+	    INNER JOIN users u ON u.id = bt.user_id
+	WHERE bt.id = ?
+	`
+
+	template, err := scanTemplate(r.conn().QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return template, nil
+}
+
+func (r templateRepository) AddTemplate(ctx context.Context, template *entities.BoardTemplate) error {
+	const query = `
+		INSERT INTO board_templates (uuid, name, description, content, user_id) VALUES (?, ?, ?, ?, ?)
+	`
+
+	content, err := json.Marshal(template.Content)
+	if err != nil {
+		return errors.Join(errors.New("failed to marshal template content"), err)
+	}
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		template.UUID,
+		template.Name,
+		template.Description,
+		content,
+		template.CreatedBy.ID,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	template.ID = int(id)
+	return nil
+}
+
+func (r templateRepository) DeleteTemplate(ctx context.Context, id int) error {
+	const query = `
+		DELETE FROM board_templates WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func scanTemplate(row scanner) (*entities.BoardTemplate, error) {
+	var template entities.BoardTemplate
+	var description sql.NullString
+	var content []byte
+	err := row.Scan(
+		&template.ID,
+		&template.UUID,
+		&template.Name,
+		&description,
+		&content,
+		&template.CreatedBy.ID,
+		&template.CreatedBy.UUID,
+		&template.CreatedBy.Email,
+		&template.CreatedAt,
+		&template.ModifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &template.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Description = description.String
+	return &template, nil
+}
@@ -0,0 +1,337 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+// timeEntriesQuery selects the columns scanned by scanTimeEntry, the tasks of the entries being joined as t
+const timeEntriesQuery = `
+	SELECT te.id,
+	       te.uuid,
+	       te.task_id,
+	       tl.board_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       te.started_at,
+	       te.ended_at,
+	       te.minutes,
+	       te.note,
+	       te.manual,
+	       te.created_at,
+	       te.modified_at
+	FROM time_entries te
+	    INNER JOIN tasks t ON t.id = te.task_id
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN users u ON u.id = te.user_id
+`
+
+type timeRepository struct {
+	conn func() *sql.DB
+}
+
+func NewTimeRepository(settings datastore.RepositorySettings) datastore.TimeRepository {
+	return timeRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r timeRepository) GetEntryByID(ctx context.Context, id int) (*entities.TimeEntry, error) {
+	const query = timeEntriesQuery + `
+	WHERE te.id = ?
+	`
+
+	entry, err := scanTimeEntry(r.conn().QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return entry, nil
+}
+
+func (r timeRepository) GetEntriesByTask(ctx context.Context, taskID int) ([]entities.TimeEntry, error) {
+	const query = timeEntriesQuery + `
+	WHERE te.task_id = ?
+	ORDER BY te.started_at DESC, te.id DESC
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	entries := make([]entities.TimeEntry, 0)
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		entries = append(entries, *entry)
+	}
+
+	return entries, nil
+}
+
+func (r timeRepository) GetRunningEntry(ctx context.Context, userID int) (*entities.TimeEntry, error) {
+	const query = timeEntriesQuery + `
+	WHERE te.user_id = ? AND te.ended_at IS NULL
+	`
+
+	entry, err := scanTimeEntry(r.conn().QueryRowContext(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return entry, nil
+}
+
+// StartTimer locks the user row while looking for a running timer, so concurrent starts of the same user can't both
+// succeed
+func (r timeRepository) StartTimer(ctx context.Context, entry *entities.TimeEntry) (bool, error) {
+	const lockQuery = `
+		SELECT id FROM users WHERE id = ? FOR UPDATE
+	`
+
+	const runningQuery = `
+		SELECT COUNT(*) FROM time_entries WHERE user_id = ? AND ended_at IS NULL
+	`
+
+	const insertQuery = `
+		INSERT INTO time_entries (uuid, task_id, user_id, started_at, note, manual)
+		VALUES (?, ?, ?, ?, ?, FALSE)
+	`
+
+	started := false
+	err := withTransaction(ctx, r.conn(), func(tx *sql.Tx) error {
+		var userID int
+		err := tx.QueryRowContext(ctx, lockQuery, entry.User.ID).Scan(&userID)
+		if err != nil {
+			return errors.Join(entities.ErrQueryRow, err)
+		}
+
+		var running int
+		err = tx.QueryRowContext(ctx, runningQuery, entry.User.ID).Scan(&running)
+		if err != nil {
+			return errors.Join(entities.ErrQueryRow, err)
+		}
+
+		if running > 0 {
+			return nil
+		}
+
+		entry.ID, err = insertID(
+			ctx,
+			tx,
+			insertQuery,
+			entry.UUID,
+			entry.IDTask,
+			entry.User.ID,
+			entry.StartedAt,
+			entry.Note,
+		)
+		if err != nil {
+			return err
+		}
+
+		started = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return started, nil
+}
+
+func (r timeRepository) StopTimer(ctx context.Context, entry *entities.TimeEntry) (bool, error) {
+	const query = `
+		UPDATE time_entries SET ended_at = ?, minutes = ? WHERE id = ? AND ended_at IS NULL
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, entry.EndedAt, entry.Minutes, entry.ID)
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	stopped, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return stopped > 0, nil
+}
+
+func (r timeRepository) AddEntry(ctx context.Context, entry *entities.TimeEntry) error {
+	const query = `
+		INSERT INTO time_entries (uuid, task_id, user_id, started_at, ended_at, minutes, note, manual)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		entry.UUID,
+		entry.IDTask,
+		entry.User.ID,
+		entry.StartedAt,
+		entry.EndedAt,
+		entry.Minutes,
+		entry.Note,
+		entry.Manual,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	entry.ID = int(id)
+	return nil
+}
+
+func (r timeRepository) UpdateEntry(ctx context.Context, entry *entities.TimeEntry) error {
+	const query = `
+		UPDATE time_entries SET started_at = ?, ended_at = ?, minutes = ?, note = ? WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, entry.StartedAt, entry.EndedAt, entry.Minutes, entry.Note, entry.ID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r timeRepository) DeleteEntry(ctx context.Context, id int) error {
+	const query = `
+		DELETE FROM time_entries WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r timeRepository) GetReportEntries(
+	ctx context.Context,
+	boardID int,
+	userID int,
+	from time.Time,
+	until time.Time,
+) ([]entities.TimeReportEntry, error) {
+	const query = `
+	SELECT te.id,
+	       te.uuid,
+	       te.task_id,
+	       tl.board_id,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       te.started_at,
+	       te.ended_at,
+	       te.minutes,
+	       te.note,
+	       te.manual,
+	       te.created_at,
+	       te.modified_at,
+	       b.title,
+	       t.name,
+	       t.original_estimate
+	FROM time_entries te
+	    INNER JOIN tasks t ON t.id = te.task_id
+	    INNER JOIN task_lists tl ON tl.id = t.task_list_id
+	    INNER JOIN boards b ON b.id = tl.board_id
+	    INNER JOIN users u ON u.id = te.user_id
+	WHERE (? = 0 OR tl.board_id = ?)
+	  AND (? = 0 OR te.user_id = ?)
+	  AND te.ended_at IS NOT NULL
+	  AND te.started_at >= ? AND te.started_at < ?
+	  AND t.status_code <> ?
+	ORDER BY te.started_at, te.id
+	`
+
+	rows, err := r.conn().QueryContext(
+		ctx,
+		query,
+		boardID,
+		boardID,
+		userID,
+		userID,
+		from,
+		until,
+		entities.StatusDeleted,
+	)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	entries := make([]entities.TimeReportEntry, 0)
+	for rows.Next() {
+		var reportEntry entities.TimeReportEntry
+		entry, err := scanTimeEntry(rows, &reportEntry.BoardTitle, &reportEntry.TaskName, &reportEntry.TaskEstimate)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+
+		reportEntry.Entry = *entry
+		entries = append(entries, reportEntry)
+	}
+
+	return entries, nil
+}
+
+// scanTimeEntry scans the columns of a time entry, followed by the columns of the extra destinations if any
+func scanTimeEntry(row scanner, extra ...any) (*entities.TimeEntry, error) {
+	var entry entities.TimeEntry
+	var endedAt sql.NullTime
+	var note sql.NullString
+	dest := []any{
+		&entry.ID,
+		&entry.UUID,
+		&entry.IDTask,
+		&entry.IDBoard,
+		&entry.User.ID,
+		&entry.User.UUID,
+		&entry.User.Email,
+		&entry.StartedAt,
+		&endedAt,
+		&entry.Minutes,
+		&note,
+		&entry.Manual,
+		&entry.CreatedAt,
+		&entry.ModifiedAt,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if endedAt.Valid {
+		entry.EndedAt = &endedAt.Time
+	}
+
+	entry.Note = note.String
+	return &entry, nil
+}
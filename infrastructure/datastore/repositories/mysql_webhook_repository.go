@@ -0,0 +1,469 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+type webhookRepository struct {
+	conn func() *sql.DB
+}
+
+func NewWebhookRepository(settings datastore.RepositorySettings) datastore.WebhookRepository {
+	return webhookRepository{
+		conn: settings.Connection,
+	}
+}
+
+func (r webhookRepository) GetWebhooksByBoard(ctx context.Context, boardID int) ([]entities.Webhook, error) {
+	const query = `
+	SELECT w.id,
+	       w.uuid,
+	       w.board_id,
+	       w.url,
+	       w.secret,
+	       w.events,
+	       w.enabled,
+	       w.consecutive_failures,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       w.created_at,
+	       w.modified_at
+	FROM webhooks w
+	    INNER JOIN users u ON u.id = w.user_id
+	WHERE w.board_id = ?
+	ORDER BY w.id
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, boardID)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]entities.Webhook, 0)
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	return webhooks, nil
+}
+
+func (r webhookRepository) GetWebhookByID(ctx context.Context, id int) (*entities.Webhook, error) {
+	const query = `
+	SELECT w.id,
+	       w.uuid,
+	       w.board_id,
+	       w.url,
+	       w.secret,
+	       w.events,
+	       w.enabled,
+	       w.consecutive_failures,
+	       u.id,
+	       u.uuid,
+	       u.email,
+	       w.created_at,
+	       w.modified_at
+	FROM webhooks w
+	    INNER JOIN users u ON u.id = w.user_id
+	WHERE w.id = ?
+	`
+
+	webhook, err := scanWebhook(r.conn().QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return webhook, nil
+}
+
+func (r webhookRepository) AddWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	const query = `
+		INSERT INTO webhooks (uuid, board_id, url, secret, events, enabled, user_id) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return errors.Join(errors.New("failed to marshal webhook events"), err)
+	}
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		webhook.UUID,
+		webhook.IDBoard,
+		webhook.URL,
+		webhook.Secret,
+		events,
+		webhook.Enabled,
+		webhook.CreatedBy.ID,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	webhook.ID = int(id)
+	return nil
+}
+
+// UpdateWebhook updates the URL, events and enabled flag of the webhook. Enabling it clears its failures count.
+func (r webhookRepository) UpdateWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	const query = `
+		UPDATE webhooks 
+		SET url = ?, 
+		    events = ?, 
+		    consecutive_failures = IF(? AND NOT enabled, 0, consecutive_failures),
+		    enabled = ?
+		WHERE id = ?
+	`
+
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return errors.Join(errors.New("failed to marshal webhook events"), err)
+	}
+
+	_, err = r.conn().ExecContext(ctx, query, webhook.URL, events, webhook.Enabled, webhook.Enabled, webhook.ID)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r webhookRepository) DeleteWebhook(ctx context.Context, id int) error {
+	const query = `
+		DELETE FROM webhooks WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r webhookRepository) ResetWebhookFailures(ctx context.Context, id int) error {
+	const query = `
+		UPDATE webhooks SET consecutive_failures = 0 WHERE id = ? AND consecutive_failures <> 0
+	`
+
+	_, err := r.conn().ExecContext(ctx, query, id)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func (r webhookRepository) AddWebhookFailure(ctx context.Context, id int, maxFailures int) (bool, error) {
+	// MySQL evaluates the assignments from left to right, so "enabled" sees the incremented count
+	const updateQuery = `
+		UPDATE webhooks 
+		SET consecutive_failures = consecutive_failures + 1,
+		    enabled = IF(consecutive_failures >= ?, FALSE, enabled)
+		WHERE id = ?
+	`
+
+	const selectQuery = `
+		SELECT enabled FROM webhooks WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(ctx, updateQuery, maxFailures, id)
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	var enabled bool
+	err = r.conn().QueryRowContext(ctx, selectQuery, id).Scan(&enabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+
+		return false, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return !enabled, nil
+}
+
+func (r webhookRepository) AddDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	const query = `
+		INSERT INTO webhook_deliveries (uuid, webhook_id, activity_id, event, payload, status, next_attempt_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.conn().ExecContext(
+		ctx,
+		query,
+		delivery.UUID,
+		delivery.IDWebhook,
+		delivery.IDActivity,
+		delivery.Event,
+		[]byte(delivery.Payload),
+		delivery.Status,
+		delivery.NextAttemptAt,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	delivery.ID = int(id)
+	return nil
+}
+
+func (r webhookRepository) GetDeliveryByID(ctx context.Context, id int) (*entities.WebhookDelivery, error) {
+	const query = `
+	SELECT id,
+	       uuid,
+	       webhook_id,
+	       activity_id,
+	       event,
+	       payload,
+	       status,
+	       attempts,
+	       response_code,
+	       error,
+	       next_attempt_at,
+	       delivered_at,
+	       created_at
+	FROM webhook_deliveries
+	WHERE id = ?
+	`
+
+	delivery, err := scanDelivery(r.conn().QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entities.ErrNotFound
+		}
+
+		return nil, errors.Join(entities.ErrQueryRow, err)
+	}
+
+	return delivery, nil
+}
+
+// GetDeliveries returns the deliveries of the webhook, newest first
+func (r webhookRepository) GetDeliveries(
+	ctx context.Context,
+	webhookID int,
+	beforeID int,
+	limit int,
+) ([]entities.WebhookDelivery, error) {
+	const query = `
+	SELECT id,
+	       uuid,
+	       webhook_id,
+	       activity_id,
+	       event,
+	       payload,
+	       status,
+	       attempts,
+	       response_code,
+	       error,
+	       next_attempt_at,
+	       delivered_at,
+	       created_at
+	FROM webhook_deliveries
+	WHERE webhook_id = ?
+	  AND (? = 0 OR id < ?)
+	ORDER BY id DESC
+	LIMIT ?
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, webhookID, beforeID, beforeID, limit)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+func (r webhookRepository) GetDueDeliveries(ctx context.Context, limit int) ([]entities.WebhookDelivery, error) {
+	const query = `
+	SELECT d.id,
+	       d.uuid,
+	       d.webhook_id,
+	       d.activity_id,
+	       d.event,
+	       d.payload,
+	       d.status,
+	       d.attempts,
+	       d.response_code,
+	       d.error,
+	       d.next_attempt_at,
+	       d.delivered_at,
+	       d.created_at
+	FROM webhook_deliveries d
+	    INNER JOIN webhooks w ON w.id = d.webhook_id
+	WHERE d.status = ?
+	  AND d.next_attempt_at <= CURRENT_TIMESTAMP
+	  AND w.enabled
+	ORDER BY d.next_attempt_at
+	LIMIT ?
+	`
+
+	rows, err := r.conn().QueryContext(ctx, query, entities.WebhookDeliveryPending, limit)
+	if err != nil {
+		return nil, errors.Join(entities.ErrExecuteQuery, err)
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+func (r webhookRepository) ClaimDelivery(ctx context.Context, id int, until time.Time) (bool, error) {
+	const query = `
+		UPDATE webhook_deliveries 
+		SET next_attempt_at = ? 
+		WHERE id = ? 
+		  AND status = ? 
+		  AND next_attempt_at <= CURRENT_TIMESTAMP
+	`
+
+	result, err := r.conn().ExecContext(ctx, query, until, id, entities.WebhookDeliveryPending)
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r webhookRepository) UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	const query = `
+		UPDATE webhook_deliveries 
+		SET status = ?, 
+		    attempts = ?, 
+		    response_code = ?, 
+		    error = ?, 
+		    next_attempt_at = ?, 
+		    delivered_at = ? 
+		WHERE id = ?
+	`
+
+	_, err := r.conn().ExecContext(
+		ctx,
+		query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseCode,
+		delivery.Error,
+		delivery.NextAttemptAt,
+		delivery.DeliveredAt,
+		delivery.ID,
+	)
+	if err != nil {
+		return errors.Join(entities.ErrExecuteQuery, err)
+	}
+
+	return nil
+}
+
+func scanWebhook(row scanner) (*entities.Webhook, error) {
+	var webhook entities.Webhook
+	var events []byte
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.UUID,
+		&webhook.IDBoard,
+		&webhook.URL,
+		&webhook.Secret,
+		&events,
+		&webhook.Enabled,
+		&webhook.ConsecutiveFailures,
+		&webhook.CreatedBy.ID,
+		&webhook.CreatedBy.UUID,
+		&webhook.CreatedBy.Email,
+		&webhook.CreatedAt,
+		&webhook.ModifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Events = make([]string, 0)
+	if len(events) > 0 {
+		err = json.Unmarshal(events, &webhook.Events)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &webhook, nil
+}
+
+func scanDelivery(row scanner) (*entities.WebhookDelivery, error) {
+	var delivery entities.WebhookDelivery
+	var payload []byte
+	var deliveryError sql.NullString
+	var deliveredAt sql.NullTime
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.UUID,
+		&delivery.IDWebhook,
+		&delivery.IDActivity,
+		&delivery.Event,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseCode,
+		&deliveryError,
+		&delivery.NextAttemptAt,
+		&deliveredAt,
+		&delivery.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = payload
+	delivery.Error = deliveryError.String
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &delivery, nil
+}
+
+func scanDeliveries(rows *sql.Rows) ([]entities.WebhookDelivery, error) {
+	deliveries := make([]entities.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, errors.Join(entities.ErrScan, err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sortKeys are the expressions rows are sorted by, the row ID being appended to break ties. Each expression refers to
+// the sorted table through the %[1]s alias, so the same keys can be evaluated on the row used as cursor.
+type sortKeys []string
+
+// on returns the keys evaluated on the table with the given alias
+func (k sortKeys) on(alias string) string {
+	expressions := make([]string, 0, len(k)+1)
+	for _, key := range k {
+		expressions = append(expressions, fmt.Sprintf(key, alias))
+	}
+
+	return strings.Join(append(expressions, alias+".id"), ", ")
+}
+
+// after returns the condition keeping the rows sorted after the cursor row of the table, whose ID is the argument
+func (k sortKeys) after(alias string, table string, descending bool) string {
+	operator := ">"
+	if descending {
+		operator = "<"
+	}
+
+	return fmt.Sprintf("(%s) %s (SELECT %s FROM %s c WHERE c.id = ?)", k.on(alias), operator, k.on("c"), table)
+}
+
+// orderBy returns the ORDER BY clause sorting the table with the given alias
+func (k sortKeys) orderBy(alias string, descending bool) string {
+	direction := " ASC"
+	if descending {
+		direction = " DESC"
+	}
+
+	keys := make([]string, 0, len(k)+1)
+	for _, key := range k {
+		keys = append(keys, fmt.Sprintf(key, alias)+direction)
+	}
+
+	return "ORDER BY " + strings.Join(append(keys, alias+".id"+direction), ", ")
+}
+
+// likePattern returns the pattern of a LIKE condition matching the values containing the text
+func likePattern(text string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+	return "%" + escaped + "%"
+}
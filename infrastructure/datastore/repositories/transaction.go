@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// withTransaction runs fn inside a transaction, committing it if fn succeeds and rolling it back otherwise
+func withTransaction(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Join(errors.New("failed to begin transaction"), err)
+	}
+
+	err = fn(tx)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return errors.Join(err, errors.New("failed to rollback transaction"), rollbackErr)
+		}
+
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Join(errors.New("failed to commit transaction"), err)
+	}
+
+	return nil
+}
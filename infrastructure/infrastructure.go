@@ -6,38 +6,231 @@ import (
 	"log"
 	"net/http"
 	"taskflow/domain/entities"
+	"taskflow/domain/rules"
 	"taskflow/domain/usecases"
 	"taskflow/infrastructure/datastore/repositories"
+	"taskflow/infrastructure/filestore/hdstore"
+	"taskflow/infrastructure/mailer/smtpmailer"
+	"taskflow/infrastructure/pubsub/membroker"
 	"taskflow/infrastructure/router"
 	"taskflow/infrastructure/router/modules"
+	"taskflow/infrastructure/scheduler"
 
 	"github.com/gorilla/mux"
 )
 
-func SetupModules(r *mux.Router, config entities.Config) error {
+// SetupModules registers the routes of every module, and returns the scheduler of the background jobs, which is
+// started and stopped along with the server
+func SetupModules(r *mux.Router, config entities.Config) (*scheduler.Scheduler, error) {
 	// Repository repoSettings
 	repoSettings, err := repositories.NewRepositorySettings(config)
 	if err != nil {
-		return errors.Join(errors.New("failed to create settings repository"), err)
+		return nil, errors.Join(errors.New("failed to create settings repository"), err)
 	}
 
 	// Repositories
 	authRepository := repositories.NewAuthRepository(repoSettings)
 	boardRepository := repositories.NewBoardRepository(repoSettings)
+	attachmentRepository := repositories.NewAttachmentRepository(repoSettings)
+	activityRepository := repositories.NewActivityRepository(repoSettings)
+	commentRepository := repositories.NewCommentRepository(repoSettings)
+	webhookRepository := repositories.NewWebhookRepository(repoSettings)
+	notificationRepository := repositories.NewNotificationRepository(repoSettings)
+	emailRepository := repositories.NewEmailRepository(repoSettings)
+	jobRepository := repositories.NewJobRepository(repoSettings)
+	checklistRepository := repositories.NewChecklistRepository(repoSettings)
+	customFieldRepository := repositories.NewCustomFieldRepository(repoSettings)
+	labelRepository := repositories.NewLabelRepository(repoSettings)
+	searchRepository := repositories.NewSearchRepository(repoSettings)
+	templateRepository := repositories.NewTemplateRepository(repoSettings)
+	calendarRepository := repositories.NewCalendarRepository(repoSettings)
+	analyticsRepository := repositories.NewAnalyticsRepository(repoSettings)
+	automationRepository := repositories.NewAutomationRepository(repoSettings)
+	timeRepository := repositories.NewTimeRepository(repoSettings)
+	sprintRepository := repositories.NewSprintRepository(repoSettings)
+
+	// File storage
+	fileStorage := hdstore.NewHDFileStorage(config)
+
+	// Pub/sub broker for real-time events
+	broker := membroker.NewMemoryBroker()
+
+	// Mailer for the notification emails
+	smtpMailer := smtpmailer.NewSMTPMailer(config.SMTPConfig)
+
+	// Scheduler for the background jobs
+	jobScheduler := scheduler.NewScheduler(jobRepository)
 
 	// Use Cases
 	authUseCases := usecases.NewAuthUseCases(authRepository, config.Paseto.SecurityKey)
-	boardUseCases := usecases.NewBoardUseCases(boardRepository)
+	activityUseCases := usecases.NewActivityUseCases(activityRepository, boardRepository, broker)
+	boardUseCases := usecases.NewBoardUseCases(
+		boardRepository,
+		attachmentRepository,
+		authRepository,
+		checklistRepository,
+		customFieldRepository,
+		labelRepository,
+		fileStorage,
+		activityUseCases,
+		rules.TrashRetention(config.Trash.RetentionDays),
+	)
+	attachmentUseCases := usecases.NewAttachmentUseCases(
+		attachmentRepository,
+		boardRepository,
+		fileStorage,
+		config.Paseto.SecurityKey,
+		activityUseCases,
+	)
+	commentUseCases := usecases.NewCommentUseCases(commentRepository, boardRepository, activityUseCases)
+	checklistUseCases := usecases.NewChecklistUseCases(checklistRepository, boardRepository, activityUseCases)
+	customFieldUseCases := usecases.NewCustomFieldUseCases(customFieldRepository, boardRepository, activityUseCases)
+	labelUseCases := usecases.NewLabelUseCases(labelRepository, boardRepository, activityUseCases)
+	searchUseCases := usecases.NewSearchUseCases(searchRepository)
+	templateUseCases := usecases.NewTemplateUseCases(
+		templateRepository,
+		boardRepository,
+		labelRepository,
+		customFieldRepository,
+		activityUseCases,
+	)
+	importUseCases := usecases.NewImportUseCases(
+		boardRepository,
+		authRepository,
+		attachmentUseCases,
+		fileStorage,
+		activityUseCases,
+		config.Import.AttachmentsFolder,
+		config.Environment,
+	)
+	exportUseCases := usecases.NewExportUseCases(
+		boardUseCases,
+		boardRepository,
+		checklistRepository,
+		commentRepository,
+		customFieldRepository,
+		labelRepository,
+		fileStorage,
+		config.Environment,
+	)
+	csvUseCases := usecases.NewCSVUseCases(boardUseCases, boardRepository, activityUseCases)
+	webhookUseCases := usecases.NewWebhookUseCases(webhookRepository, boardRepository, activityUseCases)
+	emailUseCases := usecases.NewEmailUseCases(emailRepository, smtpMailer)
+	notificationUseCases := usecases.NewNotificationUseCases(
+		notificationRepository,
+		boardRepository,
+		authRepository,
+		activityRepository,
+		emailUseCases,
+		config.Paseto.SecurityKey,
+		config.Server.PublicURL,
+	)
+	jobUseCases := usecases.NewJobUseCases(jobRepository, jobScheduler)
+	calendarUseCases := usecases.NewCalendarUseCases(calendarRepository, boardRepository, config.Server.PublicURL)
+	analyticsUseCases := usecases.NewAnalyticsUseCases(analyticsRepository, boardRepository)
+	automationUseCases := usecases.NewAutomationUseCases(
+		automationRepository,
+		boardRepository,
+		labelRepository,
+		checklistRepository,
+		boardUseCases,
+		labelUseCases,
+		commentUseCases,
+		activityUseCases,
+	)
+	timeUseCases := usecases.NewTimeUseCases(timeRepository, boardRepository, activityUseCases)
+	sprintUseCases := usecases.NewSprintUseCases(sprintRepository, boardRepository, activityUseCases)
+
+	// Activity listeners
+	activityUseCases.AddListener(webhookUseCases)
+	activityUseCases.AddListener(notificationUseCases)
+	activityUseCases.AddListener(automationUseCases)
+
+	// Background jobs
+	err = errors.Join(
+		jobScheduler.Add("due_soon_reminders", rules.DueSoonRemindersSchedule, notificationUseCases.SendDueSoonReminders),
+		jobScheduler.Add("overdue_tasks", rules.OverdueTasksSchedule, boardUseCases.FlagOverdueTasks),
+		jobScheduler.Add("recurring_tasks", rules.RecurringTasksSchedule, boardUseCases.CreateRecurringTasks),
+		jobScheduler.Add("daily_digests", rules.DailyDigestsSchedule, notificationUseCases.SendDailyDigests),
+		jobScheduler.Add("expired_tokens", rules.ExpiredTokensSchedule, authUseCases.DeleteExpiredTokens),
+		jobScheduler.Add("job_runs", rules.JobRunsSchedule, jobUseCases.DeleteOldRuns),
+		jobScheduler.Add("trash_purge", rules.TrashPurgeSchedule, boardUseCases.PurgeTrash),
+		jobScheduler.Add(
+			"automation_executions",
+			rules.AutomationExecutionsSchedule,
+			automationUseCases.DeleteOldExecutions,
+		),
+	)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to add background jobs"), err)
+	}
 
 	// Modules
 	authModule := modules.NewAuthModule(authUseCases)
 	boardModule := modules.NewBoardModule(boardUseCases)
+	attachmentModule := modules.NewAttachmentModule(attachmentUseCases)
+	fileModule := modules.NewFileModule(attachmentUseCases)
+	activityModule := modules.NewActivityModule(activityUseCases)
+	commentModule := modules.NewCommentModule(commentUseCases)
+	checklistModule := modules.NewChecklistModule(checklistUseCases)
+	customFieldModule := modules.NewCustomFieldModule(customFieldUseCases)
+	labelModule := modules.NewLabelModule(labelUseCases)
+	searchModule := modules.NewSearchModule(searchUseCases)
+	templateModule := modules.NewTemplateModule(templateUseCases)
+	importModule := modules.NewImportModule(importUseCases)
+	exportModule := modules.NewExportModule(exportUseCases)
+	csvModule := modules.NewCSVModule(csvUseCases)
+	eventModule := modules.NewEventModule(activityUseCases)
+	webhookModule := modules.NewWebhookModule(webhookUseCases)
+	notificationModule := modules.NewNotificationModule(notificationUseCases)
+	unsubscribeModule := modules.NewUnsubscribeModule(notificationUseCases)
+	jobModule := modules.NewJobModule(jobUseCases)
+	calendarModule := modules.NewCalendarModule(calendarUseCases)
+	calendarFeedModule := modules.NewCalendarFeedModule(calendarUseCases)
+	analyticsModule := modules.NewAnalyticsModule(analyticsUseCases)
+	automationModule := modules.NewAutomationModule(automationUseCases)
+	timeModule := modules.NewTimeModule(timeUseCases)
+	sprintModule := modules.NewSprintModule(sprintUseCases)
 
 	apiSubRouter := r.PathPrefix("/api").Subrouter()
 
 	_, _ = authModule.Setup(apiSubRouter)
 
-	boardModule.Setup(apiSubRouter)
+	// Files and unsubscribe links are authorized by their signatures, calendar feeds by their tokens
+	fileModule.Setup(apiSubRouter)
+	unsubscribeModule.Setup(apiSubRouter)
+	calendarFeedModule.Setup(apiSubRouter)
+
+	// Operator routes require the integration token
+	integrationSubRouter := apiSubRouter.NewRoute().Subrouter()
+	integrationSubRouter.Use(modules.IntegrationMiddleware(config.IntegrationToken))
+
+	jobModule.Setup(integrationSubRouter)
+
+	// Routes below require an authenticated user
+	sessionSubRouter := apiSubRouter.NewRoute().Subrouter()
+	sessionSubRouter.Use(modules.SessionMiddleware(authUseCases))
+
+	boardModule.Setup(sessionSubRouter)
+	attachmentModule.Setup(sessionSubRouter)
+	activityModule.Setup(sessionSubRouter)
+	commentModule.Setup(sessionSubRouter)
+	checklistModule.Setup(sessionSubRouter)
+	customFieldModule.Setup(sessionSubRouter)
+	labelModule.Setup(sessionSubRouter)
+	searchModule.Setup(sessionSubRouter)
+	templateModule.Setup(sessionSubRouter)
+	importModule.Setup(sessionSubRouter)
+	exportModule.Setup(sessionSubRouter)
+	csvModule.Setup(sessionSubRouter)
+	eventModule.Setup(sessionSubRouter)
+	webhookModule.Setup(sessionSubRouter)
+	notificationModule.Setup(sessionSubRouter)
+	calendarModule.Setup(sessionSubRouter)
+	analyticsModule.Setup(sessionSubRouter)
+	automationModule.Setup(sessionSubRouter)
+	timeModule.Setup(sessionSubRouter)
+	sprintModule.Setup(sessionSubRouter)
 
 	r.Use(router.LoggingMiddleware)
 
@@ -57,5 +250,5 @@ func SetupModules(r *mux.Router, config entities.Config) error {
 		}
 	})
 
-	return nil
+	return jobScheduler, nil
 }
@@ -0,0 +1,11 @@
+package mailer
+
+import (
+	"context"
+	"taskflow/domain/entities"
+)
+
+type Mailer interface {
+	// Send delivers the email to its recipient, with both its plain-text and HTML bodies
+	Send(ctx context.Context, email entities.Email) error
+}
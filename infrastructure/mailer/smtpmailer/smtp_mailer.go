@@ -0,0 +1,188 @@
+package smtpmailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/infrastructure/mailer"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sendTimeout limits a whole SMTP conversation, so a stuck server does not block the email workers
+const sendTimeout = 30 * time.Second
+
+type smtpMailer struct {
+	config entities.SMTPConfig
+}
+
+// NewSMTPMailer creates a mailer that sends emails through the configured SMTP server, upgrading the connection with
+// STARTTLS when the server supports it
+func NewSMTPMailer(config entities.SMTPConfig) mailer.Mailer {
+	return smtpMailer{
+		config: config,
+	}
+}
+
+func (s smtpMailer) Send(ctx context.Context, email entities.Email) error {
+	if s.config.Host == "" {
+		return errors.New("smtp server not configured")
+	}
+
+	from, err := mail.ParseAddress(s.config.From)
+	if err != nil {
+		return errors.Join(errors.New("invalid sender address"), err)
+	}
+
+	to, err := mail.ParseAddress(email.To)
+	if err != nil {
+		return errors.Join(errors.New("invalid recipient address"), err)
+	}
+
+	message, err := buildMessage(from, to, email)
+	if err != nil {
+		return errors.Join(errors.New("failed to build message"), err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.config.Addr())
+	if err != nil {
+		return errors.Join(errors.New("failed to connect to smtp server"), err)
+	}
+
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return errors.Join(errors.New("failed to start smtp session"), err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: s.config.Host})
+		if err != nil {
+			return errors.Join(errors.New("failed to start tls"), err)
+		}
+	}
+
+	if s.config.User != "" {
+		err = client.Auth(smtp.PlainAuth("", s.config.User, s.config.Password, s.config.Host))
+		if err != nil {
+			return errors.Join(errors.New("failed to authenticate"), err)
+		}
+	}
+
+	err = client.Mail(from.Address)
+	if err != nil {
+		return err
+	}
+
+	err = client.Rcpt(to.Address)
+	if err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(message)
+	if err != nil {
+		return err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// buildMessage encodes the email as a multipart/alternative message with quoted-printable text and HTML parts
+func buildMessage(from *mail.Address, to *mail.Address, email entities.Email) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	err := writePart(parts, "text/plain; charset=utf-8", email.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writePart(parts, "text/html; charset=utf-8", email.HTML)
+	if err != nil {
+		return nil, err
+	}
+
+	err = parts.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	header := func(name string, value string) {
+		_, _ = fmt.Fprintf(&message, "%s: %s\r\n", name, value)
+	}
+
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%s@%s>", uuid.NewString(), domainOf(from.Address)))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "multipart/alternative; boundary="+parts.Boundary())
+	if email.UnsubscribeURL != "" {
+		header("List-Unsubscribe", "<"+email.UnsubscribeURL+">")
+		header("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+func writePart(parts *multipart.Writer, contentType string, content string) error {
+	part, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	writer := quotedprintable.NewWriter(part)
+	_, err = writer.Write([]byte(content))
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+func domainOf(address string) string {
+	index := strings.LastIndex(address, "@")
+	if index < 0 {
+		return "localhost"
+	}
+
+	return address[index+1:]
+}
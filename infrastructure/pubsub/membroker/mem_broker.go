@@ -0,0 +1,88 @@
+package membroker
+
+import (
+	"context"
+	"sync"
+	"taskflow/infrastructure/pubsub"
+)
+
+// subscriptionBuffer is the number of messages a subscriber can fall behind before being closed
+const subscriptionBuffer = 64
+
+// memoryBroker delivers messages to the subscribers of this process only
+type memoryBroker struct {
+	mutex  *sync.RWMutex
+	topics map[string]map[*subscription]struct{}
+}
+
+func NewMemoryBroker() pubsub.Broker {
+	return memoryBroker{
+		mutex:  &sync.RWMutex{},
+		topics: make(map[string]map[*subscription]struct{}),
+	}
+}
+
+func (m memoryBroker) Publish(_ context.Context, topic string, payload []byte) error {
+	var slow []*subscription
+
+	m.mutex.RLock()
+	for s := range m.topics[topic] {
+		select {
+		case s.messages <- payload:
+		default:
+			slow = append(slow, s)
+		}
+	}
+	m.mutex.RUnlock()
+
+	// Closing requires the write lock, so it can't be done while iterating
+	for _, s := range slow {
+		s.Close()
+	}
+
+	return nil
+}
+
+func (m memoryBroker) Subscribe(_ context.Context, topic string) (pubsub.Subscription, error) {
+	s := &subscription{
+		broker:   m,
+		topic:    topic,
+		messages: make(chan []byte, subscriptionBuffer),
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.topics[topic] == nil {
+		m.topics[topic] = make(map[*subscription]struct{})
+	}
+	m.topics[topic][s] = struct{}{}
+
+	return s, nil
+}
+
+type subscription struct {
+	broker   memoryBroker
+	topic    string
+	messages chan []byte
+	once     sync.Once
+}
+
+func (s *subscription) Messages() <-chan []byte {
+	return s.messages
+}
+
+func (s *subscription) Close() {
+	s.once.Do(func() {
+		s.broker.mutex.Lock()
+		defer s.broker.mutex.Unlock()
+
+		delete(s.broker.topics[s.topic], s)
+		if len(s.broker.topics[s.topic]) == 0 {
+			delete(s.broker.topics, s.topic)
+		}
+
+		// Publishers only send while holding the read lock, so nothing can send on the closed channel
+		close(s.messages)
+	})
+}
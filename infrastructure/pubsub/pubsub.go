@@ -0,0 +1,23 @@
+package pubsub
+
+import "context"
+
+// Broker delivers the messages published on a topic to all of its current subscribers
+//
+// Delivery is best-effort: a subscriber that can't keep up is closed, so it can resume from the last message it got
+// using another source (such as the database).
+type Broker interface {
+	// Publish sends the payload to all subscribers of the topic
+	Publish(ctx context.Context, topic string, payload []byte) error
+
+	// Subscribe starts receiving the messages published on the topic, until the subscription is closed
+	Subscribe(ctx context.Context, topic string) (Subscription, error)
+}
+
+type Subscription interface {
+	// Messages returns the channel of received payloads. It is closed when the subscription ends.
+	Messages() <-chan []byte
+
+	// Close ends the subscription. It is safe to call it more than once.
+	Close()
+}
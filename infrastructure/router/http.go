@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"taskflow/domain/entities"
+	"taskflow/domain/status_codes"
 )
 
 func Write(w http.ResponseWriter, v any) error {
@@ -21,6 +23,21 @@ func Write(w http.ResponseWriter, v any) error {
 	return nil
 }
 
+// WriteStatus writes the use case status code and message, along with the optional data
+func WriteStatus(w http.ResponseWriter, statusCode status_codes.StatusCode, data any) error {
+	response := struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+		Data    any    `json:"data,omitempty"`
+	}{
+		Status:  statusCode.Int(),
+		Message: statusCode.String(),
+		Data:    data,
+	}
+
+	return Write(w, response)
+}
+
 func WriteInternalError(w http.ResponseWriter) {
 	http.Error(w, "Internal server error", http.StatusInternalServerError)
 }
@@ -36,3 +53,21 @@ func WriteUnauthorized(w http.ResponseWriter) {
 func WriteForbidden(w http.ResponseWriter) {
 	http.Error(w, "Forbidden", http.StatusForbidden)
 }
+
+func WriteNotFound(w http.ResponseWriter) {
+	http.Error(w, "Not found", http.StatusNotFound)
+}
+
+// WriteError writes the HTTP error matching the given use case error, defaulting to an internal error
+func WriteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entities.ErrNotFound):
+		WriteNotFound(w)
+	case errors.Is(err, entities.ErrForbidden):
+		WriteForbidden(w)
+	case errors.Is(err, entities.ErrBadRequest):
+		WriteBadRequest(w)
+	default:
+		WriteInternalError(w)
+	}
+}
@@ -0,0 +1,163 @@
+package modules
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type activityModule struct {
+	activityUseCases usecases.ActivityUseCases
+	name             string
+	path             string
+}
+
+func NewActivityModule(activityUseCases usecases.ActivityUseCases) router.Module {
+	return activityModule{
+		activityUseCases: activityUseCases,
+		name:             "Activity",
+		path:             "/activity",
+	}
+}
+
+func (a activityModule) Name() string {
+	return a.name
+}
+
+func (a activityModule) Path() string {
+	return a.path
+}
+
+func (a activityModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "List the activity feed of a board",
+			Handler:     a.board,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}",
+			Description: "List the activity feed of a task",
+			Handler:     a.task,
+			HttpMethods: []string{http.MethodGet},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(a.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (a activityModule) board(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	filter, err := parseActivityFilter(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse activity filter", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	filter.IDBoard = id
+	activities, err := a.activityUseCases.GetBoardActivities(ctx, user, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get board activities", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeActivityPage(ctx, w, activities, filter)
+}
+
+func (a activityModule) task(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	filter, err := parseActivityFilter(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse activity filter", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	filter.IDTask = id
+	activities, err := a.activityUseCases.GetTaskActivities(ctx, user, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get task activities", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeActivityPage(ctx, w, activities, filter)
+}
+
+// parseActivityFilter reads the actor, action, before and limit query parameters
+func parseActivityFilter(query url.Values) (entities.ActivityFilter, error) {
+	var filter entities.ActivityFilter
+	var err error
+
+	if actor := query.Get("actor"); actor != "" {
+		filter.IDActor, err = strconv.Atoi(actor)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if before := query.Get("before"); before != "" {
+		filter.BeforeID, err = strconv.Atoi(before)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	filter.Action = entities.ActivityAction(query.Get("action"))
+	return filter, nil
+}
+
+// writeActivityPage writes the activities along with the cursor of the next page, if there may be one
+func writeActivityPage(
+	ctx context.Context,
+	w http.ResponseWriter,
+	activities []entities.Activity,
+	filter entities.ActivityFilter,
+) {
+	response := struct {
+		Activities []entities.Activity `json:"activities"`
+		NextBefore int                 `json:"next_before,omitempty"`
+	}{
+		Activities: activities,
+	}
+
+	if len(activities) > 0 && len(activities) == rules.PageLimit(filter.Limit) {
+		response.NextBefore = activities[len(activities)-1].ID
+	}
+
+	write(ctx, w, response)
+}
@@ -0,0 +1,200 @@
+package modules
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type analyticsModule struct {
+	analyticsUseCases usecases.AnalyticsUseCases
+	name              string
+	path              string
+}
+
+func NewAnalyticsModule(analyticsUseCases usecases.AnalyticsUseCases) router.Module {
+	return analyticsModule{
+		analyticsUseCases: analyticsUseCases,
+		name:              "Analytics",
+		path:              "/analytics",
+	}
+}
+
+func (a analyticsModule) Name() string {
+	return a.name
+}
+
+func (a analyticsModule) Path() string {
+	return a.path
+}
+
+func (a analyticsModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/boards/{id:[0-9]+}/cumulative-flow",
+			Description: "Get how many tasks each task list of a board held at the end of every day of a range",
+			Handler:     a.getCumulativeFlow,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/boards/{id:[0-9]+}/flow-times",
+			Description: "Get the lead and cycle time percentiles of the tasks of a board completed over a range",
+			Handler:     a.getFlowTimes,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/boards/{id:[0-9]+}/throughput",
+			Description: "Get how many tasks of a board were completed each week of a range",
+			Handler:     a.getThroughput,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/boards/{id:[0-9]+}/overdue",
+			Description: "Get how many unfinished tasks of a board are overdue, by assignee",
+			Handler:     a.getOverdue,
+			HttpMethods: []string{http.MethodGet},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(a.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (a analyticsModule) getCumulativeFlow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	dateRange, err := parseAnalyticsRange(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse analytics range", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	flow, err := a.analyticsUseCases.GetCumulativeFlow(ctx, user, id, dateRange)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get cumulative flow", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, flow)
+}
+
+// getFlowTimes reads the task list starting the cycle of the tasks from the start_list query parameter
+func (a analyticsModule) getFlowTimes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	dateRange, err := parseAnalyticsRange(query)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse analytics range", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	var startListID int
+	if value := query.Get("start_list"); value != "" {
+		startListID, err = strconv.Atoi(value)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to parse start list", "cause", err)
+			router.WriteBadRequest(w)
+			return
+		}
+	}
+
+	times, err := a.analyticsUseCases.GetFlowTimes(ctx, user, id, dateRange, startListID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get flow times", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, times)
+}
+
+func (a analyticsModule) getThroughput(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	dateRange, err := parseAnalyticsRange(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse analytics range", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	weeks, err := a.analyticsUseCases.GetThroughput(ctx, user, id, dateRange)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get throughput", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, weeks)
+}
+
+func (a analyticsModule) getOverdue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	counts, err := a.analyticsUseCases.GetOverdue(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get overdue tasks", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, counts)
+}
+
+// parseAnalyticsRange reads the from and to query parameters, days such as "2024-05-31" or RFC 3339 times
+func parseAnalyticsRange(query url.Values) (entities.AnalyticsRange, error) {
+	var dateRange entities.AnalyticsRange
+
+	if value := query.Get("from"); value != "" {
+		from, err := parseTimeParam(value)
+		if err != nil {
+			return dateRange, err
+		}
+
+		dateRange.From = *from
+	}
+
+	if value := query.Get("to"); value != "" {
+		to, err := parseTimeParam(value)
+		if err != nil {
+			return dateRange, err
+		}
+
+		dateRange.To = *to
+	}
+
+	return dateRange, nil
+}
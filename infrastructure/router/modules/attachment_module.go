@@ -0,0 +1,317 @@
+package modules
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type attachmentModule struct {
+	attachmentUseCases usecases.AttachmentUseCases
+	name               string
+	path               string
+}
+
+func NewAttachmentModule(attachmentUseCases usecases.AttachmentUseCases) router.Module {
+	return attachmentModule{
+		attachmentUseCases: attachmentUseCases,
+		name:               "Attachments",
+		path:               "/attachments",
+	}
+}
+
+func (a attachmentModule) Name() string {
+	return a.name
+}
+
+func (a attachmentModule) Path() string {
+	return a.path
+}
+
+func (a attachmentModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/upload",
+			Description: "Upload a file to a task",
+			Handler:     a.upload,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Download an attachment",
+			Handler:     a.download,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/{id:[0-9]+}/thumbnail",
+			Description: "Download an image attachment thumbnail",
+			Handler:     a.thumbnail,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/{id:[0-9]+}/link",
+			Description: "Get a signed URL granting temporary access to an attachment without a session",
+			Handler:     a.link,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Delete an attachment",
+			Handler:     a.delete,
+			HttpMethods: []string{http.MethodDelete},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(a.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (a attachmentModule) upload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	type Response struct {
+		Status     int                  `json:"status"`
+		Message    string               `json:"message"`
+		Attachment *entities.Attachment `json:"attachment,omitempty"`
+	}
+
+	// Leave room for the other multipart fields
+	r.Body = http.MaxBytesReader(w, r.Body, rules.AttachmentMaxSize+1<<20)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			statusCode := status_codes.AttachmentFileTooLarge
+			err = router.Write(w, Response{Status: statusCode.Int(), Message: statusCode.String()})
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to write response", "cause", err)
+			}
+			return
+		}
+
+		slog.ErrorContext(ctx, "failed to read form file", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+	defer file.Close()
+
+	taskID, err := strconv.Atoi(r.FormValue("task_id"))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse task id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read form file", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	attachment, statusCode, err := a.attachmentUseCases.UploadAttachment(ctx, user, taskID, header.Filename, data)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to upload attachment", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	response := Response{
+		Status:     statusCode.Int(),
+		Message:    statusCode.String(),
+		Attachment: attachment,
+	}
+
+	err = router.Write(w, response)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to write response", "cause", err)
+	}
+}
+
+func (a attachmentModule) download(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	id, err := router.GetIntVar(r, "id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse attachment id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	attachment, file, err := a.attachmentUseCases.GetAttachmentFile(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get attachment file", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+	defer file.Close()
+
+	serveAttachment(w, r, attachment, file)
+}
+
+func (a attachmentModule) thumbnail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	id, err := router.GetIntVar(r, "id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse attachment id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	size := entities.ThumbnailSize(r.URL.Query().Get("size"))
+	if size == "" {
+		size = entities.ThumbnailMedium
+	}
+
+	thumbnail, file, err := a.attachmentUseCases.GetThumbnailFile(ctx, user, id, size)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get thumbnail file", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+	defer file.Close()
+
+	serveThumbnail(w, r, thumbnail, file)
+}
+
+func (a attachmentModule) link(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	id, err := router.GetIntVar(r, "id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse attachment id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	// Optional, in seconds
+	var ttl time.Duration
+	if expiresIn := r.URL.Query().Get("expires_in"); expiresIn != "" {
+		seconds, err := strconv.Atoi(expiresIn)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to parse expires_in", "cause", err)
+			router.WriteBadRequest(w)
+			return
+		}
+		ttl = time.Duration(seconds) * time.Second
+	}
+
+	signedURL, err := a.attachmentUseCases.GetSignedURL(ctx, user, id, ttl)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get signed url", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	err = router.Write(w, signedURL)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to write response", "cause", err)
+	}
+}
+
+func (a attachmentModule) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	id, err := router.GetIntVar(r, "id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse attachment id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	err = a.attachmentUseCases.DeleteAttachment(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete attachment", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveAttachment writes the attachment file, supporting range and conditional requests. Only the images are displayed
+// inline: any other file, such as an HTML page, is downloaded rather than rendered with the origin of the API, and its
+// content is never sniffed nor allowed to run scripts.
+func serveAttachment(w http.ResponseWriter, r *http.Request, attachment *entities.Attachment, file *os.File) {
+	disposition := "attachment"
+	if attachment.IsImage() {
+		disposition = "inline"
+	}
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{
+		"filename": attachment.FileName,
+	}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+
+	// Attachments are never modified, so the UUID is a strong validator for If-Range when resuming downloads
+	w.Header().Set("ETag", `"`+attachment.UUID+`"`)
+	http.ServeContent(w, r, attachment.FileName, attachment.ModifiedAt, file)
+}
+
+// serveThumbnail writes the thumbnail file, supporting range and conditional requests
+func serveThumbnail(w http.ResponseWriter, r *http.Request, thumbnail *entities.Thumbnail, file *os.File) {
+	var modTime time.Time
+	stat, err := file.Stat()
+	if err == nil {
+		modTime = stat.ModTime()
+	}
+
+	w.Header().Set("Content-Type", thumbnail.ContentType)
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", modTime, file)
+}
@@ -0,0 +1,55 @@
+package modules
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"taskflow/domain/entities"
+	"testing"
+)
+
+func TestServeAttachmentHeaders(t *testing.T) {
+	tests := []struct {
+		contentType string
+		disposition string
+	}{
+		{contentType: "image/png", disposition: `inline; filename=file`},
+		{contentType: "image/jpeg", disposition: `inline; filename=file`},
+		{contentType: "text/html; charset=utf-8", disposition: `attachment; filename=file`},
+		{contentType: "image/svg+xml", disposition: `attachment; filename=file`},
+		{contentType: "application/pdf", disposition: `attachment; filename=file`},
+	}
+
+	path := filepath.Join(t.TempDir(), "original")
+	err := os.WriteFile(path, []byte("<script>alert(1)</script>"), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+
+		attachment := &entities.Attachment{UUID: "uuid", FileName: "file", ContentType: tt.contentType}
+		w := httptest.NewRecorder()
+		serveAttachment(w, httptest.NewRequest(http.MethodGet, "/api/attachments/1", nil), attachment, file)
+		file.Close()
+
+		header := w.Header()
+		if got := header.Get("Content-Disposition"); got != tt.disposition {
+			t.Errorf("%s: Content-Disposition = %q, want %q", tt.contentType, got, tt.disposition)
+		}
+
+		if got := header.Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%s: Content-Type = %q", tt.contentType, got)
+		}
+
+		if header.Get("X-Content-Type-Options") != "nosniff" || header.Get("Content-Security-Policy") != "sandbox" {
+			t.Errorf("%s: X-Content-Type-Options = %q, Content-Security-Policy = %q", tt.contentType,
+				header.Get("X-Content-Type-Options"), header.Get("Content-Security-Policy"))
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package modules
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"log/slog"
 	"net/http"
@@ -49,6 +50,12 @@ func (a authModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router)
 			Handler:     a.register,
 			HttpMethods: []string{http.MethodPost},
 		},
+		{
+			Path:        "/logout",
+			Description: "Revoke the bearer token of the request",
+			Handler:     a.logout,
+			HttpMethods: []string{http.MethodPost},
+		},
 	}
 
 	for _, d := range defs {
@@ -57,6 +64,31 @@ func (a authModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router)
 
 	return defs, r
 }
+
+// IntegrationMiddleware returns the middleware that only lets through the requests bearing the integration token of
+// the configuration, used by the operators' tooling. Every request is rejected when no token is configured.
+func IntegrationMiddleware(integrationToken string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+			if integrationToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(integrationToken)) != 1 {
+				slog.ErrorContext(r.Context(), "invalid integration token")
+				router.WriteUnauthorized(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SessionMiddleware returns the middleware that authenticates the request user, either with basic auth or with a
+// bearer token, and stores it in the request's context
+func SessionMiddleware(authUseCases usecases.AuthUseCases) mux.MiddlewareFunc {
+	return authModule{authUseCases: authUseCases}.sessionMiddleware
+}
+
 func (a authModule) sessionMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -198,3 +230,23 @@ func (a authModule) register(w http.ResponseWriter, r *http.Request) {
 		slog.ErrorContext(ctx, "failed to write response", "cause", err)
 	}
 }
+
+func (a authModule) logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		slog.ErrorContext(ctx, "no token found in the request")
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	statusCode, err := a.authUseCases.Logout(ctx, token)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to logout user", "cause", err)
+		router.WriteInternalError(w)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
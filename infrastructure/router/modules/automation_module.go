@@ -0,0 +1,199 @@
+package modules
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type automationModule struct {
+	automationUseCases usecases.AutomationUseCases
+	name               string
+	path               string
+}
+
+func NewAutomationModule(automationUseCases usecases.AutomationUseCases) router.Module {
+	return automationModule{
+		automationUseCases: automationUseCases,
+		name:               "Automations",
+		path:               "/automations",
+	}
+}
+
+func (am automationModule) Name() string {
+	return am.name
+}
+
+func (am automationModule) Path() string {
+	return am.path
+}
+
+func (am automationModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "List the automation rules of a board",
+			Handler:     am.list,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "Create an automation rule on a board",
+			Handler:     am.create,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Update an automation rule",
+			Handler:     am.update,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Delete an automation rule",
+			Handler:     am.delete,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/{id:[0-9]+}/executions",
+			Description: "List the executions of an automation rule",
+			Handler:     am.executions,
+			HttpMethods: []string{http.MethodGet},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(am.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (am automationModule) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	automationRules, err := am.automationUseCases.GetRules(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get automation rules", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, automationRules)
+}
+
+func (am automationModule) create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var rule entities.AutomationRule
+	err := json.NewDecoder(r.Body).Decode(&rule)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	rule.IDBoard = id
+	created, statusCode, err := am.automationUseCases.CreateRule(ctx, user, rule)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create automation rule", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (am automationModule) update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var rule entities.AutomationRule
+	err := json.NewDecoder(r.Body).Decode(&rule)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	rule.ID = id
+	statusCode, err := am.automationUseCases.UpdateRule(ctx, user, rule)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update automation rule", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (am automationModule) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := am.automationUseCases.DeleteRule(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete automation rule", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (am automationModule) executions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var before, limit int
+	var err error
+	query := r.URL.Query()
+	if value := query.Get("before"); value != "" {
+		before, err = strconv.Atoi(value)
+	}
+	if value := query.Get("limit"); value != "" && err == nil {
+		limit, err = strconv.Atoi(value)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse pagination", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	executions, err := am.automationUseCases.GetExecutions(ctx, user, id, before, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get automation executions", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, executions)
+}
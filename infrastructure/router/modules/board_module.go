@@ -1,10 +1,20 @@
 package modules
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
 	"taskflow/domain/usecases"
 	"taskflow/infrastructure/router"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -35,7 +45,7 @@ func (b boardModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router
 	defs := []router.RouteDefinition{
 		{
 			Path:        "/list",
-			Description: "List boards",
+			Description: "List the active or archived boards of the user, filtered by title and sorted by date or title",
 			Handler:     b.list,
 			HttpMethods: []string{http.MethodGet},
 		},
@@ -45,6 +55,192 @@ func (b boardModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router
 			Handler:     b.create,
 			HttpMethods: []string{http.MethodPost},
 		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Get a board with its members and its active or archived task lists and tasks",
+			Handler:     b.get,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Update a board",
+			Handler:     b.update,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Delete a board",
+			Handler:     b.delete,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/{id:[0-9]+}/archive",
+			Description: "Archive a board",
+			Handler:     b.setArchived(b.boardUseCases.SetBoardArchived, true),
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}/archive",
+			Description: "Unarchive a board",
+			Handler:     b.setArchived(b.boardUseCases.SetBoardArchived, false),
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/trash",
+			Description: "List the deleted boards, task lists and tasks the user can restore",
+			Handler:     b.getTrash,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/trash/{type:board|task_list|task}/{id:[0-9]+}/restore",
+			Description: "Restore a deleted board, task list or task",
+			Handler:     b.restoreTrashItem,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}/duplicate",
+			Description: "Copy a board, optionally with its tasks, members and attachments",
+			Handler:     b.duplicate,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}/members",
+			Description: "Add a member to a board",
+			Handler:     b.addMember,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}/members/{user_id:[0-9]+}",
+			Description: "Remove a member from a board",
+			Handler:     b.removeMember,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/{id:[0-9]+}/lists",
+			Description: "Create a task list on a board",
+			Handler:     b.createTaskList,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/lists/{id:[0-9]+}",
+			Description: "Update a task list",
+			Handler:     b.updateTaskList,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/lists/{id:[0-9]+}/move",
+			Description: "Move a task list to another position",
+			Handler:     b.moveTaskList,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/lists/{id:[0-9]+}",
+			Description: "Delete a task list",
+			Handler:     b.deleteTaskList,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/lists/{id:[0-9]+}/archive",
+			Description: "Archive a task list along with its tasks",
+			Handler:     b.setArchived(b.boardUseCases.SetTaskListArchived, true),
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/lists/{id:[0-9]+}/archive",
+			Description: "Unarchive a task list",
+			Handler:     b.setArchived(b.boardUseCases.SetTaskListArchived, false),
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/lists/{id:[0-9]+}/tasks",
+			Description: "Create a task on a task list",
+			Handler:     b.createTask,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}",
+			Description: "Get a task with its assignees, attachments, checklists and dependencies",
+			Handler:     b.getTask,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}",
+			Description: "Update a task",
+			Handler:     b.updateTask,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/move",
+			Description: "Move a task to another task list or position",
+			Handler:     b.moveTask,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}",
+			Description: "Delete a task",
+			Handler:     b.deleteTask,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/archive",
+			Description: "Archive a task",
+			Handler:     b.setTaskArchived(true),
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/archive",
+			Description: "Unarchive a task",
+			Handler:     b.setTaskArchived(false),
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/assignees",
+			Description: "Assign a board member to a task",
+			Handler:     b.assignTask,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/assignees/{user_id:[0-9]+}",
+			Description: "Remove an assignee from a task",
+			Handler:     b.unassignTask,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/recurrence",
+			Description: "Make a task repeat, creating its next occurrence once it is completed or due",
+			Handler:     b.setTaskRecurrence,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/recurrence",
+			Description: "Stop a task from repeating",
+			Handler:     b.removeTaskRecurrence,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/blockers",
+			Description: "Make a task of the same board block a task",
+			Handler:     b.addTaskDependency,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/blockers/{blocker_id:[0-9]+}",
+			Description: "Stop a task from blocking a task",
+			Handler:     b.removeTaskDependency,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/{id:[0-9]+}/tasks",
+			Description: "Search the tasks of a board by assignee, label, due date, status, text and custom fields",
+			Handler:     b.searchTasks,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/{id:[0-9]+}/dependencies",
+			Description: "Get the dependency graph of a board",
+			Handler:     b.getDependencyGraph,
+			HttpMethods: []string{http.MethodGet},
+		},
 	}
 
 	for _, d := range defs {
@@ -57,31 +253,859 @@ func (b boardModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router
 func (b boardModule) list(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	boards, err := b.boardUseCases.GetBoards(ctx)
+	user, err := router.GetAppUser(r)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to get boards", "cause", err)
-		router.WriteInternalError(w)
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
 		return
 	}
 
-	err = router.Write(w, boards)
+	query := r.URL.Query()
+	filter := entities.BoardFilter{
+		Text:       query.Get("q"),
+		Archived:   query.Get("archived") == "true",
+		Sort:       entities.BoardSort(query.Get("sort")),
+		Descending: query.Get("order") == "desc",
+	}
+
+	filter.AfterID, filter.Limit, err = parsePage(query)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to write response", "cause", err)
+		slog.ErrorContext(ctx, "failed to parse pagination", "cause", err)
+		router.WriteBadRequest(w)
+		return
 	}
+
+	boards, statusCode, err := b.boardUseCases.GetBoards(ctx, user, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get boards", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	if statusCode != status_codes.BoardSuccess {
+		writeStatus(ctx, w, statusCode, nil)
+		return
+	}
+
+	response := struct {
+		Boards    []entities.Board `json:"boards"`
+		NextAfter int              `json:"next_after,omitempty"`
+	}{
+		Boards: boards,
+	}
+
+	if len(boards) > 0 && len(boards) == rules.PageLimit(filter.Limit) {
+		response.NextAfter = boards[len(boards)-1].ID
+	}
+
+	write(ctx, w, response)
 }
 
 func (b boardModule) create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	boards, err := b.boardUseCases.GetBoards(ctx)
+	user, err := router.GetAppUser(r)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to get boards", "cause", err)
-		router.WriteInternalError(w)
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	var board entities.Board
+	err = json.NewDecoder(r.Body).Decode(&board)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	created, statusCode, err := b.boardUseCases.CreateBoard(ctx, user, board)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create board", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (b boardModule) get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	archived := r.URL.Query().Get("archived") == "true"
+	board, err := b.boardUseCases.GetBoard(ctx, user, id, archived)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get board", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, board)
+}
+
+func (b boardModule) update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var board entities.Board
+	err := json.NewDecoder(r.Body).Decode(&board)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	board.ID = id
+	statusCode, err := b.boardUseCases.UpdateBoard(ctx, user, board)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update board", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := b.boardUseCases.DeleteBoard(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete board", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+// setArchived returns the handler archiving or unarchiving the board, task list or task with the ID of the path
+func (b boardModule) setArchived(
+	setArchived func(
+		ctx context.Context,
+		user *entities.User,
+		id int,
+		archived bool,
+	) (status_codes.BoardStatusCode, error),
+	archived bool,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		user, id, ok := readUserAndID(w, r, "id")
+		if !ok {
+			return
+		}
+
+		statusCode, err := setArchived(ctx, user, id, archived)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to archive", "archived", archived, "cause", err)
+			router.WriteError(w, err)
+			return
+		}
+
+		writeStatus(ctx, w, statusCode, nil)
+	}
+}
+
+// setTaskArchived is the same as setArchived for tasks, whose unarchiving is flagged when the board only warns about
+// the WIP limit of the task list
+func (b boardModule) setTaskArchived(archived bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		user, id, ok := readUserAndID(w, r, "id")
+		if !ok {
+			return
+		}
+
+		violation, statusCode, err := b.boardUseCases.SetTaskArchived(ctx, user, id, archived)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to archive", "archived", archived, "cause", err)
+			router.WriteError(w, err)
+			return
+		}
+
+		writeWIPViolation(ctx, w, statusCode, violation)
+	}
+}
+
+func (b boardModule) getTrash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	items, err := b.boardUseCases.GetTrash(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get trash", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, items)
+}
+
+func (b boardModule) restoreTrashItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	itemType := entities.TrashItemType(mux.Vars(r)["type"])
+	violation, statusCode, err := b.boardUseCases.RestoreTrashItem(ctx, user, itemType, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to restore trash item", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeWIPViolation(ctx, w, statusCode, violation)
+}
+
+// duplicate reads the title of the copy, which keeps the title of the board when not given, and the options
+func (b boardModule) duplicate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Title string `json:"title"`
+		entities.DuplicateOptions
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	duplicated, statusCode, err := b.boardUseCases.DuplicateBoard(ctx, user, id, body.Title, body.DuplicateOptions)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to duplicate board", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, duplicated)
+}
+
+func (b boardModule) addMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Email string `json:"email"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := b.boardUseCases.AddBoardMember(ctx, user, id, body.Email)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to add board member", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) removeMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	memberID, err := router.GetIntVar(r, "user_id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse user id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := b.boardUseCases.RemoveBoardMember(ctx, user, id, memberID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to remove board member", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) createTaskList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var taskList entities.TaskList
+	err := json.NewDecoder(r.Body).Decode(&taskList)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	taskList.IDBoard = id
+	created, statusCode, err := b.boardUseCases.CreateTaskList(ctx, user, taskList)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create task list", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (b boardModule) updateTaskList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var taskList entities.TaskList
+	err := json.NewDecoder(r.Body).Decode(&taskList)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	taskList.ID = id
+	statusCode, err := b.boardUseCases.UpdateTaskList(ctx, user, taskList)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update task list", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) moveTaskList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Position int `json:"position"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := b.boardUseCases.MoveTaskList(ctx, user, id, body.Position)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to move task list", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) deleteTaskList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := b.boardUseCases.DeleteTaskList(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete task list", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) createTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var task entities.Task
+	err := json.NewDecoder(r.Body).Decode(&task)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	task.IDTaskList = id
+	created, statusCode, err := b.boardUseCases.CreateTask(ctx, user, task)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create task", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (b boardModule) getTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	task, err := b.boardUseCases.GetTask(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get task", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, task)
+}
+
+func (b boardModule) updateTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var task entities.Task
+	err := json.NewDecoder(r.Body).Decode(&task)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	task.ID = id
+	statusCode, err := b.boardUseCases.UpdateTask(ctx, user, task)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update task", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) moveTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		IDTaskList int `json:"id_task_list"`
+		Position   int `json:"position"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	violation, statusCode, err := b.boardUseCases.MoveTask(ctx, user, id, body.IDTaskList, body.Position)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to move task", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeWIPViolation(ctx, w, statusCode, violation)
+}
+
+// writeWIPViolation writes the status code, along with the violation of the WIP limit of the task list when the board
+// only warns about it
+func writeWIPViolation(
+	ctx context.Context,
+	w http.ResponseWriter,
+	statusCode status_codes.BoardStatusCode,
+	violation *entities.WIPViolation,
+) {
+	if violation != nil {
+		response := struct {
+			WIPViolation *entities.WIPViolation `json:"wip_violation"`
+		}{
+			WIPViolation: violation,
+		}
+
+		writeStatus(ctx, w, statusCode, response)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) deleteTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := b.boardUseCases.DeleteTask(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete task", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) assignTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		IDUser int `json:"id_user"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := b.boardUseCases.AssignTask(ctx, user, id, body.IDUser)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to assign task", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) unassignTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	assigneeID, err := router.GetIntVar(r, "user_id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse user id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := b.boardUseCases.UnassignTask(ctx, user, id, assigneeID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to unassign task", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) setTaskRecurrence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var recurrence entities.Recurrence
+	err := json.NewDecoder(r.Body).Decode(&recurrence)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
 		return
 	}
 
-	err = router.Write(w, boards)
+	statusCode, err := b.boardUseCases.SetTaskRecurrence(ctx, user, id, &recurrence)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to write response", "cause", err)
+		slog.ErrorContext(ctx, "failed to set task recurrence", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) removeTaskRecurrence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
 	}
+
+	statusCode, err := b.boardUseCases.SetTaskRecurrence(ctx, user, id, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to remove task recurrence", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) addTaskDependency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		IDBlocker int `json:"id_blocker"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := b.boardUseCases.AddTaskDependency(ctx, user, id, body.IDBlocker)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to add task dependency", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) removeTaskDependency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	blockerID, err := router.GetIntVar(r, "blocker_id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse blocker id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := b.boardUseCases.RemoveTaskDependency(ctx, user, id, blockerID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to remove task dependency", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (b boardModule) searchTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	filter, err := parseTaskFilter(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse task filter", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	filter.IDBoard = id
+	tasks, statusCode, err := b.boardUseCases.SearchTasks(ctx, user, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to search tasks", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	if statusCode != status_codes.BoardSuccess {
+		writeStatus(ctx, w, statusCode, nil)
+		return
+	}
+
+	response := struct {
+		Tasks     []entities.Task `json:"tasks"`
+		NextAfter int             `json:"next_after,omitempty"`
+	}{
+		Tasks: tasks,
+	}
+
+	if len(tasks) > 0 && len(tasks) == rules.PageLimit(filter.Limit) {
+		response.NextAfter = tasks[len(tasks)-1].ID
+	}
+
+	write(ctx, w, response)
+}
+
+func (b boardModule) getDependencyGraph(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	graph, err := b.boardUseCases.GetDependencyGraph(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get dependency graph", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, graph)
+}
+
+// parseTaskFilter reads the assignee, label, due_after, due_before, status, q, sort, order, after and limit query
+// parameters, along with the custom field filters given as field.<id>=<value>. Tasks are sorted by the value of a
+// custom field with sort=field:<id>.
+func parseTaskFilter(query url.Values) (entities.TaskFilter, error) {
+	filter := entities.TaskFilter{
+		Text:       query.Get("q"),
+		Archived:   query.Get("archived") == "true",
+		Sort:       entities.TaskSort(query.Get("sort")),
+		Descending: query.Get("order") == "desc",
+	}
+
+	var err error
+	if value := query.Get("assignee"); value != "" {
+		filter.IDAssignee, err = strconv.Atoi(value)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if value := query.Get("label"); value != "" {
+		filter.IDLabel, err = strconv.Atoi(value)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if value := query.Get("due_after"); value != "" {
+		filter.DueAfter, err = parseTimeParam(value)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if value := query.Get("due_before"); value != "" {
+		filter.DueBefore, err = parseTimeParam(value)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if value := query.Get("status"); value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, err
+		}
+
+		if status != entities.TaskNotFinished && status != entities.TaskFinished {
+			return filter, errors.New("invalid task status")
+		}
+
+		taskStatus := entities.TaskCompletionStatus(status)
+		filter.Status = &taskStatus
+	}
+
+	for key, values := range query {
+		fieldID, found := strings.CutPrefix(key, "field.")
+		if !found {
+			continue
+		}
+
+		id, err := strconv.Atoi(fieldID)
+		if err != nil {
+			return filter, err
+		}
+
+		for _, value := range values {
+			filter.Fields = append(filter.Fields, entities.TaskFieldFilter{IDField: id, Raw: value})
+		}
+	}
+
+	filter.AfterID, filter.Limit, err = parsePage(query)
+	return filter, err
+}
+
+// parseTimeParam parses a time query parameter, either in RFC 3339 or a day such as "2024-05-31"
+func parseTimeParam(value string) (*time.Time, error) {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		parsed, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &parsed, nil
 }
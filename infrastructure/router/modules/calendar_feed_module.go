@@ -0,0 +1,70 @@
+package modules
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// calendarFeedModule serves the iCalendar feeds polled by calendar applications, authorized by the secret token of
+// their URL instead of a session
+type calendarFeedModule struct {
+	calendarUseCases usecases.CalendarUseCases
+	name             string
+	path             string
+}
+
+func NewCalendarFeedModule(calendarUseCases usecases.CalendarUseCases) router.Module {
+	return calendarFeedModule{
+		calendarUseCases: calendarUseCases,
+		name:             "Calendar feeds",
+		path:             "/calendar/feeds",
+	}
+}
+
+func (c calendarFeedModule) Name() string {
+	return c.name
+}
+
+func (c calendarFeedModule) Path() string {
+	return c.path
+}
+
+func (c calendarFeedModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/{token:[0-9a-f]+}.ics",
+			Description: "Get the tasks of a calendar feed as an iCalendar file, supporting conditional requests",
+			Handler:     c.getCalendar,
+			HttpMethods: []string{http.MethodGet, http.MethodHead},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(c.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (c calendarFeedModule) getCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	document, etag, err := c.calendarUseCases.GetCalendar(ctx, mux.Vars(r)["token"])
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get calendar", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	// The entity tag lets the calendar applications polling the feed get a 304 response while nothing changed
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("ETag", etag)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(document))
+}
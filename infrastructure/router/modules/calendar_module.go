@@ -0,0 +1,157 @@
+package modules
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type calendarModule struct {
+	calendarUseCases usecases.CalendarUseCases
+	name             string
+	path             string
+}
+
+func NewCalendarModule(calendarUseCases usecases.CalendarUseCases) router.Module {
+	return calendarModule{
+		calendarUseCases: calendarUseCases,
+		name:             "Calendar",
+		path:             "/calendar/feed",
+	}
+}
+
+func (c calendarModule) Name() string {
+	return c.name
+}
+
+func (c calendarModule) Path() string {
+	return c.path
+}
+
+func (c calendarModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "",
+			Description: "Get the calendar feed of the user, without its URL",
+			Handler:     c.getFeed,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "",
+			Description: "Create or update the calendar feed of the user, returning its URL when created",
+			Handler:     c.saveFeed,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "",
+			Description: "Delete the calendar feed of the user, revoking its URL",
+			Handler:     c.deleteFeed,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/token",
+			Description: "Replace the URL of the calendar feed of the user, revoking the previous one",
+			Handler:     c.regenerateToken,
+			HttpMethods: []string{http.MethodPost},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(c.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (c calendarModule) getFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	feed, err := c.calendarUseCases.GetFeed(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get calendar feed", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, feed)
+}
+
+func (c calendarModule) saveFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	var feed entities.CalendarFeed
+	err = json.NewDecoder(r.Body).Decode(&feed)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	saved, statusCode, err := c.calendarUseCases.SaveFeed(ctx, user, feed)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to save calendar feed", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, saved)
+}
+
+func (c calendarModule) deleteFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	statusCode, err := c.calendarUseCases.DeleteFeed(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete calendar feed", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (c calendarModule) regenerateToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	feed, statusCode, err := c.calendarUseCases.RegenerateToken(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to regenerate calendar feed token", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, feed)
+}
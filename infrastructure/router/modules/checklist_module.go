@@ -0,0 +1,358 @@
+package modules
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type checklistModule struct {
+	checklistUseCases usecases.ChecklistUseCases
+	name              string
+	path              string
+}
+
+func NewChecklistModule(checklistUseCases usecases.ChecklistUseCases) router.Module {
+	return checklistModule{
+		checklistUseCases: checklistUseCases,
+		name:              "Checklists",
+		path:              "/checklists",
+	}
+}
+
+func (c checklistModule) Name() string {
+	return c.name
+}
+
+func (c checklistModule) Path() string {
+	return c.path
+}
+
+func (c checklistModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/tasks/{id:[0-9]+}",
+			Description: "List the checklists of a task",
+			Handler:     c.list,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}",
+			Description: "Add a checklist to a task",
+			Handler:     c.create,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Rename a checklist",
+			Handler:     c.update,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}/move",
+			Description: "Move a checklist to another position",
+			Handler:     c.move,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Delete a checklist",
+			Handler:     c.delete,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/{id:[0-9]+}/items",
+			Description: "Add an item to a checklist",
+			Handler:     c.createItem,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/items/{id:[0-9]+}",
+			Description: "Update a checklist item",
+			Handler:     c.updateItem,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/items/{id:[0-9]+}/move",
+			Description: "Move a checklist item to another checklist or position",
+			Handler:     c.moveItem,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/items/{id:[0-9]+}",
+			Description: "Delete a checklist item",
+			Handler:     c.deleteItem,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/items/{id:[0-9]+}/promote",
+			Description: "Promote a checklist item into a subtask",
+			Handler:     c.promoteItem,
+			HttpMethods: []string{http.MethodPost},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(c.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (c checklistModule) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	checklists, err := c.checklistUseCases.GetChecklists(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get checklists", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, checklists)
+}
+
+func (c checklistModule) create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var checklist entities.Checklist
+	err := json.NewDecoder(r.Body).Decode(&checklist)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	checklist.IDTask = id
+	created, statusCode, err := c.checklistUseCases.CreateChecklist(ctx, user, checklist)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create checklist", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (c checklistModule) update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var checklist entities.Checklist
+	err := json.NewDecoder(r.Body).Decode(&checklist)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	checklist.ID = id
+	statusCode, err := c.checklistUseCases.UpdateChecklist(ctx, user, checklist)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update checklist", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (c checklistModule) move(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Position int `json:"position"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := c.checklistUseCases.MoveChecklist(ctx, user, id, body.Position)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to move checklist", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (c checklistModule) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := c.checklistUseCases.DeleteChecklist(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete checklist", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (c checklistModule) createItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var item entities.ChecklistItem
+	err := json.NewDecoder(r.Body).Decode(&item)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	item.IDChecklist = id
+	created, statusCode, err := c.checklistUseCases.CreateItem(ctx, user, item)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create checklist item", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (c checklistModule) updateItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var item entities.ChecklistItem
+	err := json.NewDecoder(r.Body).Decode(&item)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	item.ID = id
+	statusCode, err := c.checklistUseCases.UpdateItem(ctx, user, item)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update checklist item", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (c checklistModule) moveItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		IDChecklist int `json:"id_checklist"`
+		Position    int `json:"position"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := c.checklistUseCases.MoveItem(ctx, user, id, body.IDChecklist, body.Position)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to move checklist item", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (c checklistModule) deleteItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := c.checklistUseCases.DeleteItem(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete checklist item", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (c checklistModule) promoteItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	// The body is optional, the subtask is created in the list of its parent by default
+	var body struct {
+		IDTaskList int `json:"id_task_list"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	subtask, statusCode, err := c.checklistUseCases.PromoteItem(ctx, user, id, body.IDTaskList)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to promote checklist item", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, subtask)
+}
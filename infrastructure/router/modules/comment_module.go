@@ -0,0 +1,178 @@
+package modules
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type commentModule struct {
+	commentUseCases usecases.CommentUseCases
+	name            string
+	path            string
+}
+
+func NewCommentModule(commentUseCases usecases.CommentUseCases) router.Module {
+	return commentModule{
+		commentUseCases: commentUseCases,
+		name:            "Comments",
+		path:            "/comments",
+	}
+}
+
+func (c commentModule) Name() string {
+	return c.name
+}
+
+func (c commentModule) Path() string {
+	return c.path
+}
+
+func (c commentModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/tasks/{id:[0-9]+}",
+			Description: "List the comments of a task",
+			Handler:     c.list,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}",
+			Description: "Comment on a task",
+			Handler:     c.create,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Update a comment",
+			Handler:     c.update,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Delete a comment",
+			Handler:     c.delete,
+			HttpMethods: []string{http.MethodDelete},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(c.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (c commentModule) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	after, limit, err := parsePage(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse pagination", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	comments, err := c.commentUseCases.GetComments(ctx, user, id, after, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get comments", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	response := struct {
+		Comments  []entities.Comment `json:"comments"`
+		NextAfter int                `json:"next_after,omitempty"`
+	}{
+		Comments: comments,
+	}
+
+	if len(comments) > 0 && len(comments) == rules.PageLimit(limit) {
+		response.NextAfter = comments[len(comments)-1].ID
+	}
+
+	write(ctx, w, response)
+}
+
+func (c commentModule) create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var comment entities.Comment
+	err := json.NewDecoder(r.Body).Decode(&comment)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	comment.IDTask = id
+	created, statusCode, err := c.commentUseCases.CreateComment(ctx, user, comment)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create comment", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (c commentModule) update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var comment entities.Comment
+	err := json.NewDecoder(r.Body).Decode(&comment)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	comment.ID = id
+	statusCode, err := c.commentUseCases.UpdateComment(ctx, user, comment)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update comment", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (c commentModule) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := c.commentUseCases.DeleteComment(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete comment", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
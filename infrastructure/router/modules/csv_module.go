@@ -0,0 +1,146 @@
+package modules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type csvModule struct {
+	csvUseCases usecases.CSVUseCases
+	name        string
+	path        string
+}
+
+func NewCSVModule(csvUseCases usecases.CSVUseCases) router.Module {
+	return csvModule{
+		csvUseCases: csvUseCases,
+		name:        "CSV",
+		path:        "/csv",
+	}
+}
+
+func (c csvModule) Name() string {
+	return c.name
+}
+
+func (c csvModule) Path() string {
+	return c.path
+}
+
+func (c csvModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/boards/{id:[0-9]+}/tasks",
+			Description: "Export the tasks of a board as CSV, the archived ones with archived=true",
+			Handler:     c.export,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/boards/{id:[0-9]+}/tasks",
+			Description: "Create tasks on a board from a CSV file, reporting the errors of the invalid rows",
+			Handler:     c.importTasks,
+			HttpMethods: []string{http.MethodPost},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(c.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (c csvModule) export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	archived := r.URL.Query().Get("archived") == "true"
+	data, err := c.csvUseCases.ExportTasks(ctx, user, id, archived)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to export tasks", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="board-%d-tasks.csv"`, id))
+
+	_, err = w.Write(data)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to write response", "cause", err)
+	}
+}
+
+// importTasks reads the file from the file multipart field, the column mapping as JSON from the mapping field and
+// whether any invalid row cancels the import from the all_or_nothing field
+func (c csvModule) importTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	// Leave room for the other multipart fields
+	r.Body = http.MaxBytesReader(w, r.Body, rules.CSVImportMaxSize+1<<20)
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeStatus(ctx, w, status_codes.CSVFileTooLarge, nil)
+			return
+		}
+
+		slog.ErrorContext(ctx, "failed to read form file", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, rules.CSVImportMaxSize+1))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read form file", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	if len(data) > rules.CSVImportMaxSize {
+		writeStatus(ctx, w, status_codes.CSVFileTooLarge, nil)
+		return
+	}
+
+	options := entities.CSVImportOptions{AllOrNothing: r.FormValue("all_or_nothing") == "true"}
+	if mapping := r.FormValue("mapping"); mapping != "" {
+		err = json.Unmarshal([]byte(mapping), &options.Mapping)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to decode column mapping", "cause", err)
+			router.WriteBadRequest(w)
+			return
+		}
+	}
+
+	report, statusCode, err := c.csvUseCases.ImportTasks(ctx, user, id, data, options)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to import tasks", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, report)
+}
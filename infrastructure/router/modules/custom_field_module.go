@@ -0,0 +1,230 @@
+package modules
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type customFieldModule struct {
+	customFieldUseCases usecases.CustomFieldUseCases
+	name                string
+	path                string
+}
+
+func NewCustomFieldModule(customFieldUseCases usecases.CustomFieldUseCases) router.Module {
+	return customFieldModule{
+		customFieldUseCases: customFieldUseCases,
+		name:                "Custom fields",
+		path:                "/custom-fields",
+	}
+}
+
+func (c customFieldModule) Name() string {
+	return c.name
+}
+
+func (c customFieldModule) Path() string {
+	return c.path
+}
+
+func (c customFieldModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "List the custom fields of a board",
+			Handler:     c.list,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "Define a custom field on a board",
+			Handler:     c.create,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Update the name and options of a custom field",
+			Handler:     c.update,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Delete a custom field along with its values",
+			Handler:     c.delete,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/{id:[0-9]+}/tasks/{task_id:[0-9]+}",
+			Description: "Set the value of a custom field on a task",
+			Handler:     c.setValue,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}/tasks/{task_id:[0-9]+}",
+			Description: "Clear the value of a custom field on a task",
+			Handler:     c.removeValue,
+			HttpMethods: []string{http.MethodDelete},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(c.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (c customFieldModule) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	fields, err := c.customFieldUseCases.GetFields(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get custom fields", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, fields)
+}
+
+func (c customFieldModule) create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var field entities.CustomField
+	err := json.NewDecoder(r.Body).Decode(&field)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	field.IDBoard = id
+	created, statusCode, err := c.customFieldUseCases.CreateField(ctx, user, field)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create custom field", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (c customFieldModule) update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var field entities.CustomField
+	err := json.NewDecoder(r.Body).Decode(&field)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	field.ID = id
+	statusCode, err := c.customFieldUseCases.UpdateField(ctx, user, field)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update custom field", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (c customFieldModule) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := c.customFieldUseCases.DeleteField(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete custom field", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (c customFieldModule) setValue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	taskID, err := router.GetIntVar(r, "task_id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse task id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	var value entities.CustomFieldValue
+	err = json.NewDecoder(r.Body).Decode(&value)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	value.IDField = id
+	statusCode, err := c.customFieldUseCases.SetFieldValue(ctx, user, taskID, value)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to set custom field value", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (c customFieldModule) removeValue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	taskID, err := router.GetIntVar(r, "task_id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse task id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := c.customFieldUseCases.RemoveFieldValue(ctx, user, taskID, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to remove custom field value", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
@@ -0,0 +1,152 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// eventHeartbeatInterval is how often a comment is sent on idle streams, so proxies don't close them
+	eventHeartbeatInterval = 15 * time.Second
+
+	// eventRetryInterval is how long clients wait before reconnecting a closed stream
+	eventRetryInterval = 3 * time.Second
+)
+
+// eventModule streams board activities with Server-Sent Events
+type eventModule struct {
+	activityUseCases usecases.ActivityUseCases
+	name             string
+	path             string
+}
+
+func NewEventModule(activityUseCases usecases.ActivityUseCases) router.Module {
+	return eventModule{
+		activityUseCases: activityUseCases,
+		name:             "Events",
+		path:             "/boards",
+	}
+}
+
+func (e eventModule) Name() string {
+	return e.name
+}
+
+func (e eventModule) Path() string {
+	return e.path
+}
+
+func (e eventModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/{id:[0-9]+}/events",
+			Description: "Stream the board events",
+			Handler:     e.stream,
+			HttpMethods: []string{http.MethodGet},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(e.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (e eventModule) stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	// Sent by EventSource when reconnecting; the query parameter is for clients that can't set it
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	var lastID int
+	if lastEventID != "" {
+		var err error
+		lastID, err = strconv.Atoi(lastEventID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to parse last event id", "cause", err)
+			router.WriteBadRequest(w)
+			return
+		}
+	}
+
+	activities, err := e.activityUseCases.Subscribe(ctx, user, id, lastID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to subscribe to board events", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	// The stream lives longer than the server write timeout
+	controller := http.NewResponseController(w)
+	err = controller.SetWriteDeadline(time.Time{})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to clear write deadline", "cause", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	_, err = fmt.Fprintf(w, "retry: %d\n\n", eventRetryInterval.Milliseconds())
+	if err == nil {
+		err = controller.Flush()
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to start event stream", "cause", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case activity, ok := <-activities:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(activity)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to marshal event", "cause", err)
+				continue
+			}
+
+			_, err = fmt.Fprintf(w, "id: %d\nevent: %s.%s\ndata: %s\n\n", activity.ID, activity.EntityType, activity.Action, data)
+			if err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+			if err != nil {
+				return
+			}
+		}
+
+		err = controller.Flush()
+		if err != nil {
+			return
+		}
+	}
+}
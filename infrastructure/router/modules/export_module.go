@@ -0,0 +1,108 @@
+package modules
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type exportModule struct {
+	exportUseCases usecases.ExportUseCases
+	name           string
+	path           string
+}
+
+func NewExportModule(exportUseCases usecases.ExportUseCases) router.Module {
+	return exportModule{
+		exportUseCases: exportUseCases,
+		name:           "Exports",
+		path:           "/exports",
+	}
+}
+
+func (e exportModule) Name() string {
+	return e.name
+}
+
+func (e exportModule) Path() string {
+	return e.path
+}
+
+func (e exportModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "Download the archive of a board with all its content and attachments",
+			Handler:     e.exportBoard,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/workspace",
+			Description: "Download the archive of every board the user owns",
+			Handler:     e.exportWorkspace,
+			HttpMethods: []string{http.MethodGet},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(e.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (e exportModule) exportBoard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	archive, err := e.exportUseCases.ExportBoard(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to export board", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	e.writeArchive(w, r, archive, fmt.Sprintf("board-%d.zip", id))
+}
+
+func (e exportModule) exportWorkspace(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	archive, err := e.exportUseCases.ExportWorkspace(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to export workspace", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	e.writeArchive(w, r, archive, "workspace.zip")
+}
+
+// writeArchive streams the archive as a zip file download. Failures past the headers can only be logged.
+func (e exportModule) writeArchive(w http.ResponseWriter, r *http.Request, archive *entities.Archive, fileName string) {
+	ctx := r.Context()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+
+	err := e.exportUseCases.WriteArchive(ctx, w, archive)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to write archive", "cause", err)
+	}
+}
@@ -0,0 +1,124 @@
+package modules
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// fileModule serves attachments through signed URLs, without requiring a session
+type fileModule struct {
+	attachmentUseCases usecases.AttachmentUseCases
+	name               string
+	path               string
+}
+
+func NewFileModule(attachmentUseCases usecases.AttachmentUseCases) router.Module {
+	return fileModule{
+		attachmentUseCases: attachmentUseCases,
+		name:               "Files",
+		path:               "/files",
+	}
+}
+
+func (f fileModule) Name() string {
+	return f.name
+}
+
+func (f fileModule) Path() string {
+	return f.path
+}
+
+func (f fileModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Download an attachment with a signed URL",
+			Handler:     f.download,
+			HttpMethods: []string{http.MethodGet, http.MethodHead},
+		},
+		{
+			Path:        "/{id:[0-9]+}/thumbnail",
+			Description: "Download an image attachment thumbnail with a signed URL",
+			Handler:     f.thumbnail,
+			HttpMethods: []string{http.MethodGet, http.MethodHead},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(f.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+// download serves the attachment the same way as the authenticated route: a signed link to anything but an image
+// downloads the file rather than rendering it
+func (f fileModule) download(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, expiresAt, signature, err := parseSignedURL(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse signed url", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	attachment, file, err := f.attachmentUseCases.GetSignedAttachmentFile(ctx, id, expiresAt, signature)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get signed attachment file", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+	defer file.Close()
+
+	serveAttachment(w, r, attachment, file)
+}
+
+func (f fileModule) thumbnail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, expiresAt, signature, err := parseSignedURL(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse signed url", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	size := entities.ThumbnailSize(r.URL.Query().Get("size"))
+	if size == "" {
+		size = entities.ThumbnailMedium
+	}
+
+	thumbnail, file, err := f.attachmentUseCases.GetSignedThumbnailFile(ctx, id, expiresAt, signature, size)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get signed thumbnail file", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+	defer file.Close()
+
+	serveThumbnail(w, r, thumbnail, file)
+}
+
+// parseSignedURL reads the attachment ID, the expiration and the signature from a signed URL
+func parseSignedURL(r *http.Request) (int, time.Time, string, error) {
+	id, err := router.GetIntVar(r, "id")
+	if err != nil {
+		return 0, time.Time{}, "", err
+	}
+
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return 0, time.Time{}, "", err
+	}
+
+	return id, time.Unix(expires, 0), query.Get("signature"), nil
+}
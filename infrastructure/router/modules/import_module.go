@@ -0,0 +1,145 @@
+package modules
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type importModule struct {
+	importUseCases usecases.ImportUseCases
+	name           string
+	path           string
+}
+
+func NewImportModule(importUseCases usecases.ImportUseCases) router.Module {
+	return importModule{
+		importUseCases: importUseCases,
+		name:           "Imports",
+		path:           "/imports",
+	}
+}
+
+func (i importModule) Name() string {
+	return i.name
+}
+
+func (i importModule) Path() string {
+	return i.path
+}
+
+func (i importModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/trello",
+			Description: "Create a board from a Trello board JSON export, or report what would be created on a dry run",
+			Handler:     i.importTrello,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/archive",
+			Description: "Create the boards of an archive exported from another instance",
+			Handler:     i.importArchive,
+			HttpMethods: []string{http.MethodPost},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(i.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+// importTrello reads the export from the request body, and whether it is a dry run from the dry_run query parameter.
+// The members of the export are not matched to accounts, an uploaded export being able to name anyone.
+func (i importModule) importTrello(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, rules.ImportMaxSize)
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeStatus(ctx, w, status_codes.ImportFileTooLarge, nil)
+			return
+		}
+
+		slog.ErrorContext(ctx, "failed to read request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	report, statusCode, err := i.importUseCases.ImportTrelloBoard(ctx, user, data, dryRun, false)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to import trello board", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, report)
+}
+
+// importArchive reads the archive zip file from the request body, spooling it to a temporary file as zip files are
+// read from their end. The users of the archive are not matched to accounts, an uploaded archive being able to name
+// anyone.
+func (i importModule) importArchive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	file, err := os.CreateTemp("", "archive-*.zip")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create temporary file", "cause", err)
+		router.WriteInternalError(w)
+		return
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	r.Body = http.MaxBytesReader(w, r.Body, rules.ArchiveMaxSize)
+
+	size, err := io.Copy(file, r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeStatus(ctx, w, status_codes.ImportFileTooLarge, nil)
+			return
+		}
+
+		slog.ErrorContext(ctx, "failed to read request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	reports, statusCode, err := i.importUseCases.ImportArchive(ctx, user, file, size, false)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to import archive", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, reports)
+}
@@ -0,0 +1,104 @@
+package modules
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type jobModule struct {
+	jobUseCases usecases.JobUseCases
+	name        string
+	path        string
+}
+
+func NewJobModule(jobUseCases usecases.JobUseCases) router.Module {
+	return jobModule{
+		jobUseCases: jobUseCases,
+		name:        "Jobs",
+		path:        "/jobs",
+	}
+}
+
+func (j jobModule) Name() string {
+	return j.name
+}
+
+func (j jobModule) Path() string {
+	return j.path
+}
+
+func (j jobModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "",
+			Description: "Get the background jobs and their next run",
+			Handler:     j.getJobs,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/runs",
+			Description: "Get the run history of the background jobs, newest first",
+			Handler:     j.getRuns,
+			HttpMethods: []string{http.MethodGet},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(j.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (j jobModule) getJobs(w http.ResponseWriter, r *http.Request) {
+	write(r.Context(), w, j.jobUseCases.GetJobs())
+}
+
+func (j jobModule) getRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	filter := entities.JobRunFilter{
+		Job: query.Get("job"),
+	}
+
+	var err error
+	if before := query.Get("before"); before != "" {
+		filter.BeforeID, err = strconv.Atoi(before)
+	}
+	if limit := query.Get("limit"); limit != "" && err == nil {
+		filter.Limit, err = strconv.Atoi(limit)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse query parameters", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	runs, err := j.jobUseCases.GetJobRuns(ctx, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get job runs", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	response := struct {
+		Runs       []entities.JobRun `json:"runs"`
+		NextBefore int               `json:"next_before,omitempty"`
+	}{
+		Runs: runs,
+	}
+
+	if len(runs) > 0 && len(runs) == rules.PageLimit(filter.Limit) {
+		response.NextBefore = runs[len(runs)-1].ID
+	}
+
+	write(ctx, w, response)
+}
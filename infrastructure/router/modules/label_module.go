@@ -0,0 +1,221 @@
+package modules
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type labelModule struct {
+	labelUseCases usecases.LabelUseCases
+	name          string
+	path          string
+}
+
+func NewLabelModule(labelUseCases usecases.LabelUseCases) router.Module {
+	return labelModule{
+		labelUseCases: labelUseCases,
+		name:          "Labels",
+		path:          "/labels",
+	}
+}
+
+func (l labelModule) Name() string {
+	return l.name
+}
+
+func (l labelModule) Path() string {
+	return l.path
+}
+
+func (l labelModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "List the labels of a board",
+			Handler:     l.list,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "Define a label on a board",
+			Handler:     l.create,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Update the name and color of a label",
+			Handler:     l.update,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Delete a label, removing it from its tasks",
+			Handler:     l.delete,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/{id:[0-9]+}/tasks/{task_id:[0-9]+}",
+			Description: "Tag a task with a label",
+			Handler:     l.addToTask,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}/tasks/{task_id:[0-9]+}",
+			Description: "Remove a label from a task",
+			Handler:     l.removeFromTask,
+			HttpMethods: []string{http.MethodDelete},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(l.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (l labelModule) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	labels, err := l.labelUseCases.GetLabels(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get labels", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, labels)
+}
+
+func (l labelModule) create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var label entities.Label
+	err := json.NewDecoder(r.Body).Decode(&label)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	label.IDBoard = id
+	created, statusCode, err := l.labelUseCases.CreateLabel(ctx, user, label)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create label", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (l labelModule) update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var label entities.Label
+	err := json.NewDecoder(r.Body).Decode(&label)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	label.ID = id
+	statusCode, err := l.labelUseCases.UpdateLabel(ctx, user, label)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update label", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (l labelModule) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := l.labelUseCases.DeleteLabel(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete label", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (l labelModule) addToTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	taskID, err := router.GetIntVar(r, "task_id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse task id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := l.labelUseCases.AddTaskLabel(ctx, user, taskID, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to add task label", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (l labelModule) removeFromTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	taskID, err := router.GetIntVar(r, "task_id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse task id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := l.labelUseCases.RemoveTaskLabel(ctx, user, taskID, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to remove task label", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
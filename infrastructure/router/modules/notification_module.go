@@ -0,0 +1,315 @@
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/rules"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type notificationModule struct {
+	notificationUseCases usecases.NotificationUseCases
+	name                 string
+	path                 string
+}
+
+func NewNotificationModule(notificationUseCases usecases.NotificationUseCases) router.Module {
+	return notificationModule{
+		notificationUseCases: notificationUseCases,
+		name:                 "Notifications",
+		path:                 "/notifications",
+	}
+}
+
+func (n notificationModule) Name() string {
+	return n.name
+}
+
+func (n notificationModule) Path() string {
+	return n.path
+}
+
+func (n notificationModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "",
+			Description: "Get the notifications of the user, newest first",
+			Handler:     n.getNotifications,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/unread-count",
+			Description: "Get the number of unread notifications of the user",
+			Handler:     n.getUnreadCount,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/read-all",
+			Description: "Mark every notification of the user as read",
+			Handler:     n.markAllRead,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}/read",
+			Description: "Mark a notification as read",
+			Handler:     n.markRead,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/watch/boards/{id:[0-9]+}",
+			Description: "Watch the comments and task changes of a board",
+			Handler:     n.watch(n.notificationUseCases.WatchBoard),
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/watch/boards/{id:[0-9]+}",
+			Description: "Stop watching a board",
+			Handler:     n.watch(n.notificationUseCases.UnwatchBoard),
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/watch/tasks/{id:[0-9]+}",
+			Description: "Watch the comments and changes of a task",
+			Handler:     n.watch(n.notificationUseCases.WatchTask),
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/watch/tasks/{id:[0-9]+}",
+			Description: "Stop watching a task",
+			Handler:     n.watch(n.notificationUseCases.UnwatchTask),
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/preferences",
+			Description: "Get the notification preferences of the user",
+			Handler:     n.getPreferences,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/preferences",
+			Description: "Update the notification preferences of the user",
+			Handler:     n.updatePreferences,
+			HttpMethods: []string{http.MethodPut},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(n.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (n notificationModule) getPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	preferences, err := n.notificationUseCases.GetPreferences(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get notification preferences", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, preferences)
+}
+
+func (n notificationModule) updatePreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	var preferences entities.NotificationPreferences
+	err = json.NewDecoder(r.Body).Decode(&preferences)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := n.notificationUseCases.UpdatePreferences(ctx, user, preferences)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update notification preferences", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (n notificationModule) getNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	filter, err := parseNotificationFilter(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse notification filter", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	notifications, err := n.notificationUseCases.GetNotifications(ctx, user, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get notifications", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	unread, err := n.notificationUseCases.GetUnreadCount(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count unread notifications", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	response := struct {
+		Notifications []entities.Notification `json:"notifications"`
+		NextBefore    int                     `json:"next_before,omitempty"`
+		UnreadCount   int                     `json:"unread_count"`
+	}{
+		Notifications: notifications,
+		UnreadCount:   unread,
+	}
+
+	if len(notifications) > 0 && len(notifications) == rules.PageLimit(filter.Limit) {
+		response.NextBefore = notifications[len(notifications)-1].ID
+	}
+
+	write(ctx, w, response)
+}
+
+func (n notificationModule) getUnreadCount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	unread, err := n.notificationUseCases.GetUnreadCount(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count unread notifications", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, map[string]int{"unread_count": unread})
+}
+
+func (n notificationModule) markRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := n.notificationUseCases.MarkRead(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to mark notification as read", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (n notificationModule) markAllRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	statusCode, err := n.notificationUseCases.MarkAllRead(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to mark notifications as read", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+// watch returns the handler starting or stopping watching the board or task given by the id route variable
+func (n notificationModule) watch(
+	useCase func(ctx context.Context, user *entities.User, id int) (status_codes.NotificationStatusCode, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		user, id, ok := readUserAndID(w, r, "id")
+		if !ok {
+			return
+		}
+
+		statusCode, err := useCase(ctx, user, id)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to update watch", "cause", err)
+			router.WriteError(w, err)
+			return
+		}
+
+		writeStatus(ctx, w, statusCode, nil)
+	}
+}
+
+// parseNotificationFilter reads the unread, before and limit query parameters
+func parseNotificationFilter(query url.Values) (entities.NotificationFilter, error) {
+	var filter entities.NotificationFilter
+	var err error
+
+	if unread := query.Get("unread"); unread != "" {
+		filter.Unread, err = strconv.ParseBool(unread)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if before := query.Get("before"); before != "" {
+		filter.BeforeID, err = strconv.Atoi(before)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,99 @@
+package modules
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"taskflow/domain/entities"
+	"taskflow/domain/status_codes"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type searchModule struct {
+	searchUseCases usecases.SearchUseCases
+	name           string
+	path           string
+}
+
+func NewSearchModule(searchUseCases usecases.SearchUseCases) router.Module {
+	return searchModule{
+		searchUseCases: searchUseCases,
+		name:           "Search",
+		path:           "/search",
+	}
+}
+
+func (s searchModule) Name() string {
+	return s.name
+}
+
+func (s searchModule) Path() string {
+	return s.path
+}
+
+func (s searchModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "",
+			Description: "Search the titles, descriptions and comments of the boards of the user",
+			Handler:     s.search,
+			HttpMethods: []string{http.MethodGet},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(s.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+// search reads the q, type (comma separated), board and limit query parameters
+func (s searchModule) search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	query := r.URL.Query()
+	var filter entities.SearchFilter
+	if value := query.Get("type"); value != "" {
+		for _, resultType := range strings.Split(value, ",") {
+			filter.Types = append(filter.Types, entities.SearchResultType(strings.TrimSpace(resultType)))
+		}
+	}
+
+	if value := query.Get("board"); value != "" {
+		filter.IDBoard, err = strconv.Atoi(value)
+	}
+	if value := query.Get("limit"); value != "" && err == nil {
+		filter.Limit, err = strconv.Atoi(value)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse search filter", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	results, statusCode, err := s.searchUseCases.Search(ctx, user, query.Get("q"), filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to search", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	if statusCode != status_codes.SearchSuccess {
+		writeStatus(ctx, w, statusCode, nil)
+		return
+	}
+
+	write(ctx, w, results)
+}
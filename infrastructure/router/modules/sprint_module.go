@@ -0,0 +1,355 @@
+package modules
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type sprintModule struct {
+	sprintUseCases usecases.SprintUseCases
+	name           string
+	path           string
+}
+
+func NewSprintModule(sprintUseCases usecases.SprintUseCases) router.Module {
+	return sprintModule{
+		sprintUseCases: sprintUseCases,
+		name:           "Sprints",
+		path:           "/sprints",
+	}
+}
+
+func (s sprintModule) Name() string {
+	return s.name
+}
+
+func (s sprintModule) Path() string {
+	return s.path
+}
+
+func (s sprintModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "List the sprints of a board",
+			Handler:     s.list,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "Plan a sprint on a board",
+			Handler:     s.create,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Get a sprint along with its tasks",
+			Handler:     s.get,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Update the name, goal and dates of a sprint",
+			Handler:     s.update,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Delete a sprint, leaving its tasks uncommitted",
+			Handler:     s.delete,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/{id:[0-9]+}/start",
+			Description: "Start a planned sprint",
+			Handler:     s.start,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}/close",
+			Description: "Close the active sprint, carrying its unfinished tasks over",
+			Handler:     s.close,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}/tasks/{task_id:[0-9]+}",
+			Description: "Commit a task to a sprint",
+			Handler:     s.addTask,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}/tasks/{task_id:[0-9]+}",
+			Description: "Remove a task from a sprint",
+			Handler:     s.removeTask,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/{id:[0-9]+}/burndown",
+			Description: "Get the daily burndown of a sprint",
+			Handler:     s.getBurndown,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/{id:[0-9]+}/report",
+			Description: "Get the report of a sprint",
+			Handler:     s.getReport,
+			HttpMethods: []string{http.MethodGet},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(s.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (s sprintModule) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	sprints, err := s.sprintUseCases.GetSprints(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get sprints", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, sprints)
+}
+
+func (s sprintModule) create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var sprint entities.Sprint
+	err := json.NewDecoder(r.Body).Decode(&sprint)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	sprint.IDBoard = id
+	created, statusCode, err := s.sprintUseCases.CreateSprint(ctx, user, sprint)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create sprint", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (s sprintModule) get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	sprint, err := s.sprintUseCases.GetSprint(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get sprint", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, sprint)
+}
+
+func (s sprintModule) update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var sprint entities.Sprint
+	err := json.NewDecoder(r.Body).Decode(&sprint)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	sprint.ID = id
+	statusCode, err := s.sprintUseCases.UpdateSprint(ctx, user, sprint)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update sprint", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (s sprintModule) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := s.sprintUseCases.DeleteSprint(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete sprint", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (s sprintModule) start(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := s.sprintUseCases.StartSprint(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to start sprint", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (s sprintModule) close(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	// The body is optional, the unfinished tasks going back to the backlog by default
+	var body struct {
+		CarryOverTo int `json:"carry_over_to"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	sprint, statusCode, err := s.sprintUseCases.CloseSprint(ctx, user, id, body.CarryOverTo)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to close sprint", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, sprint)
+}
+
+func (s sprintModule) addTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	taskID, err := router.GetIntVar(r, "task_id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse task id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := s.sprintUseCases.AddTask(ctx, user, id, taskID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to add sprint task", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (s sprintModule) removeTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	taskID, err := router.GetIntVar(r, "task_id")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse task id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	statusCode, err := s.sprintUseCases.RemoveTask(ctx, user, id, taskID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to remove sprint task", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (s sprintModule) getBurndown(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	burndown, err := s.sprintUseCases.GetBurndown(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get sprint burndown", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, burndown)
+}
+
+func (s sprintModule) getReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	report, err := s.sprintUseCases.GetReport(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get sprint report", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, report)
+}
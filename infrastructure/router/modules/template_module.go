@@ -0,0 +1,191 @@
+package modules
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type templateModule struct {
+	templateUseCases usecases.TemplateUseCases
+	name             string
+	path             string
+}
+
+func NewTemplateModule(templateUseCases usecases.TemplateUseCases) router.Module {
+	return templateModule{
+		templateUseCases: templateUseCases,
+		name:             "Templates",
+		path:             "/templates",
+	}
+}
+
+func (t templateModule) Name() string {
+	return t.name
+}
+
+func (t templateModule) Path() string {
+	return t.path
+}
+
+func (t templateModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "",
+			Description: "List the board templates of the user",
+			Handler:     t.list,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Get a board template with its content",
+			Handler:     t.get,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Delete a board template",
+			Handler:     t.delete,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "Save a board as a template, optionally with its tasks",
+			Handler:     t.save,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}/boards",
+			Description: "Create a board from a template",
+			Handler:     t.createBoard,
+			HttpMethods: []string{http.MethodPost},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(t.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (t templateModule) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	templates, err := t.templateUseCases.GetTemplates(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get templates", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, templates)
+}
+
+func (t templateModule) get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	template, err := t.templateUseCases.GetTemplate(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get template", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, template)
+}
+
+func (t templateModule) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := t.templateUseCases.DeleteTemplate(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete template", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+// save reads the name and description of the template, which takes the title of the board when no name is given,
+// and whether the tasks are included
+func (t templateModule) save(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Name         string `json:"name"`
+		Description  string `json:"description"`
+		IncludeTasks bool   `json:"include_tasks"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	template := entities.BoardTemplate{Name: body.Name, Description: body.Description}
+	saved, statusCode, err := t.templateUseCases.SaveTemplate(ctx, user, id, template, body.IncludeTasks)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to save template", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, saved)
+}
+
+func (t templateModule) createBoard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var board entities.Board
+	err := json.NewDecoder(r.Body).Decode(&board)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	created, statusCode, err := t.templateUseCases.CreateBoard(ctx, user, id, board)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create board from template", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
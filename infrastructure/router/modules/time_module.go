@@ -0,0 +1,353 @@
+package modules
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type timeModule struct {
+	timeUseCases usecases.TimeUseCases
+	name         string
+	path         string
+}
+
+func NewTimeModule(timeUseCases usecases.TimeUseCases) router.Module {
+	return timeModule{
+		timeUseCases: timeUseCases,
+		name:         "Time tracking",
+		path:         "/time",
+	}
+}
+
+func (t timeModule) Name() string {
+	return t.name
+}
+
+func (t timeModule) Path() string {
+	return t.path
+}
+
+func (t timeModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/timer",
+			Description: "Get the timer running for the current user",
+			Handler:     t.getTimer,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/timer/stop",
+			Description: "Stop the timer running for the current user",
+			Handler:     t.stopTimer,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/timer",
+			Description: "Start a timer on a task",
+			Handler:     t.startTimer,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/entries",
+			Description: "List the time entries of a task",
+			Handler:     t.listEntries,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/tasks/{id:[0-9]+}/entries",
+			Description: "Enter time spent on a task",
+			Handler:     t.addEntry,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/entries/{id:[0-9]+}",
+			Description: "Update a time entry",
+			Handler:     t.updateEntry,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/entries/{id:[0-9]+}",
+			Description: "Delete a time entry",
+			Handler:     t.deleteEntry,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/report",
+			Description: "Get the time spent by user and by task over a range",
+			Handler:     t.getReport,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/report/csv",
+			Description: "Export the time entries of a report as CSV",
+			Handler:     t.exportReport,
+			HttpMethods: []string{http.MethodGet},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(t.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (t timeModule) getTimer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	entry, err := t.timeUseCases.GetRunningTimer(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get running timer", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, entry)
+}
+
+func (t timeModule) stopTimer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	entry, statusCode, err := t.timeUseCases.StopTimer(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to stop timer", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, entry)
+}
+
+func (t timeModule) startTimer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	// The body is optional, the timer starts without a note by default
+	var body struct {
+		Note string `json:"note"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	entry, statusCode, err := t.timeUseCases.StartTimer(ctx, user, id, body.Note)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to start timer", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, entry)
+}
+
+func (t timeModule) listEntries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	entries, err := t.timeUseCases.GetTaskEntries(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get time entries", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, entries)
+}
+
+func (t timeModule) addEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var entry entities.TimeEntry
+	err := json.NewDecoder(r.Body).Decode(&entry)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	entry.IDTask = id
+	created, statusCode, err := t.timeUseCases.AddEntry(ctx, user, entry)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to add time entry", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (t timeModule) updateEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var entry entities.TimeEntry
+	err := json.NewDecoder(r.Body).Decode(&entry)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	entry.ID = id
+	statusCode, err := t.timeUseCases.UpdateEntry(ctx, user, entry)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update time entry", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (t timeModule) deleteEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := t.timeUseCases.DeleteEntry(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete time entry", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (t timeModule) getReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	filter, err := parseTimeReportFilter(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse time report filter", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	report, err := t.timeUseCases.GetReport(ctx, user, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get time report", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, report)
+}
+
+func (t timeModule) exportReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return
+	}
+
+	filter, err := parseTimeReportFilter(r.URL.Query())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse time report filter", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	data, err := t.timeUseCases.ExportReport(ctx, user, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to export time report", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="time-report.csv"`)
+
+	_, err = w.Write(data)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to write response", "cause", err)
+	}
+}
+
+// parseTimeReportFilter reads the board and user query parameters, along with the range of days like
+// parseAnalyticsRange
+func parseTimeReportFilter(query url.Values) (entities.TimeReportFilter, error) {
+	var filter entities.TimeReportFilter
+
+	dateRange, err := parseAnalyticsRange(query)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.From = dateRange.From
+	filter.To = dateRange.To
+
+	if value := query.Get("board"); value != "" {
+		filter.IDBoard, err = strconv.Atoi(value)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if value := query.Get("user"); value != "" {
+		filter.IDUser, err = strconv.Atoi(value)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,75 @@
+package modules
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+// unsubscribeModule handles the unsubscribe links of the notification emails, authorized by their signature instead
+// of a session
+type unsubscribeModule struct {
+	notificationUseCases usecases.NotificationUseCases
+	name                 string
+	path                 string
+}
+
+func NewUnsubscribeModule(notificationUseCases usecases.NotificationUseCases) router.Module {
+	return unsubscribeModule{
+		notificationUseCases: notificationUseCases,
+		name:                 "Unsubscribe",
+		path:                 "/notifications/unsubscribe",
+	}
+}
+
+func (u unsubscribeModule) Name() string {
+	return u.name
+}
+
+func (u unsubscribeModule) Path() string {
+	return u.path
+}
+
+func (u unsubscribeModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "",
+			Description: "Unsubscribe from a notification email, from a link or a one-click List-Unsubscribe request",
+			Handler:     u.unsubscribe,
+			HttpMethods: []string{http.MethodGet, http.MethodPost},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(u.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (u unsubscribeModule) unsubscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query := r.URL.Query()
+	userID, err := strconv.Atoi(query.Get("user"))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse user id", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	notificationType := entities.NotificationType(query.Get("type"))
+	statusCode, err := u.notificationUseCases.Unsubscribe(ctx, userID, notificationType, query.Get("signature"))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to unsubscribe", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
@@ -0,0 +1,74 @@
+package modules
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/status_codes"
+	"taskflow/infrastructure/router"
+)
+
+// readUserAndID returns the session user and the integer route variable with the given name
+//
+// If any of them can't be read, the error response is written and false is returned
+func readUserAndID(w http.ResponseWriter, r *http.Request, name string) (*entities.User, int, bool) {
+	ctx := r.Context()
+
+	user, err := router.GetAppUser(r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get app user", "cause", err)
+		router.WriteUnauthorized(w)
+		return nil, 0, false
+	}
+
+	id, err := router.GetIntVar(r, name)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse route variable", "name", name, "cause", err)
+		router.WriteBadRequest(w)
+		return nil, 0, false
+	}
+
+	return user, id, true
+}
+
+// writeStatus writes the use case status code along with the optional data, logging any failure
+func writeStatus(ctx context.Context, w http.ResponseWriter, statusCode status_codes.StatusCode, data any) {
+	err := router.WriteStatus(w, statusCode, data)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to write response", "cause", err)
+	}
+}
+
+// write writes the response body, logging any failure
+func write(ctx context.Context, w http.ResponseWriter, v any) {
+	err := router.Write(w, v)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to write response", "cause", err)
+	}
+}
+
+// parsePage reads the after and limit query parameters of a page, after being the ID of the last item of the previous
+// page
+func parsePage(query url.Values) (int, int, error) {
+	var after, limit int
+	var err error
+
+	if value := query.Get("after"); value != "" {
+		after, err = strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return after, limit, nil
+}
@@ -0,0 +1,223 @@
+package modules
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"taskflow/domain/entities"
+	"taskflow/domain/usecases"
+	"taskflow/infrastructure/router"
+
+	"github.com/gorilla/mux"
+)
+
+type webhookModule struct {
+	webhookUseCases usecases.WebhookUseCases
+	name            string
+	path            string
+}
+
+func NewWebhookModule(webhookUseCases usecases.WebhookUseCases) router.Module {
+	return webhookModule{
+		webhookUseCases: webhookUseCases,
+		name:            "Webhooks",
+		path:            "/webhooks",
+	}
+}
+
+func (wm webhookModule) Name() string {
+	return wm.name
+}
+
+func (wm webhookModule) Path() string {
+	return wm.path
+}
+
+func (wm webhookModule) Setup(r *mux.Router) ([]router.RouteDefinition, *mux.Router) {
+	defs := []router.RouteDefinition{
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "List the webhooks of a board",
+			Handler:     wm.list,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/boards/{id:[0-9]+}",
+			Description: "Register a webhook on a board",
+			Handler:     wm.create,
+			HttpMethods: []string{http.MethodPost},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Update a webhook",
+			Handler:     wm.update,
+			HttpMethods: []string{http.MethodPut},
+		},
+		{
+			Path:        "/{id:[0-9]+}",
+			Description: "Delete a webhook",
+			Handler:     wm.delete,
+			HttpMethods: []string{http.MethodDelete},
+		},
+		{
+			Path:        "/{id:[0-9]+}/deliveries",
+			Description: "List the deliveries of a webhook",
+			Handler:     wm.deliveries,
+			HttpMethods: []string{http.MethodGet},
+		},
+		{
+			Path:        "/deliveries/{id:[0-9]+}/redeliver",
+			Description: "Send a webhook delivery again",
+			Handler:     wm.redeliver,
+			HttpMethods: []string{http.MethodPost},
+		},
+	}
+
+	for _, d := range defs {
+		r.HandleFunc(wm.path+d.Path, d.Handler).Methods(d.HttpMethods...)
+	}
+
+	return defs, r
+}
+
+func (wm webhookModule) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	webhooks, err := wm.webhookUseCases.GetWebhooks(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get webhooks", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, webhooks)
+}
+
+func (wm webhookModule) create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var webhook entities.Webhook
+	err := json.NewDecoder(r.Body).Decode(&webhook)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	webhook.IDBoard = id
+	created, statusCode, err := wm.webhookUseCases.CreateWebhook(ctx, user, webhook)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create webhook", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, created)
+}
+
+func (wm webhookModule) update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var webhook entities.Webhook
+	err := json.NewDecoder(r.Body).Decode(&webhook)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to decode request body", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	webhook.ID = id
+	statusCode, err := wm.webhookUseCases.UpdateWebhook(ctx, user, webhook)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to update webhook", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (wm webhookModule) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	statusCode, err := wm.webhookUseCases.DeleteWebhook(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to delete webhook", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, nil)
+}
+
+func (wm webhookModule) deliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var before, limit int
+	var err error
+	query := r.URL.Query()
+	if value := query.Get("before"); value != "" {
+		before, err = strconv.Atoi(value)
+	}
+	if value := query.Get("limit"); value != "" && err == nil {
+		limit, err = strconv.Atoi(value)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse pagination", "cause", err)
+		router.WriteBadRequest(w)
+		return
+	}
+
+	deliveries, err := wm.webhookUseCases.GetDeliveries(ctx, user, id, before, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get webhook deliveries", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	write(ctx, w, deliveries)
+}
+
+func (wm webhookModule) redeliver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, id, ok := readUserAndID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	delivery, statusCode, err := wm.webhookUseCases.Redeliver(ctx, user, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to redeliver webhook", "cause", err)
+		router.WriteError(w, err)
+		return
+	}
+
+	writeStatus(ctx, w, statusCode, delivery)
+}
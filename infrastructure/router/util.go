@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
 	"taskflow/domain/entities"
+
+	"github.com/gorilla/mux"
 )
 
 type userKey string
@@ -25,3 +28,13 @@ func GetAppUser(r *http.Request) (*entities.User, error) {
 
 	return contextUser.(*entities.User), nil
 }
+
+// GetIntVar parses the route variable with the given name as an integer
+func GetIntVar(r *http.Request, name string) (int, error) {
+	value, ok := mux.Vars(r)[name]
+	if !ok {
+		return 0, errors.New("route variable " + name + " not found")
+	}
+
+	return strconv.Atoi(value)
+}
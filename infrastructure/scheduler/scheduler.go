@@ -0,0 +1,219 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"taskflow/domain/entities"
+	"taskflow/domain/util"
+	"taskflow/infrastructure/datastore"
+	"time"
+)
+
+const (
+	// lockPrefix namespaces the database locks of the jobs
+	lockPrefix = "taskflow:job:"
+
+	// maxErrorLetters limits the error stored with a failed run
+	maxErrorLetters = 1024
+)
+
+type job struct {
+	name     string
+	spec     string
+	schedule util.CronSchedule
+	run      func(ctx context.Context) error
+}
+
+// Scheduler runs background jobs on cron schedules
+//
+// Every replica runs the same scheduler. Each run is elected through a database lock and recorded in the run history
+// under its scheduled time, so a job runs on a single replica per scheduled time.
+type Scheduler struct {
+	repository datastore.JobRepository
+	instance   string
+	jobs       []job
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopping chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+func NewScheduler(repository datastore.JobRepository) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return &Scheduler{
+		repository: repository,
+		instance:   fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+		ctx:        ctx,
+		cancel:     cancel,
+		stopping:   make(chan struct{}),
+	}
+}
+
+// Add registers a job running on the cron schedule. Jobs must be added before the scheduler starts.
+func (s *Scheduler) Add(name string, spec string, run func(ctx context.Context) error) error {
+	schedule, err := util.ParseCronSchedule(spec)
+	if err != nil {
+		return errors.Join(fmt.Errorf("invalid schedule of job %q", name), err)
+	}
+
+	s.jobs = append(s.jobs, job{
+		name:     name,
+		spec:     spec,
+		schedule: schedule,
+		run:      run,
+	})
+
+	return nil
+}
+
+// Jobs returns the registered jobs along with their next run
+func (s *Scheduler) Jobs() []entities.Job {
+	now := time.Now()
+
+	jobs := make([]entities.Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, entities.Job{
+			Name:      j.name,
+			Schedule:  j.spec,
+			NextRunAt: j.schedule.Next(now),
+		})
+	}
+
+	return jobs
+}
+
+// Start schedules the jobs in background
+func (s *Scheduler) Start() {
+	slog.Info("starting job scheduler", "jobs", len(s.jobs), "instance", s.instance)
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+}
+
+// Stop stops scheduling jobs and waits for the running ones to finish. When the context ends first, the running jobs
+// are canceled and the context error is returned.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.stopOnce.Do(func() {
+		close(s.stopping)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(j job) {
+	defer s.wg.Done()
+
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Warn("job has no next run", "job", j.name)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.stopping:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.runJob(j, next)
+	}
+}
+
+// runJob runs the job for the scheduled time, unless another instance is running it or already ran it
+func (s *Scheduler) runJob(j job, scheduledAt time.Time) {
+	ctx := s.ctx
+
+	unlock, acquired, err := s.repository.Lock(ctx, lockPrefix+j.name)
+	if err != nil {
+		slog.Error("failed to lock job", "job", j.name, "cause", err)
+		return
+	}
+
+	if !acquired {
+		slog.Debug("job is running on another instance", "job", j.name)
+		return
+	}
+	defer unlock()
+
+	run := entities.JobRun{
+		Job:         j.name,
+		Instance:    s.instance,
+		Status:      entities.JobRunRunning,
+		ScheduledAt: scheduledAt,
+		StartedAt:   time.Now(),
+	}
+
+	added, err := s.repository.AddJobRun(ctx, &run)
+	if err != nil {
+		slog.Error("failed to save job run", "job", j.name, "cause", err)
+		return
+	}
+
+	if !added {
+		slog.Debug("job already ran on another instance", "job", j.name, "scheduled_at", scheduledAt)
+		return
+	}
+
+	err = safeRun(ctx, j.run)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = entities.JobRunSucceeded
+	if err != nil {
+		slog.Error("job failed", "job", j.name, "cause", err)
+
+		run.Status = entities.JobRunFailed
+		run.Error = err.Error()
+		if len(run.Error) > maxErrorLetters {
+			run.Error = strings.ToValidUTF8(run.Error[:maxErrorLetters], "")
+		}
+	}
+
+	// The run is saved even if the job was canceled by a shutdown
+	err = s.repository.UpdateJobRun(context.WithoutCancel(ctx), &run)
+	if err != nil {
+		slog.Error("failed to update job run", "job", j.name, "cause", err)
+	}
+}
+
+// safeRun runs the job, turning a panic into an error so a faulty job does not stop the scheduler
+func safeRun(ctx context.Context, run func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return run(ctx)
+}
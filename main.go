@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
@@ -11,6 +12,7 @@ import (
 	"taskflow/domain/entities"
 	"taskflow/domain/util"
 	"taskflow/infrastructure"
+	"taskflow/infrastructure/scheduler"
 	"time"
 
 	"github.com/gorilla/handlers"
@@ -31,17 +33,19 @@ func main() {
 }
 
 type program struct {
-	server *http.Server
-	cfg    entities.Config
+	server    *http.Server
+	scheduler *scheduler.Scheduler
+	cfg       entities.Config
 }
 
-func (p *program) SetupServer(r *mux.Router) {
-	err := infrastructure.SetupModules(r, p.cfg)
+func (p *program) SetupServer(r *mux.Router) error {
+	jobScheduler, err := infrastructure.SetupModules(r, p.cfg)
 	if err != nil {
-		slog.Error("failed to setup modules", slog.String("cause", err.Error()))
-		return
+		return errors.Join(errors.New("failed to setup modules"), err)
 	}
 
+	p.scheduler = jobScheduler
+
 	corsOptions := handlers.AllowedOriginValidator(func(s string) bool {
 		// todo implement a proper cors validation here
 		return true
@@ -60,6 +64,8 @@ func (p *program) SetupServer(r *mux.Router) {
 		ReadHeaderTimeout: time.Second * 2,
 		IdleTimeout:       time.Second * 60,
 	}
+
+	return nil
 }
 
 func (p *program) StartListening() {
@@ -73,8 +79,14 @@ func (p *program) StartListening() {
 }
 
 func (p *program) run(block bool) error {
-	p.SetupServer(mux.NewRouter())
+	// Nothing is started when the modules can't be set up, the server and the scheduler being left nil
+	err := p.SetupServer(mux.NewRouter())
+	if err != nil {
+		return err
+	}
+
 	p.StartListening()
+	p.scheduler.Start()
 
 	if !block {
 		return nil
@@ -87,17 +99,28 @@ func (p *program) run(block bool) error {
 	// Block until we receive our signal.
 	<-c
 
+	return p.shutdown()
+}
+
+// shutdown stops the server and the background jobs, waiting for the requests and the jobs in progress until the
+// shutdown time
+func (p *program) shutdown() error {
 	// Create a deadline to wait for.
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTime)
 	defer cancel()
 
 	// Doesn't block if no connections, but will otherwise wait
 	// until the timeout deadline.
-	err := p.server.Shutdown(ctx)
-	if err != nil {
-		slog.Error("failed to shutdown http server", slog.String("cause", err.Error()))
-		return err
+	serverErr := p.server.Shutdown(ctx)
+	if serverErr != nil {
+		slog.Error("failed to shutdown http server", slog.String("cause", serverErr.Error()))
 	}
 
-	return nil
+	// Jobs still running at the deadline are canceled
+	schedulerErr := p.scheduler.Stop(ctx)
+	if schedulerErr != nil {
+		slog.Error("failed to stop job scheduler", slog.String("cause", schedulerErr.Error()))
+	}
+
+	return errors.Join(serverErr, schedulerErr)
 }
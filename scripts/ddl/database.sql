@@ -15,14 +15,497 @@ CREATE TABLE IF NOT EXISTS users
     modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
 );
 
+CREATE TABLE IF NOT EXISTS revoked_tokens
+(
+    token_id   VARCHAR(64) PRIMARY KEY,
+    user_id    INT       NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_revoked_tokens_expires_at (expires_at),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS boards
+(
+    id            INT PRIMARY KEY AUTO_INCREMENT,
+    uuid          VARCHAR(255) NOT NULL,
+    title         VARCHAR(255) NOT NULL,
+    description   TEXT,
+    user_id       INT          NOT NULL,
+    wip_warn_only BOOLEAN   DEFAULT FALSE,
+    status_code   INT       DEFAULT 0,
+    deleted_at    TIMESTAMP    NULL,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_boards_trash (status_code, deleted_at),
+    FULLTEXT INDEX ft_boards (title, description)
+);
+
+CREATE TABLE IF NOT EXISTS board_users
+(
+    board_id    INT NOT NULL,
+    user_id     INT NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (board_id, user_id),
+    FOREIGN KEY (board_id) REFERENCES boards (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS task_lists
 (
     id          INT PRIMARY KEY AUTO_INCREMENT,
     uuid        VARCHAR(255) NOT NULL,
-    title       VARCHAR(255) NOT NULL,
+    board_id    INT          NOT NULL,
+    name        VARCHAR(255) NOT NULL,
     description TEXT,
+    position    INT       DEFAULT 0,
+    wip_limit   INT       DEFAULT 0,
     user_id     INT          NOT NULL,
     status_code INT       DEFAULT 0,
+    deleted_at  TIMESTAMP    NULL,
     created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
-)
\ No newline at end of file
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_task_lists_trash (status_code, deleted_at),
+    FOREIGN KEY (board_id) REFERENCES boards (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tasks
+(
+    id                 INT PRIMARY KEY AUTO_INCREMENT,
+    uuid               VARCHAR(255) NOT NULL,
+    task_list_id       INT          NOT NULL,
+    name               VARCHAR(255) NOT NULL,
+    description        TEXT,
+    position           INT       DEFAULT 0,
+    status             INT       DEFAULT 0,
+    priority           INT       DEFAULT 0,
+    original_estimate  INT       DEFAULT 0,
+    due_date           DATETIME     NULL,
+    reminded_at        TIMESTAMP    NULL,
+    overdue_at         TIMESTAMP    NULL,
+    recurrence_rule    VARCHAR(255) NULL,
+    recurrence_list_id INT          NULL,
+    parent_task_id     INT          NULL,
+    user_id            INT          NOT NULL,
+    imported_author    VARCHAR(255) NULL,
+    status_code        INT       DEFAULT 0,
+    deleted_at         TIMESTAMP    NULL,
+    created_at         TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_tasks_due_date (due_date),
+    INDEX idx_tasks_trash (status_code, deleted_at),
+    INDEX idx_tasks_modified_at (modified_at),
+    FULLTEXT INDEX ft_tasks (name, description),
+    FOREIGN KEY (task_list_id) REFERENCES task_lists (id) ON DELETE CASCADE,
+    FOREIGN KEY (recurrence_list_id) REFERENCES task_lists (id) ON DELETE SET NULL,
+    FOREIGN KEY (parent_task_id) REFERENCES tasks (id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS task_assignees
+(
+    task_id    INT NOT NULL,
+    user_id    INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS task_dependencies
+(
+    task_id         INT NOT NULL,
+    blocker_task_id INT NOT NULL,
+    user_id         INT NOT NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, blocker_task_id),
+    INDEX idx_task_dependencies_blocker (blocker_task_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (blocker_task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS labels
+(
+    id          INT PRIMARY KEY AUTO_INCREMENT,
+    uuid        VARCHAR(255) NOT NULL,
+    board_id    INT          NOT NULL,
+    name        VARCHAR(50)  NOT NULL,
+    color       VARCHAR(7)   NOT NULL,
+    user_id     INT          NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_labels_name (board_id, name),
+    FOREIGN KEY (board_id) REFERENCES boards (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS task_labels
+(
+    task_id    INT NOT NULL,
+    label_id   INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, label_id),
+    INDEX idx_task_labels_label (label_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES labels (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS custom_fields
+(
+    id          INT PRIMARY KEY AUTO_INCREMENT,
+    uuid        VARCHAR(255) NOT NULL,
+    board_id    INT          NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    type        VARCHAR(20)  NOT NULL,
+    options     JSON,
+    position    INT       DEFAULT 0,
+    user_id     INT          NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_custom_fields_name (board_id, name),
+    FOREIGN KEY (board_id) REFERENCES boards (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS task_field_values
+(
+    task_id       INT NOT NULL,
+    field_id      INT NOT NULL,
+    value_text    VARCHAR(1024) NULL,
+    value_number  DOUBLE        NULL,
+    value_date    DATETIME      NULL,
+    value_options JSON,
+    value_user_id INT           NULL,
+    modified_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, field_id),
+    INDEX idx_task_field_values_text (field_id, value_text(191)),
+    INDEX idx_task_field_values_number (field_id, value_number),
+    INDEX idx_task_field_values_date (field_id, value_date),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (field_id) REFERENCES custom_fields (id) ON DELETE CASCADE,
+    FOREIGN KEY (value_user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS board_templates
+(
+    id          INT PRIMARY KEY AUTO_INCREMENT,
+    uuid        VARCHAR(255) NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT,
+    content     JSON         NOT NULL,
+    user_id     INT          NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_board_templates_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS checklists
+(
+    id          INT PRIMARY KEY AUTO_INCREMENT,
+    uuid        VARCHAR(255) NOT NULL,
+    task_id     INT          NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    position    INT       DEFAULT 0,
+    user_id     INT          NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS checklist_items
+(
+    id           INT PRIMARY KEY AUTO_INCREMENT,
+    uuid         VARCHAR(255)  NOT NULL,
+    checklist_id INT           NOT NULL,
+    text         VARCHAR(1024) NOT NULL,
+    position     INT       DEFAULT 0,
+    checked_at   TIMESTAMP     NULL,
+    assignee_id  INT           NULL,
+    due_date     DATETIME      NULL,
+    subtask_id   INT           NULL,
+    user_id      INT           NOT NULL,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (checklist_id) REFERENCES checklists (id) ON DELETE CASCADE,
+    FOREIGN KEY (assignee_id) REFERENCES users (id) ON DELETE SET NULL,
+    FOREIGN KEY (subtask_id) REFERENCES tasks (id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS attachments
+(
+    id              INT PRIMARY KEY AUTO_INCREMENT,
+    uuid            VARCHAR(255) NOT NULL,
+    task_id         INT          NOT NULL,
+    file_name       VARCHAR(255) NOT NULL,
+    content_type    VARCHAR(255) NOT NULL,
+    size            BIGINT       NOT NULL,
+    path            VARCHAR(512) NOT NULL,
+    user_id         INT          NOT NULL,
+    imported_author VARCHAR(255) NULL,
+    status_code     INT       DEFAULT 0,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS attachment_thumbnails
+(
+    attachment_id INT          NOT NULL,
+    size          VARCHAR(16)  NOT NULL,
+    width         INT          NOT NULL,
+    height        INT          NOT NULL,
+    content_type  VARCHAR(255) NOT NULL,
+    path          VARCHAR(512) NOT NULL,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (attachment_id, size),
+    FOREIGN KEY (attachment_id) REFERENCES attachments (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS activities
+(
+    id          INT PRIMARY KEY AUTO_INCREMENT,
+    board_id    INT         NOT NULL,
+    task_id     INT,
+    user_id     INT         NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id   INT         NOT NULL,
+    action      VARCHAR(32) NOT NULL,
+    changes     JSON,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_activities_board (board_id, id),
+    INDEX idx_activities_task (task_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS task_comments
+(
+    id              INT PRIMARY KEY AUTO_INCREMENT,
+    uuid            VARCHAR(255) NOT NULL,
+    task_id         INT          NOT NULL,
+    user_id         INT          NOT NULL,
+    imported_author VARCHAR(255) NULL,
+    body            TEXT         NOT NULL,
+    status_code     INT       DEFAULT 0,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FULLTEXT INDEX ft_task_comments (body),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS time_entries
+(
+    id          INT PRIMARY KEY AUTO_INCREMENT,
+    uuid        VARCHAR(255) NOT NULL,
+    task_id     INT          NOT NULL,
+    user_id     INT          NOT NULL,
+    started_at  TIMESTAMP    NOT NULL,
+    ended_at    TIMESTAMP    NULL,
+    minutes     INT       DEFAULT 0,
+    note        TEXT,
+    manual      BOOLEAN   DEFAULT FALSE,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_time_entries_user (user_id, ended_at),
+    INDEX idx_time_entries_started_at (started_at),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS sprints
+(
+    id          INT PRIMARY KEY AUTO_INCREMENT,
+    uuid        VARCHAR(255) NOT NULL,
+    board_id    INT          NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    goal        TEXT,
+    start_date  DATE         NOT NULL,
+    end_date    DATE         NOT NULL,
+    status      VARCHAR(16)  NOT NULL,
+    started_at  TIMESTAMP    NULL,
+    closed_at   TIMESTAMP    NULL,
+    user_id     INT          NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_sprints_board (board_id, status),
+    FOREIGN KEY (board_id) REFERENCES boards (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS sprint_tasks
+(
+    sprint_id    INT NOT NULL,
+    task_id      INT NOT NULL,
+    carried_over BOOLEAN   DEFAULT FALSE,
+    added_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (sprint_id, task_id),
+    INDEX idx_sprint_tasks_task (task_id),
+    FOREIGN KEY (sprint_id) REFERENCES sprints (id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id                   INT PRIMARY KEY AUTO_INCREMENT,
+    uuid                 VARCHAR(255)  NOT NULL,
+    board_id             INT           NOT NULL,
+    url                  VARCHAR(2048) NOT NULL,
+    secret               VARCHAR(255)  NOT NULL,
+    events               JSON,
+    enabled              BOOLEAN   DEFAULT TRUE,
+    consecutive_failures INT       DEFAULT 0,
+    user_id              INT           NOT NULL,
+    status_code          INT       DEFAULT 0,
+    created_at           TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (board_id) REFERENCES boards (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              INT PRIMARY KEY AUTO_INCREMENT,
+    uuid            VARCHAR(255) NOT NULL,
+    webhook_id      INT          NOT NULL,
+    activity_id     INT          NOT NULL,
+    event           VARCHAR(64)  NOT NULL,
+    payload         JSON         NOT NULL,
+    status          VARCHAR(16)  NOT NULL,
+    attempts        INT       DEFAULT 0,
+    response_code   INT       DEFAULT 0,
+    error           TEXT,
+    next_attempt_at TIMESTAMP    NOT NULL,
+    delivered_at    TIMESTAMP    NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+    FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS automation_rules
+(
+    id               INT PRIMARY KEY AUTO_INCREMENT,
+    uuid             VARCHAR(255) NOT NULL,
+    board_id         INT          NOT NULL,
+    name             VARCHAR(255) NOT NULL,
+    trigger_type     VARCHAR(32)  NOT NULL,
+    trigger_list_id  INT       DEFAULT 0,
+    trigger_label_id INT       DEFAULT 0,
+    actions          JSON         NOT NULL,
+    secret           VARCHAR(255) NOT NULL,
+    enabled          BOOLEAN   DEFAULT TRUE,
+    user_id          INT          NOT NULL,
+    created_at       TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (board_id) REFERENCES boards (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS automation_executions
+(
+    id          INT PRIMARY KEY AUTO_INCREMENT,
+    rule_id     INT         NOT NULL,
+    activity_id INT         NOT NULL,
+    task_id     INT         NOT NULL,
+    status      VARCHAR(16) NOT NULL,
+    depth       INT       DEFAULT 0,
+    results     JSON        NOT NULL,
+    error       TEXT,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_automation_executions_created_at (created_at),
+    FOREIGN KEY (rule_id) REFERENCES automation_rules (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS notification_preferences
+(
+    user_id        INT PRIMARY KEY,
+    assigned       BOOLEAN   DEFAULT TRUE,
+    mentioned      BOOLEAN   DEFAULT TRUE,
+    due_soon       BOOLEAN   DEFAULT TRUE,
+    daily_digest   BOOLEAN   DEFAULT FALSE,
+    digest_sent_at TIMESTAMP NULL,
+    modified_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id          INT PRIMARY KEY AUTO_INCREMENT,
+    user_id     INT           NOT NULL,
+    type        VARCHAR(32)   NOT NULL,
+    board_id    INT           NOT NULL,
+    task_id     INT           NULL,
+    activity_id INT           NULL,
+    actor_id    INT           NULL,
+    message     VARCHAR(1024) NOT NULL,
+    read_at     TIMESTAMP     NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_notifications_user (user_id, id),
+    INDEX idx_notifications_unread (user_id, read_at),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS board_watchers
+(
+    board_id   INT NOT NULL,
+    user_id    INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (board_id, user_id),
+    FOREIGN KEY (board_id) REFERENCES boards (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS task_watchers
+(
+    task_id    INT NOT NULL,
+    user_id    INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS calendar_feeds
+(
+    user_id     INT PRIMARY KEY,
+    token_hash  CHAR(64)    NOT NULL,
+    assigned    BOOLEAN   DEFAULT TRUE,
+    finished    BOOLEAN   DEFAULT FALSE,
+    component   VARCHAR(16) NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_calendar_feeds_token (token_hash),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS calendar_feed_boards
+(
+    user_id  INT NOT NULL,
+    board_id INT NOT NULL,
+    PRIMARY KEY (user_id, board_id),
+    FOREIGN KEY (user_id) REFERENCES calendar_feeds (user_id) ON DELETE CASCADE,
+    FOREIGN KEY (board_id) REFERENCES boards (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS email_queue
+(
+    id              INT PRIMARY KEY AUTO_INCREMENT,
+    recipient       VARCHAR(255) NOT NULL,
+    subject         VARCHAR(255) NOT NULL,
+    text_body       MEDIUMTEXT   NOT NULL,
+    html_body       MEDIUMTEXT   NOT NULL,
+    unsubscribe_url VARCHAR(2048),
+    status          VARCHAR(16)  NOT NULL,
+    attempts        INT       DEFAULT 0,
+    error           TEXT,
+    next_attempt_at TIMESTAMP    NOT NULL,
+    sent_at         TIMESTAMP    NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_email_queue_due (status, next_attempt_at)
+);
+
+CREATE TABLE IF NOT EXISTS job_runs
+(
+    id           INT PRIMARY KEY AUTO_INCREMENT,
+    job          VARCHAR(64)  NOT NULL,
+    instance     VARCHAR(255) NOT NULL,
+    status       VARCHAR(16)  NOT NULL,
+    error        TEXT,
+    scheduled_at TIMESTAMP    NOT NULL,
+    started_at   TIMESTAMP    NOT NULL,
+    finished_at  TIMESTAMP    NULL,
+    UNIQUE INDEX idx_job_runs_schedule (job, scheduled_at)
+);
@@ -3,6 +3,8 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -12,6 +14,7 @@ import (
 	"os"
 	"strings"
 	"taskflow/domain/entities"
+	"taskflow/infrastructure"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -19,10 +22,10 @@ import (
 )
 
 func start() error {
-	configsPath, action, terminal := loadFlags()
-	cfg := readCFGFile(configsPath)
+	flags := loadFlags()
+	cfg := readCFGFile(flags.configsPath)
 
-	if !terminal {
+	if !flags.terminal {
 		file, err := configureOutput(cfg.LogDir)
 		if err != nil {
 			return errors.Join(errors.New("failed to configure log outputs"), err)
@@ -35,11 +38,11 @@ func start() error {
 		return errors.Join(errors.New("failed to create service"), err)
 	}
 
-	if strings.TrimSpace(action) == "" {
+	if strings.TrimSpace(flags.action) == "" {
 		return errors.New("action not provided")
 	}
 
-	switch action {
+	switch flags.action {
 	case "run":
 		slog.Info("run service")
 
@@ -68,6 +71,43 @@ func start() error {
 		if err != nil {
 			return errors.Join(errors.New("failed to stop service"), err)
 		}
+	case "import":
+		slog.Info("importing trello board", "file", flags.file, "user", flags.user, "dry_run", flags.dryRun)
+
+		report, err := infrastructure.ImportTrelloBoard(context.Background(), *cfg, flags.user, flags.file, flags.dryRun)
+		if err != nil {
+			return errors.Join(errors.New("failed to import trello board"), err)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(report)
+		if err != nil {
+			return errors.Join(errors.New("failed to write import report"), err)
+		}
+	case "import-archive":
+		slog.Info("importing archive", "file", flags.file, "user", flags.user)
+
+		reports, err := infrastructure.ImportArchive(context.Background(), *cfg, flags.user, flags.file)
+		if err != nil {
+			return errors.Join(errors.New("failed to import archive"), err)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(reports)
+		if err != nil {
+			return errors.Join(errors.New("failed to write import reports"), err)
+		}
+	case "export":
+		slog.Info("exporting archive", "file", flags.file, "user", flags.user, "board", flags.board)
+
+		err = infrastructure.ExportArchive(context.Background(), *cfg, flags.user, flags.board, flags.file)
+		if err != nil {
+			return errors.Join(errors.New("failed to export archive"), err)
+		}
 	}
 
 	return nil
@@ -77,7 +117,12 @@ func (p *program) Start(s service.Service) error {
 	slog.Info("received call to program#start")
 
 	// Start should not block. Do the actual work async.
-	go p.run(false)
+	go func() {
+		err := p.run(false)
+		if err != nil {
+			slog.Error("failed to start server", slog.String("cause", err.Error()))
+		}
+	}()
 
 	return nil
 }
@@ -85,32 +130,50 @@ func (p *program) Start(s service.Service) error {
 func (p *program) Stop(s service.Service) error {
 	slog.Info("received call to program#stop")
 
-	// Stop should not block. Return with a few seconds
-	return nil
+	// Stop should not block for long. The shutdown gives up after the shutdown time
+	if p.server == nil {
+		return nil
+	}
+
+	return p.shutdown()
+}
+
+type appFlags struct {
+	configsPath string
+	action      string
+	terminal    bool
+
+	// file, user and dryRun are the arguments of the import actions, file, user and board those of the export action
+	file   string
+	user   string
+	dryRun bool
+	board  int
 }
 
-func loadFlags() (string, string, bool) {
-	var cfgPath string
-	var action string
-	var terminal bool
+func loadFlags() appFlags {
+	var f appFlags
 
 	// Try to read the configuration file from the command line arguments
-	flag.StringVar(&cfgPath, "configs", "", "the path to the application config file")
-	flag.StringVar(&action, "action", "", "the action to execute")
-	flag.BoolVar(&terminal, "terminal", false, "display the logs in the terminal instead of in the log files")
+	flag.StringVar(&f.configsPath, "configs", "", "the path to the application config file")
+	flag.StringVar(&f.action, "action", "", "the action to execute")
+	flag.BoolVar(&f.terminal, "terminal", false, "display the logs in the terminal instead of in the log files")
+	flag.StringVar(&f.file, "file", "", "the path to the file to import, or to export to")
+	flag.StringVar(&f.user, "user", "", "the email of the user owning the imported boards, or exporting the archive")
+	flag.BoolVar(&f.dryRun, "dry-run", false, "report what the Trello import would create without creating it")
+	flag.IntVar(&f.board, "board", 0, "the ID of the board to export, the whole workspace when not set")
 	flag.Parse()
 
 	// If not provided as argument, read from the environment
-	if cfgPath == "" {
-		cfgPath = os.Getenv("configs")
+	if f.configsPath == "" {
+		f.configsPath = os.Getenv("configs")
 	}
 
 	// And panic if the argument was not found
-	if cfgPath == "" {
+	if f.configsPath == "" {
 		panic("[configs] argument not found")
 	}
 
-	return cfgPath, action, terminal
+	return f
 }
 
 func configureOutput(logFolder string) (*os.File, error) {